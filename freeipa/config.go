@@ -2,7 +2,9 @@ package freeipa
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -10,8 +12,11 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	ipa "github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 )
 
 // Config is the configuration parameters for the FreeIPA API
@@ -19,31 +24,129 @@ type Config struct {
 	Host               string
 	Username           string
 	Password           string
+	PasswordFile       string
 	KerberosEnabled    bool
 	KerberosPrincipal  string
 	KerberosRealm      string
 	Krb5ConfPath       string
 	KeytabPath         string
 	KeytabBase64       string
+	KeytabBase64File   string
+	KerberosCCache     string
+	PKINITCertPath     string
+	PKINITKeyPath      string
 	InsecureSkipVerify bool
+	CACertificate      string
+	CACertificatePath  string
+	RequestTimeout     time.Duration
+	MaxRetries         int
+	MaxConcurrency     int
+	RetryBackoff       time.Duration
+	ReadOnly           bool
+	Debug              bool
+
+	// base is shared by the clients created from the configuration, so that
+	// they share its connections and its max_concurrent_requests slots
+	baseMu sync.Mutex
+	base   http.RoundTripper
 }
 
 // Client creates a FreeIPA client scoped to the global API
 func (c *Config) Client() (*ipa.Client, error) {
-	tspt := &http.Transport{
+	return c.ClientWithContext(context.Background())
+}
+
+// ClientWithContext creates a FreeIPA client whose requests, including the
+// initial login and retries, are bound to ctx. Resources with configurable
+// timeouts use it so that their deadline applies to every call.
+func (c *Config) ClientWithContext(ctx context.Context) (*ipa.Client, error) {
+	host, err := utils.NormalizeHost(c.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host: %w", err)
+	}
+
+	base, err := c.baseTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	tspt := utils.NewRetryTransport(base, utils.RetryOptions{
+		RequestTimeout: c.RequestTimeout,
+		MaxRetries:     c.MaxRetries,
+		Backoff:        c.RetryBackoff,
+	})
+	if c.ReadOnly {
+		tspt = utils.NewReadOnlyTransport(tspt)
+	}
+	tspt = utils.NewResultFixupTransport(tspt)
+	tspt = utils.NewContextTransport(ctx, tspt)
+	if utils.DebugLoggingEnabled(c.Debug) {
+		tspt = utils.NewDebugTransport(ctx, tspt, c.Debug)
+	}
+
+	session := utils.NewSessionTransport(tspt)
+
+	client, err := c.connect(host, session)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Reconnect = func() error {
+		_, err := c.connect(host, session)
+		return err
+	}
+
+	log.Printf("[INFO] FreeIPA Client configured for host: %s", host)
+
+	return client, nil
+}
+
+// baseTransport returns the transport sending the requests of every client
+// created from the configuration, creating it on first use.
+func (c *Config) baseTransport() (http.RoundTripper, error) {
+	c.baseMu.Lock()
+	defer c.baseMu.Unlock()
+
+	if c.base != nil {
+		return c.base, nil
+	}
+
+	rootCAs, err := loadCACertPool(c.CACertificate, c.CACertificatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	c.base = utils.NewConcurrencyTransport(&http.Transport{
+		Proxy: http.ProxyFromEnvironment,
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: c.InsecureSkipVerify,
+			RootCAs:            rootCAs,
 		},
-	}
+	}, c.MaxConcurrency)
 
-	var (
-		client *ipa.Client
-		err    error
-	)
+	return c.base, nil
+}
 
-	if c.KerberosEnabled {
-		if c.KeytabPath == "" && c.KeytabBase64 == "" {
-			return nil, fmt.Errorf("kerberos_enabled is true but neither keytab_path nor keytab_base64 is set")
+// connect connects a new client to the FreeIPA host through tspt.
+func (c *Config) connect(host string, tspt http.RoundTripper) (*ipa.Client, error) {
+	if c.KerberosEnabled && c.PKINITCertPath != "" {
+		return utils.ConnectWithPKINIT(host, tspt, c.Krb5ConfPath, utils.PKINITOptions{
+			Principal:       c.KerberosPrincipal,
+			Realm:           c.KerberosRealm,
+			CertificatePath: c.PKINITCertPath,
+			KeyPath:         c.PKINITKeyPath,
+		})
+	} else if c.KerberosEnabled && c.KerberosCCache != "" {
+		krb5ConfFile, err := os.Open(c.Krb5ConfPath)
+		if err != nil {
+			return nil, err
+		}
+		defer krb5ConfFile.Close()
+
+		return utils.ConnectWithKerberosCCache(host, tspt, krb5ConfFile, c.KerberosCCache)
+	} else if c.KerberosEnabled {
+		if c.KeytabPath == "" && c.KeytabBase64 == "" && c.KeytabBase64File == "" {
+			return nil, fmt.Errorf("kerberos_enabled is true but neither keytab_path, keytab_base64 nor keytab_base64_file is set")
 		}
 
 		krb5ConfFile, err := os.Open(c.Krb5ConfPath)
@@ -52,33 +155,45 @@ func (c *Config) Client() (*ipa.Client, error) {
 		}
 		defer krb5ConfFile.Close()
 
-		keytabReader, err := openKeytabReader(c.KeytabPath, c.KeytabBase64)
+		keytabReader, err := openKeytabReader(c.KeytabPath, c.KeytabBase64, c.KeytabBase64File)
 		if err != nil {
 			return nil, err
 		}
 		defer keytabReader.Close()
 
+		keytab, err := io.ReadAll(keytabReader)
+		if err != nil {
+			return nil, fmt.Errorf("reading keytab: %w", err)
+		}
+
+		principal, realm, err := utils.KerberosIdentity(keytab, c.KerberosPrincipal, c.KerberosRealm, host)
+		if err != nil {
+			return nil, err
+		}
+
 		kerberosOpts := &ipa.KerberosConnectOptions{
 			Krb5ConfigReader: krb5ConfFile,
-			KeytabReader:     keytabReader,
-			Username:         c.KerberosPrincipal,
-			Realm:            c.KerberosRealm,
+			KeytabReader:     bytes.NewReader(keytab),
+			Username:         principal,
+			Realm:            realm,
 		}
 
-		client, err = ipa.ConnectWithKerberos(c.Host, tspt, kerberosOpts)
-	} else {
-		client, err = ipa.Connect(c.Host, tspt, c.Username, c.Password)
-	}
-	if err != nil {
-		return nil, err
+		return ipa.ConnectWithKerberos(host, tspt, kerberosOpts)
 	}
 
-	log.Printf("[INFO] FreeIPA Client configured for host: %s", c.Host)
+	// The file is read on every login so that rotated passwords are used
+	password := c.Password
+	if password == "" && c.PasswordFile != "" {
+		var err error
+		if password, err = utils.ReadSecretFile(c.PasswordFile); err != nil {
+			return nil, fmt.Errorf("reading password_file: %w", err)
+		}
+	}
 
-	return client, nil
+	return ipa.Connect(host, tspt, c.Username, password)
 }
 
-func openKeytabReader(path, b64 string) (io.ReadCloser, error) {
+func openKeytabReader(path, b64, b64File string) (io.ReadCloser, error) {
 	if b64 != "" {
 		clean := compactBase64Whitespace(b64)
 		decoded, err := base64.StdEncoding.DecodeString(clean)
@@ -88,6 +203,14 @@ func openKeytabReader(path, b64 string) (io.ReadCloser, error) {
 		return io.NopCloser(bytes.NewReader(decoded)), nil
 	}
 
+	if b64File != "" {
+		data, err := utils.ReadKeytabFile(b64File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keytab_base64_file: %w", err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -95,6 +218,30 @@ func openKeytabReader(path, b64 string) (io.ReadCloser, error) {
 	return file, nil
 }
 
+func loadCACertPool(pemContent, path string) (*x509.CertPool, error) {
+	if pemContent == "" && path == "" {
+		return nil, nil
+	}
+
+	pool := x509.NewCertPool()
+
+	if pemContent != "" && !pool.AppendCertsFromPEM([]byte(pemContent)) {
+		return nil, fmt.Errorf("no valid PEM certificate found in ca_certificate")
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid PEM certificate found in %s", path)
+		}
+	}
+
+	return pool, nil
+}
+
 func compactBase64Whitespace(s string) string {
 	for i := 0; i < len(s); i++ {
 		switch s[i] {
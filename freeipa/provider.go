@@ -1,7 +1,15 @@
 package freeipa
 
 import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Provider returns a terraform.ResourceProvider.
@@ -26,6 +34,12 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_PASSWORD", ""),
 				Description: descriptions["password"],
 			},
+			"password_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_PASSWORD_FILE", ""),
+				Description: descriptions["password_file"],
+			},
 			"kerberos_enabled": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -63,22 +77,103 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_KEYTAB_BASE64", ""),
 				Description: descriptions["keytab_base64"],
 			},
+			"keytab_base64_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_KEYTAB_BASE64_FILE", ""),
+				Description: descriptions["keytab_base64_file"],
+			},
+			"kerberos_ccache": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_KERBEROS_CCACHE", ""),
+				Description: descriptions["kerberos_ccache"],
+			},
+			"pkinit_certificate_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_PKINIT_CERTIFICATE_PATH", ""),
+				Description: descriptions["pkinit_certificate_path"],
+			},
+			"pkinit_key_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_PKINIT_KEY_PATH", ""),
+				Description: descriptions["pkinit_key_path"],
+			},
 			"insecure": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_INSECURE", false),
 				Description: descriptions["insecure"],
 			},
+			"ca_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_CA_CERTIFICATE", ""),
+				Description: descriptions["ca_certificate"],
+			},
+			"ca_certificate_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_CA_CERTIFICATE_PATH", ""),
+				Description: descriptions["ca_certificate_path"],
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("FREEIPA_REQUEST_TIMEOUT", ""),
+				ValidateFunc: validateDuration,
+				Description:  descriptions["request_timeout"],
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("FREEIPA_MAX_RETRIES", utils.DefaultMaxRetries),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descriptions["max_retries"],
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("FREEIPA_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  descriptions["max_concurrent_requests"],
+			},
+			"retry_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("FREEIPA_RETRY_BACKOFF", utils.DefaultRetryBackoff.String()),
+				ValidateFunc: validateDuration,
+				Description:  descriptions["retry_backoff"],
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_READ_ONLY", false),
+				Description: descriptions["read_only"],
+			},
+			"debug": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FREEIPA_DEBUG", false),
+				Description: descriptions["debug"],
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
 			"freeipa_automemberadd":                   resourceFreeIPAAutomemberadd(),
 			"freeipa_automemberadd_condition":         resourceFreeIPAAutomemberaddCondition(),
+			"freeipa_automember_rule":                 resourceFreeIPAAutomemberRule(),
 			"freeipa_dns_zone":                        resourceFreeIPADNSZone(),
 			"freeipa_hbac_policy":                     resourceFreeIPAHBACPolicy(),
 			"freeipa_hbac_policy_host_membership":     resourceFreeIPAHBACPolicyHostMembership(),
 			"freeipa_hbac_policy_service_membership":  resourceFreeIPAHBACPolicyServiceMembership(),
 			"freeipa_hbac_policy_user_membership":     resourceFreeIPAHBACPolicyUserMembership(),
+			"freeipa_hbac_rule":                       resourceFreeIPAHBACRule(),
+			"freeipa_hbac_rule_host_membership":       resourceFreeIPAHBACRuleHostMembership(),
+			"freeipa_hbac_rule_service_membership":    resourceFreeIPAHBACRuleServiceMembership(),
+			"freeipa_hbac_rule_user_membership":       resourceFreeIPAHBACRuleUserMembership(),
 			"freeipa_host_hostgroup_membership":       resourceFreeIPAHostHostGroupMembership(),
 			"freeipa_hostgroup":                       resourceFreeIPAHostGroup(),
 			"freeipa_sudo_cmd":                        resourceFreeIPASudocmd(),
@@ -92,7 +187,6 @@ func Provider() *schema.Provider {
 			"freeipa_sudo_rule_runasgroup_membership": resourceFreeIPASudoRuleRunAsGroupMembership(),
 			"freeipa_sudo_rule_runasuser_membership":  resourceFreeIPASudoRuleRunAsUserMembership(),
 			"freeipa_sudo_rule_user_membership":       resourceFreeIPASudoRuleUserMembership(),
-			"freeipa_user":                            resourceFreeIPAUser(),
 			"freeipa_user_group_membership":           resourceFreeIPAUserGroupMembership(),
 		},
 
@@ -107,34 +201,129 @@ var descriptions map[string]string
 
 func init() {
 	descriptions = map[string]string{
-		"host": "FreeIPA host to connect to",
+		"host": "FreeIPA host to connect to, as a host name (e.g. `ipa.example.com`) or an https URL",
 
 		"username": "Username to use for connection",
 
-		"password": "Password to use for connection",
+		"password":      "Password to use for connection",
+		"password_file": "Path to a file holding the password, read whenever the provider logs in. Surrounding whitespace is ignored, `password` takes precedence",
 
 		"kerberos_enabled":   "Use Kerberos/keytab authentication instead of username/password",
-		"kerberos_principal": "Kerberos principal to use when kerberos_enabled is true",
-		"kerberos_realm":     "Kerberos realm to use when kerberos_enabled is true",
+		"kerberos_principal": "Kerberos principal to use when kerberos_enabled is true, defaults to the only principal of the keytab",
+		"kerberos_realm":     "Kerberos realm to use when kerberos_enabled is true, uppercased. Defaults to the realm of the keytab principal, or to the uppercased domain of host when the keytab holds several realms",
 		"krb5_conf_path":     "Path to krb5.conf to use for Kerberos authentication",
 		"keytab_path":        "Path to keytab file to use for Kerberos authentication",
 		"keytab_base64":      "Base64 encoded keytab content. When set it takes precedence over keytab_path.",
+		"keytab_base64_file": "Path to a file holding the keytab, raw or base64 encoded, read whenever the provider logs in. It takes precedence over keytab_path, keytab_base64 takes precedence over it.",
+		"kerberos_ccache":    "Kerberos credential cache holding the tickets of an earlier `kinit`, used instead of a keytab. Conflicts with keytab_path and keytab_base64.",
+
+		"pkinit_certificate_path": "Path to a PEM encoded certificate, such as the host certificate of an enrolled machine, with which the tickets of kerberos_principal are obtained through PKINIT instead of a keytab. Requires kerberos_enabled, pkinit_key_path and the `kinit` command of MIT Kerberos. Conflicts with the keytab arguments and kerberos_ccache.",
+		"pkinit_key_path":         "Path to the unencrypted PEM encoded private key of pkinit_certificate_path.",
+
+		"insecure":            "Set to true to disable FreeIPA host TLS certificate verification",
+		"ca_certificate":      "PEM encoded CA certificate(s) used to verify the FreeIPA host TLS certificate",
+		"ca_certificate_path": "Path to a PEM encoded CA certificate bundle used to verify the FreeIPA host TLS certificate",
 
-		"insecure": "Set to true to disable FreeIPA host TLS certificate verification",
+		"request_timeout": "Timeout of a single request to FreeIPA as a duration string (e.g. `30s`). No timeout when unset.",
+		"max_retries":     "Number of times a failed request to FreeIPA is retried. Defaults to 3.",
+		"retry_backoff":   "Delay before the first retry as a duration string, doubled on every attempt. Defaults to `1s`.",
+
+		"max_concurrent_requests": "Maximum number of requests sent to FreeIPA at once, whatever the Terraform parallelism. Further requests wait for one to complete. Unlimited when unset or 0.",
+
+		"read_only": "Refuse every FreeIPA call which may modify the server, e.g. to run plans with credentials which must never write. Applying a change fails without reaching FreeIPA. Defaults to false.",
+		"debug":     "Log every FreeIPA call at the DEBUG level, with its method, its parameters with passwords, keytabs and secrets redacted, and its outcome. Calls are also logged when `TF_LOG` is `TRACE`. Defaults to false.",
 	}
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	var requestTimeout, retryBackoff time.Duration
+	if v := d.Get("request_timeout").(string); v != "" {
+		requestTimeout, _ = time.ParseDuration(v)
+	}
+	if v := d.Get("retry_backoff").(string); v != "" {
+		retryBackoff, _ = time.ParseDuration(v)
+	}
+
+	// An explicitly configured keytab takes precedence over the ambient
+	// credential cache of KRB5CCNAME.
+	keytabConfigured := os.Getenv("FREEIPA_KEYTAB") != "" || os.Getenv("FREEIPA_KEYTAB_BASE64") != "" || os.Getenv("FREEIPA_KEYTAB_BASE64_FILE") != ""
+	if raw := d.GetRawConfig(); !raw.IsNull() {
+		keytabConfigured = keytabConfigured || !raw.GetAttr("keytab_path").IsNull() || !raw.GetAttr("keytab_base64").IsNull() || !raw.GetAttr("keytab_base64_file").IsNull()
+	}
+
+	kerberosCCache := d.Get("kerberos_ccache").(string)
+	if d.Get("kerberos_enabled").(bool) && kerberosCCache != "" && keytabConfigured {
+		return nil, fmt.Errorf("kerberos_ccache cannot be used together with keytab_path, keytab_base64 or keytab_base64_file")
+	}
+
+	pkinitCertPath := d.Get("pkinit_certificate_path").(string)
+	pkinitKeyPath := d.Get("pkinit_key_path").(string)
+	pkinit := pkinitCertPath != "" || pkinitKeyPath != ""
+	if pkinit {
+		switch {
+		case pkinitCertPath == "" || pkinitKeyPath == "":
+			return nil, fmt.Errorf("pkinit_certificate_path and pkinit_key_path must be set together")
+		case !d.Get("kerberos_enabled").(bool):
+			return nil, fmt.Errorf("kerberos_enabled must be true to authenticate with pkinit_certificate_path")
+		case d.Get("kerberos_principal").(string) == "":
+			return nil, fmt.Errorf("kerberos_principal is required to authenticate with PKINIT")
+		case kerberosCCache != "" || keytabConfigured:
+			return nil, fmt.Errorf("pkinit_certificate_path cannot be used together with kerberos_ccache, keytab_path, keytab_base64 or keytab_base64_file")
+		}
+	}
+
+	if kerberosCCache == "" && !keytabConfigured && !pkinit {
+		kerberosCCache = os.Getenv("KRB5CCNAME")
+	}
+
+	host := d.Get("host").(string)
+	if host != "" {
+		var err error
+		if host, err = utils.NormalizeHost(host); err != nil {
+			return nil, fmt.Errorf("invalid host, expected a host name such as \"ipa.example.com\" or an https URL: %w", err)
+		}
+	}
+
+	// Kerberos realms are uppercase by convention, a mismatching case makes the
+	// login fail with an unhelpful KDC error
+	kerberosRealm := d.Get("kerberos_realm").(string)
+	if realm := strings.ToUpper(kerberosRealm); realm != kerberosRealm {
+		log.Printf("[WARN] The Kerberos realm %q is used as %q", kerberosRealm, realm)
+		kerberosRealm = realm
+	}
+
 	return &Config{
-		Host:               d.Get("host").(string),
+		Host:               host,
 		Username:           d.Get("username").(string),
 		Password:           d.Get("password").(string),
+		PasswordFile:       d.Get("password_file").(string),
 		KerberosEnabled:    d.Get("kerberos_enabled").(bool),
 		KerberosPrincipal:  d.Get("kerberos_principal").(string),
-		KerberosRealm:      d.Get("kerberos_realm").(string),
+		KerberosRealm:      kerberosRealm,
 		Krb5ConfPath:       d.Get("krb5_conf_path").(string),
 		KeytabPath:         d.Get("keytab_path").(string),
 		KeytabBase64:       d.Get("keytab_base64").(string),
+		KeytabBase64File:   d.Get("keytab_base64_file").(string),
+		KerberosCCache:     kerberosCCache,
+		PKINITCertPath:     pkinitCertPath,
+		PKINITKeyPath:      pkinitKeyPath,
 		InsecureSkipVerify: d.Get("insecure").(bool),
+		CACertificate:      d.Get("ca_certificate").(string),
+		CACertificatePath:  d.Get("ca_certificate_path").(string),
+		RequestTimeout:     requestTimeout,
+		MaxRetries:         d.Get("max_retries").(int),
+		MaxConcurrency:     d.Get("max_concurrent_requests").(int),
+		RetryBackoff:       retryBackoff,
+		ReadOnly:           d.Get("read_only").(bool),
+		Debug:              d.Get("debug").(bool),
 	}, nil
 }
+
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	if s := v.(string); s != "" {
+		if _, err := time.ParseDuration(s); err != nil {
+			errors = append(errors, fmt.Errorf("%q must be a duration string: %s", k, err))
+		}
+	}
+	return
+}
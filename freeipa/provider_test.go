@@ -2,8 +2,11 @@ package freeipa
 
 import (
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -28,3 +31,239 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("FREEIPA_PASSWORD must be set for acceptance tests")
 	}
 }
+
+// providerEnvVars are cleared before each configuration test so that the
+// environment of the machine running the tests does not leak in.
+var providerEnvVars = []string{
+	"FREEIPA_HOST",
+	"FREEIPA_USERNAME",
+	"FREEIPA_PASSWORD",
+	"FREEIPA_PASSWORD_FILE",
+	"FREEIPA_INSECURE",
+	"FREEIPA_CA_CERTIFICATE",
+	"FREEIPA_CA_CERTIFICATE_PATH",
+	"FREEIPA_REQUEST_TIMEOUT",
+	"FREEIPA_MAX_RETRIES",
+	"FREEIPA_MAX_CONCURRENT_REQUESTS",
+	"FREEIPA_RETRY_BACKOFF",
+	"FREEIPA_KERBEROS_ENABLED",
+	"FREEIPA_KERBEROS_PRINCIPAL",
+	"FREEIPA_KERBEROS_REALM",
+	"FREEIPA_KRB5_CONF",
+	"FREEIPA_KEYTAB",
+	"FREEIPA_KEYTAB_BASE64",
+	"FREEIPA_KEYTAB_BASE64_FILE",
+	"FREEIPA_KERBEROS_CCACHE",
+	"FREEIPA_READ_ONLY",
+	"FREEIPA_DEBUG",
+	"KRB5CCNAME",
+}
+
+func TestProviderConfigure(t *testing.T) {
+	cases := []struct {
+		name   string
+		env    map[string]string
+		config map[string]interface{}
+		want   func(c *Config)
+	}{
+		{
+			name: "defaults",
+			want: func(c *Config) {},
+		},
+		{
+			name: "FREEIPA_HOST",
+			env:  map[string]string{"FREEIPA_HOST": "ipa.example.test"},
+			want: func(c *Config) { c.Host = "ipa.example.test" },
+		},
+		{
+			name:   "host URL",
+			config: map[string]interface{}{"host": "https://ipa.example.test/ipa/ui/"},
+			want:   func(c *Config) { c.Host = "ipa.example.test" },
+		},
+		{
+			name: "FREEIPA_USERNAME",
+			env:  map[string]string{"FREEIPA_USERNAME": "admin"},
+			want: func(c *Config) { c.Username = "admin" },
+		},
+		{
+			name: "FREEIPA_PASSWORD",
+			env:  map[string]string{"FREEIPA_PASSWORD": "secret"},
+			want: func(c *Config) { c.Password = "secret" },
+		},
+		{
+			name: "FREEIPA_PASSWORD_FILE",
+			env:  map[string]string{"FREEIPA_PASSWORD_FILE": "/run/secrets/password"},
+			want: func(c *Config) { c.PasswordFile = "/run/secrets/password" },
+		},
+		{
+			name: "FREEIPA_INSECURE",
+			env:  map[string]string{"FREEIPA_INSECURE": "true"},
+			want: func(c *Config) { c.InsecureSkipVerify = true },
+		},
+		{
+			name: "FREEIPA_INSECURE numeric",
+			env:  map[string]string{"FREEIPA_INSECURE": "1"},
+			want: func(c *Config) { c.InsecureSkipVerify = true },
+		},
+		{
+			name: "FREEIPA_CA_CERTIFICATE",
+			env:  map[string]string{"FREEIPA_CA_CERTIFICATE": "-----BEGIN CERTIFICATE-----"},
+			want: func(c *Config) { c.CACertificate = "-----BEGIN CERTIFICATE-----" },
+		},
+		{
+			name: "FREEIPA_CA_CERTIFICATE_PATH",
+			env:  map[string]string{"FREEIPA_CA_CERTIFICATE_PATH": "/etc/ipa/ca.crt"},
+			want: func(c *Config) { c.CACertificatePath = "/etc/ipa/ca.crt" },
+		},
+		{
+			name: "FREEIPA_REQUEST_TIMEOUT",
+			env:  map[string]string{"FREEIPA_REQUEST_TIMEOUT": "30s"},
+			want: func(c *Config) { c.RequestTimeout = 30 * time.Second },
+		},
+		{
+			name: "FREEIPA_MAX_RETRIES",
+			env:  map[string]string{"FREEIPA_MAX_RETRIES": "5"},
+			want: func(c *Config) { c.MaxRetries = 5 },
+		},
+		{
+			name: "FREEIPA_RETRY_BACKOFF",
+			env:  map[string]string{"FREEIPA_RETRY_BACKOFF": "2s"},
+			want: func(c *Config) { c.RetryBackoff = 2 * time.Second },
+		},
+		{
+			name: "FREEIPA_KERBEROS_ENABLED",
+			env:  map[string]string{"FREEIPA_KERBEROS_ENABLED": "true"},
+			want: func(c *Config) { c.KerberosEnabled = true },
+		},
+		{
+			name: "FREEIPA_KERBEROS_PRINCIPAL",
+			env:  map[string]string{"FREEIPA_KERBEROS_PRINCIPAL": "terraform"},
+			want: func(c *Config) { c.KerberosPrincipal = "terraform" },
+		},
+		{
+			name: "FREEIPA_KERBEROS_REALM",
+			env:  map[string]string{"FREEIPA_KERBEROS_REALM": "EXAMPLE.TEST"},
+			want: func(c *Config) { c.KerberosRealm = "EXAMPLE.TEST" },
+		},
+		{
+			name: "FREEIPA_KERBEROS_REALM lowercase",
+			env:  map[string]string{"FREEIPA_KERBEROS_REALM": "example.test"},
+			want: func(c *Config) { c.KerberosRealm = "EXAMPLE.TEST" },
+		},
+		{
+			name: "FREEIPA_KRB5_CONF",
+			env:  map[string]string{"FREEIPA_KRB5_CONF": "/tmp/krb5.conf"},
+			want: func(c *Config) { c.Krb5ConfPath = "/tmp/krb5.conf" },
+		},
+		{
+			name: "FREEIPA_KEYTAB",
+			env:  map[string]string{"FREEIPA_KEYTAB": "/tmp/terraform.keytab", "KRB5CCNAME": "FILE:/tmp/krb5cc"},
+			want: func(c *Config) { c.KeytabPath = "/tmp/terraform.keytab" },
+		},
+		{
+			name: "FREEIPA_KEYTAB_BASE64",
+			env:  map[string]string{"FREEIPA_KEYTAB_BASE64": "BQI=", "KRB5CCNAME": "FILE:/tmp/krb5cc"},
+			want: func(c *Config) { c.KeytabBase64 = "BQI=" },
+		},
+		{
+			name: "FREEIPA_KEYTAB_BASE64_FILE",
+			env:  map[string]string{"FREEIPA_KEYTAB_BASE64_FILE": "/run/secrets/keytab", "KRB5CCNAME": "FILE:/tmp/krb5cc"},
+			want: func(c *Config) { c.KeytabBase64File = "/run/secrets/keytab" },
+		},
+		{
+			name: "FREEIPA_KERBEROS_CCACHE",
+			env:  map[string]string{"FREEIPA_KERBEROS_CCACHE": "FILE:/tmp/terraform", "KRB5CCNAME": "FILE:/tmp/krb5cc"},
+			want: func(c *Config) { c.KerberosCCache = "FILE:/tmp/terraform" },
+		},
+		{
+			name: "KRB5CCNAME",
+			env:  map[string]string{"KRB5CCNAME": "FILE:/tmp/krb5cc"},
+			want: func(c *Config) { c.KerberosCCache = "FILE:/tmp/krb5cc" },
+		},
+		{
+			name: "FREEIPA_READ_ONLY",
+			env:  map[string]string{"FREEIPA_READ_ONLY": "true"},
+			want: func(c *Config) { c.ReadOnly = true },
+		},
+		{
+			name: "FREEIPA_DEBUG",
+			env:  map[string]string{"FREEIPA_DEBUG": "true"},
+			want: func(c *Config) { c.Debug = true },
+		},
+		{
+			name: "FREEIPA_MAX_CONCURRENT_REQUESTS",
+			env:  map[string]string{"FREEIPA_MAX_CONCURRENT_REQUESTS": "4"},
+			want: func(c *Config) { c.MaxConcurrency = 4 },
+		},
+		{
+			name:   "configuration over environment",
+			env:    map[string]string{"FREEIPA_HOST": "env.example.test", "FREEIPA_INSECURE": "true", "FREEIPA_MAX_RETRIES": "5"},
+			config: map[string]interface{}{"host": "config.example.test", "insecure": false, "max_retries": 1},
+			want: func(c *Config) {
+				c.Host = "config.example.test"
+				c.MaxRetries = 1
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, k := range providerEnvVars {
+				t.Setenv(k, "")
+			}
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			config := tc.config
+			if config == nil {
+				config = map[string]interface{}{}
+			}
+
+			got, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().Schema, config))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			want := &Config{
+				Krb5ConfPath: "/etc/krb5.conf",
+				KeytabPath:   "/etc/krb5.keytab",
+				MaxRetries:   utils.DefaultMaxRetries,
+				RetryBackoff: utils.DefaultRetryBackoff,
+			}
+			tc.want(want)
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestConfigBaseTransport(t *testing.T) {
+	c := &Config{MaxConcurrency: 2}
+
+	first, err := c.baseTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := c.baseTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The clients of a configuration share its concurrency limit
+	if first != second {
+		t.Error("got a new transport, want the one of the first client")
+	}
+
+	other, err := (&Config{MaxConcurrency: 2}).baseTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if other == first {
+		t.Error("got the transport of another configuration")
+	}
+}
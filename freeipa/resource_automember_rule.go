@@ -0,0 +1,305 @@
+package freeipa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	ipa "github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/exp/slices"
+)
+
+func automemberConditionSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"key": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Attribute the expression is matched against (e.g. `uid`, `fqdn`)",
+				},
+				"expression": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Regular expression matched against the attribute",
+				},
+			},
+		},
+	}
+}
+
+func resourceFreeIPAAutomemberRule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFreeIPAAutomemberRuleCreate,
+		ReadContext:   resourceFreeIPAAutomemberRuleRead,
+		UpdateContext: resourceFreeIPAAutomemberRuleUpdate,
+		DeleteContext: resourceFreeIPAAutomemberRuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the group or host group the rule adds entries to",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"group", "hostgroup"}, false),
+				Description:  "Grouping the rule applies to (`group` or `hostgroup`)",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Automember rule description",
+			},
+			"inclusive": automemberConditionSchema("Conditions adding matching entries to the group"),
+			"exclusive": automemberConditionSchema("Conditions preventing matching entries from being added to the group"),
+		},
+	}
+}
+
+func resourceFreeIPAAutomemberRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Creating freeipa automember rule")
+
+	client, err := meta.(*Config).Client()
+	if err != nil {
+		return diag.Errorf("Error creating freeipa identity client: %s", err)
+	}
+
+	name := d.Get("name").(string)
+	_type := d.Get("type").(string)
+
+	args := ipa.AutomemberAddArgs{
+		Cn:   name,
+		Type: _type,
+	}
+	optArgs := ipa.AutomemberAddOptionalArgs{}
+	if _v, ok := d.GetOk("description"); ok {
+		v := _v.(string)
+		optArgs.Description = &v
+	}
+
+	_, err = client.AutomemberAdd(&args, &optArgs)
+	if err != nil {
+		return diag.Errorf("Error creating freeipa automember rule: %s", err)
+	}
+
+	d.SetId(automemberRuleID(_type, name))
+
+	inclusive := automemberConditionsByKey(d.Get("inclusive").(*schema.Set))
+	exclusive := automemberConditionsByKey(d.Get("exclusive").(*schema.Set))
+	if err := addAutomemberConditions(client, name, _type, inclusive, exclusive); err != nil {
+		return diag.Errorf("Error creating freeipa automember rule conditions: %s", err)
+	}
+
+	return resourceFreeIPAAutomemberRuleRead(ctx, d, meta)
+}
+
+func resourceFreeIPAAutomemberRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Read freeipa automember rule")
+
+	_type, name, err := parseAutomemberRuleID(d.Id())
+	if err != nil {
+		return diag.Errorf("Error parsing ID of freeipa_automember_rule: %s", err)
+	}
+
+	client, err := meta.(*Config).Client()
+	if err != nil {
+		return diag.Errorf("Error creating freeipa identity client: %s", err)
+	}
+
+	all := true
+	res, err := client.AutomemberShow(&ipa.AutomemberShowArgs{Cn: name, Type: _type}, &ipa.AutomemberShowOptionalArgs{All: &all})
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Automember rule %s not found", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error reading freeipa automember rule: %s", err)
+	}
+
+	d.Set("name", name)
+	d.Set("type", _type)
+	d.Set("description", stringValue(res.Result.Description))
+
+	if res.Result.Automemberinclusiveregex != nil {
+		d.Set("inclusive", flattenAutomemberConditions(*res.Result.Automemberinclusiveregex))
+	} else {
+		d.Set("inclusive", nil)
+	}
+	if res.Result.Automemberexclusiveregex != nil {
+		d.Set("exclusive", flattenAutomemberConditions(*res.Result.Automemberexclusiveregex))
+	} else {
+		d.Set("exclusive", nil)
+	}
+
+	return nil
+}
+
+func resourceFreeIPAAutomemberRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Update freeipa automember rule")
+
+	client, err := meta.(*Config).Client()
+	if err != nil {
+		return diag.Errorf("Error creating freeipa identity client: %s", err)
+	}
+
+	name := d.Get("name").(string)
+	_type := d.Get("type").(string)
+
+	if d.HasChange("description") {
+		v := d.Get("description").(string)
+		_, err = client.AutomemberMod(&ipa.AutomemberModArgs{Cn: name, Type: _type}, &ipa.AutomemberModOptionalArgs{Description: &v})
+		if err != nil && !strings.Contains(err.Error(), "EmptyModlist") {
+			return diag.Errorf("Error update freeipa automember rule: %s", err)
+		}
+	}
+
+	if d.HasChanges("inclusive", "exclusive") {
+		oi, ni := d.GetChange("inclusive")
+		oe, ne := d.GetChange("exclusive")
+
+		// Only the conditions which changed are sent, the other ones are kept
+		// untouched on the rule.
+		removedInclusive := automemberConditionsByKey(oi.(*schema.Set).Difference(ni.(*schema.Set)))
+		removedExclusive := automemberConditionsByKey(oe.(*schema.Set).Difference(ne.(*schema.Set)))
+		if err := removeAutomemberConditions(client, name, _type, removedInclusive, removedExclusive); err != nil {
+			return diag.Errorf("Error update freeipa automember rule conditions: %s", err)
+		}
+
+		addedInclusive := automemberConditionsByKey(ni.(*schema.Set).Difference(oi.(*schema.Set)))
+		addedExclusive := automemberConditionsByKey(ne.(*schema.Set).Difference(oe.(*schema.Set)))
+		if err := addAutomemberConditions(client, name, _type, addedInclusive, addedExclusive); err != nil {
+			return diag.Errorf("Error update freeipa automember rule conditions: %s", err)
+		}
+	}
+
+	return resourceFreeIPAAutomemberRuleRead(ctx, d, meta)
+}
+
+func resourceFreeIPAAutomemberRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Delete freeipa automember rule")
+
+	_type, name, err := parseAutomemberRuleID(d.Id())
+	if err != nil {
+		return diag.Errorf("Error parsing ID of freeipa_automember_rule: %s", err)
+	}
+
+	client, err := meta.(*Config).Client()
+	if err != nil {
+		return diag.Errorf("Error creating freeipa identity client: %s", err)
+	}
+
+	_, err = client.AutomemberDel(&ipa.AutomemberDelArgs{Cn: []string{name}, Type: _type}, &ipa.AutomemberDelOptionalArgs{})
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Automember rule %s already deleted", d.Id())
+		} else {
+			return diag.Errorf("Error delete freeipa automember rule: %s", err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func automemberRuleID(_type, name string) string {
+	return fmt.Sprintf("%s/%s", _type, name)
+}
+
+func parseAutomemberRuleID(id string) (string, string, error) {
+	idParts := strings.SplitN(id, "/", 2)
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		return "", "", fmt.Errorf("Unable to determine automember rule ID %s, expected <type>/<name>", id)
+	}
+
+	return idParts[0], idParts[1], nil
+}
+
+// automemberConditionsByKey groups the expressions of a condition set by
+// attribute, FreeIPA takes a single key per add/remove condition call.
+func automemberConditionsByKey(conditions *schema.Set) map[string][]string {
+	res := map[string][]string{}
+	for _, raw := range conditions.List() {
+		c := raw.(map[string]interface{})
+		key := c["key"].(string)
+		res[key] = append(res[key], c["expression"].(string))
+	}
+	return res
+}
+
+// flattenAutomemberConditions parses the `<key>=<expression>` values returned
+// by FreeIPA.
+func flattenAutomemberConditions(regexes []string) []interface{} {
+	res := make([]interface{}, 0, len(regexes))
+	for _, regex := range regexes {
+		key, expression, _ := strings.Cut(regex, "=")
+		res = append(res, map[string]interface{}{
+			"key":        key,
+			"expression": expression,
+		})
+	}
+	return res
+}
+
+func automemberConditionKeys(inclusive, exclusive map[string][]string) []string {
+	var keys []string
+	for k := range inclusive {
+		keys = append(keys, k)
+	}
+	for k := range exclusive {
+		if _, ok := inclusive[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func optionalStrings(v []string) *[]string {
+	if len(v) == 0 {
+		return nil
+	}
+	return &v
+}
+
+func addAutomemberConditions(client *ipa.Client, name, _type string, inclusive, exclusive map[string][]string) error {
+	for _, key := range automemberConditionKeys(inclusive, exclusive) {
+		optArgs := ipa.AutomemberAddConditionOptionalArgs{
+			Automemberinclusiveregex: optionalStrings(inclusive[key]),
+			Automemberexclusiveregex: optionalStrings(exclusive[key]),
+		}
+		_, err := client.AutomemberAddCondition(&ipa.AutomemberAddConditionArgs{Cn: name, Type: _type, Key: key}, &optArgs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeAutomemberConditions(client *ipa.Client, name, _type string, inclusive, exclusive map[string][]string) error {
+	for _, key := range automemberConditionKeys(inclusive, exclusive) {
+		optArgs := ipa.AutomemberRemoveConditionOptionalArgs{
+			Automemberinclusiveregex: optionalStrings(inclusive[key]),
+			Automemberexclusiveregex: optionalStrings(exclusive[key]),
+		}
+		_, err := client.AutomemberRemoveCondition(&ipa.AutomemberRemoveConditionArgs{Cn: name, Type: _type, Key: key}, &optArgs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
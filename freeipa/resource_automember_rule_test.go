@@ -0,0 +1,98 @@
+package freeipa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFreeIPAAutomemberRule_Hostgroup(t *testing.T) {
+	testAutomemberRule := map[string]string{
+		"name":        "hostgroup-testautomemberrule",
+		"description": "automember rule",
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFreeIPAAutomemberRuleResource_basic(testAutomemberRule),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "name", testAutomemberRule["name"]),
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "type", "hostgroup"),
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "inclusive.#", "1"),
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "exclusive.#", "0"),
+				),
+			},
+			{
+				Config: testAccFreeIPAAutomemberRuleResource_full(testAutomemberRule),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "description", testAutomemberRule["description"]),
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "inclusive.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("freeipa_automember_rule.rule", "inclusive.*", map[string]string{
+						"key":        "fqdn",
+						"expression": "^db[0-9]+\\.example\\.test$",
+					}),
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "exclusive.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "freeipa_automember_rule.rule",
+				ImportState:       true,
+				ImportStateId:     "hostgroup/" + testAutomemberRule["name"],
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccFreeIPAAutomemberRuleResource_basic(testAutomemberRule),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "description", ""),
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "inclusive.#", "1"),
+					resource.TestCheckResourceAttr("freeipa_automember_rule.rule", "exclusive.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFreeIPAAutomemberRuleResource_basic(dataset map[string]string) string {
+	return fmt.Sprintf(`
+	resource "freeipa_hostgroup" "hostgroup" {
+		name = "%s"
+	}
+	resource "freeipa_automember_rule" "rule" {
+		name = resource.freeipa_hostgroup.hostgroup.name
+		type = "hostgroup"
+		inclusive {
+			key        = "fqdn"
+			expression = "^web[0-9]+\\.example\\.test$"
+		}
+	}
+	`, dataset["name"])
+}
+
+func testAccFreeIPAAutomemberRuleResource_full(dataset map[string]string) string {
+	return fmt.Sprintf(`
+	resource "freeipa_hostgroup" "hostgroup" {
+		name = "%s"
+	}
+	resource "freeipa_automember_rule" "rule" {
+		name        = resource.freeipa_hostgroup.hostgroup.name
+		type        = "hostgroup"
+		description = "%s"
+		inclusive {
+			key        = "fqdn"
+			expression = "^web[0-9]+\\.example\\.test$"
+		}
+		inclusive {
+			key        = "fqdn"
+			expression = "^db[0-9]+\\.example\\.test$"
+		}
+		exclusive {
+			key        = "fqdn"
+			expression = "^web0\\.example\\.test$"
+		}
+	}
+	`, dataset["name"], dataset["description"])
+}
@@ -2,14 +2,26 @@ package freeipa
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net"
 	"strings"
+	"time"
 
 	ipa "github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/exp/slices"
 )
 
+// dnsQueryTimeout bounds the DNS queries sent to the FreeIPA server.
+const dnsQueryTimeout = 10 * time.Second
+
+// dnsACLKeywords are the predefined BIND address match lists FreeIPA accepts
+// in zone ACLs besides addresses and networks.
+var dnsACLKeywords = []string{"any", "none", "localhost", "localnets"}
+
 func resourceFreeIPADNSZone() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPADNSDNSZoneCreate,
@@ -22,16 +34,17 @@ func resourceFreeIPADNSZone() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"zone_name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Zone name (FQDN)",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressDNSNameTrailingDot,
+				Description:      "Zone name (FQDN)",
 			},
 			"is_reverse_zone": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				Description: "Allow create the reverse zone",
+				Description: "Allow create the reverse zone. The zone name may either be an IP network (`192.168.1.0/24`) or the reverse zone name itself (`1.168.192.in-addr.arpa.`)",
 			},
 			"disable_zone": {
 				Type:        schema.TypeBool,
@@ -46,9 +59,11 @@ func resourceFreeIPADNSZone() *schema.Resource {
 				Description: "Force DNS zone creation even if it will overlap with an existing zone",
 			},
 			"authoritative_nameserver": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Authoritative nameserver domain name",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressDNSNameTrailingDot,
+				Description:      "Authoritative nameserver domain name",
 			},
 			"skip_nameserver_check": {
 				Type:        schema.TypeBool,
@@ -57,9 +72,11 @@ func resourceFreeIPADNSZone() *schema.Resource {
 				Description: "Force DNS zone creation even if nameserver is not resolvable",
 			},
 			"admin_email_address": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Administrator e-mail address",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressDNSAdminEmail,
+				Description:      "Administrator e-mail address",
 			},
 			"soa_serial_number": {
 				Type:        schema.TypeInt,
@@ -109,19 +126,44 @@ func resourceFreeIPADNSZone() *schema.Resource {
 			"bind_update_policy": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				Description: "BIND update policy",
 			},
 			"allow_query": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "any",
-				Description: "Semicolon separated list of IP addresses or networks which are allowed to issue queries",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "any",
+				DiffSuppressFunc: suppressDNSACL,
+				Description:      "Semicolon separated list of IP addresses or networks which are allowed to issue queries",
+			},
+			"allow_query_acl": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateDNSACLElement,
+				},
+				ConflictsWith: []string{"allow_query"},
+				Description:   "IP addresses, networks, `any`, `none`, `localhost` or `localnets`, optionally negated with `!`, which are allowed to issue queries, in BIND order (the first matching element wins). Alternative to allow_query",
 			},
 			"allow_transfer": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "none",
-				Description: "Semicolon separated list of IP addresses or networks which are allowed to transfer the zone",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "none",
+				DiffSuppressFunc: suppressDNSACL,
+				Description:      "Semicolon separated list of IP addresses or networks which are allowed to transfer the zone",
+			},
+			"allow_transfer_acl": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateDNSACLElement,
+				},
+				ConflictsWith: []string{"allow_transfer"},
+				Description:   "IP addresses, networks, `any`, `none`, `localhost` or `localnets`, optionally negated with `!`, which are allowed to transfer the zone, in BIND order (the first matching element wins). Alternative to allow_transfer",
 			},
 			"zone_forwarders": {
 				Type:        schema.TypeList,
@@ -146,6 +188,12 @@ func resourceFreeIPADNSZone() *schema.Resource {
 				Optional:    true,
 				Description: "NSEC3PARAM record for zone in format: hash_algorithm flags iterations salt",
 			},
+			"ds_records": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "DS records of the key signing keys of the zone, to publish in the parent zone. Empty until the keys have been generated after enabling allow_inline_dnssec_signing",
+			},
 		},
 	}
 }
@@ -165,7 +213,7 @@ func resourceFreeIPADNSDNSZoneCreate(ctx context.Context, d *schema.ResourceData
 	// 	args.Idnssoaserial = _v.(int)
 	// }
 
-	if d.Get("is_reverse_zone").(bool) {
+	if d.Get("is_reverse_zone").(bool) && !isReverseDNSZoneName(d.Get("zone_name").(string)) {
 		if _v, ok := d.GetOkExists("zone_name"); ok {
 			v := _v.(string)
 			optArgs.NameFromIP = &v
@@ -233,6 +281,12 @@ func resourceFreeIPADNSDNSZoneCreate(ctx context.Context, d *schema.ResourceData
 		v := _v.(string)
 		optArgs.Idnsallowtransfer = &v
 	}
+	if v, ok := dnsZoneConfiguredACL(d, "allow_query_acl"); ok {
+		optArgs.Idnsallowquery = &v
+	}
+	if v, ok := dnsZoneConfiguredACL(d, "allow_transfer_acl"); ok {
+		optArgs.Idnsallowtransfer = &v
+	}
 	if _v, ok := d.GetOkExists("zone_forwarders"); ok {
 		v := utilsGetArry(_v.([]interface{}))
 		optArgs.Idnsforwarders = &v
@@ -296,7 +350,70 @@ func resourceFreeIPADNSDNSZoneRead(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	d.Set("disable_zone", !*res.Result.Idnszoneactive)
+	zone := res.Result
+
+	if _, ok := d.GetOk("zone_name"); !ok {
+		// Imported zones only know their ID
+		d.Set("zone_name", d.Id())
+		d.Set("is_reverse_zone", isReverseDNSZoneName(d.Id()))
+	}
+
+	if zone.Idnszoneactive != nil {
+		d.Set("disable_zone", !*zone.Idnszoneactive)
+	}
+	if zone.Idnssoamname != nil {
+		d.Set("authoritative_nameserver", utils.DnsNameValue(*zone.Idnssoamname))
+	}
+	d.Set("admin_email_address", utils.DnsNameValue(zone.Idnssoarname))
+	d.Set("soa_refresh", zone.Idnssoarefresh)
+	d.Set("soa_retry", zone.Idnssoaretry)
+	d.Set("soa_expire", zone.Idnssoaexpire)
+	d.Set("soa_minimum", zone.Idnssoaminimum)
+	d.Set("ttl", zone.Dnsttl)
+	d.Set("default_ttl", zone.Dnsdefaultttl)
+	if zone.Idnsallowdynupdate != nil {
+		d.Set("dynamic_updates", *zone.Idnsallowdynupdate)
+	}
+	if zone.Idnsupdatepolicy != nil {
+		d.Set("bind_update_policy", *zone.Idnsupdatepolicy)
+	}
+	if zone.Idnsallowquery != nil {
+		d.Set("allow_query", *zone.Idnsallowquery)
+		d.Set("allow_query_acl", parseDNSACL(*zone.Idnsallowquery))
+	}
+	if zone.Idnsallowtransfer != nil {
+		d.Set("allow_transfer", *zone.Idnsallowtransfer)
+		d.Set("allow_transfer_acl", parseDNSACL(*zone.Idnsallowtransfer))
+	}
+	if zone.Idnsforwarders != nil {
+		d.Set("zone_forwarders", *zone.Idnsforwarders)
+	} else {
+		d.Set("zone_forwarders", nil)
+	}
+	if zone.Idnsallowsyncptr != nil {
+		d.Set("allow_prt_sync", *zone.Idnsallowsyncptr)
+	}
+	if zone.Idnssecinlinesigning != nil {
+		d.Set("allow_inline_dnssec_signing", *zone.Idnssecinlinesigning)
+	}
+	if zone.Nsec3paramrecord != nil {
+		d.Set("nsec3param_record", *zone.Nsec3paramrecord)
+	} else {
+		d.Set("nsec3param_record", "")
+	}
+
+	if zone.Idnssecinlinesigning != nil && *zone.Idnssecinlinesigning {
+		// The keys are generated in the background once signing is enabled,
+		// failed lookups keep the known records rather than clearing them.
+		records, err := dnsZoneDSRecords(ctx, meta.(*Config).Host, d.Id())
+		if err != nil {
+			log.Printf("[WARN] Failed to read the DS records of DNS zone %s: %s", d.Id(), err)
+		} else {
+			d.Set("ds_records", records)
+		}
+	} else {
+		d.Set("ds_records", []string{})
+	}
 
 	log.Printf("[DEBUG] Read freeipa dns zone %s", res.Result.Idnsname)
 	return nil
@@ -428,6 +545,18 @@ func resourceFreeIPADNSDNSZoneUpdate(ctx context.Context, d *schema.ResourceData
 			hasChange = true
 		}
 	}
+	if d.HasChange("allow_query_acl") {
+		if v, ok := dnsZoneConfiguredACL(d, "allow_query_acl"); ok {
+			optArgs.Idnsallowquery = &v
+			hasChange = true
+		}
+	}
+	if d.HasChange("allow_transfer_acl") {
+		if v, ok := dnsZoneConfiguredACL(d, "allow_transfer_acl"); ok {
+			optArgs.Idnsallowtransfer = &v
+			hasChange = true
+		}
+	}
 	if d.HasChange("zone_forwarders") {
 		if _v, ok := d.GetOkExists("zone_forwarders"); ok {
 			v := utilsGetArry(_v.([]interface{}))
@@ -489,9 +618,121 @@ func resourceFreeIPADNSDNSZoneDelete(ctx context.Context, d *schema.ResourceData
 	}
 	_, err = client.DnszoneDel(&ipa.DnszoneDelArgs{}, &optArgs)
 	if err != nil {
-		return diag.Errorf("Error delete freeipa dns zone: %s", err)
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] DNS Zone %s already deleted", d.Id())
+		} else {
+			return diag.Errorf("Error delete freeipa dns zone: %s", err)
+		}
 	}
 
 	d.SetId("")
 	return nil
 }
+
+// dnsZoneDSRecords queries the DS records of the zone from the DNS server of
+// the FreeIPA host.
+func dnsZoneDSRecords(ctx context.Context, host, zone string) ([]string, error) {
+	host, err := utils.NormalizeHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	// The port of the API is not the one of the DNS server
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dnsQueryTimeout)
+	defer cancel()
+
+	return utils.ZoneDSRecords(ctx, strings.Trim(host, "[]"), zone)
+}
+
+func isReverseDNSZoneName(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	return strings.HasSuffix(name, ".in-addr.arpa") || strings.HasSuffix(name, ".ip6.arpa")
+}
+
+func normalizeDNSName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+func suppressDNSNameTrailingDot(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeDNSName(old) == normalizeDNSName(new)
+}
+
+// suppressDNSAdminEmail compares administrator addresses given as e-mail
+// (hostmaster@example.com) with the SOA RNAME form (hostmaster.example.com.)
+// FreeIPA stores.
+func suppressDNSAdminEmail(k, old, new string, d *schema.ResourceData) bool {
+	if new == "" {
+		return false
+	}
+	toRName := func(v string) string {
+		return normalizeDNSName(strings.Replace(v, "@", ".", 1))
+	}
+	return toRName(old) == toRName(new)
+}
+
+// parseDNSACL splits a BIND address match list such as `10.0.0.0/8;!10.1.0.0/16;`
+// into its elements.
+func parseDNSACL(acl string) []string {
+	items := []string{}
+	for _, item := range strings.Split(acl, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// formatDNSACL serializes the elements of a BIND address match list, each
+// terminated by a semicolon.
+func formatDNSACL(items []string) string {
+	var acl strings.Builder
+	for _, item := range items {
+		acl.WriteString(strings.TrimSpace(item) + ";")
+	}
+	return acl.String()
+}
+
+// dnsZoneConfiguredACL returns the serialized ACL of the structured ACL
+// attribute when it is set in the configuration. The attribute is computed,
+// its value read from FreeIPA is not sent back.
+func dnsZoneConfiguredACL(d *schema.ResourceData, key string) (string, bool) {
+	if raw := d.GetRawConfig(); raw.IsNull() || raw.GetAttr(key).IsNull() {
+		return "", false
+	}
+	return formatDNSACL(utilsGetArry(d.Get(key).([]interface{}))), true
+}
+
+func normalizeDNSACL(acl string) string {
+	return strings.Join(parseDNSACL(acl), ";")
+}
+
+// suppressDNSACL compares ACL strings regardless of spacing and of the final
+// semicolon. The string is ignored while the structured ACL replacing it is
+// configured, its default would otherwise reset the ACL.
+func suppressDNSACL(k, old, new string, d *schema.ResourceData) bool {
+	if raw := d.GetRawConfig(); !raw.IsNull() && !raw.GetAttr(k+"_acl").IsNull() {
+		return true
+	}
+	return normalizeDNSACL(old) == normalizeDNSACL(new)
+}
+
+// validateDNSACLElement accepts the elements of the zone ACLs FreeIPA
+// supports: IP addresses, networks and the predefined address match lists,
+// optionally negated.
+func validateDNSACLElement(v interface{}, k string) (ws []string, es []error) {
+	item := strings.TrimPrefix(strings.TrimSpace(v.(string)), "!")
+
+	if slices.Contains(dnsACLKeywords, item) || net.ParseIP(item) != nil {
+		return
+	}
+	if _, _, err := net.ParseCIDR(item); err == nil {
+		return
+	}
+
+	es = append(es, fmt.Errorf("%q must be an IP address, a network or one of %s, optionally prefixed with \"!\", got %q", k, strings.Join(dnsACLKeywords, ", "), v.(string)))
+	return
+}
@@ -72,6 +72,14 @@ func TestAccFreeIPADNSDNSZone(t *testing.T) {
 					resource.TestCheckResourceAttr("freeipa_dns_zone.dnszone", "bind_update_policy", testDnsZone["bind_update_policy"]),
 				),
 			},
+			{
+				Config: testAccFreeIPADNSDNSZoneResource_acl(testDnsZone),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("freeipa_dns_zone.dnszone", "allow_query", "192.168.1.0/24;!192.168.1.128/25;localhost;"),
+					resource.TestCheckResourceAttr("freeipa_dns_zone.dnszone", "allow_transfer_acl.#", "1"),
+					resource.TestCheckResourceAttr("freeipa_dns_zone.dnszone", "allow_transfer_acl.0", "none"),
+				),
+			},
 			{
 				Config: testAccFreeIPADNSDNSZoneReverseResource_basic(testDnsZoneReverse),
 				Check: resource.ComposeTestCheckFunc(
@@ -98,6 +106,16 @@ func testAccFreeIPADNSDNSZoneResource_basic(dataset map[string]string) string {
 	`, dataset["zone_name"])
 }
 
+func testAccFreeIPADNSDNSZoneResource_acl(dataset map[string]string) string {
+	return fmt.Sprintf(`
+	resource "freeipa_dns_zone" "dnszone" {
+		zone_name          = "%s"
+		allow_query_acl    = ["192.168.1.0/24", "!192.168.1.128/25", "localhost"]
+		allow_transfer_acl = ["none"]
+	}
+	`, dataset["zone_name"])
+}
+
 func testAccFreeIPADNSDNSZoneResource_full(dataset map[string]string) string {
 	provider_host := os.Getenv("FREEIPA_HOST")
 	return fmt.Sprintf(`
@@ -2,15 +2,23 @@ package freeipa
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 
 	ipa "github.com/camptocamp/go-freeipa/freeipa"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceFreeIPAHBACPolicy() *schema.Resource {
+	r := resourceFreeIPAHBACRule()
+	r.DeprecationMessage = "freeipa_hbac_policy is deprecated, use freeipa_hbac_rule instead"
+	return r
+}
+
+func resourceFreeIPAHBACRule() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPADNSHBACPolicyCreate,
 		ReadContext:   resourceFreeIPADNSHBACPolicyRead,
@@ -41,22 +49,25 @@ func resourceFreeIPAHBACPolicy() *schema.Resource {
 				Description: "Enable this policy (Defaults to `true`)",
 			},
 			"usercategory": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    false,
-				Description: "User category the policy is applied to (allowed value: `all`)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validation.StringInSlice([]string{"all", ""}, false),
+				Description:  "User category the policy is applied to (allowed value: `all`)",
 			},
 			"hostcategory": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    false,
-				Description: "Host category the policy is applied to (allowed value: `all`)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validation.StringInSlice([]string{"all", ""}, false),
+				Description:  "Host category the policy is applied to (allowed value: `all`)",
 			},
 			"servicecategory": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    false,
-				Description: "Service category the policy is applied to (allowed value: `all`)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validation.StringInSlice([]string{"all", ""}, false),
+				Description:  "Service category the policy is applied to (allowed value: `all`)",
 			},
 		},
 	}
@@ -79,19 +90,15 @@ func resourceFreeIPADNSHBACPolicyCreate(ctx context.Context, d *schema.ResourceD
 		v := _v.(string)
 		optArgs.Description = &v
 	}
-	if _v, ok := d.GetOkExists("enabled"); ok {
-		v := _v.(bool)
-		optArgs.Ipaenabledflag = &v
-	}
-	if _v, ok := d.GetOkExists("usercategory"); ok {
+	if _v, ok := d.GetOk("usercategory"); ok {
 		v := _v.(string)
 		optArgs.Usercategory = &v
 	}
-	if _v, ok := d.GetOkExists("hostcategory"); ok {
+	if _v, ok := d.GetOk("hostcategory"); ok {
 		v := _v.(string)
 		optArgs.Hostcategory = &v
 	}
-	if _v, ok := d.GetOkExists("servicecategory"); ok {
+	if _v, ok := d.GetOk("servicecategory"); ok {
 		v := _v.(string)
 		optArgs.Servicecategory = &v
 	}
@@ -103,6 +110,13 @@ func resourceFreeIPADNSHBACPolicyCreate(ctx context.Context, d *schema.ResourceD
 
 	d.SetId(d.Get("name").(string))
 
+	if !d.Get("enabled").(bool) {
+		_, err = client.HbacruleDisable(&ipa.HbacruleDisableArgs{Cn: d.Id()}, &ipa.HbacruleDisableOptionalArgs{})
+		if err != nil {
+			return diag.Errorf("Error disabling freeipa the HBAC policy: %s", err)
+		}
+	}
+
 	return resourceFreeIPADNSHBACPolicyRead(ctx, d, meta)
 }
 
@@ -134,6 +148,16 @@ func resourceFreeIPADNSHBACPolicyRead(ctx context.Context, d *schema.ResourceDat
 		}
 	}
 
+	rule := res.Result
+
+	d.Set("name", rule.Cn)
+	d.Set("description", stringValue(rule.Description))
+	// A missing flag means the rule is enabled, which is the FreeIPA default
+	d.Set("enabled", rule.Ipaenabledflag == nil || *rule.Ipaenabledflag)
+	d.Set("usercategory", stringValue(rule.Usercategory))
+	d.Set("hostcategory", stringValue(rule.Hostcategory))
+	d.Set("servicecategory", stringValue(rule.Servicecategory))
+
 	log.Printf("[DEBUG] Read freeipa HBAC policy %s", res.Result.Cn)
 	return nil
 }
@@ -154,38 +178,35 @@ func resourceFreeIPADNSHBACPolicyUpdate(ctx context.Context, d *schema.ResourceD
 	var hasChange = false
 
 	if d.HasChange("description") {
-		if _v, ok := d.GetOkExists("description"); ok {
-			v := _v.(string)
-			optArgs.Description = &v
-			hasChange = true
-		}
-	}
-	if d.HasChange("enabled") {
-		if _v, ok := d.GetOkExists("enabled"); ok {
-			v := _v.(bool)
-			optArgs.Ipaenabledflag = &v
-			hasChange = true
-		}
+		v := d.Get("description").(string)
+		optArgs.Description = &v
+		hasChange = true
 	}
-	if d.HasChange("usercategory") {
-		if _v, ok := d.GetOkExists("usercategory"); ok {
-			v := _v.(string)
-			optArgs.Usercategory = &v
-			hasChange = true
-		}
+
+	categories := []struct {
+		key string
+		arg **string
+	}{
+		{"usercategory", &optArgs.Usercategory},
+		{"hostcategory", &optArgs.Hostcategory},
+		{"servicecategory", &optArgs.Servicecategory},
 	}
-	if d.HasChange("hostcategory") {
-		if _v, ok := d.GetOkExists("hostcategory"); ok {
-			v := _v.(string)
-			optArgs.Hostcategory = &v
+
+	var toAll []string
+	for _, c := range categories {
+		if d.HasChange(c.key) {
+			v := d.Get(c.key).(string)
+			*c.arg = &v
 			hasChange = true
+			if v == "all" {
+				toAll = append(toAll, c.key)
+			}
 		}
 	}
-	if d.HasChange("servicecategory") {
-		if _v, ok := d.GetOkExists("servicecategory"); ok {
-			v := _v.(string)
-			optArgs.Servicecategory = &v
-			hasChange = true
+
+	if len(toAll) > 0 {
+		if err := checkHBACRuleCategoryMembers(client, d.Id(), toAll); err != nil {
+			return diag.Errorf("Error update freeipa HBAC policy: %s", err)
 		}
 	}
 
@@ -200,6 +221,17 @@ func resourceFreeIPADNSHBACPolicyUpdate(ctx context.Context, d *schema.ResourceD
 		}
 	}
 
+	if d.HasChange("enabled") {
+		if d.Get("enabled").(bool) {
+			_, err = client.HbacruleEnable(&ipa.HbacruleEnableArgs{Cn: d.Id()}, &ipa.HbacruleEnableOptionalArgs{})
+		} else {
+			_, err = client.HbacruleDisable(&ipa.HbacruleDisableArgs{Cn: d.Id()}, &ipa.HbacruleDisableOptionalArgs{})
+		}
+		if err != nil {
+			return diag.Errorf("Error update freeipa HBAC policy enabled state: %s", err)
+		}
+	}
+
 	d.SetId(d.Get("name").(string))
 
 	return resourceFreeIPADNSHBACPolicyRead(ctx, d, meta)
@@ -218,10 +250,69 @@ func resourceFreeIPADNSHBACPolicyDelete(ctx context.Context, d *schema.ResourceD
 	}
 	_, err = client.HbacruleDel(&args, &ipa.HbacruleDelOptionalArgs{})
 	if err != nil {
-		return diag.Errorf("Error delete freeipa the HBAC policy: %s", err)
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] HBAC policy %s already deleted", d.Id())
+		} else {
+			return diag.Errorf("Error delete freeipa the HBAC policy: %s", err)
+		}
 	}
 
 	d.SetId("")
 
 	return nil
 }
+
+// checkHBACRuleCategoryMembers returns an error when the HBAC rule has
+// explicit members for one of the categories about to be set to "all".
+func checkHBACRuleCategoryMembers(client *ipa.Client, name string, categories []string) error {
+	all := true
+	res, err := client.HbacruleShow(&ipa.HbacruleShowArgs{Cn: name}, &ipa.HbacruleShowOptionalArgs{All: &all})
+	if err != nil {
+		return err
+	}
+	rule := res.Result
+
+	for _, category := range categories {
+		var hasMembers bool
+		switch category {
+		case "usercategory":
+			hasMembers = rule.MemberuserUser != nil || rule.MemberuserGroup != nil
+		case "hostcategory":
+			hasMembers = rule.MemberhostHost != nil || rule.MemberhostHostgroup != nil
+		case "servicecategory":
+			hasMembers = rule.MemberserviceHbacsvc != nil || rule.MemberserviceHbacsvcgroup != nil
+		}
+		if hasMembers {
+			return fmt.Errorf("HBAC rule %s has explicit members, remove their membership resources before setting %s to \"all\"", name, category)
+		}
+	}
+
+	return nil
+}
+
+// checkHBACRuleCategory returns an error when the category attribute of the
+// HBAC rule is set to "all", in which case explicit members are rejected.
+func checkHBACRuleCategory(client *ipa.Client, name string, category string) error {
+	all := true
+	res, err := client.HbacruleShow(&ipa.HbacruleShowArgs{Cn: name}, &ipa.HbacruleShowOptionalArgs{All: &all})
+	if err != nil {
+		return err
+	}
+	rule := res.Result
+
+	var value *string
+	switch category {
+	case "usercategory":
+		value = rule.Usercategory
+	case "hostcategory":
+		value = rule.Hostcategory
+	case "servicecategory":
+		value = rule.Servicecategory
+	}
+
+	if value != nil && *value == "all" {
+		return fmt.Errorf("HBAC rule %s has %s set to \"all\", explicit members cannot be added", name, category)
+	}
+
+	return nil
+}
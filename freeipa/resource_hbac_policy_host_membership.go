@@ -12,14 +12,23 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var hbacRuleHostMembershipTypes = map[string]string{
+	"h":  "host",
+	"hg": "hostgroup",
+}
+
 func resourceFreeIPAHBACPolicyHostMembership() *schema.Resource {
+	r := resourceFreeIPAHBACRuleHostMembership()
+	r.DeprecationMessage = "freeipa_hbac_policy_host_membership is deprecated, use freeipa_hbac_rule_host_membership instead"
+	return r
+}
+
+func resourceFreeIPAHBACRuleHostMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPADNSHBACPolicyHostMembershipCreate,
 		ReadContext:   resourceFreeIPADNSHBACPolicyHostMembershipRead,
 		DeleteContext: resourceFreeIPADNSHBACPolicyHostMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:      membershipImporter(hbacRuleHostMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -72,10 +81,18 @@ func resourceFreeIPADNSHBACPolicyHostMembershipCreate(ctx context.Context, d *sc
 		hostmember_id = "hg"
 	}
 
-	_, err = client.HbacruleAddHost(&args, &optArgs)
+	if err := checkHBACRuleCategory(client, args.Cn, "hostcategory"); err != nil {
+		return diag.Errorf("Error creating freeipa the HBAC policy host membership: %s", err)
+	}
+
+	res, err := client.HbacruleAddHost(&args, &optArgs)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa the HBAC policy host membership: %s", err)
 	}
+	// Already present members are reported as failures, keep creation idempotent
+	if err := membershipFailuresError(res.Failed, ipa.FailedReasonAlreadyAMember); err != nil {
+		return diag.Errorf("Error creating freeipa the HBAC policy host membership: %s", err)
+	}
 	switch hostmember_id {
 	case "hg":
 		id := fmt.Sprintf("%s/hg/%s", d.Get("name").(string), d.Get("hostgroup").(string))
@@ -92,6 +109,10 @@ func resourceFreeIPADNSHBACPolicyHostMembershipRead(ctx context.Context, d *sche
 	log.Printf("[DEBUG] Read freeipa the HBAC policy host membership")
 
 	name, typeId, hostId, err := parseHBACPolicyHostMembershipID(d.Id())
+	if err != nil {
+		return diag.Errorf("Error parsing ID of freeipa_hbac_rule_host_membership: %s", err)
+	}
+
 	all := true
 	client, err := meta.(*Config).Client()
 	if err != nil {
@@ -136,6 +157,14 @@ func resourceFreeIPADNSHBACPolicyHostMembershipRead(ctx context.Context, d *sche
 		}
 	}
 
+	d.Set("name", name)
+	switch typeId {
+	case "h":
+		d.Set("host", hostId)
+	case "hg":
+		d.Set("hostgroup", hostId)
+	}
+
 	return nil
 }
 
@@ -143,6 +172,9 @@ func resourceFreeIPADNSHBACPolicyHostMembershipDelete(ctx context.Context, d *sc
 	log.Printf("[DEBUG] Delete freeipa the HBAC policy host membership")
 
 	name, typeId, hostId, err := parseHBACPolicyHostMembershipID(d.Id())
+	if err != nil {
+		return diag.Errorf("Error parsing ID of freeipa_hbac_rule_host_membership: %s", err)
+	}
 
 	client, err := meta.(*Config).Client()
 	if err != nil {
@@ -163,8 +195,14 @@ func resourceFreeIPADNSHBACPolicyHostMembershipDelete(ctx context.Context, d *sc
 		optArgs.Hostgroup = &v
 	}
 
-	_, err = client.HbacruleRemoveHost(&args, &optArgs)
+	res, err := client.HbacruleRemoveHost(&args, &optArgs)
 	if err != nil {
+		if !strings.Contains(err.Error(), "NotFound") {
+			return diag.Errorf("Error delete freeipa the HBAC policy host membership: %s", err)
+		}
+		log.Printf("[DEBUG] HBAC policy %s not found", name)
+	} else if err := membershipFailuresError(res.Failed, failedReasonNotAMember, ipa.FailedReasonNoSuchEntry); err != nil {
+		// Members removed outside of Terraform are reported as failures
 		return diag.Errorf("Error delete freeipa the HBAC policy host membership: %s", err)
 	}
 
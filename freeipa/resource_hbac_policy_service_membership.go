@@ -12,14 +12,23 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var hbacRuleServiceMembershipTypes = map[string]string{
+	"s":  "service",
+	"sg": "servicegroup",
+}
+
 func resourceFreeIPAHBACPolicyServiceMembership() *schema.Resource {
+	r := resourceFreeIPAHBACRuleServiceMembership()
+	r.DeprecationMessage = "freeipa_hbac_policy_service_membership is deprecated, use freeipa_hbac_rule_service_membership instead"
+	return r
+}
+
+func resourceFreeIPAHBACRuleServiceMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPADNSHBACPolicyServiceMembershipCreate,
 		ReadContext:   resourceFreeIPADNSHBACPolicyServiceMembershipRead,
 		DeleteContext: resourceFreeIPADNSHBACPolicyServiceMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:      membershipImporter(hbacRuleServiceMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -72,10 +81,18 @@ func resourceFreeIPADNSHBACPolicyServiceMembershipCreate(ctx context.Context, d
 		svcmember_id = "sg"
 	}
 
-	_, err = client.HbacruleAddService(&args, &optArgs)
+	if err := checkHBACRuleCategory(client, args.Cn, "servicecategory"); err != nil {
+		return diag.Errorf("Error creating freeipa the HBAC policy service membership: %s", err)
+	}
+
+	res, err := client.HbacruleAddService(&args, &optArgs)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa the HBAC policy service membership: %s", err)
 	}
+	// Already present members are reported as failures, keep creation idempotent
+	if err := membershipFailuresError(res.Failed, ipa.FailedReasonAlreadyAMember); err != nil {
+		return diag.Errorf("Error creating freeipa the HBAC policy service membership: %s", err)
+	}
 	switch svcmember_id {
 	case "sg":
 		id := fmt.Sprintf("%s/sg/%s", d.Get("name").(string), d.Get("servicegroup").(string))
@@ -93,6 +110,9 @@ func resourceFreeIPADNSHBACPolicyServiceMembershipRead(ctx context.Context, d *s
 
 	all := true
 	name, typeId, svcId, err := parseHBACPolicyServiceMembershipID(d.Id())
+	if err != nil {
+		return diag.Errorf("Error parsing ID of freeipa_hbac_rule_service_membership: %s", err)
+	}
 
 	client, err := meta.(*Config).Client()
 	if err != nil {
@@ -137,6 +157,14 @@ func resourceFreeIPADNSHBACPolicyServiceMembershipRead(ctx context.Context, d *s
 		}
 	}
 
+	d.Set("name", name)
+	switch typeId {
+	case "s":
+		d.Set("service", svcId)
+	case "sg":
+		d.Set("servicegroup", svcId)
+	}
+
 	return nil
 }
 
@@ -144,6 +172,9 @@ func resourceFreeIPADNSHBACPolicyServiceMembershipDelete(ctx context.Context, d
 	log.Printf("[DEBUG] Delete freeipa the HBAC policy service membership")
 
 	name, typeId, svcId, err := parseHBACPolicyServiceMembershipID(d.Id())
+	if err != nil {
+		return diag.Errorf("Error parsing ID of freeipa_hbac_rule_service_membership: %s", err)
+	}
 
 	client, err := meta.(*Config).Client()
 	if err != nil {
@@ -164,8 +195,14 @@ func resourceFreeIPADNSHBACPolicyServiceMembershipDelete(ctx context.Context, d
 		optArgs.Hbacsvcgroup = &v
 	}
 
-	_, err = client.HbacruleRemoveService(&args, &optArgs)
+	res, err := client.HbacruleRemoveService(&args, &optArgs)
 	if err != nil {
+		if !strings.Contains(err.Error(), "NotFound") {
+			return diag.Errorf("Error delete freeipa the HBAC policy service membership: %s", err)
+		}
+		log.Printf("[DEBUG] HBAC policy %s not found", name)
+	} else if err := membershipFailuresError(res.Failed, failedReasonNotAMember, ipa.FailedReasonNoSuchEntry); err != nil {
+		// Members removed outside of Terraform are reported as failures
 		return diag.Errorf("Error delete freeipa the HBAC policy service membership: %s", err)
 	}
 
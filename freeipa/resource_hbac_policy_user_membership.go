@@ -12,14 +12,23 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var hbacRuleUserMembershipTypes = map[string]string{
+	"u": "user",
+	"g": "group",
+}
+
 func resourceFreeIPAHBACPolicyUserMembership() *schema.Resource {
+	r := resourceFreeIPAHBACRuleUserMembership()
+	r.DeprecationMessage = "freeipa_hbac_policy_user_membership is deprecated, use freeipa_hbac_rule_user_membership instead"
+	return r
+}
+
+func resourceFreeIPAHBACRuleUserMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPADNSHBACPolicyUserMembershipCreate,
 		ReadContext:   resourceFreeIPADNSHBACPolicyUserMembershipRead,
 		DeleteContext: resourceFreeIPADNSHBACPolicyUserMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:      membershipImporter(hbacRuleUserMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -72,10 +81,18 @@ func resourceFreeIPADNSHBACPolicyUserMembershipCreate(ctx context.Context, d *sc
 		user_id = "g"
 	}
 
-	_, err = client.HbacruleAddUser(&args, &optArgs)
+	if err := checkHBACRuleCategory(client, args.Cn, "usercategory"); err != nil {
+		return diag.Errorf("Error creating freeipa the HBAC policy user membership: %s", err)
+	}
+
+	res, err := client.HbacruleAddUser(&args, &optArgs)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa the HBAC policy user membership: %s", err)
 	}
+	// Already present members are reported as failures, keep creation idempotent
+	if err := membershipFailuresError(res.Failed, ipa.FailedReasonAlreadyAMember); err != nil {
+		return diag.Errorf("Error creating freeipa the HBAC policy user membership: %s", err)
+	}
 	switch user_id {
 	case "g":
 		id := fmt.Sprintf("%s/g/%s", d.Get("name").(string), d.Get("group").(string))
@@ -92,6 +109,10 @@ func resourceFreeIPADNSHBACPolicyUserMembershipRead(ctx context.Context, d *sche
 	log.Printf("[DEBUG] Read freeipa the HBAC policy user membership")
 
 	name, typeId, userId, err := parseHBACPolicyUserMembershipID(d.Id())
+	if err != nil {
+		return diag.Errorf("Error parsing ID of freeipa_hbac_rule_user_membership: %s", err)
+	}
+
 	all := true
 	client, err := meta.(*Config).Client()
 	if err != nil {
@@ -136,6 +157,14 @@ func resourceFreeIPADNSHBACPolicyUserMembershipRead(ctx context.Context, d *sche
 		}
 	}
 
+	d.Set("name", name)
+	switch typeId {
+	case "u":
+		d.Set("user", userId)
+	case "g":
+		d.Set("group", userId)
+	}
+
 	return nil
 }
 
@@ -143,6 +172,9 @@ func resourceFreeIPADNSHBACPolicyUserMembershipDelete(ctx context.Context, d *sc
 	log.Printf("[DEBUG] Delete freeipa the HBAC policy user membership")
 
 	name, typeId, userId, err := parseHBACPolicyUserMembershipID(d.Id())
+	if err != nil {
+		return diag.Errorf("Error parsing ID of freeipa_hbac_rule_user_membership: %s", err)
+	}
 
 	client, err := meta.(*Config).Client()
 	if err != nil {
@@ -163,8 +195,14 @@ func resourceFreeIPADNSHBACPolicyUserMembershipDelete(ctx context.Context, d *sc
 		optArgs.Group = &v
 	}
 
-	_, err = client.HbacruleRemoveUser(&args, &optArgs)
+	res, err := client.HbacruleRemoveUser(&args, &optArgs)
 	if err != nil {
+		if !strings.Contains(err.Error(), "NotFound") {
+			return diag.Errorf("Error delete freeipa the HBAC policy user membership: %s", err)
+		}
+		log.Printf("[DEBUG] HBAC policy %s not found", name)
+	} else if err := membershipFailuresError(res.Failed, failedReasonNotAMember, ipa.FailedReasonNoSuchEntry); err != nil {
+		// Members removed outside of Terraform are reported as failures
 		return diag.Errorf("Error delete freeipa the HBAC policy user membership: %s", err)
 	}
 
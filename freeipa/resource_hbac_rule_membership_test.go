@@ -0,0 +1,74 @@
+package freeipa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFreeIPAHBACRuleMemberships(t *testing.T) {
+	testHbacMembers := map[string]string{
+		"name":      "hbac_rule_members_test",
+		"hostgroup": "test-hbac-rule-hostgroup",
+		"group":     "admins",
+		"service":   "sshd",
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFreeIPAHBACRuleMemberships(testHbacMembers),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("freeipa_hbac_rule_host_membership.hostgroup", "hostgroup", testHbacMembers["hostgroup"]),
+					resource.TestCheckResourceAttr("freeipa_hbac_rule_user_membership.group", "group", testHbacMembers["group"]),
+					resource.TestCheckResourceAttr("freeipa_hbac_rule_service_membership.service", "service", testHbacMembers["service"]),
+				),
+			},
+			{
+				ResourceName:      "freeipa_hbac_rule_host_membership.hostgroup",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "freeipa_hbac_rule_user_membership.group",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "freeipa_hbac_rule_service_membership.service",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccFreeIPAHBACRuleMemberships(dataset map[string]string) string {
+	return fmt.Sprintf(`
+	resource "freeipa_hbac_rule" "hbac_rule" {
+		name = "%s"
+	}
+
+	resource "freeipa_hostgroup" "hostgroup" {
+		name = "%s"
+	}
+
+	resource "freeipa_hbac_rule_host_membership" "hostgroup" {
+		name      = freeipa_hbac_rule.hbac_rule.name
+		hostgroup = freeipa_hostgroup.hostgroup.name
+	}
+
+	resource "freeipa_hbac_rule_user_membership" "group" {
+		name  = freeipa_hbac_rule.hbac_rule.name
+		group = "%s"
+	}
+
+	resource "freeipa_hbac_rule_service_membership" "service" {
+		name    = freeipa_hbac_rule.hbac_rule.name
+		service = "%s"
+	}
+	`, dataset["name"], dataset["hostgroup"], dataset["group"], dataset["service"])
+}
@@ -0,0 +1,67 @@
+package freeipa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFreeIPAHBACRule(t *testing.T) {
+	testHbac := map[string]string{
+		"name":            "hbac_rule_test",
+		"description":     "Automatic test HBAC rule",
+		"enabled":         "false",
+		"usercategory":    "all",
+		"servicecategory": "all",
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFreeIPAHBACRuleResource_basic(testHbac),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("freeipa_hbac_rule.hbac_rule", "name", testHbac["name"]),
+					resource.TestCheckResourceAttr("freeipa_hbac_rule.hbac_rule", "enabled", "true"),
+				),
+			},
+			{
+				Config: testAccFreeIPAHBACRuleResource_full(testHbac),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("freeipa_hbac_rule.hbac_rule", "name", testHbac["name"]),
+					resource.TestCheckResourceAttr("freeipa_hbac_rule.hbac_rule", "description", testHbac["description"]),
+					resource.TestCheckResourceAttr("freeipa_hbac_rule.hbac_rule", "enabled", testHbac["enabled"]),
+					resource.TestCheckResourceAttr("freeipa_hbac_rule.hbac_rule", "usercategory", testHbac["usercategory"]),
+					resource.TestCheckResourceAttr("freeipa_hbac_rule.hbac_rule", "servicecategory", testHbac["servicecategory"]),
+				),
+			},
+			{
+				ResourceName:      "freeipa_hbac_rule.hbac_rule",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccFreeIPAHBACRuleResource_basic(dataset map[string]string) string {
+	return fmt.Sprintf(`
+	resource "freeipa_hbac_rule" "hbac_rule" {
+		name = "%s"
+	}
+	`, dataset["name"])
+}
+
+func testAccFreeIPAHBACRuleResource_full(dataset map[string]string) string {
+	return fmt.Sprintf(`
+	resource "freeipa_hbac_rule" "hbac_rule" {
+		name            = "%s"
+		description     = "%s"
+		enabled         = %s
+		usercategory    = "%s"
+		servicecategory = "%s"
+	}
+	`, dataset["name"], dataset["description"], dataset["enabled"], dataset["usercategory"], dataset["servicecategory"])
+}
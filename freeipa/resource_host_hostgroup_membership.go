@@ -5,19 +5,31 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	ipa "github.com/camptocamp/go-freeipa/freeipa"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+var hostHostgroupMembershipTypes = map[string]string{
+	"h":  "host",
+	"hg": "hostgroup",
+}
+
 func resourceFreeIPAHostHostGroupMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPAHostHostGroupMembershipCreate,
 		ReadContext:   resourceFreeIPAHostHostGroupMembershipRead,
 		DeleteContext: resourceFreeIPAHostHostGroupMembershipDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceFreeIPAHostHostGroupMembershipImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -48,7 +60,7 @@ func resourceFreeIPAHostHostGroupMembership() *schema.Resource {
 func resourceFreeIPAHostHostGroupMembershipCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Creating freeipa the host group membership")
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
@@ -71,11 +83,17 @@ func resourceFreeIPAHostHostGroupMembershipCreate(ctx context.Context, d *schema
 		host_id = "hg"
 	}
 
-	_, err = client.HostgroupAddMember(&args, &optArgs)
+	res, err := client.HostgroupAddMember(&args, &optArgs)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa the host group membership: %s", err)
 	}
 
+	// Members which already belong to the host group are reported as failures
+	// by FreeIPA, treat them as success to keep the creation idempotent.
+	if err := membershipFailuresError(res.Failed, ipa.FailedReasonAlreadyAMember); err != nil {
+		return diag.Errorf("Error creating freeipa the host group membership: %s", err)
+	}
+
 	switch host_id {
 	case "hg":
 		id := fmt.Sprintf("%s/hg/%s", d.Get("name").(string), d.Get("hostgroup").(string))
@@ -97,7 +115,7 @@ func resourceFreeIPAHostHostGroupMembershipRead(ctx context.Context, d *schema.R
 		return diag.Errorf("Error parsing ID of freeipa_host_group_membership: %s", err)
 	}
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
@@ -125,15 +143,18 @@ func resourceFreeIPAHostHostGroupMembershipRead(ctx context.Context, d *schema.R
 		d.Set("host", "")
 		d.Set("hostgroup", "")
 		d.SetId("")
+		return nil
 	}
 
+	setMembershipAttributes(d, name, typeId, hostId, hostHostgroupMembershipTypes)
+
 	return nil
 }
 
 func resourceFreeIPAHostHostGroupMembershipDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Delete freeipa the host group membership")
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
@@ -159,7 +180,7 @@ func resourceFreeIPAHostHostGroupMembershipDelete(ctx context.Context, d *schema
 		optArgs.Host = &v
 	}
 
-	_, err = client.HostgroupRemoveMember(&args, &optArgs)
+	res, err := client.HostgroupRemoveMember(&args, &optArgs)
 	if err != nil {
 		if strings.Contains(err.Error(), "NotFound") {
 			d.SetId("")
@@ -169,11 +190,31 @@ func resourceFreeIPAHostHostGroupMembershipDelete(ctx context.Context, d *schema
 			return diag.Errorf("Error delete freeipa the host group membership: %s", err)
 		}
 	}
+
+	// Members removed out-of-band are reported as failures, ignore them.
+	if err := membershipFailuresError(res.Failed, failedReasonNotAMember, ipa.FailedReasonNoSuchEntry); err != nil {
+		return diag.Errorf("Error delete freeipa the host group membership: %s", err)
+	}
+
 	d.SetId("")
 
 	return nil
 }
 
+// resourceFreeIPAHostHostGroupMembershipImport accepts the `<hostgroup>/<fqdn>`
+// shorthand for host members besides the `<hostgroup>/<type>/<member>` ID.
+func resourceFreeIPAHostHostGroupMembershipImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if idParts := strings.Split(d.Id(), "/"); len(idParts) == 2 && idParts[0] != "" && idParts[1] != "" {
+		d.SetId(fmt.Sprintf("%s/h/%s", idParts[0], idParts[1]))
+	}
+
+	if _, _, _, err := parseMembershipID(d.Id(), hostHostgroupMembershipTypes); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func parseHostMembershipID(id string) (string, string, string, error) {
 	idParts := strings.Split(id, "/")
 	if len(idParts) < 3 {
@@ -26,6 +26,12 @@ func TestAccFreeIPADNSHostGroupMembership(t *testing.T) {
 					resource.TestCheckResourceAttr("freeipa_host_hostgroup_membership.groupmembership", "hostgroup", testDatasetHostgroup2["name"]),
 				),
 			},
+			{
+				ResourceName:      "freeipa_host_hostgroup_membership.groupmembership",
+				ImportState:       true,
+				ImportStateId:     testDatasetHostgroup["name"] + "/hg/" + testDatasetHostgroup2["name"],
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
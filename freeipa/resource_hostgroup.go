@@ -4,8 +4,10 @@ import (
 	"context"
 	"log"
 	"strings"
+	"time"
 
 	ipa "github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -20,6 +22,13 @@ func resourceFreeIPAHostGroup() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -40,7 +49,7 @@ func resourceFreeIPAHostGroup() *schema.Resource {
 func resourceFreeIPADNSHostGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Creating freeipa hostgroup")
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
@@ -68,21 +77,28 @@ func resourceFreeIPADNSHostGroupCreate(ctx context.Context, d *schema.ResourceDa
 func resourceFreeIPADNSHostGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Read freeipa hostgroup")
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
 
 	all := true
+	noMembers := true
 	args := ipa.HostgroupShowArgs{
-		Cn: d.Get("name").(string),
+		Cn: d.Id(),
 	}
+	// Members are managed by freeipa_host_hostgroup_membership, do not process them
 	optArgs := ipa.HostgroupShowOptionalArgs{
-		All: &all,
+		All:       &all,
+		NoMembers: &noMembers,
 	}
 
 	res, err := client.HostgroupShow(&args, &optArgs)
 	if err != nil {
+		if utils.IsMembermanagerGroupDecodeError(err) {
+			log.Printf("[WARN] Ignoring go-freeipa MembermanagerGroup decode error on HostgroupShow: %v", err)
+			return nil
+		}
 		if strings.Contains(err.Error(), "NotFound") {
 			d.SetId("")
 			log.Printf("[DEBUG] Hostgroup not found")
@@ -92,6 +108,13 @@ func resourceFreeIPADNSHostGroupRead(ctx context.Context, d *schema.ResourceData
 		}
 	}
 
+	d.Set("name", res.Result.Cn)
+	if res.Result.Description != nil {
+		d.Set("description", *res.Result.Description)
+	} else {
+		d.Set("description", "")
+	}
+
 	log.Printf("[DEBUG] Read freeipa hostgroup %s", res.Result.Cn)
 
 	return nil
@@ -100,24 +123,20 @@ func resourceFreeIPADNSHostGroupRead(ctx context.Context, d *schema.ResourceData
 func resourceFreeIPADNSHostGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Update freeipa hostgroup")
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
 	var hasChange = false
 	args := ipa.HostgroupModArgs{
-		Cn: d.Get("name").(string),
+		Cn: d.Id(),
 	}
 	optArgs := ipa.HostgroupModOptionalArgs{}
 
 	if d.HasChange("description") {
-		if _v, ok := d.GetOkExists("description"); ok {
-			v := _v.(string)
-			if v != "" {
-				optArgs.Description = &v
-				hasChange = true
-			}
-		}
+		v := d.Get("description").(string)
+		optArgs.Description = &v
+		hasChange = true
 	}
 	if hasChange {
 		_, err = client.HostgroupMod(&args, &optArgs)
@@ -136,18 +155,22 @@ func resourceFreeIPADNSHostGroupUpdate(ctx context.Context, d *schema.ResourceDa
 func resourceFreeIPADNSHostGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Delete freeipa hostgroup")
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
 	args := ipa.HostgroupDelArgs{
-		Cn: []string{d.Get("name").(string)},
+		Cn: []string{d.Id()},
 	}
 	optArgs := ipa.HostgroupDelOptionalArgs{}
 
 	_, err = client.HostgroupDel(&args, &optArgs)
 	if err != nil {
-		return diag.Errorf("Error delete freeipa host: %s", err)
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Hostgroup %s already deleted", d.Id())
+		} else {
+			return diag.Errorf("Error delete freeipa hostgroup: %s", err)
+		}
 	}
 
 	d.SetId("")
@@ -2,10 +2,12 @@ package freeipa
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 
 	ipa "github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -91,6 +93,9 @@ func resourceFreeIPASudocmdRead(ctx context.Context, d *schema.ResourceData, met
 		}
 	}
 
+	d.Set("name", res.Result.Sudocmd)
+	d.Set("description", stringValue(res.Result.Description))
+
 	log.Printf("[DEBUG] Read freeipa sudo command %s", res.Result.Sudocmd)
 	return nil
 }
@@ -111,11 +116,9 @@ func resourceFreeIPASudocmdUpdate(ctx context.Context, d *schema.ResourceData, m
 	var hasChange = false
 
 	if d.HasChange("description") {
-		if _v, ok := d.GetOkExists("description"); ok {
-			v := _v.(string)
-			optArgs.Description = &v
-			hasChange = true
-		}
+		v := d.Get("description").(string)
+		optArgs.Description = &v
+		hasChange = true
 	}
 
 	// TODO: Change No-Posix, Posix, External
@@ -148,7 +151,18 @@ func resourceFreeIPASudocmdDelete(ctx context.Context, d *schema.ResourceData, m
 	}
 	_, err = client.SudocmdDel(&args, &ipa.SudocmdDelOptionalArgs{})
 	if err != nil {
-		return diag.Errorf("Error delete freeipa sudo command: %s", err)
+		if utils.IsDependentEntryError(err) {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "Sudo command is still used by a sudo rule",
+				Detail:   fmt.Sprintf("%s. Remove the command from the sudo rules referencing it before deleting it.", err),
+			}}
+		}
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Sudo command %s already deleted", d.Id())
+		} else {
+			return diag.Errorf("Error delete freeipa sudo command: %s", err)
+		}
 	}
 
 	d.SetId("")
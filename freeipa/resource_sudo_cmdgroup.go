@@ -91,6 +91,9 @@ func resourceFreeIPASudocmdgroupRead(ctx context.Context, d *schema.ResourceData
 		}
 	}
 
+	d.Set("name", res.Result.Cn)
+	d.Set("description", stringValue(res.Result.Description))
+
 	log.Printf("[DEBUG] Read freeipa sudo command group %s", res.Result.Cn)
 	return nil
 }
@@ -111,11 +114,9 @@ func resourceFreeIPASudocmdgroupUpdate(ctx context.Context, d *schema.ResourceDa
 	var hasChange = false
 
 	if d.HasChange("description") {
-		if _v, ok := d.GetOkExists("description"); ok {
-			v := _v.(string)
-			optArgs.Description = &v
-			hasChange = true
-		}
+		v := d.Get("description").(string)
+		optArgs.Description = &v
+		hasChange = true
 	}
 
 	// TODO: Change No-Posix, Posix, External
@@ -148,7 +149,11 @@ func resourceFreeIPASudocmdgroupDelete(ctx context.Context, d *schema.ResourceDa
 	}
 	_, err = client.SudocmdgroupDel(&args, &ipa.SudocmdgroupDelOptionalArgs{})
 	if err != nil {
-		return diag.Errorf("Error delete freeipa sudo command group: %s", err)
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Sudo command group %s already deleted", d.Id())
+		} else {
+			return diag.Errorf("Error delete freeipa sudo command group: %s", err)
+		}
 	}
 
 	d.SetId("")
@@ -12,14 +12,16 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var sudocmdgroupMembershipTypes = map[string]string{
+	"sc": "sudocmd",
+}
+
 func resourceFreeIPASudocmdgroupMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPASudocmdgroupMembershipCreate,
 		ReadContext:   resourceFreeIPASudocmdgroupMembershipRead,
 		DeleteContext: resourceFreeIPASudocmdgroupMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:      membershipImporter(sudocmdgroupMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -116,6 +118,8 @@ func resourceFreeIPASudocmdgroupMembershipRead(ctx context.Context, d *schema.Re
 		return diag.Errorf("Error show freeipa sudo command group membership: %s", err)
 	}
 
+	setMembershipAttributes(d, cmdgrpId, typeId, cmdId, sudocmdgroupMembershipTypes)
+
 	log.Printf("[DEBUG] Read freeipa sudo command group membership %s", res.Result.Cn)
 	return nil
 }
@@ -2,12 +2,15 @@ package freeipa
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 
 	ipa "github.com/camptocamp/go-freeipa/freeipa"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/exp/slices"
 )
 
 func resourceFreeIPASudoRule() *schema.Resource {
@@ -40,34 +43,39 @@ func resourceFreeIPASudoRule() *schema.Resource {
 				Description: "Enable this sudo rule",
 			},
 			"usercategory": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    false,
-				Description: "User category the sudo rule is applied to (allowed value: all)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validation.StringInSlice([]string{"all", ""}, false),
+				Description:  "User category the sudo rule is applied to (allowed value: all)",
 			},
 			"hostcategory": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    false,
-				Description: "Host category the sudo rule is applied to (allowed value: all)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validation.StringInSlice([]string{"all", ""}, false),
+				Description:  "Host category the sudo rule is applied to (allowed value: all)",
 			},
 			"commandcategory": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    false,
-				Description: "Command category the sudo rule is applied to (allowed value: all)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validation.StringInSlice([]string{"all", ""}, false),
+				Description:  "Command category the sudo rule is applied to (allowed value: all)",
 			},
 			"runasusercategory": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    false,
-				Description: "Run as user category the sudo rule is applied to (allowed value: all)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validation.StringInSlice([]string{"all", ""}, false),
+				Description:  "Run as user category the sudo rule is applied to (allowed value: all)",
 			},
 			"runasgroupcategory": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    false,
-				Description: "Run as group category the sudo rule is applied to (allowed value: all)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validation.StringInSlice([]string{"all", ""}, false),
+				Description:  "Run as group category the sudo rule is applied to (allowed value: all)",
 			},
 			"order": {
 				Type:        schema.TypeInt,
@@ -75,6 +83,13 @@ func resourceFreeIPASudoRule() *schema.Resource {
 				ForceNew:    false,
 				Description: "Sudo rule order (must be unique)",
 			},
+			"options": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Sudo options of the sudo rule, such as `!authenticate` or `env_keep+=SSH_AUTH_SOCK`. The options of the rule are left untouched when unset",
+			},
 		},
 	}
 }
@@ -96,27 +111,23 @@ func resourceFreeIPASudoRuleCreate(ctx context.Context, d *schema.ResourceData,
 		v := _v.(string)
 		optArgs.Description = &v
 	}
-	if _v, ok := d.GetOkExists("enabled"); ok {
-		v := _v.(bool)
-		optArgs.Ipaenabledflag = &v
-	}
-	if _v, ok := d.GetOkExists("usercategory"); ok {
+	if _v, ok := d.GetOk("usercategory"); ok {
 		v := _v.(string)
 		optArgs.Usercategory = &v
 	}
-	if _v, ok := d.GetOkExists("hostcategory"); ok {
+	if _v, ok := d.GetOk("hostcategory"); ok {
 		v := _v.(string)
 		optArgs.Hostcategory = &v
 	}
-	if _v, ok := d.GetOkExists("runasusercategory"); ok {
+	if _v, ok := d.GetOk("runasusercategory"); ok {
 		v := _v.(string)
 		optArgs.Ipasudorunasusercategory = &v
 	}
-	if _v, ok := d.GetOkExists("commandcategory"); ok {
+	if _v, ok := d.GetOk("commandcategory"); ok {
 		v := _v.(string)
 		optArgs.Cmdcategory = &v
 	}
-	if _v, ok := d.GetOkExists("runasgroupcategory"); ok {
+	if _v, ok := d.GetOk("runasgroupcategory"); ok {
 		v := _v.(string)
 		optArgs.Ipasudorunasgroupcategory = &v
 	}
@@ -131,6 +142,19 @@ func resourceFreeIPASudoRuleCreate(ctx context.Context, d *schema.ResourceData,
 
 	d.SetId(d.Get("name").(string))
 
+	if !d.Get("enabled").(bool) {
+		_, err = client.SudoruleDisable(&ipa.SudoruleDisableArgs{Cn: d.Id()}, &ipa.SudoruleDisableOptionalArgs{})
+		if err != nil {
+			return diag.Errorf("Error disabling freeipa sudo rule: %s", err)
+		}
+	}
+
+	if _v, ok := d.GetOk("options"); ok {
+		if err := updateSudoRuleOptions(client, d.Id(), nil, utilsGetArry(_v.(*schema.Set).List())); err != nil {
+			return diag.Errorf("Error creating freeipa sudo rule options: %s", err)
+		}
+	}
+
 	return resourceFreeIPASudoRuleRead(ctx, d, meta)
 }
 
@@ -162,6 +186,36 @@ func resourceFreeIPASudoRuleRead(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
+	rule := res.Result
+
+	d.Set("name", rule.Cn)
+	d.Set("description", stringValue(rule.Description))
+	if rule.Ipaenabledflag != nil {
+		d.Set("enabled", *rule.Ipaenabledflag)
+	}
+	d.Set("usercategory", stringValue(rule.Usercategory))
+	d.Set("hostcategory", stringValue(rule.Hostcategory))
+	d.Set("commandcategory", stringValue(rule.Cmdcategory))
+	d.Set("runasusercategory", stringValue(rule.Ipasudorunasusercategory))
+	d.Set("runasgroupcategory", stringValue(rule.Ipasudorunasgroupcategory))
+	if rule.Sudoorder != nil {
+		d.Set("order", *rule.Sudoorder)
+	} else {
+		d.Set("order", nil)
+	}
+	// Only the configured options still set on the rule are kept, so that
+	// the options added outside of Terraform are not seen as drift
+	configuredOptions := utilsGetArry(d.Get("options").(*schema.Set).List())
+	options := []string{}
+	if rule.Ipasudoopt != nil {
+		for _, opt := range *rule.Ipasudoopt {
+			if slices.Contains(configuredOptions, opt) {
+				options = append(options, opt)
+			}
+		}
+	}
+	d.Set("options", options)
+
 	log.Printf("[DEBUG] Read freeipa sudo rule %s", res.Result.Cn)
 	return nil
 }
@@ -182,54 +236,42 @@ func resourceFreeIPASudoRuleUpdate(ctx context.Context, d *schema.ResourceData,
 	var hasChange = false
 
 	if d.HasChange("description") {
-		if _v, ok := d.GetOkExists("description"); ok {
-			v := _v.(string)
-			optArgs.Description = &v
-			hasChange = true
-		}
-	}
-	if d.HasChange("enabled") {
-		if _v, ok := d.GetOkExists("enabled"); ok {
-			v := _v.(bool)
-			optArgs.Ipaenabledflag = &v
-			hasChange = true
-		}
-	}
-	if d.HasChange("usercategory") {
-		if _v, ok := d.GetOkExists("usercategory"); ok {
-			v := _v.(string)
-			optArgs.Usercategory = &v
-			hasChange = true
-		}
-	}
-	if d.HasChange("hostcategory") {
-		if _v, ok := d.GetOkExists("hostcategory"); ok {
-			v := _v.(string)
-			optArgs.Hostcategory = &v
-			hasChange = true
-		}
+		v := d.Get("description").(string)
+		optArgs.Description = &v
+		hasChange = true
 	}
-	if d.HasChange("runasusercategory") {
-		if _v, ok := d.GetOkExists("runasusercategory"); ok {
-			v := _v.(string)
-			optArgs.Ipasudorunasusercategory = &v
-			hasChange = true
-		}
+
+	categories := []struct {
+		key string
+		arg **string
+	}{
+		{"usercategory", &optArgs.Usercategory},
+		{"hostcategory", &optArgs.Hostcategory},
+		{"commandcategory", &optArgs.Cmdcategory},
+		{"runasusercategory", &optArgs.Ipasudorunasusercategory},
+		{"runasgroupcategory", &optArgs.Ipasudorunasgroupcategory},
 	}
-	if d.HasChange("commandcategory") {
-		if _v, ok := d.GetOkExists("commandcategory"); ok {
-			v := _v.(string)
-			optArgs.Cmdcategory = &v
+
+	var toAll []string
+	for _, c := range categories {
+		if d.HasChange(c.key) {
+			v := d.Get(c.key).(string)
+			*c.arg = &v
 			hasChange = true
+			if v == "all" {
+				toAll = append(toAll, c.key)
+			}
 		}
 	}
-	if d.HasChange("runasgroupcategory") {
-		if _v, ok := d.GetOkExists("runasgroupcategory"); ok {
-			v := _v.(string)
-			optArgs.Ipasudorunasgroupcategory = &v
-			hasChange = true
+
+	// FreeIPA refuses to set a category to "all" while the rule has explicit
+	// members of that category, remove them first.
+	if len(toAll) > 0 {
+		if err := clearSudoRuleCategoryMembers(client, d.Id(), toAll); err != nil {
+			return diag.Errorf("Error update freeipa sudo rule: %s", err)
 		}
 	}
+
 	if d.HasChange("order") {
 		if _v, ok := d.GetOkExists("order"); ok {
 			v := _v.(int)
@@ -238,8 +280,6 @@ func resourceFreeIPASudoRuleUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	// TODO: Change No-Posix, Posix, External
-
 	if hasChange {
 		_, err = client.SudoruleMod(&args, &optArgs)
 		if err != nil {
@@ -251,6 +291,24 @@ func resourceFreeIPASudoRuleUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
+	if d.HasChange("enabled") {
+		if d.Get("enabled").(bool) {
+			_, err = client.SudoruleEnable(&ipa.SudoruleEnableArgs{Cn: d.Id()}, &ipa.SudoruleEnableOptionalArgs{})
+		} else {
+			_, err = client.SudoruleDisable(&ipa.SudoruleDisableArgs{Cn: d.Id()}, &ipa.SudoruleDisableOptionalArgs{})
+		}
+		if err != nil {
+			return diag.Errorf("Error update freeipa sudo rule enabled state: %s", err)
+		}
+	}
+
+	if d.HasChange("options") {
+		o, n := d.GetChange("options")
+		if err := updateSudoRuleOptions(client, d.Id(), utilsGetArry(o.(*schema.Set).List()), utilsGetArry(n.(*schema.Set).List())); err != nil {
+			return diag.Errorf("Error update freeipa sudo rule options: %s", err)
+		}
+	}
+
 	d.SetId(d.Get("name").(string))
 
 	return resourceFreeIPASudoRuleRead(ctx, d, meta)
@@ -268,9 +326,136 @@ func resourceFreeIPASudoRuleDelete(ctx context.Context, d *schema.ResourceData,
 	}
 	_, err = client.SudoruleDel(&args, &ipa.SudoruleDelOptionalArgs{})
 	if err != nil {
-		return diag.Errorf("Error delete freeipa sudo rule: %s", err)
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Sudo rule %s already deleted", d.Id())
+		} else {
+			return diag.Errorf("Error delete freeipa sudo rule: %s", err)
+		}
 	}
 
 	d.SetId("")
 	return nil
 }
+
+// updateSudoRuleOptions removes the options of the sudo rule which are not
+// wanted anymore and adds the new ones, leaving the other options untouched.
+func updateSudoRuleOptions(client *ipa.Client, name string, old []string, new []string) error {
+	for _, opt := range old {
+		if slices.Contains(new, opt) {
+			continue
+		}
+
+		_, err := client.SudoruleRemoveOption(&ipa.SudoruleRemoveOptionArgs{Cn: name, Ipasudoopt: opt}, &ipa.SudoruleRemoveOptionOptionalArgs{})
+		if err != nil {
+			if strings.Contains(err.Error(), "AttrValueNotFound") {
+				log.Printf("[DEBUG] Sudo rule option %s already removed", opt)
+			} else {
+				return fmt.Errorf("failed to remove option %s: %w", opt, err)
+			}
+		}
+	}
+
+	for _, opt := range new {
+		if slices.Contains(old, opt) {
+			continue
+		}
+
+		_, err := client.SudoruleAddOption(&ipa.SudoruleAddOptionArgs{Cn: name, Ipasudoopt: opt}, &ipa.SudoruleAddOptionOptionalArgs{})
+		if err != nil {
+			if strings.Contains(err.Error(), "EmptyModlist") {
+				log.Printf("[DEBUG] Sudo rule option %s already present", opt)
+			} else {
+				return fmt.Errorf("failed to add option %s: %w", opt, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// clearSudoRuleCategoryMembers removes the explicit members matching the
+// given category attributes from the sudo rule.
+func clearSudoRuleCategoryMembers(client *ipa.Client, name string, categories []string) error {
+	all := true
+	res, err := client.SudoruleShow(&ipa.SudoruleShowArgs{Cn: name}, &ipa.SudoruleShowOptionalArgs{All: &all})
+	if err != nil {
+		return err
+	}
+	rule := res.Result
+
+	for _, category := range categories {
+		switch category {
+		case "usercategory":
+			if rule.MemberuserUser != nil || rule.MemberuserGroup != nil {
+				_, err = client.SudoruleRemoveUser(&ipa.SudoruleRemoveUserArgs{Cn: name}, &ipa.SudoruleRemoveUserOptionalArgs{
+					User:  rule.MemberuserUser,
+					Group: rule.MemberuserGroup,
+				})
+			}
+		case "hostcategory":
+			if rule.MemberhostHost != nil || rule.MemberhostHostgroup != nil || rule.Hostmask != nil {
+				_, err = client.SudoruleRemoveHost(&ipa.SudoruleRemoveHostArgs{Cn: name}, &ipa.SudoruleRemoveHostOptionalArgs{
+					Host:      rule.MemberhostHost,
+					Hostgroup: rule.MemberhostHostgroup,
+					Hostmask:  rule.Hostmask,
+				})
+			}
+		case "commandcategory":
+			if rule.MemberallowcmdSudocmd != nil || rule.MemberallowcmdSudocmdgroup != nil {
+				_, err = client.SudoruleRemoveAllowCommand(&ipa.SudoruleRemoveAllowCommandArgs{Cn: name}, &ipa.SudoruleRemoveAllowCommandOptionalArgs{
+					Sudocmd:      rule.MemberallowcmdSudocmd,
+					Sudocmdgroup: rule.MemberallowcmdSudocmdgroup,
+				})
+			}
+		case "runasusercategory":
+			if rule.IpasudorunasUser != nil || rule.IpasudorunasGroup != nil {
+				_, err = client.SudoruleRemoveRunasuser(&ipa.SudoruleRemoveRunasuserArgs{Cn: name}, &ipa.SudoruleRemoveRunasuserOptionalArgs{
+					User:  rule.IpasudorunasUser,
+					Group: rule.IpasudorunasGroup,
+				})
+			}
+		case "runasgroupcategory":
+			if rule.IpasudorunasgroupGroup != nil {
+				_, err = client.SudoruleRemoveRunasgroup(&ipa.SudoruleRemoveRunasgroupArgs{Cn: name}, &ipa.SudoruleRemoveRunasgroupOptionalArgs{
+					Group: rule.IpasudorunasgroupGroup,
+				})
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to remove explicit members before setting %s to all: %w", category, err)
+		}
+	}
+
+	return nil
+}
+
+// checkSudoRuleCategory returns an error when the category attribute of the
+// sudo rule is set to "all", in which case explicit members are rejected.
+func checkSudoRuleCategory(client *ipa.Client, name string, category string) error {
+	all := true
+	res, err := client.SudoruleShow(&ipa.SudoruleShowArgs{Cn: name}, &ipa.SudoruleShowOptionalArgs{All: &all})
+	if err != nil {
+		return err
+	}
+	rule := res.Result
+
+	var value *string
+	switch category {
+	case "usercategory":
+		value = rule.Usercategory
+	case "hostcategory":
+		value = rule.Hostcategory
+	case "commandcategory":
+		value = rule.Cmdcategory
+	case "runasusercategory":
+		value = rule.Ipasudorunasusercategory
+	case "runasgroupcategory":
+		value = rule.Ipasudorunasgroupcategory
+	}
+
+	if value != nil && *value == "all" {
+		return fmt.Errorf("sudo rule %s has %s set to \"all\", explicit members cannot be added", name, category)
+	}
+
+	return nil
+}
@@ -12,14 +12,17 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var sudoRuleAllowCommandMembershipTypes = map[string]string{
+	"srac":  "sudocmd",
+	"sracg": "sudocmd_group",
+}
+
 func resourceFreeIPASudoRuleAllowCommandMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPASudoRuleAllowCommandMembershipCreate,
 		ReadContext:   resourceFreeIPASudoRuleAllowCommandMembershipRead,
 		DeleteContext: resourceFreeIPASudoRuleAllowCommandMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:      membershipImporter(sudoRuleAllowCommandMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -72,7 +75,16 @@ func resourceFreeIPASudoRuleAllowCommandMembershipCreate(ctx context.Context, d
 		cmd_id = "sracg"
 	}
 
-	_, err = client.SudoruleAddAllowCommand(&args, &optArgs)
+	if err := checkSudoRuleCategory(client, args.Cn, "commandcategory"); err != nil {
+		return diag.Errorf("Error creating freeipa sudo rule allowed command membership: %s", err)
+	}
+
+	res, err := client.SudoruleAddAllowCommand(&args, &optArgs)
+	if err == nil {
+		// Commands which are already allowed by the rule are reported as
+		// failures, treat them as success to keep the creation idempotent.
+		err = membershipFailuresError(res.Failed, ipa.FailedReasonAlreadyAMember)
+	}
 	if err != nil {
 		return diag.Errorf("Error creating freeipa sudo rule allowed command membership: %s", err)
 	}
@@ -148,6 +160,8 @@ func resourceFreeIPASudoRuleAllowCommandMembershipRead(ctx context.Context, d *s
 		return diag.Errorf("Error show freeipa sudo rule allowed command membership: %s", err)
 	}
 
+	setMembershipAttributes(d, sudoruleId, typeId, cmdId, sudoRuleAllowCommandMembershipTypes)
+
 	log.Printf("[DEBUG] Read freeipa sudo rule allowed command membership %s", res.Result.Cn)
 	return nil
 }
@@ -181,8 +195,18 @@ func resourceFreeIPASudoRuleAllowCommandMembershipDelete(ctx context.Context, d
 		optArgs.Sudocmdgroup = &v
 	}
 
-	_, err = client.SudoruleRemoveAllowCommand(&args, &optArgs)
+	res, err := client.SudoruleRemoveAllowCommand(&args, &optArgs)
 	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Sudo rule %s already deleted", sudoruleId)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error delete freeipa sudo rule allowed command membership: %s", err)
+	}
+
+	// Commands removed out-of-band are reported as failures, ignore them.
+	if err := membershipFailuresError(res.Failed, failedReasonNotAMember, ipa.FailedReasonNoSuchEntry); err != nil {
 		return diag.Errorf("Error delete freeipa sudo rule allowed command membership: %s", err)
 	}
 
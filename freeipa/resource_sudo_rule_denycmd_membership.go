@@ -12,14 +12,17 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var sudoRuleDenyCommandMembershipTypes = map[string]string{
+	"srdc":  "sudocmd",
+	"srdcg": "sudocmd_group",
+}
+
 func resourceFreeIPASudoRuleDenyCommandMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPASudoRuleDenyCommandMembershipCreate,
 		ReadContext:   resourceFreeIPASudoRuleDenyCommandMembershipRead,
 		DeleteContext: resourceFreeIPASudoRuleDenyCommandMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:      membershipImporter(sudoRuleDenyCommandMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -72,7 +75,12 @@ func resourceFreeIPASudoRuleDenyCommandMembershipCreate(ctx context.Context, d *
 		cmd_id = "srdcg"
 	}
 
-	_, err = client.SudoruleAddDenyCommand(&args, &optArgs)
+	res, err := client.SudoruleAddDenyCommand(&args, &optArgs)
+	if err == nil {
+		// Commands which are already denied by the rule are reported as
+		// failures, treat them as success to keep the creation idempotent.
+		err = membershipFailuresError(res.Failed, ipa.FailedReasonAlreadyAMember)
+	}
 	if err != nil {
 		return diag.Errorf("Error creating freeipa sudo rule denied command membership: %s", err)
 	}
@@ -148,6 +156,8 @@ func resourceFreeIPASudoRuleDenyCommandMembershipRead(ctx context.Context, d *sc
 		return diag.Errorf("Error show freeipa sudo rule denied command membership: %s", err)
 	}
 
+	setMembershipAttributes(d, sudoruleId, typeId, cmdId, sudoRuleDenyCommandMembershipTypes)
+
 	log.Printf("[DEBUG] Read freeipa sudo rule denied command membership %s", res.Result.Cn)
 	return nil
 }
@@ -181,8 +191,18 @@ func resourceFreeIPASudoRuleDenyCommandMembershipDelete(ctx context.Context, d *
 		optArgs.Sudocmdgroup = &v
 	}
 
-	_, err = client.SudoruleRemoveDenyCommand(&args, &optArgs)
+	res, err := client.SudoruleRemoveDenyCommand(&args, &optArgs)
 	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Sudo rule %s already deleted", sudoruleId)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error delete freeipa sudo rule denied command membership: %s", err)
+	}
+
+	// Commands removed out-of-band are reported as failures, ignore them.
+	if err := membershipFailuresError(res.Failed, failedReasonNotAMember, ipa.FailedReasonNoSuchEntry); err != nil {
 		return diag.Errorf("Error delete freeipa sudo rule denied command membership: %s", err)
 	}
 
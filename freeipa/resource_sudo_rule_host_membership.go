@@ -12,14 +12,17 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var sudoRuleHostMembershipTypes = map[string]string{
+	"srh":  "host",
+	"srhg": "hostgroup",
+}
+
 func resourceFreeIPASudoRuleHostMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPASudoRuleHostMembershipCreate,
 		ReadContext:   resourceFreeIPASudoRuleHostMembershipRead,
 		DeleteContext: resourceFreeIPASudoRuleHostMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:      membershipImporter(sudoRuleHostMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -85,6 +88,10 @@ func resourceFreeIPASudoRuleHostMembershipCreate(ctx context.Context, d *schema.
 	// 	host_id = "srhm"
 	// }
 
+	if err := checkSudoRuleCategory(client, args.Cn, "hostcategory"); err != nil {
+		return diag.Errorf("Error creating freeipa sudo rule host membership: %s", err)
+	}
+
 	_, err = client.SudoruleAddHost(&args, &optArgs)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa sudo rule host membership: %s", err)
@@ -175,6 +182,8 @@ func resourceFreeIPASudoRuleHostMembershipRead(ctx context.Context, d *schema.Re
 		return diag.Errorf("Error show freeipa sudo rule host membership: %s", err)
 	}
 
+	setMembershipAttributes(d, sudoruleId, typeId, host_id, sudoRuleHostMembershipTypes)
+
 	log.Printf("[DEBUG] Read freeipa sudo rule host membership %s", res.Result.Cn)
 	return nil
 }
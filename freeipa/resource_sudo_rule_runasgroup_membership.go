@@ -12,14 +12,18 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var sudoRuleRunAsGroupMembershipTypes = map[string]string{
+	"srraug": "runasgroup",
+}
+
 func resourceFreeIPASudoRuleRunAsGroupMembership() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourceFreeIPASudoRuleRunAsGroupMembershipCreate,
-		ReadContext:   resourceFreeIPASudoRuleRunAsGroupMembershipRead,
-		DeleteContext: resourceFreeIPASudoRuleRunAsGroupMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Description:        "Adds a run-as group to a FreeIPA sudo rule (deprecated).",
+		DeprecationMessage: "freeipa_sudo_rule_runasgroup_membership is deprecated, use freeipa_sudo_rule_runasgroup instead",
+		CreateContext:      resourceFreeIPASudoRuleRunAsGroupMembershipCreate,
+		ReadContext:        resourceFreeIPASudoRuleRunAsGroupMembershipRead,
+		DeleteContext:      resourceFreeIPASudoRuleRunAsGroupMembershipDelete,
+		Importer:           membershipImporter(sudoRuleRunAsGroupMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -59,6 +63,10 @@ func resourceFreeIPASudoRuleRunAsGroupMembershipCreate(ctx context.Context, d *s
 		group_id = "srraug"
 	}
 
+	if err := checkSudoRuleCategory(client, args.Cn, "runasgroupcategory"); err != nil {
+		return diag.Errorf("Error creating freeipa sudo rule runasgroup membership: %s", err)
+	}
+
 	_, err = client.SudoruleAddRunasgroup(&args, &optArgs)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa sudo rule runasgroup membership: %s", err)
@@ -123,6 +131,8 @@ func resourceFreeIPASudoRuleRunAsGroupMembershipRead(ctx context.Context, d *sch
 		return diag.Errorf("Error show freeipa sudo rule runasgroup membership: %s", err)
 	}
 
+	setMembershipAttributes(d, sudoruleId, typeId, group_id, sudoRuleRunAsGroupMembershipTypes)
+
 	log.Printf("[DEBUG] Read freeipa sudo rule user membership %s", res.Result.Cn)
 	return nil
 }
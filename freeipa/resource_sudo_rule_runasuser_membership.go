@@ -12,14 +12,18 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var sudoRuleRunAsUserMembershipTypes = map[string]string{
+	"srrau": "runasuser",
+}
+
 func resourceFreeIPASudoRuleRunAsUserMembership() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourceFreeIPASudoRuleRunAsUserMembershipCreate,
-		ReadContext:   resourceFreeIPASudoRuleRunAsUserMembershipRead,
-		DeleteContext: resourceFreeIPASudoRuleRunAsUserMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Description:        "Adds a run-as user to a FreeIPA sudo rule (deprecated).",
+		DeprecationMessage: "freeipa_sudo_rule_runasuser_membership is deprecated, use freeipa_sudo_rule_runasuser instead",
+		CreateContext:      resourceFreeIPASudoRuleRunAsUserMembershipCreate,
+		ReadContext:        resourceFreeIPASudoRuleRunAsUserMembershipRead,
+		DeleteContext:      resourceFreeIPASudoRuleRunAsUserMembershipDelete,
+		Importer:           membershipImporter(sudoRuleRunAsUserMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -59,6 +63,10 @@ func resourceFreeIPASudoRuleRunAsUserMembershipCreate(ctx context.Context, d *sc
 		user_id = "srrau"
 	}
 
+	if err := checkSudoRuleCategory(client, args.Cn, "runasusercategory"); err != nil {
+		return diag.Errorf("Error creating freeipa sudo rule runasuser membership: %s", err)
+	}
+
 	_, err = client.SudoruleAddRunasuser(&args, &optArgs)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa sudo rule runasuser membership: %s", err)
@@ -123,6 +131,8 @@ func resourceFreeIPASudoRuleRunAsUserMembershipRead(ctx context.Context, d *sche
 		return diag.Errorf("Error show freeipa sudo rule runasuser membership: %s", err)
 	}
 
+	setMembershipAttributes(d, sudoruleId, typeId, user_id, sudoRuleRunAsUserMembershipTypes)
+
 	log.Printf("[DEBUG] Read freeipa sudo rule user membership %s", res.Result.Cn)
 	return nil
 }
@@ -2,9 +2,12 @@ package freeipa
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
+	ipa "github.com/camptocamp/go-freeipa/freeipa"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccFreeIPASudoRule(t *testing.T) {
@@ -35,6 +38,17 @@ func TestAccFreeIPASudoRule(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("freeipa_sudo_rule.test_rule", "name", testSudoRule["name"]),
 					resource.TestCheckResourceAttr("freeipa_sudo_rule.test_rule", "description", testSudoRule["description"]),
+					resource.TestCheckResourceAttr("freeipa_sudo_rule.test_rule", "options.#", "2"),
+					resource.TestCheckTypeSetElemAttr("freeipa_sudo_rule.test_rule", "options.*", "!authenticate"),
+				),
+			},
+			{
+				// An option added outside of Terraform is neither drift nor removed
+				PreConfig: func() { testAccFreeIPASudoRuleAddOption(t, testSudoRule["name"], "!requiretty") },
+				Config:    testAccFreeIPASudoRuleResource_full(testSudoRule),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("freeipa_sudo_rule.test_rule", "options.#", "2"),
+					testAccCheckFreeIPASudoRuleHasOption("freeipa_sudo_rule.test_rule", "!requiretty"),
 				),
 			},
 		},
@@ -61,7 +75,45 @@ func testAccFreeIPASudoRuleResource_full(dataset map[string]string) string {
 		runasusercategory = "%s"
 		runasgroupcategory = "%s"
 		order = %s
+		options = ["!authenticate", "env_keep+=SSH_AUTH_SOCK"]
 	}
 	`, dataset["name"], dataset["description"], dataset["enabled"], dataset["usercategory"], dataset["hostcategory"],
 		dataset["commandcategory"], dataset["runasusercategory"], dataset["runasgroupcategory"], dataset["order"])
 }
+
+func testAccFreeIPASudoRuleAddOption(t *testing.T, name string, option string) {
+	client, err := testAccProvider.Meta().(*Config).Client()
+	if err != nil {
+		t.Fatalf("Error creating freeipa identity client: %s", err)
+	}
+
+	_, err = client.SudoruleAddOption(&ipa.SudoruleAddOptionArgs{Cn: name, Ipasudoopt: option}, &ipa.SudoruleAddOptionOptionalArgs{})
+	if err != nil {
+		t.Fatalf("Error adding freeipa sudo rule option %s: %s", option, err)
+	}
+}
+
+func testAccCheckFreeIPASudoRuleHasOption(resourceName string, option string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", resourceName)
+		}
+
+		client, err := testAccProvider.Meta().(*Config).Client()
+		if err != nil {
+			return fmt.Errorf("Error creating freeipa identity client: %s", err)
+		}
+
+		res, err := client.SudoruleShow(&ipa.SudoruleShowArgs{Cn: rs.Primary.ID}, &ipa.SudoruleShowOptionalArgs{})
+		if err != nil {
+			return fmt.Errorf("Error reading freeipa sudo rule %s: %s", rs.Primary.ID, err)
+		}
+
+		if res.Result.Ipasudoopt == nil || !slices.Contains(*res.Result.Ipasudoopt, option) {
+			return fmt.Errorf("Sudo rule %s lost the option %s", rs.Primary.ID, option)
+		}
+
+		return nil
+	}
+}
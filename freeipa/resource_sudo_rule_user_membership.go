@@ -12,14 +12,17 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+var sudoRuleUserMembershipTypes = map[string]string{
+	"sru":  "user",
+	"srug": "group",
+}
+
 func resourceFreeIPASudoRuleUserMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPASudoRuleUserMembershipCreate,
 		ReadContext:   resourceFreeIPASudoRuleUserMembershipRead,
 		DeleteContext: resourceFreeIPASudoRuleUserMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:      membershipImporter(sudoRuleUserMembershipTypes),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -72,6 +75,10 @@ func resourceFreeIPASudoRuleUserMembershipCreate(ctx context.Context, d *schema.
 		user_id = "srug"
 	}
 
+	if err := checkSudoRuleCategory(client, args.Cn, "usercategory"); err != nil {
+		return diag.Errorf("Error creating freeipa sudo rule user membership: %s", err)
+	}
+
 	_, err = client.SudoruleAddUser(&args, &optArgs)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa sudo rule user membership: %s", err)
@@ -148,6 +155,8 @@ func resourceFreeIPASudoRuleUserMembershipRead(ctx context.Context, d *schema.Re
 		return diag.Errorf("Error show freeipa sudo rule user membership: %s", err)
 	}
 
+	setMembershipAttributes(d, sudoruleId, typeId, user_id, sudoRuleUserMembershipTypes)
+
 	log.Printf("[DEBUG] Read freeipa sudo rule user membership %s", res.Result.Cn)
 	return nil
 }
@@ -5,20 +5,34 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	ipa "github.com/camptocamp/go-freeipa/freeipa"
 	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/exp/slices"
 )
 
+var userGroupMembershipTypes = map[string]string{
+	"u":  "user",
+	"us": "users",
+	"g":  "group",
+}
+
 func resourceFreeIPAUserGroupMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFreeIPAUserGroupMembershipCreate,
 		ReadContext:   resourceFreeIPAUserGroupMembershipRead,
+		UpdateContext: resourceFreeIPAUserGroupMembershipUpdate,
 		DeleteContext: resourceFreeIPAUserGroupMembershipDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+		Importer:      membershipImporter(userGroupMembershipTypes),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -32,14 +46,21 @@ func resourceFreeIPAUserGroupMembership() *schema.Resource {
 				Type:          schema.TypeString,
 				Optional:      true,
 				ForceNew:      true,
-				ConflictsWith: []string{"group"},
+				ConflictsWith: []string{"group", "users"},
 				Description:   "User to add",
 			},
+			"users": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"user", "group"},
+				Description:   "Users to add",
+			},
 			"group": {
 				Type:          schema.TypeString,
 				Optional:      true,
 				ForceNew:      true,
-				ConflictsWith: []string{"user"},
+				ConflictsWith: []string{"user", "users"},
 				Description:   "Group to add",
 			},
 		},
@@ -49,46 +70,23 @@ func resourceFreeIPAUserGroupMembership() *schema.Resource {
 func resourceFreeIPAUserGroupMembershipCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Creating freeipa the user group membership")
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
 
-	user_id := "u"
-
-	optArgs := ipa.GroupAddMemberOptionalArgs{}
+	name := d.Get("name").(string)
+	typeId, members := userGroupMembershipFromConfig(d)
 
-	args := ipa.GroupAddMemberArgs{
-		Cn: d.Get("name").(string),
-	}
-	if _v, ok := d.GetOkExists("user"); ok {
-		v := []string{_v.(string)}
-		optArgs.User = &v
-		user_id = "u"
-	}
-	if _v, ok := d.GetOkExists("group"); ok {
-		v := []string{_v.(string)}
-		optArgs.Group = &v
-		user_id = "g"
+	if len(members) == 0 {
+		return diag.Errorf("Error creating freeipa the user group membership: one of user, users or group must be set")
 	}
 
-	_, err = client.GroupAddMember(&args, &optArgs)
-	if err != nil {
-		if utils.IsMembermanagerGroupDecodeError(err) {
-			log.Printf("[WARN] Ignoring go-freeipa MembermanagerGroup decode error on GroupAddMember: %v", err)
-		} else {
-			return diag.Errorf("Error creating freeipa the user group membership: %s", err)
-		}
+	if err := addUserGroupMembers(client, name, typeId, members); err != nil {
+		return diag.Errorf("Error creating freeipa the user group membership: %s", err)
 	}
 
-	switch user_id {
-	case "g":
-		id := fmt.Sprintf("%s/g/%s", d.Get("name").(string), d.Get("group").(string))
-		d.SetId(id)
-	case "u":
-		id := fmt.Sprintf("%s/u/%s", d.Get("name").(string), d.Get("user").(string))
-		d.SetId(id)
-	}
+	d.SetId(userGroupMembershipID(name, typeId, members))
 
 	return resourceFreeIPAUserGroupMembershipRead(ctx, d, meta)
 }
@@ -96,82 +94,132 @@ func resourceFreeIPAUserGroupMembershipCreate(ctx context.Context, d *schema.Res
 func resourceFreeIPAUserGroupMembershipRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Read freeipa the user group membership")
 
-	name, typeId, userId, err := parseUserMembershipID(d.Id())
+	name, typeId, memberId, err := parseUserMembershipID(d.Id())
 
 	if err != nil {
 		return diag.Errorf("Error parsing ID of freeipa_user_group_membership: %s", err)
 	}
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
 
-	optArgs := ipa.GroupFindOptionalArgs{
-		Cn: &name,
+	all := true
+	res, err := client.GroupShow(&ipa.GroupShowArgs{Cn: name}, &ipa.GroupShowOptionalArgs{All: &all})
+	if err != nil {
+		if utils.IsMembermanagerGroupDecodeError(err) {
+			log.Printf("[WARN] Ignoring go-freeipa MembermanagerGroup decode error on GroupShow: %v", err)
+			// The members cannot be read without the membership manager
+			// attributes, keep the last known state.
+			return diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Membership of group %s could not be refreshed", name),
+				Detail:   fmt.Sprintf("go-freeipa failed to decode the membership manager attributes of the group, the last known state is kept: %s", err),
+			}}
+		}
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Warning! Group %s does not exist", name)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Error reading freeipa the user group membership: %s", err)
 	}
 
+	var actual []string
 	switch typeId {
 	case "g":
-		v := []string{userId}
-		optArgs.Group = &v
-	case "u":
-		v := []string{userId}
-		optArgs.User = &v
+		if res.Result.MemberGroup != nil {
+			actual = *res.Result.MemberGroup
+		}
+	default:
+		if res.Result.MemberUser != nil {
+			actual = *res.Result.MemberUser
+		}
 	}
 
-	res, err := client.GroupFind("", &ipa.GroupFindArgs{}, &optArgs)
-	if err != nil {
-		if utils.IsMembermanagerGroupDecodeError(err) {
-			log.Printf("[WARN] Ignoring go-freeipa MembermanagerGroup decode error on GroupFind: %v", err)
-			return nil
+	var present []string
+	for _, m := range strings.Split(memberId, ",") {
+		if slices.Contains(actual, m) {
+			present = append(present, m)
 		}
-		return diag.Errorf("Error find freeipa the user group membership: %s", err)
 	}
 
-	if strings.Contains(*res.Summary, "0 groups matched") {
+	if len(present) == 0 {
 		log.Printf("[DEBUG] Warning! Group or User membership not exist")
 		d.Set("user", "")
+		d.Set("users", nil)
 		d.Set("group", "")
 		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", name)
+	switch typeId {
+	case "g":
+		d.Set("group", present[0])
+	case "u":
+		d.Set("user", present[0])
+	case "us":
+		d.Set("users", present)
 	}
 
 	return nil
 }
 
-func resourceFreeIPAUserGroupMembershipDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Printf("[DEBUG] Delete freeipa the user group membership")
+func resourceFreeIPAUserGroupMembershipUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Update freeipa the user group membership")
 
-	client, err := meta.(*Config).Client()
+	client, err := meta.(*Config).ClientWithContext(ctx)
 	if err != nil {
 		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
 
-	optArgs := ipa.GroupRemoveMemberOptionalArgs{}
+	name := d.Get("name").(string)
 
-	nameId, typeId, userId, err := parseUserMembershipID(d.Id())
+	if d.HasChange("users") {
+		o, n := d.GetChange("users")
+		oldUsers := o.(*schema.Set)
+		newUsers := n.(*schema.Set)
 
-	if err != nil {
-		return diag.Errorf("Error parsing ID of freeipa_user_group_membership: %s", err)
+		toAdd := utilsGetArry(newUsers.Difference(oldUsers).List())
+		toRemove := utilsGetArry(oldUsers.Difference(newUsers).List())
+
+		if len(toAdd) > 0 {
+			if err := addUserGroupMembers(client, name, "us", toAdd); err != nil {
+				return diag.Errorf("Error update freeipa the user group membership: %s", err)
+			}
+		}
+		if len(toRemove) > 0 {
+			if err := removeUserGroupMembers(client, name, "us", toRemove); err != nil {
+				return diag.Errorf("Error update freeipa the user group membership: %s", err)
+			}
+		}
+
+		d.SetId(userGroupMembershipID(name, "us", utilsGetArry(newUsers.List())))
 	}
 
-	args := ipa.GroupRemoveMemberArgs{
-		Cn: nameId,
+	return resourceFreeIPAUserGroupMembershipRead(ctx, d, meta)
+}
+
+func resourceFreeIPAUserGroupMembershipDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Delete freeipa the user group membership")
+
+	client, err := meta.(*Config).ClientWithContext(ctx)
+	if err != nil {
+		return diag.Errorf("Error creating freeipa identity client: %s", err)
 	}
 
-	switch typeId {
-	case "g":
-		v := []string{userId}
-		optArgs.Group = &v
-	case "u":
-		v := []string{userId}
-		optArgs.User = &v
+	nameId, typeId, memberId, err := parseUserMembershipID(d.Id())
+
+	if err != nil {
+		return diag.Errorf("Error parsing ID of freeipa_user_group_membership: %s", err)
 	}
 
-	_, err = client.GroupRemoveMember(&args, &optArgs)
+	err = removeUserGroupMembers(client, nameId, typeId, strings.Split(memberId, ","))
 	if err != nil {
-		if utils.IsMembermanagerGroupDecodeError(err) {
-			log.Printf("[WARN] Ignoring go-freeipa MembermanagerGroup decode error on GroupRemoveMember: %v", err)
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] Group %s not found", nameId)
 		} else {
 			return diag.Errorf("Error delete freeipa the user group membership: %s", err)
 		}
@@ -182,6 +230,70 @@ func resourceFreeIPAUserGroupMembershipDelete(ctx context.Context, d *schema.Res
 	return nil
 }
 
+func userGroupMembershipFromConfig(d *schema.ResourceData) (string, []string) {
+	if _v, ok := d.GetOk("group"); ok {
+		return "g", []string{_v.(string)}
+	}
+	if _v, ok := d.GetOk("users"); ok {
+		members := utilsGetArry(_v.(*schema.Set).List())
+		slices.Sort(members)
+		return "us", members
+	}
+	if _v, ok := d.GetOk("user"); ok {
+		return "u", []string{_v.(string)}
+	}
+	return "u", nil
+}
+
+func userGroupMembershipID(name, typeId string, members []string) string {
+	sorted := slices.Clone(members)
+	slices.Sort(sorted)
+	return fmt.Sprintf("%s/%s/%s", name, typeId, strings.Join(sorted, ","))
+}
+
+func userGroupMembershipOptArgs(typeId string, members []string) (*[]string, *[]string) {
+	v := members
+	if typeId == "g" {
+		return nil, &v
+	}
+	return &v, nil
+}
+
+func addUserGroupMembers(client *ipa.Client, name, typeId string, members []string) error {
+	optArgs := ipa.GroupAddMemberOptionalArgs{}
+	optArgs.User, optArgs.Group = userGroupMembershipOptArgs(typeId, members)
+
+	res, err := client.GroupAddMember(&ipa.GroupAddMemberArgs{Cn: name}, &optArgs)
+	if err != nil {
+		if utils.IsMembermanagerGroupDecodeError(err) {
+			log.Printf("[WARN] Ignoring go-freeipa MembermanagerGroup decode error on GroupAddMember: %v", err)
+			return nil
+		}
+		return err
+	}
+
+	// Members which already belong to the group are reported as failures by
+	// FreeIPA, treat them as success to keep the creation idempotent.
+	return membershipFailuresError(res.Failed, ipa.FailedReasonAlreadyAMember)
+}
+
+func removeUserGroupMembers(client *ipa.Client, name, typeId string, members []string) error {
+	optArgs := ipa.GroupRemoveMemberOptionalArgs{}
+	optArgs.User, optArgs.Group = userGroupMembershipOptArgs(typeId, members)
+
+	res, err := client.GroupRemoveMember(&ipa.GroupRemoveMemberArgs{Cn: name}, &optArgs)
+	if err != nil {
+		if utils.IsMembermanagerGroupDecodeError(err) {
+			log.Printf("[WARN] Ignoring go-freeipa MembermanagerGroup decode error on GroupRemoveMember: %v", err)
+			return nil
+		}
+		return err
+	}
+
+	// Members removed out-of-band are reported as failures, ignore them.
+	return membershipFailuresError(res.Failed, failedReasonNotAMember, ipa.FailedReasonNoSuchEntry)
+}
+
 func parseUserMembershipID(id string) (string, string, string, error) {
 	idParts := strings.Split(id, "/")
 	if len(idParts) < 3 {
@@ -1,5 +1,19 @@
 package freeipa
 
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ipa "github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/exp/slices"
+)
+
+// failedReasonNotAMember is reported by FreeIPA when removing a member which
+// does not belong to the target entry.
+const failedReasonNotAMember = "This entry is not a member"
+
 func utilsGetArry(itemsRaw []interface{}) []string {
 	res := make([]string, len(itemsRaw))
 	for i, raw := range itemsRaw {
@@ -7,3 +21,77 @@ func utilsGetArry(itemsRaw []interface{}) []string {
 	}
 	return res
 }
+
+// stringValue dereferences optional string attributes returned by FreeIPA.
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// membershipFailuresError turns the failures reported by a FreeIPA
+// add/remove member call into an error, ignoring the given reasons.
+func membershipFailuresError(failed ipa.FailedOperations, ignoredReasons ...string) error {
+	var msgs []string
+	for kind, ops := range failed.GetFailures() {
+		for _, op := range ops {
+			if slices.Contains(ignoredReasons, op.Reason) {
+				continue
+			}
+			msgs = append(msgs, fmt.Sprintf("%s %s: %s", kind, op.Name, op.Reason))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	slices.Sort(msgs)
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
+
+// parseMembershipID splits the `<name>/<type>/<member>` ID of a membership
+// resource, checking the member type against the ones the resource supports.
+// The member part may itself contain slashes (e.g. sudo command paths).
+func parseMembershipID(id string, typeAttrs map[string]string) (string, string, string, error) {
+	idParts := strings.SplitN(id, "/", 3)
+	if len(idParts) != 3 || idParts[0] == "" || idParts[2] == "" {
+		return "", "", "", fmt.Errorf("Invalid membership ID %q, expected <name>/<type>/<member>", id)
+	}
+
+	if _, ok := typeAttrs[idParts[1]]; !ok {
+		types := make([]string, 0, len(typeAttrs))
+		for t := range typeAttrs {
+			types = append(types, t)
+		}
+		slices.Sort(types)
+		return "", "", "", fmt.Errorf("Invalid membership ID %q, member type must be one of %s", id, strings.Join(types, ", "))
+	}
+
+	return idParts[0], idParts[1], idParts[2], nil
+}
+
+// membershipImporter validates the import ID of a membership resource, the
+// attributes are then set from the ID by the resource Read.
+func membershipImporter(typeAttrs map[string]string) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			if _, _, _, err := parseMembershipID(d.Id(), typeAttrs); err != nil {
+				return nil, err
+			}
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+// setMembershipAttributes sets the name and member attributes of a membership
+// resource from its parsed ID.
+func setMembershipAttributes(d *schema.ResourceData, name, typeId, member string, typeAttrs map[string]string) {
+	d.Set("name", name)
+	for t, attr := range typeAttrs {
+		if t == typeId {
+			d.Set(attr, member)
+		} else {
+			d.Set(attr, "")
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package datasources
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Cert struct {
+	provider *provider.Provider
+}
+
+type CertModel struct {
+	SerialNumber     types.Int64  `tfsdk:"serial_number"`
+	CA               types.String `tfsdk:"cacn"`
+	Certificate      types.String `tfsdk:"certificate"`
+	Subject          types.String `tfsdk:"subject"`
+	Issuer           types.String `tfsdk:"issuer"`
+	ValidNotBefore   types.String `tfsdk:"valid_not_before"`
+	ValidNotAfter    types.String `tfsdk:"valid_not_after"`
+	Revoked          types.Bool   `tfsdk:"revoked"`
+	RevocationReason types.Int64  `tfsdk:"revocation_reason"`
+}
+
+func (d *Cert) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cert"
+}
+
+func (d *Cert) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a certificate issued by the FreeIPA CA.",
+		Attributes: map[string]schema.Attribute{
+			"serial_number": schema.Int64Attribute{
+				Description: "Serial number of the certificate",
+				Required:    true,
+			},
+			"cacn": schema.StringAttribute{
+				Description: "Name of the CA that issued the certificate. Defaults to the IPA CA",
+				Optional:    true,
+			},
+			"certificate": schema.StringAttribute{
+				Description: "PEM encoded certificate",
+				Computed:    true,
+			},
+			"subject": schema.StringAttribute{
+				Description: "Subject DN of the certificate",
+				Computed:    true,
+			},
+			"issuer": schema.StringAttribute{
+				Description: "Issuer DN of the certificate",
+				Computed:    true,
+			},
+			"valid_not_before": schema.StringAttribute{
+				Description: "Start of the certificate validity, in RFC 3339 format",
+				Computed:    true,
+			},
+			"valid_not_after": schema.StringAttribute{
+				Description: "End of the certificate validity, in RFC 3339 format",
+				Computed:    true,
+			},
+			"revoked": schema.BoolAttribute{
+				Description: "Whether the certificate is revoked",
+				Computed:    true,
+			},
+			"revocation_reason": schema.Int64Attribute{
+				Description: "RFC 5280 reason code of the revocation, null when the certificate is not revoked",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *Cert) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state CertModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CertShowArgs{
+		SerialNumber: int(state.SerialNumber.ValueInt64()),
+	}
+
+	optArgs := &freeipa.CertShowOptionalArgs{
+		Cacn:      state.CA.ValueStringPointer(),
+		All:       freeipa.Bool(true),
+		NoMembers: freeipa.Bool(true),
+	}
+
+	res, err := d.provider.Client().CertShow(args, optArgs)
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Certificate not found", fmt.Sprintf("No certificate with serial number %d was issued by the CA.", state.SerialNumber.ValueInt64()))
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read certificate", "Reason: "+err.Error())
+
+		return
+	}
+
+	cert := res.Result
+
+	der, err := utils.CertificateDER(cert.Certificate)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read certificate", "Reason: "+err.Error())
+
+		return
+	}
+
+	state.Certificate = types.StringValue(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	state.Subject = types.StringValue(cert.Subject)
+	state.Issuer = types.StringValue(cert.Issuer)
+	state.ValidNotBefore = types.StringValue(cert.ValidNotBefore.UTC().Format(time.RFC3339))
+	state.ValidNotAfter = types.StringValue(cert.ValidNotAfter.UTC().Format(time.RFC3339))
+	state.Revoked = types.BoolValue(cert.Revoked != nil && *cert.Revoked)
+
+	state.RevocationReason = types.Int64Null()
+	if state.Revoked.ValueBool() {
+		state.RevocationReason = types.Int64Value(int64(cert.RevocationReason))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewCert(p *provider.Provider) datasource.DataSource {
+	d := &Cert{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewCert)
+}
@@ -12,3 +12,13 @@ var (
 func DataSources() []func(p *provider.Provider) datasource.DataSource {
 	return dataSources
 }
+
+// stringSliceValue dereferences optional multi-valued attributes returned by
+// FreeIPA, so that missing ones are exposed as empty collections.
+func stringSliceValue(v *[]string) []string {
+	if v == nil {
+		return []string{}
+	}
+
+	return *v
+}
@@ -0,0 +1,214 @@
+package datasources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type DnsRecord struct {
+	provider *provider.Provider
+}
+
+type DnsRecordModel struct {
+	Name     types.String `tfsdk:"idnsname"`
+	ZoneName types.String `tfsdk:"dnszoneidnsname"`
+	Type     types.String `tfsdk:"type"`
+	TTL      types.Int64  `tfsdk:"dnsttl"`
+	Records  types.Set    `tfsdk:"records"`
+}
+
+func (d *DnsRecord) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_record"
+}
+
+func (d *DnsRecord) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up the records of an existing FreeIPA DNS record name.",
+		Attributes: map[string]schema.Attribute{
+			"idnsname": schema.StringAttribute{
+				Description: "Record name, relative to the zone (use `@` for the zone apex) or absolute with a trailing dot",
+				Required:    true,
+			},
+			"dnszoneidnsname": schema.StringAttribute{
+				Description: "Zone name",
+				Required:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: "Record type, one of " + strings.Join(utils.DnsRecordTypes, ", ") + ". Can be omitted when the name only holds records of a single type",
+				Optional:    true,
+				Computed:    true,
+			},
+			"dnsttl": schema.Int64Attribute{
+				Description: "Time to live of the records, null when the zone default applies",
+				Computed:    true,
+			},
+			"records": schema.SetAttribute{
+				Description: "Records of the given type",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *DnsRecord) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config DnsRecordModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Type.IsUnknown() || config.Type.IsNull() {
+		return
+	}
+
+	if !slices.Contains(utils.DnsRecordTypes, config.Type.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid configuration",
+			fmt.Sprintf("Unsupported record type “%s”, expected one of %s.", config.Type.ValueString(), strings.Join(utils.DnsRecordTypes, ", ")),
+		)
+	}
+}
+
+func (d *DnsRecord) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state DnsRecordModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var zone any = state.ZoneName.ValueString()
+
+	args := &freeipa.DnsrecordShowArgs{
+		Idnsname: utils.RelativeDnsName(state.Name.ValueString(), state.ZoneName.ValueString()),
+	}
+
+	optArgs := &freeipa.DnsrecordShowOptionalArgs{
+		Dnszoneidnsname: &zone,
+		All:             freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling DnsrecordShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := d.provider.Client().DnsrecordShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called DnsrecordShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			resp.Diagnostics.AddError(
+				"DNS record not found",
+				fmt.Sprintf("No DNS record named %q exists in zone %q.", state.Name.ValueString(), state.ZoneName.ValueString()),
+			)
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read DNS record", "Reason: "+err.Error())
+
+		return
+	}
+
+	recordType := state.Type.ValueString()
+
+	if state.Type.IsNull() {
+		var found []string
+
+		for _, t := range utils.DnsRecordTypes {
+			if values := utils.DnsRecordValues(&res.Result, t); values != nil && len(*values) > 0 {
+				found = append(found, t)
+			}
+		}
+
+		if len(found) == 0 {
+			resp.Diagnostics.AddError(
+				"DNS record not found",
+				fmt.Sprintf("DNS record %q of zone %q holds no record of a supported type.", state.Name.ValueString(), state.ZoneName.ValueString()),
+			)
+
+			return
+		}
+
+		if len(found) > 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("type"),
+				"Ambiguous DNS record type",
+				fmt.Sprintf("DNS record %q of zone %q holds records of the types %s, set “type” to select one of them.", state.Name.ValueString(), state.ZoneName.ValueString(), strings.Join(found, ", ")),
+			)
+
+			return
+		}
+
+		recordType = found[0]
+	}
+
+	records := utils.DnsRecordValues(&res.Result, recordType)
+
+	if records == nil || len(*records) == 0 {
+		resp.Diagnostics.AddError(
+			"DNS record not found",
+			fmt.Sprintf("DNS record %q of zone %q holds no %s record.", state.Name.ValueString(), state.ZoneName.ValueString(), recordType),
+		)
+
+		return
+	}
+
+	var diags diag.Diagnostics
+
+	state.Type = types.StringValue(recordType)
+
+	state.TTL = types.Int64Null()
+	if res.Result.Dnsttl != nil {
+		state.TTL = types.Int64Value(int64(*res.Result.Dnsttl))
+	}
+
+	state.Records, diags = types.SetValueFrom(ctx, types.StringType, *records)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewDnsRecord(p *provider.Provider) datasource.DataSource {
+	d := &DnsRecord{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+	var _ datasource.DataSourceWithValidateConfig = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewDnsRecord)
+}
@@ -0,0 +1,250 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type DnsZone struct {
+	provider *provider.Provider
+}
+
+type DnsZoneModel struct {
+	Name          types.String `tfsdk:"idnsname"`
+	Active        types.Bool   `tfsdk:"idnszoneactive"`
+	SoaMname      types.String `tfsdk:"idnssoamname"`
+	SoaRname      types.String `tfsdk:"idnssoarname"`
+	SoaSerial     types.Int64  `tfsdk:"idnssoaserial"`
+	SoaRefresh    types.Int64  `tfsdk:"idnssoarefresh"`
+	SoaRetry      types.Int64  `tfsdk:"idnssoaretry"`
+	SoaExpire     types.Int64  `tfsdk:"idnssoaexpire"`
+	SoaMinimum    types.Int64  `tfsdk:"idnssoaminimum"`
+	TTL           types.Int64  `tfsdk:"dnsttl"`
+	DefaultTTL    types.Int64  `tfsdk:"dnsdefaultttl"`
+	DynamicUpdate types.Bool   `tfsdk:"idnsallowdynupdate"`
+	UpdatePolicy  types.String `tfsdk:"idnsupdatepolicy"`
+	ManagedBy     types.String `tfsdk:"managedby"`
+	Nameservers   types.Set    `tfsdk:"nameservers"`
+}
+
+func (d *DnsZone) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_zone"
+}
+
+func (d *DnsZone) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up an existing FreeIPA DNS zone.",
+		Attributes: map[string]schema.Attribute{
+			"idnsname": schema.StringAttribute{
+				Description: "Zone name, with or without the trailing dot",
+				Required:    true,
+			},
+			"idnszoneactive": schema.BoolAttribute{
+				Description: "Whether the zone is enabled",
+				Computed:    true,
+			},
+			"idnssoamname": schema.StringAttribute{
+				Description: "Authoritative nameserver of the SOA record",
+				Computed:    true,
+			},
+			"idnssoarname": schema.StringAttribute{
+				Description: "Administrator e-mail address of the SOA record, in DNS name form",
+				Computed:    true,
+			},
+			"idnssoaserial": schema.Int64Attribute{
+				Description: "SOA record serial number",
+				Computed:    true,
+			},
+			"idnssoarefresh": schema.Int64Attribute{
+				Description: "SOA record refresh time",
+				Computed:    true,
+			},
+			"idnssoaretry": schema.Int64Attribute{
+				Description: "SOA record retry time",
+				Computed:    true,
+			},
+			"idnssoaexpire": schema.Int64Attribute{
+				Description: "SOA record expire time",
+				Computed:    true,
+			},
+			"idnssoaminimum": schema.Int64Attribute{
+				Description: "How long negative responses are cached",
+				Computed:    true,
+			},
+			"dnsttl": schema.Int64Attribute{
+				Description: "Time to live of the SOA record, null when unset",
+				Computed:    true,
+			},
+			"dnsdefaultttl": schema.Int64Attribute{
+				Description: "Time to live of the records of the zone which do not set one, null when unset",
+				Computed:    true,
+			},
+			"idnsallowdynupdate": schema.BoolAttribute{
+				Description: "Whether dynamic updates are allowed",
+				Computed:    true,
+			},
+			"idnsupdatepolicy": schema.StringAttribute{
+				Description: "BIND update policy",
+				Computed:    true,
+			},
+			"managedby": schema.StringAttribute{
+				Description: "DN of the permission granting to manage the zone, null when none was added",
+				Computed:    true,
+			},
+			"nameservers": schema.SetAttribute{
+				Description: "Nameservers of the NS records of the zone apex",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *DnsZone) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state DnsZoneModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// FreeIPA stores zone names with their trailing dot
+	var name any = utils.AbsoluteDnsName(state.Name.ValueString())
+
+	args := &freeipa.DnszoneShowArgs{}
+	optArgs := &freeipa.DnszoneShowOptionalArgs{
+		Idnsname: &name,
+		All:      freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling DnszoneShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := d.provider.Client().DnszoneShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called DnszoneShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(
+				"DNS zone not found",
+				fmt.Sprintf("No DNS zone named %q exists.", state.Name.ValueString()),
+			)
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read DNS zone", "Reason: "+err.Error())
+
+		return
+	}
+
+	zone := res.Result
+
+	state.Active = types.BoolPointerValue(zone.Idnszoneactive)
+	state.SoaMname = types.StringNull()
+	if zone.Idnssoamname != nil {
+		state.SoaMname = types.StringValue(utils.DnsNameValue(*zone.Idnssoamname))
+	}
+	state.SoaRname = types.StringValue(utils.DnsNameValue(zone.Idnssoarname))
+	state.SoaSerial = types.Int64Null()
+	if zone.Idnssoaserial != nil {
+		state.SoaSerial = types.Int64Value(int64(*zone.Idnssoaserial))
+	}
+	state.SoaRefresh = types.Int64Value(int64(zone.Idnssoarefresh))
+	state.SoaRetry = types.Int64Value(int64(zone.Idnssoaretry))
+	state.SoaExpire = types.Int64Value(int64(zone.Idnssoaexpire))
+	state.SoaMinimum = types.Int64Value(int64(zone.Idnssoaminimum))
+	state.TTL = types.Int64Null()
+	if zone.Dnsttl != nil {
+		state.TTL = types.Int64Value(int64(*zone.Dnsttl))
+	}
+	state.DefaultTTL = types.Int64Null()
+	if zone.Dnsdefaultttl != nil {
+		state.DefaultTTL = types.Int64Value(int64(*zone.Dnsdefaultttl))
+	}
+	state.DynamicUpdate = types.BoolPointerValue(zone.Idnsallowdynupdate)
+	state.UpdatePolicy = types.StringPointerValue(zone.Idnsupdatepolicy)
+	state.ManagedBy = types.StringPointerValue(zone.Managedby)
+
+	nameservers, err := d.nameservers(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read DNS zone nameservers", "Reason: "+err.Error())
+
+		return
+	}
+
+	var diags diag.Diagnostics
+
+	state.Nameservers, diags = types.SetValueFrom(ctx, types.StringType, nameservers)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// nameservers returns the NS records of the apex of zone, which the zone
+// itself does not hold.
+func (d *DnsZone) nameservers(ctx context.Context, zone any) ([]string, error) {
+	args := &freeipa.DnsrecordShowArgs{
+		Idnsname: "@",
+	}
+	optArgs := &freeipa.DnsrecordShowOptionalArgs{
+		Dnszoneidnsname: &zone,
+	}
+
+	tflog.Trace(ctx, "Calling DnsrecordShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := d.provider.Client().DnsrecordShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called DnsrecordShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		// Zones without apex records
+		if utils.IsNotFoundError(err) {
+			return []string{}, nil
+		}
+
+		return nil, err
+	}
+
+	return stringSliceValue(res.Result.Nsrecord), nil
+}
+
+func NewDnsZone(p *provider.Provider) datasource.DataSource {
+	d := &DnsZone{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewDnsZone)
+}
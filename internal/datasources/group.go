@@ -0,0 +1,174 @@
+package datasources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type Group struct {
+	provider *provider.Provider
+}
+
+type GroupModel struct {
+	Name                   types.String `tfsdk:"cn"`
+	Description            types.String `tfsdk:"description"`
+	GIDNumber              types.Int64  `tfsdk:"gidnumber"`
+	MemberUsers            types.Set    `tfsdk:"member_users"`
+	MemberGroups           types.Set    `tfsdk:"member_groups"`
+	MemberIndirectUsers    types.Set    `tfsdk:"memberindirect_users"`
+	MemberOfGroups         types.Set    `tfsdk:"memberof_groups"`
+	MemberOfIndirectGroups types.Set    `tfsdk:"memberofindirect_groups"`
+	ExternalMembers        types.Set    `tfsdk:"external_members"`
+}
+
+func (d *Group) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group"
+}
+
+func (d *Group) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up an existing FreeIPA user group.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Group name",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Group description",
+				Computed:    true,
+			},
+			"gidnumber": schema.Int64Attribute{
+				Description: "GID number, null for non-POSIX groups",
+				Computed:    true,
+			},
+			"member_users": schema.SetAttribute{
+				Description: "Users which are direct members of the group",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"member_groups": schema.SetAttribute{
+				Description: "Groups which are direct members of the group",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"memberindirect_users": schema.SetAttribute{
+				Description: "Users which are members of the group through nested groups",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"memberof_groups": schema.SetAttribute{
+				Description: "Groups the group is a direct member of",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"memberofindirect_groups": schema.SetAttribute{
+				Description: "Groups the group is a member of through nested groups",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"external_members": schema.SetAttribute{
+				Description: "External members (e.g. trusted domain SIDs) of the group",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *Group) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state GroupModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.GroupShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.GroupShowOptionalArgs{
+		All: freeipa.Bool(true),
+	}
+
+	res, withoutMembers, err := utils.GroupShow(ctx, d.provider.Client(), args, optArgs)
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Group not found", fmt.Sprintf("No FreeIPA group named %q exists.", state.Name.ValueString()))
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read group", "Reason: "+err.Error())
+
+		return
+	}
+
+	if withoutMembers {
+		resp.Diagnostics.AddWarning(
+			"Group members could not be read",
+			"go-freeipa failed to decode the membership manager attributes of group “"+state.Name.ValueString()+"”, the member attributes are left empty.",
+		)
+	}
+
+	var diags diag.Diagnostics
+
+	group := res.Result
+
+	state.Description = types.StringPointerValue(group.Description)
+
+	state.GIDNumber = types.Int64Null()
+	if group.Gidnumber != nil {
+		state.GIDNumber = types.Int64Value(int64(*group.Gidnumber))
+	}
+
+	sets := []struct {
+		attr  *types.Set
+		value *[]string
+	}{
+		{&state.MemberUsers, group.MemberUser},
+		{&state.MemberGroups, group.MemberGroup},
+		{&state.MemberIndirectUsers, group.MemberindirectUser},
+		{&state.MemberOfGroups, group.MemberofGroup},
+		{&state.MemberOfIndirectGroups, group.MemberofindirectGroup},
+		{&state.ExternalMembers, group.Ipaexternalmember},
+	}
+
+	for _, s := range sets {
+		*s.attr, diags = types.SetValueFrom(ctx, types.StringType, stringSliceValue(s.value))
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewGroup(p *provider.Provider) datasource.DataSource {
+	d := &Group{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewGroup)
+}
@@ -0,0 +1,200 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type HbacTest struct {
+	provider *provider.Provider
+}
+
+type HbacTestModel struct {
+	User       types.String `tfsdk:"user"`
+	TargetHost types.String `tfsdk:"targethost"`
+	Service    types.String `tfsdk:"service"`
+	Rules      types.Set    `tfsdk:"rules"`
+	Disabled   types.Bool   `tfsdk:"disabled"`
+	Allowed    types.Bool   `tfsdk:"allowed"`
+	Matched    types.Set    `tfsdk:"matched"`
+	NotMatched types.Set    `tfsdk:"notmatched"`
+	Invalid    types.Set    `tfsdk:"invalid"`
+}
+
+func (d *HbacTest) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hbac_test"
+}
+
+func (d *HbacTest) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Simulates the HBAC rules of FreeIPA to tell whether a user may access a service on a host.",
+		Attributes: map[string]schema.Attribute{
+			"user": schema.StringAttribute{
+				Description: "User accessing the host",
+				Required:    true,
+			},
+			"targethost": schema.StringAttribute{
+				Description: "Host the user accesses",
+				Required:    true,
+			},
+			"service": schema.StringAttribute{
+				Description: "HBAC service the user accesses, e.g. `sshd`",
+				Required:    true,
+			},
+			"rules": schema.SetAttribute{
+				Description: "HBAC rules to test. Defaults to all the enabled rules",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"disabled": schema.BoolAttribute{
+				Description: "Whether the disabled HBAC rules are tested too, in addition to the enabled ones or to `rules`",
+				Optional:    true,
+			},
+			"allowed": schema.BoolAttribute{
+				Description: "Whether the tested rules grant the access",
+				Computed:    true,
+			},
+			"matched": schema.SetAttribute{
+				Description: "Tested rules granting the access",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"notmatched": schema.SetAttribute{
+				Description: "Tested rules not granting the access",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"invalid": schema.SetAttribute{
+				Description: "Rules given in `rules` which do not exist or are invalid",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *HbacTest) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state HbacTestModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbactestArgs{
+		User:       state.User.ValueString(),
+		Targethost: state.TargetHost.ValueString(),
+		Service:    state.Service.ValueString(),
+	}
+
+	optArgs := &freeipa.HbactestOptionalArgs{
+		Disabled: state.Disabled.ValueBoolPointer(),
+	}
+
+	if !state.Rules.IsNull() {
+		var rules []string
+
+		resp.Diagnostics.Append(state.Rules.ElementsAs(ctx, &rules, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		optArgs.Rules = &rules
+	}
+
+	// FreeIPA tests only the disabled rules when they are requested alone, the
+	// enabled ones are requested along
+	if state.Disabled.ValueBool() && optArgs.Rules == nil {
+		optArgs.Enabled = freeipa.Bool(true)
+	}
+
+	tflog.Trace(ctx, "Calling Hbactest", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := d.provider.Client().Hbactest(args, optArgs)
+
+	tflog.Trace(ctx, "Called Hbactest", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to test HBAC rules", "Reason: "+err.Error())
+
+		return
+	}
+
+	if res.Warning != nil {
+		for _, w := range *res.Warning {
+			resp.Diagnostics.AddWarning("HBAC test warning", fmt.Sprint(w))
+		}
+	}
+
+	var diags diag.Diagnostics
+
+	state.Allowed = types.BoolValue(res.Value)
+
+	sets := []struct {
+		attr  *types.Set
+		value *[]interface{}
+	}{
+		{&state.Matched, res.Matched},
+		{&state.NotMatched, res.Notmatched},
+		{&state.Invalid, res.Error},
+	}
+
+	for _, s := range sets {
+		*s.attr, diags = types.SetValueFrom(ctx, types.StringType, hbacTestRules(s.value))
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// hbacTestRules returns the rule names of a result list of hbactest, which
+// go-freeipa does not decode.
+func hbacTestRules(v *[]interface{}) []string {
+	rules := []string{}
+
+	if v == nil {
+		return rules
+	}
+
+	for _, r := range *v {
+		if name, ok := r.(string); ok {
+			rules = append(rules, name)
+		}
+	}
+
+	return rules
+}
+
+func NewHbacTest(p *provider.Provider) datasource.DataSource {
+	d := &HbacTest{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewHbacTest)
+}
@@ -0,0 +1,183 @@
+package datasources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Hostgroup struct {
+	provider *provider.Provider
+}
+
+type HostgroupModel struct {
+	Name                       types.String `tfsdk:"cn"`
+	Description                types.String `tfsdk:"description"`
+	MemberHosts                types.Set    `tfsdk:"member_hosts"`
+	MemberHostgroups           types.Set    `tfsdk:"member_hostgroups"`
+	MemberIndirectHosts        types.Set    `tfsdk:"memberindirect_hosts"`
+	MemberIndirectHostgroups   types.Set    `tfsdk:"memberindirect_hostgroups"`
+	MemberOfHostgroups         types.Set    `tfsdk:"memberof_hostgroups"`
+	MemberOfIndirectHostgroups types.Set    `tfsdk:"memberofindirect_hostgroups"`
+}
+
+func (d *Hostgroup) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hostgroup"
+}
+
+func (d *Hostgroup) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up the direct and indirect members of an existing FreeIPA host group.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Host group name",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Host group description",
+				Computed:    true,
+			},
+			"member_hosts": schema.SetAttribute{
+				Description: "Hosts which are direct members of the host group",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"member_hostgroups": schema.SetAttribute{
+				Description: "Host groups which are direct members of the host group",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"memberindirect_hosts": schema.SetAttribute{
+				Description: "Hosts which are members of the host group through nested host groups only",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"memberindirect_hostgroups": schema.SetAttribute{
+				Description: "Host groups which are members of the host group through nested host groups only",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"memberof_hostgroups": schema.SetAttribute{
+				Description: "Host groups the host group is a direct member of",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"memberofindirect_hostgroups": schema.SetAttribute{
+				Description: "Host groups the host group is a member of through nested host groups",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *Hostgroup) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state HostgroupModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// go-freeipa cannot decode host groups with several member managers
+	args := []interface{}{state.Name.ValueString()}
+	options := map[string]interface{}{"all": true}
+
+	tflog.Trace(ctx, "Calling hostgroup_show", map[string]any{
+		"args":    args,
+		"options": options,
+	})
+
+	var res struct {
+		Result struct {
+			Description               []string `json:"description"`
+			MemberHost                []string `json:"member_host"`
+			MemberHostgroup           []string `json:"member_hostgroup"`
+			MemberindirectHost        []string `json:"memberindirect_host"`
+			MemberindirectHostgroup   []string `json:"memberindirect_hostgroup"`
+			MemberofHostgroup         []string `json:"memberof_hostgroup"`
+			MemberofindirectHostgroup []string `json:"memberofindirect_hostgroup"`
+		} `json:"result"`
+	}
+
+	err := d.provider.RPC().Call(ctx, "hostgroup_show", args, options, &res)
+
+	tflog.Trace(ctx, "Called hostgroup_show", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Host group not found", fmt.Sprintf("No FreeIPA host group named %q exists.", state.Name.ValueString()))
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read host group", "Reason: "+err.Error())
+
+		return
+	}
+
+	var diags diag.Diagnostics
+
+	hostgroup := res.Result
+
+	state.Description = types.StringNull()
+	if len(hostgroup.Description) > 0 {
+		state.Description = types.StringValue(hostgroup.Description[0])
+	}
+
+	sets := []struct {
+		attr  *types.Set
+		value []string
+	}{
+		{&state.MemberHosts, hostgroup.MemberHost},
+		{&state.MemberHostgroups, hostgroup.MemberHostgroup},
+		{&state.MemberIndirectHosts, hostgroup.MemberindirectHost},
+		{&state.MemberIndirectHostgroups, hostgroup.MemberindirectHostgroup},
+		{&state.MemberOfHostgroups, hostgroup.MemberofHostgroup},
+		{&state.MemberOfIndirectHostgroups, hostgroup.MemberofindirectHostgroup},
+	}
+
+	for _, s := range sets {
+		values := s.value
+		if values == nil {
+			values = []string{}
+		}
+
+		*s.attr, diags = types.SetValueFrom(ctx, types.StringType, values)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewHostgroup(p *provider.Provider) datasource.DataSource {
+	d := &Hostgroup{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewHostgroup)
+}
@@ -0,0 +1,95 @@
+package datasources
+
+import (
+	"context"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Ping struct {
+	provider *provider.Provider
+}
+
+type PingModel struct {
+	Summary    types.String `tfsdk:"summary"`
+	Version    types.String `tfsdk:"version"`
+	APIVersion types.String `tfsdk:"api_version"`
+}
+
+func (d *Ping) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ping"
+}
+
+func (d *Ping) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Checks that the FreeIPA server is reachable and accepts the provider credentials.",
+		Attributes: map[string]schema.Attribute{
+			"summary": schema.StringAttribute{
+				Description: "Answer of the server (e.g. `IPA server version 4.11.1. API version 2.254`)",
+				Computed:    true,
+			},
+			"version": schema.StringAttribute{
+				Description: "Version of the FreeIPA server (e.g. `4.11.1`)",
+				Computed:    true,
+			},
+			"api_version": schema.StringAttribute{
+				Description: "API version of the FreeIPA server (e.g. `2.254`)",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *Ping) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	args := &freeipa.PingArgs{}
+
+	tflog.Trace(ctx, "Calling Ping", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	// The call goes through the session, so expired or revoked credentials
+	// fail it like an unreachable server
+	res, err := d.provider.Client().Ping(args, nil)
+
+	tflog.Trace(ctx, "Called Ping", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("FreeIPA server is not available", "Reason: "+err.Error())
+
+		return
+	}
+
+	// The transport records the version of every ping response
+	info := d.provider.ServerInfo()
+
+	state := PingModel{
+		Summary:    types.StringPointerValue(res.Summary),
+		Version:    types.StringValue(info.Version),
+		APIVersion: types.StringValue(info.APIVersion),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewPing(p *provider.Provider) datasource.DataSource {
+	d := &Ping{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewPing)
+}
@@ -0,0 +1,82 @@
+package datasources
+
+import (
+	"context"
+
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ServerInfo struct {
+	provider *provider.Provider
+}
+
+type ServerInfoModel struct {
+	Version    types.String `tfsdk:"version"`
+	APIVersion types.String `tfsdk:"api_version"`
+	Domain     types.String `tfsdk:"domain"`
+	Realm      types.String `tfsdk:"realm"`
+}
+
+func (d *ServerInfo) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_info"
+}
+
+func (d *ServerInfo) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Describes the FreeIPA server the provider is connected to.",
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				Description: "Version of the FreeIPA server (e.g. `4.11.1`)",
+				Computed:    true,
+			},
+			"api_version": schema.StringAttribute{
+				Description: "API version of the FreeIPA server (e.g. `2.254`)",
+				Computed:    true,
+			},
+			"domain": schema.StringAttribute{
+				Description: "Primary DNS domain of the FreeIPA deployment",
+				Computed:    true,
+			},
+			"realm": schema.StringAttribute{
+				Description: "Kerberos realm of the FreeIPA deployment",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *ServerInfo) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	info := d.provider.ServerInfo()
+
+	if info.Version == "" {
+		resp.Diagnostics.AddError("Failed to read FreeIPA server information", "The server information could not be read when configuring the provider, see the provider warnings.")
+
+		return
+	}
+
+	state := ServerInfoModel{
+		Version:    types.StringValue(info.Version),
+		APIVersion: types.StringValue(info.APIVersion),
+		Domain:     types.StringValue(info.Domain),
+		Realm:      types.StringValue(info.Realm),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewServerInfo(p *provider.Provider) datasource.DataSource {
+	d := &ServerInfo{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewServerInfo)
+}
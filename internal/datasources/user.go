@@ -0,0 +1,289 @@
+package datasources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type User struct {
+	provider *provider.Provider
+}
+
+type UserModel struct {
+	UID                    types.String `tfsdk:"uid"`
+	GivenName              types.String `tfsdk:"givenname"`
+	Surname                types.String `tfsdk:"sn"`
+	FullName               types.String `tfsdk:"cn"`
+	DisplayName            types.String `tfsdk:"displayname"`
+	Initials               types.String `tfsdk:"initials"`
+	Gecos                  types.String `tfsdk:"gecos"`
+	HomeDirectory          types.String `tfsdk:"homedirectory"`
+	LoginShell             types.String `tfsdk:"loginshell"`
+	Mail                   types.List   `tfsdk:"mail"`
+	TelephoneNumber        types.List   `tfsdk:"telephonenumber"`
+	Mobile                 types.List   `tfsdk:"mobile"`
+	Title                  types.String `tfsdk:"title"`
+	OrgUnit                types.String `tfsdk:"ou"`
+	Street                 types.String `tfsdk:"street"`
+	City                   types.String `tfsdk:"l"`
+	Province               types.String `tfsdk:"st"`
+	PostalCode             types.String `tfsdk:"postalcode"`
+	UIDNumber              types.Int64  `tfsdk:"uidnumber"`
+	GIDNumber              types.Int64  `tfsdk:"gidnumber"`
+	KrbCanonicalName       types.String `tfsdk:"krbcanonicalname"`
+	KrbPrincipalName       types.List   `tfsdk:"krbprincipalname"`
+	MemberOfGroups         types.Set    `tfsdk:"memberof_groups"`
+	MemberOfIndirectGroups types.Set    `tfsdk:"memberofindirect_groups"`
+	HasKeytab              types.Bool   `tfsdk:"has_keytab"`
+	HasPassword            types.Bool   `tfsdk:"has_password"`
+	AccountLocked          types.Bool   `tfsdk:"nsaccountlock"`
+}
+
+func (d *User) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (d *User) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up an existing FreeIPA user account.",
+		Attributes: map[string]schema.Attribute{
+			"uid": schema.StringAttribute{
+				Description: "User login",
+				Required:    true,
+			},
+			"givenname": schema.StringAttribute{
+				Description: "First name",
+				Computed:    true,
+			},
+			"sn": schema.StringAttribute{
+				Description: "Last name",
+				Computed:    true,
+			},
+			"cn": schema.StringAttribute{
+				Description: "Full name",
+				Computed:    true,
+			},
+			"displayname": schema.StringAttribute{
+				Description: "Display name",
+				Computed:    true,
+			},
+			"initials": schema.StringAttribute{
+				Description: "Initials",
+				Computed:    true,
+			},
+			"gecos": schema.StringAttribute{
+				Description: "GECOS field",
+				Computed:    true,
+			},
+			"homedirectory": schema.StringAttribute{
+				Description: "Home directory",
+				Computed:    true,
+			},
+			"loginshell": schema.StringAttribute{
+				Description: "Login shell",
+				Computed:    true,
+			},
+			"mail": schema.ListAttribute{
+				Description: "Email addresses",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"telephonenumber": schema.ListAttribute{
+				Description: "Telephone numbers",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"mobile": schema.ListAttribute{
+				Description: "Mobile telephone numbers",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"title": schema.StringAttribute{
+				Description: "Job title",
+				Computed:    true,
+			},
+			"ou": schema.StringAttribute{
+				Description: "Organisational unit",
+				Computed:    true,
+			},
+			"street": schema.StringAttribute{
+				Description: "Street address",
+				Computed:    true,
+			},
+			"l": schema.StringAttribute{
+				Description: "City",
+				Computed:    true,
+			},
+			"st": schema.StringAttribute{
+				Description: "State/Province",
+				Computed:    true,
+			},
+			"postalcode": schema.StringAttribute{
+				Description: "ZIP code",
+				Computed:    true,
+			},
+			"uidnumber": schema.Int64Attribute{
+				Description: "User ID number",
+				Computed:    true,
+			},
+			"gidnumber": schema.Int64Attribute{
+				Description: "Group ID number",
+				Computed:    true,
+			},
+			"krbcanonicalname": schema.StringAttribute{
+				Description: "Canonical Kerberos principal name",
+				Computed:    true,
+			},
+			"krbprincipalname": schema.ListAttribute{
+				Description: "Kerberos principal aliases",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"memberof_groups": schema.SetAttribute{
+				Description: "Groups the user is a direct member of",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"memberofindirect_groups": schema.SetAttribute{
+				Description: "Groups the user is a member of through nested groups",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"has_keytab": schema.BoolAttribute{
+				Description: "Whether the user has Kerberos keys",
+				Computed:    true,
+			},
+			"has_password": schema.BoolAttribute{
+				Description: "Whether the user has a password set",
+				Computed:    true,
+			},
+			"nsaccountlock": schema.BoolAttribute{
+				Description: "Whether the account is disabled",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *User) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state UserModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.UserShowArgs{}
+
+	optArgs := &freeipa.UserShowOptionalArgs{
+		UID: state.UID.ValueStringPointer(),
+		All: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling UserShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := d.provider.Client().UserShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called UserShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("User not found", fmt.Sprintf("No FreeIPA user with login %q exists.", state.UID.ValueString()))
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read user", "Reason: "+err.Error())
+
+		return
+	}
+
+	var diags diag.Diagnostics
+
+	user := res.Result
+
+	state.GivenName = types.StringPointerValue(user.Givenname)
+	state.Surname = types.StringValue(user.Sn)
+	state.FullName = types.StringPointerValue(user.Cn)
+	state.DisplayName = types.StringPointerValue(user.Displayname)
+	state.Initials = types.StringPointerValue(user.Initials)
+	state.Gecos = types.StringPointerValue(user.Gecos)
+	state.HomeDirectory = types.StringPointerValue(user.Homedirectory)
+	state.LoginShell = types.StringPointerValue(user.Loginshell)
+	state.Title = types.StringPointerValue(user.Title)
+	state.OrgUnit = types.StringPointerValue(user.Ou)
+	state.Street = types.StringPointerValue(user.Street)
+	state.City = types.StringPointerValue(user.L)
+	state.Province = types.StringPointerValue(user.St)
+	state.PostalCode = types.StringPointerValue(user.Postalcode)
+	state.KrbCanonicalName = types.StringPointerValue(user.Krbcanonicalname)
+	state.HasKeytab = types.BoolValue(user.HasKeytab != nil && *user.HasKeytab)
+	state.HasPassword = types.BoolValue(user.HasPassword != nil && *user.HasPassword)
+	state.AccountLocked = types.BoolValue(user.Nsaccountlock != nil && *user.Nsaccountlock)
+
+	state.UIDNumber = types.Int64Null()
+	if user.Uidnumber != nil {
+		state.UIDNumber = types.Int64Value(int64(*user.Uidnumber))
+	}
+
+	state.GIDNumber = types.Int64Null()
+	if user.Gidnumber != nil {
+		state.GIDNumber = types.Int64Value(int64(*user.Gidnumber))
+	}
+
+	state.Mail, diags = types.ListValueFrom(ctx, types.StringType, stringSliceValue(user.Mail))
+	resp.Diagnostics.Append(diags...)
+
+	state.TelephoneNumber, diags = types.ListValueFrom(ctx, types.StringType, stringSliceValue(user.Telephonenumber))
+	resp.Diagnostics.Append(diags...)
+
+	state.Mobile, diags = types.ListValueFrom(ctx, types.StringType, stringSliceValue(user.Mobile))
+	resp.Diagnostics.Append(diags...)
+
+	state.KrbPrincipalName, diags = types.ListValueFrom(ctx, types.StringType, stringSliceValue(user.Krbprincipalname))
+	resp.Diagnostics.Append(diags...)
+
+	state.MemberOfGroups, diags = types.SetValueFrom(ctx, types.StringType, stringSliceValue(user.MemberofGroup))
+	resp.Diagnostics.Append(diags...)
+
+	state.MemberOfIndirectGroups, diags = types.SetValueFrom(ctx, types.StringType, stringSliceValue(user.MemberofindirectGroup))
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewUser(p *provider.Provider) datasource.DataSource {
+	d := &User{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewUser)
+}
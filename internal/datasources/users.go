@@ -0,0 +1,205 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Users struct {
+	provider *provider.Provider
+}
+
+type UsersModel struct {
+	Criteria       types.String `tfsdk:"criteria"`
+	Mail           types.String `tfsdk:"mail"`
+	EmployeeNumber types.String `tfsdk:"employeenumber"`
+	EmployeeType   types.String `tfsdk:"employeetype"`
+	OrgUnit        types.String `tfsdk:"ou"`
+	Title          types.String `tfsdk:"title"`
+	InGroup        types.String `tfsdk:"in_group"`
+	Max            types.Int64  `tfsdk:"max"`
+	UIDs           types.List   `tfsdk:"uids"`
+}
+
+func (d *Users) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_users"
+}
+
+func (d *Users) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Searches the FreeIPA user accounts matching every given filter.",
+		Attributes: map[string]schema.Attribute{
+			"criteria": schema.StringAttribute{
+				Description: "String searched in the login, names and email addresses of the users, as with `ipa user-find`",
+				Optional:    true,
+			},
+			"mail": schema.StringAttribute{
+				Description: "Email address of the users",
+				Optional:    true,
+			},
+			"employeenumber": schema.StringAttribute{
+				Description: "Employee number of the users",
+				Optional:    true,
+			},
+			"employeetype": schema.StringAttribute{
+				Description: "Employee type of the users",
+				Optional:    true,
+			},
+			"ou": schema.StringAttribute{
+				Description: "Organisational unit of the users",
+				Optional:    true,
+			},
+			"title": schema.StringAttribute{
+				Description: "Job title of the users",
+				Optional:    true,
+			},
+			"in_group": schema.StringAttribute{
+				Description: "Group the users are members of, directly or through nested groups",
+				Optional:    true,
+			},
+			"max": schema.Int64Attribute{
+				Description: "Maximum number of matching users, reading the data source fails when more users match. Set it to 1 to look up a single user. Unlimited when unset",
+				Optional:    true,
+			},
+			"uids": schema.ListAttribute{
+				Description: "Logins of the matching users, sorted",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *Users) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config UsersModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Criteria.IsNull() && config.Mail.IsNull() && config.EmployeeNumber.IsNull() && config.EmployeeType.IsNull() &&
+		config.OrgUnit.IsNull() && config.Title.IsNull() && config.InGroup.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("criteria"),
+			"Invalid configuration",
+			`At least one of “criteria”, “mail”, “employeenumber”, “employeetype”, “ou”, “title” and “in_group” must be set.`,
+		)
+	}
+
+	if !config.Max.IsUnknown() && !config.Max.IsNull() && config.Max.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max"),
+			"Invalid configuration",
+			`“max” must be at least 1.`,
+		)
+	}
+}
+
+func (d *Users) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state UsersModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.UserFindArgs{}
+
+	optArgs := &freeipa.UserFindOptionalArgs{
+		Employeenumber: state.EmployeeNumber.ValueStringPointer(),
+		Employeetype:   state.EmployeeType.ValueStringPointer(),
+		Ou:             state.OrgUnit.ValueStringPointer(),
+		Title:          state.Title.ValueStringPointer(),
+		NoMembers:      freeipa.Bool(true),
+		Sizelimit:      freeipa.Int(0),
+	}
+
+	if !state.Mail.IsNull() {
+		optArgs.Mail = &[]string{state.Mail.ValueString()}
+	}
+
+	if !state.InGroup.IsNull() {
+		optArgs.InGroup = &[]string{state.InGroup.ValueString()}
+	}
+
+	// One more user than allowed is enough to tell the search is ambiguous
+	if !state.Max.IsNull() {
+		optArgs.Sizelimit = freeipa.Int(int(state.Max.ValueInt64()) + 1)
+	}
+
+	tflog.Trace(ctx, "Calling UserFind", map[string]any{
+		"criteria": state.Criteria.ValueString(),
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := d.provider.Client().UserFind(state.Criteria.ValueString(), args, optArgs)
+
+	tflog.Trace(ctx, "Called UserFind", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to search users", "Reason: "+err.Error())
+
+		return
+	}
+
+	uids := make([]string, 0, len(res.Result))
+
+	for _, user := range res.Result {
+		uids = append(uids, user.UID)
+	}
+
+	sort.Strings(uids)
+
+	if !state.Max.IsNull() && int64(len(uids)) > state.Max.ValueInt64() {
+		resp.Diagnostics.AddError(
+			"Ambiguous user search",
+			fmt.Sprintf("More users than the %d expected match the search, among them %s.", state.Max.ValueInt64(), strings.Join(uids, ", ")),
+		)
+
+		return
+	}
+
+	var diags diag.Diagnostics
+
+	state.UIDs, diags = types.ListValueFrom(ctx, types.StringType, uids)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewUsers(p *provider.Provider) datasource.DataSource {
+	d := &Users{
+		provider: p,
+	}
+
+	var _ datasource.DataSource = d
+	var _ datasource.DataSourceWithValidateConfig = d
+
+	return d
+}
+
+func init() {
+	dataSources = append(dataSources, NewUsers)
+}
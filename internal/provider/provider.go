@@ -4,15 +4,20 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -29,20 +34,36 @@ type Provider struct {
 	dataSources []func() datasource.DataSource
 	resources   []func() resource.Resource
 
-	client *freeipa.Client
+	client     *freeipa.Client
+	rpc        *utils.RPCClient
+	serverInfo utils.ServerInfoRecorder
+	settings   settings
 }
 
 type Model struct {
 	Host               types.String `tfsdk:"host"`
 	Username           types.String `tfsdk:"username"`
 	Password           types.String `tfsdk:"password"`
+	PasswordFile       types.String `tfsdk:"password_file"`
 	InsecureSkipVerify types.Bool   `tfsdk:"insecure"`
+	CACertificate      types.String `tfsdk:"ca_certificate"`
+	CACertificatePath  types.String `tfsdk:"ca_certificate_path"`
+	RequestTimeout     types.String `tfsdk:"request_timeout"`
+	MaxRetries         types.Int64  `tfsdk:"max_retries"`
+	MaxConcurrency     types.Int64  `tfsdk:"max_concurrent_requests"`
+	RetryBackoff       types.String `tfsdk:"retry_backoff"`
 	KerberosEnabled    types.Bool   `tfsdk:"kerberos_enabled"`
 	KerberosPrincipal  types.String `tfsdk:"kerberos_principal"`
 	KerberosRealm      types.String `tfsdk:"kerberos_realm"`
 	Krb5ConfPath       types.String `tfsdk:"krb5_conf_path"`
 	KeytabPath         types.String `tfsdk:"keytab_path"`
 	KeytabBase64       types.String `tfsdk:"keytab_base64"`
+	KeytabBase64File   types.String `tfsdk:"keytab_base64_file"`
+	KerberosCCache     types.String `tfsdk:"kerberos_ccache"`
+	PKINITCertPath     types.String `tfsdk:"pkinit_certificate_path"`
+	PKINITKeyPath      types.String `tfsdk:"pkinit_key_path"`
+	ReadOnly           types.Bool   `tfsdk:"read_only"`
+	Debug              types.Bool   `tfsdk:"debug"`
 }
 
 func (p *Provider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -55,7 +76,7 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
 				Optional:    true,
-				Description: "FreeIPA host to connect to",
+				Description: "FreeIPA host to connect to, as a host name (e.g. `ipa.example.com`) or an https URL",
 			},
 			"username": schema.StringAttribute{
 				Optional:    true,
@@ -65,21 +86,49 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 				Optional:    true,
 				Description: "Password to use for connection",
 			},
+			"password_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a file holding the password, read whenever the provider logs in. Surrounding whitespace is ignored, `password` takes precedence",
+			},
 			"insecure": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Set to true to disable FreeIPA host TLS certificate verification",
 			},
+			"ca_certificate": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded CA certificate(s) used to verify the FreeIPA host TLS certificate",
+			},
+			"ca_certificate_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM encoded CA certificate bundle used to verify the FreeIPA host TLS certificate",
+			},
+			"request_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "Timeout of a single request to FreeIPA as a duration string (e.g. `30s`). No timeout when unset.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of times a failed request to FreeIPA is retried. Defaults to 3.",
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of requests sent to FreeIPA at once, whatever the Terraform parallelism. Further requests wait for one to complete. Unlimited when unset or 0.",
+			},
+			"retry_backoff": schema.StringAttribute{
+				Optional:    true,
+				Description: "Delay before the first retry as a duration string, doubled on every attempt. Defaults to `1s`.",
+			},
 			"kerberos_enabled": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Use Kerberos/keytab authentication instead of username/password",
 			},
 			"kerberos_principal": schema.StringAttribute{
 				Optional:    true,
-				Description: "Kerberos principal to use when kerberos_enabled is true",
+				Description: "Kerberos principal to use when kerberos_enabled is true, defaults to the only principal of the keytab",
 			},
 			"kerberos_realm": schema.StringAttribute{
 				Optional:    true,
-				Description: "Kerberos realm to use when kerberos_enabled is true",
+				Description: "Kerberos realm to use when kerberos_enabled is true, uppercased. Defaults to the realm of the keytab principal, or to the uppercased domain of host when the keytab holds several realms",
 			},
 			"krb5_conf_path": schema.StringAttribute{
 				Optional:    true,
@@ -94,116 +143,287 @@ func (p *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp
 				Sensitive:   true,
 				Description: "Base64 encoded keytab content. When set it takes precedence over keytab_path.",
 			},
+			"keytab_base64_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a file holding the keytab, raw or base64 encoded, read whenever the provider logs in. It takes precedence over keytab_path, keytab_base64 takes precedence over it.",
+			},
+			"kerberos_ccache": schema.StringAttribute{
+				Optional:    true,
+				Description: "Kerberos credential cache holding the tickets of an earlier `kinit`, used instead of a keytab. Conflicts with keytab_path and keytab_base64.",
+			},
+			"pkinit_certificate_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM encoded certificate, such as the host certificate of an enrolled machine, with which the tickets of kerberos_principal are obtained through PKINIT instead of a keytab. Requires kerberos_enabled, pkinit_key_path and the `kinit` command of MIT Kerberos. Conflicts with the keytab arguments and kerberos_ccache.",
+			},
+			"pkinit_key_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to the unencrypted PEM encoded private key of pkinit_certificate_path.",
+			},
+			"read_only": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Refuse every FreeIPA call which may modify the server, e.g. to run plans with credentials which must never write. Applying a change fails without reaching FreeIPA. Defaults to false.",
+			},
+			"debug": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Log every FreeIPA call at the DEBUG level, with its method, its parameters with passwords, keytabs and secrets redacted, and its outcome. Calls are also logged when `TF_LOG` is `TRACE`. Defaults to false.",
+			},
 		},
 	}
 }
 
-func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
-	var config Model
+// settings are the provider arguments resolved from, in order of precedence,
+// the provider configuration, the FREEIPA_* environment variables and the
+// defaults.
+type settings struct {
+	Host               string
+	Username           string
+	Password           string
+	PasswordFile       string
+	InsecureSkipVerify bool
+	CACertificate      string
+	CACertificatePath  string
+	Retry              utils.RetryOptions
+	MaxConcurrency     int
+	KerberosEnabled    bool
+	KerberosPrincipal  string
+	KerberosRealm      string
+	Krb5ConfPath       string
+	KeytabPath         string
+	KeytabBase64       string
+	KeytabBase64File   string
+	KerberosCCache     string
+	PKINITCertPath     string
+	PKINITKeyPath      string
+	ReadOnly           bool
+	Debug              bool
+}
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+func resolveSettings(config Model) (settings, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var err error
 
-	if resp.Diagnostics.HasError() {
-		return
+	s := settings{
+		Host:              stringSetting(config.Host, "FREEIPA_HOST", ""),
+		Username:          stringSetting(config.Username, "FREEIPA_USERNAME", ""),
+		Password:          stringSetting(config.Password, "FREEIPA_PASSWORD", ""),
+		PasswordFile:      stringSetting(config.PasswordFile, "FREEIPA_PASSWORD_FILE", ""),
+		CACertificate:     stringSetting(config.CACertificate, "FREEIPA_CA_CERTIFICATE", ""),
+		CACertificatePath: stringSetting(config.CACertificatePath, "FREEIPA_CA_CERTIFICATE_PATH", ""),
+		KerberosPrincipal: stringSetting(config.KerberosPrincipal, "FREEIPA_KERBEROS_PRINCIPAL", ""),
+		KerberosRealm:     stringSetting(config.KerberosRealm, "FREEIPA_KERBEROS_REALM", ""),
+		Krb5ConfPath:      stringSetting(config.Krb5ConfPath, "FREEIPA_KRB5_CONF", "/etc/krb5.conf"),
+		KeytabPath:        stringSetting(config.KeytabPath, "FREEIPA_KEYTAB", "/etc/krb5.keytab"),
+		KeytabBase64:      stringSetting(config.KeytabBase64, "FREEIPA_KEYTAB_BASE64", ""),
+		KeytabBase64File:  stringSetting(config.KeytabBase64File, "FREEIPA_KEYTAB_BASE64_FILE", ""),
+		KerberosCCache:    stringSetting(config.KerberosCCache, "FREEIPA_KERBEROS_CCACHE", ""),
+		PKINITCertPath:    stringSetting(config.PKINITCertPath, "FREEIPA_PKINIT_CERTIFICATE_PATH", ""),
+		PKINITKeyPath:     stringSetting(config.PKINITKeyPath, "FREEIPA_PKINIT_KEY_PATH", ""),
+		Retry: utils.RetryOptions{
+			MaxRetries: utils.DefaultMaxRetries,
+			Backoff:    utils.DefaultRetryBackoff,
+		},
 	}
 
-	host := os.Getenv("FREEIPA_HOST")
-	username := os.Getenv("FREEIPA_USERNAME")
-	password := os.Getenv("FREEIPA_PASSWORD")
-	insecureSkipVerify := false
+	if s.Host != "" {
+		if s.Host, err = utils.NormalizeHost(s.Host); err != nil {
+			diags.AddAttributeError(path.Root("host"), "Invalid FreeIPA host",
+				"Expected a host name such as “ipa.example.com” or an https URL. Reason: "+err.Error(),
+			)
+		}
+	}
 
-	if !config.Host.IsNull() {
-		host = config.Host.ValueString()
+	// Kerberos realms are uppercase by convention, a mismatching case makes the
+	// login fail with an unhelpful KDC error
+	if realm := strings.ToUpper(s.KerberosRealm); realm != s.KerberosRealm {
+		diags.AddAttributeWarning(path.Root("kerberos_realm"), "Kerberos realm is not uppercase",
+			fmt.Sprintf("The Kerberos realm %q is used as %q.", s.KerberosRealm, realm),
+		)
+		s.KerberosRealm = realm
 	}
 
-	if !config.Username.IsNull() {
-		username = config.Username.ValueString()
+	if s.InsecureSkipVerify, err = boolSetting(config.InsecureSkipVerify, "FREEIPA_INSECURE", false); err != nil {
+		diags.AddAttributeError(path.Root("insecure"), "Invalid insecure", "Reason: "+err.Error())
 	}
 
-	if !config.Password.IsNull() {
-		password = config.Password.ValueString()
+	if s.KerberosEnabled, err = boolSetting(config.KerberosEnabled, "FREEIPA_KERBEROS_ENABLED", false); err != nil {
+		diags.AddAttributeError(path.Root("kerberos_enabled"), "Invalid kerberos enabled", "Reason: "+err.Error())
 	}
 
-	if !config.InsecureSkipVerify.IsNull() {
-		insecureSkipVerify = config.InsecureSkipVerify.ValueBool()
+	if s.ReadOnly, err = boolSetting(config.ReadOnly, "FREEIPA_READ_ONLY", false); err != nil {
+		diags.AddAttributeError(path.Root("read_only"), "Invalid read only", "Reason: "+err.Error())
 	}
 
-	kerberosEnabled := false
-	if !config.KerberosEnabled.IsNull() {
-		kerberosEnabled = config.KerberosEnabled.ValueBool()
-	} else if os.Getenv("FREEIPA_KERBEROS_ENABLED") == "true" {
-		kerberosEnabled = true
+	if s.Debug, err = boolSetting(config.Debug, "FREEIPA_DEBUG", false); err != nil {
+		diags.AddAttributeError(path.Root("debug"), "Invalid debug", "Reason: "+err.Error())
 	}
 
-	kerberosPrincipal := os.Getenv("FREEIPA_KERBEROS_PRINCIPAL")
-	if !config.KerberosPrincipal.IsNull() {
-		kerberosPrincipal = config.KerberosPrincipal.ValueString()
+	if v := stringSetting(config.RequestTimeout, "FREEIPA_REQUEST_TIMEOUT", ""); v != "" {
+		if s.Retry.RequestTimeout, err = time.ParseDuration(v); err != nil {
+			diags.AddAttributeError(path.Root("request_timeout"), "Invalid request timeout", "Reason: "+err.Error())
+		}
 	}
 
-	kerberosRealm := os.Getenv("FREEIPA_KERBEROS_REALM")
-	if !config.KerberosRealm.IsNull() {
-		kerberosRealm = config.KerberosRealm.ValueString()
+	if !config.MaxRetries.IsNull() {
+		s.Retry.MaxRetries = int(config.MaxRetries.ValueInt64())
+	} else if v := os.Getenv("FREEIPA_MAX_RETRIES"); v != "" {
+		if s.Retry.MaxRetries, err = strconv.Atoi(v); err != nil {
+			diags.AddAttributeError(path.Root("max_retries"), "Invalid max retries", "Reason: "+err.Error())
+		}
+	}
+	if s.Retry.MaxRetries < 0 {
+		diags.AddAttributeError(path.Root("max_retries"), "Invalid max retries",
+			`max_retries must not be negative.`,
+		)
 	}
 
-	krb5ConfPath := os.Getenv("FREEIPA_KRB5_CONF")
-	if krb5ConfPath == "" {
-		krb5ConfPath = "/etc/krb5.conf"
+	if !config.MaxConcurrency.IsNull() {
+		s.MaxConcurrency = int(config.MaxConcurrency.ValueInt64())
+	} else if v := os.Getenv("FREEIPA_MAX_CONCURRENT_REQUESTS"); v != "" {
+		if s.MaxConcurrency, err = strconv.Atoi(v); err != nil {
+			diags.AddAttributeError(path.Root("max_concurrent_requests"), "Invalid max concurrent requests", "Reason: "+err.Error())
+		}
 	}
-	if !config.Krb5ConfPath.IsNull() {
-		krb5ConfPath = config.Krb5ConfPath.ValueString()
+	if s.MaxConcurrency < 0 {
+		diags.AddAttributeError(path.Root("max_concurrent_requests"), "Invalid max concurrent requests",
+			`max_concurrent_requests must not be negative.`,
+		)
 	}
 
-	keytabPath := os.Getenv("FREEIPA_KEYTAB")
-	if keytabPath == "" {
-		keytabPath = "/etc/krb5.keytab"
+	if v := stringSetting(config.RetryBackoff, "FREEIPA_RETRY_BACKOFF", ""); v != "" {
+		if s.Retry.Backoff, err = time.ParseDuration(v); err != nil {
+			diags.AddAttributeError(path.Root("retry_backoff"), "Invalid retry backoff", "Reason: "+err.Error())
+		}
 	}
-	if !config.KeytabPath.IsNull() {
-		keytabPath = config.KeytabPath.ValueString()
+
+	// An explicitly configured keytab takes precedence over the ambient
+	// credential cache of KRB5CCNAME.
+	keytabConfigured := !config.KeytabPath.IsNull() || !config.KeytabBase64.IsNull() || !config.KeytabBase64File.IsNull() ||
+		os.Getenv("FREEIPA_KEYTAB") != "" || os.Getenv("FREEIPA_KEYTAB_BASE64") != "" || os.Getenv("FREEIPA_KEYTAB_BASE64_FILE") != ""
+
+	if s.KerberosEnabled && s.KerberosCCache != "" && keytabConfigured {
+		diags.AddAttributeError(path.Root("kerberos_ccache"), "Conflicting Kerberos credentials",
+			`kerberos_ccache cannot be used together with keytab_path, keytab_base64 or keytab_base64_file.`,
+		)
 	}
 
-	keytabBase64 := os.Getenv("FREEIPA_KEYTAB_BASE64")
-	if !config.KeytabBase64.IsNull() {
-		keytabBase64 = config.KeytabBase64.ValueString()
+	if s.PKINITCertPath == "" && s.PKINITKeyPath != "" {
+		diags.AddAttributeError(path.Root("pkinit_certificate_path"), "Missing PKINIT certificate",
+			`pkinit_key_path requires pkinit_certificate_path.`,
+		)
+	} else if s.PKINITCertPath != "" && s.PKINITKeyPath == "" {
+		diags.AddAttributeError(path.Root("pkinit_key_path"), "Missing PKINIT key",
+			`pkinit_certificate_path requires pkinit_key_path.`,
+		)
 	}
 
-	if host == "" {
-		resp.Diagnostics.AddAttributeError(path.Root("host"), "Missing FreeIPA host",
-			`Host is required to establish a connection to FreeIPA.`,
+	if s.KerberosEnabled && s.pkinit() && (s.KerberosCCache != "" || keytabConfigured) {
+		diags.AddAttributeError(path.Root("pkinit_certificate_path"), "Conflicting Kerberos credentials",
+			`pkinit_certificate_path cannot be used together with kerberos_ccache, keytab_path, keytab_base64 or keytab_base64_file.`,
 		)
 	}
 
-	if kerberosEnabled {
-		if keytabBase64 == "" && keytabPath == "" {
-			resp.Diagnostics.AddAttributeError(path.Root("keytab_path"), "Missing keytab information",
-				`When kerberos_enabled is true you must set either keytab_path or keytab_base64.`,
-			)
+	if s.KerberosCCache == "" && !keytabConfigured && !s.pkinit() {
+		s.KerberosCCache = os.Getenv("KRB5CCNAME")
+	}
+
+	return s, diags
+}
+
+// pkinit reports whether the Kerberos tickets are obtained with PKINIT.
+func (s settings) pkinit() bool {
+	return s.PKINITCertPath != "" || s.PKINITKeyPath != ""
+}
+
+// pkinitOptions returns the PKINIT credentials of the provider.
+func (s settings) pkinitOptions() utils.PKINITOptions {
+	return utils.PKINITOptions{
+		Principal:       s.KerberosPrincipal,
+		Realm:           s.KerberosRealm,
+		CertificatePath: s.PKINITCertPath,
+		KeyPath:         s.PKINITKeyPath,
+	}
+}
+
+// stringSetting returns the configured value, or the value of the env
+// environment variable, or def when neither is set.
+func stringSetting(v types.String, env, def string) string {
+	if !v.IsNull() {
+		return v.ValueString()
+	}
+	if e := os.Getenv(env); e != "" {
+		return e
+	}
+	return def
+}
+
+// boolSetting is the boolean counterpart of stringSetting, the environment
+// variable accepts the values of strconv.ParseBool.
+func boolSetting(v types.Bool, env string, def bool) (bool, error) {
+	if !v.IsNull() {
+		return v.ValueBool(), nil
+	}
+	if e := os.Getenv(env); e != "" {
+		b, err := strconv.ParseBool(e)
+		if err != nil {
+			return def, fmt.Errorf("%s must be a boolean, got %q", env, e)
 		}
+		return b, nil
+	}
+	return def, nil
+}
+
+func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config Model
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		if kerberosPrincipal == "" {
+	s, diags := resolveSettings(config)
+	resp.Diagnostics.Append(diags...)
+
+	if s.Host == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("host"), "Missing FreeIPA host",
+			`Host is required to establish a connection to FreeIPA.`,
+		)
+	}
+
+	if s.KerberosEnabled && s.pkinit() {
+		if s.KerberosPrincipal == "" {
 			resp.Diagnostics.AddAttributeError(path.Root("kerberos_principal"), "Missing Kerberos principal",
-				`Kerberos principal is required when kerberos_enabled is true.`,
+				`kerberos_principal is required to authenticate with PKINIT.`,
 			)
 		}
-		if kerberosRealm == "" {
-			resp.Diagnostics.AddAttributeError(path.Root("kerberos_realm"), "Missing Kerberos realm",
-				`Kerberos realm is required when kerberos_enabled is true.`,
+	} else if s.KerberosEnabled && s.KerberosCCache == "" {
+		if s.KeytabBase64 == "" && s.KeytabBase64File == "" && s.KeytabPath == "" {
+			resp.Diagnostics.AddAttributeError(path.Root("keytab_path"), "Missing keytab information",
+				`When kerberos_enabled is true you must set either keytab_path or keytab_base64.`,
 			)
 		}
-		if keytabPath == "" {
+
+		if s.KeytabPath == "" {
 			resp.Diagnostics.AddAttributeError(path.Root("keytab_path"), "Missing keytab path",
 				`Path to keytab file is required when kerberos_enabled is true.`,
 			)
 		}
-	} else {
-		if username == "" {
+	} else if !s.KerberosEnabled {
+		if s.pkinit() {
+			resp.Diagnostics.AddAttributeError(path.Root("kerberos_enabled"), "PKINIT requires Kerberos",
+				`kerberos_enabled must be true to authenticate with pkinit_certificate_path.`,
+			)
+		}
+
+		if s.Username == "" {
 			resp.Diagnostics.AddAttributeError(path.Root("username"), "Missing FreeIPA username",
 				`Username is required to establish a connection to FreeIPA.`,
 			)
 		}
 
-		if password == "" {
+		if s.Password == "" && s.PasswordFile == "" {
 			resp.Diagnostics.AddAttributeError(path.Root("password"), "Missing FreeIPA password",
-				`Password is required to establish a connection to FreeIPA.`,
+				`Password or password_file is required to establish a connection to FreeIPA.`,
 			)
 		}
 	}
@@ -212,55 +432,143 @@ func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest,
 		return
 	}
 
-	tspt := &http.Transport{
+	tflog.Debug(ctx, "Resolved FreeIPA host", map[string]any{
+		"host": s.Host,
+	})
+
+	rootCAs, err := loadCACertPool(s.CACertificate, s.CACertificatePath)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to load CA certificate", "Reason: "+err.Error())
+		return
+	}
+
+	tspt := utils.NewRetryTransport(utils.NewConcurrencyTransport(&http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: insecureSkipVerify,
+			InsecureSkipVerify: s.InsecureSkipVerify,
+			RootCAs:            rootCAs,
 		},
+	}, s.MaxConcurrency), s.Retry)
+	if s.ReadOnly {
+		tspt = utils.NewReadOnlyTransport(tspt)
+	}
+	tspt = utils.NewResultFixupTransport(tspt)
+	tspt = utils.NewServerInfoTransport(tspt, &p.serverInfo)
+	if utils.DebugLoggingEnabled(s.Debug) {
+		tspt = utils.NewDebugTransport(ctx, tspt, s.Debug)
 	}
 
-	var err error
+	session := utils.NewSessionTransport(tspt)
+
+	var summary string
+
+	p.client, summary, err = s.connect(session)
+	if err != nil {
+		resp.Diagnostics.AddError(summary, "Reason: "+err.Error())
+		return
+	}
+
+	p.rpc = utils.NewRPCClient(s.Host, session)
+	p.settings = s
+
+	// Logging in again reads the credentials again, e.g. a credential cache
+	// renewed since the provider was configured
+	session.Reconnect = func() error {
+		_, _, err := s.connect(session)
+		return err
+	}
+
+	tflog.Info(ctx, "Successfully connected to FreeIPA", map[string]any{
+		"host":             s.Host,
+		"username":         s.Username,
+		"kerberos_enabled": s.KerberosEnabled,
+		"pkinit":           s.pkinit(),
+		"read_only":        s.ReadOnly,
+		"debug":            s.Debug,
+	})
 
-	if kerberosEnabled {
-		krb5ConfFile, err := os.Open(krb5ConfPath)
+	// The server information is recorded by the transport from the response
+	if _, err := p.client.Ping(&freeipa.PingArgs{}, nil); err != nil {
+		resp.Diagnostics.AddWarning("Failed to read FreeIPA server information", "Reason: "+err.Error())
+		return
+	}
+
+	tflog.Debug(ctx, "Read FreeIPA server information", map[string]any{
+		"server_info": p.serverInfo.Info(),
+	})
+}
+
+// connect connects a new client to FreeIPA through tspt. The returned summary
+// describes the step which failed.
+func (s settings) connect(tspt http.RoundTripper) (*freeipa.Client, string, error) {
+	if s.KerberosEnabled && s.pkinit() {
+		client, err := utils.ConnectWithPKINIT(s.Host, tspt, s.Krb5ConfPath, s.pkinitOptions())
+		if err != nil {
+			return nil, "Failed to connect to FreeIPA with PKINIT", err
+		}
+		return client, "", nil
+	} else if s.KerberosEnabled && s.KerberosCCache != "" {
+		krb5ConfFile, err := os.Open(s.Krb5ConfPath)
+		if err != nil {
+			return nil, "Failed to open krb5.conf", err
+		}
+		defer krb5ConfFile.Close()
+
+		client, err := utils.ConnectWithKerberosCCache(s.Host, tspt, krb5ConfFile, s.KerberosCCache)
+		if err != nil {
+			return nil, "Failed to connect to FreeIPA", err
+		}
+		return client, "", nil
+	} else if s.KerberosEnabled {
+		krb5ConfFile, err := os.Open(s.Krb5ConfPath)
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to open krb5.conf", "Reason: "+err.Error())
-			return
+			return nil, "Failed to open krb5.conf", err
 		}
 		defer krb5ConfFile.Close()
 
-		keytabReader, err := openKeytabReader(keytabPath, keytabBase64)
+		keytabReader, err := openKeytabReader(s.KeytabPath, s.KeytabBase64, s.KeytabBase64File)
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to load keytab", "Reason: "+err.Error())
-			return
+			return nil, "Failed to load keytab", err
 		}
 		defer keytabReader.Close()
 
+		keytab, err := io.ReadAll(keytabReader)
+		if err != nil {
+			return nil, "Failed to load keytab", err
+		}
+
+		principal, realm, err := utils.KerberosIdentity(keytab, s.KerberosPrincipal, s.KerberosRealm, s.Host)
+		if err != nil {
+			return nil, "Failed to find the Kerberos principal", err
+		}
+
 		kerberosOpts := &freeipa.KerberosConnectOptions{
 			Krb5ConfigReader: krb5ConfFile,
-			KeytabReader:     keytabReader,
-			Username:         kerberosPrincipal,
-			Realm:            kerberosRealm,
+			KeytabReader:     bytes.NewReader(keytab),
+			Username:         principal,
+			Realm:            realm,
 		}
 
-		p.client, err = freeipa.ConnectWithKerberos(host, tspt, kerberosOpts)
+		client, err := freeipa.ConnectWithKerberos(s.Host, tspt, kerberosOpts)
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to connect to FreeIPA", "Reason: "+err.Error())
-			return
+			return nil, "Failed to connect to FreeIPA", err
 		}
-	} else {
-		p.client, err = freeipa.Connect(host, tspt, username, password)
-		if err != nil {
-			resp.Diagnostics.AddError("Failed to connect to FreeIPA", "Reason: "+err.Error())
-			return
+		return client, "", nil
+	}
+
+	password := s.Password
+	if password == "" && s.PasswordFile != "" {
+		var err error
+		if password, err = utils.ReadSecretFile(s.PasswordFile); err != nil {
+			return nil, "Failed to read password file", err
 		}
 	}
 
-	tflog.Info(ctx, "Successfully connected to FreeIPA", map[string]any{
-		"host":             host,
-		"username":         username,
-		"kerberos_enabled": kerberosEnabled,
-	})
+	client, err := freeipa.Connect(s.Host, tspt, s.Username, password)
+	if err != nil {
+		return nil, "Failed to connect to FreeIPA", err
+	}
+	return client, "", nil
 }
 
 func (p *Provider) DataSources(ctx context.Context) []func() datasource.DataSource {
@@ -275,6 +583,64 @@ func (p *Provider) Client() *freeipa.Client {
 	return p.client
 }
 
+// RPC returns the client sending the calls go-freeipa does not implement, with
+// the session of Client.
+func (p *Provider) RPC() *utils.RPCClient {
+	return p.rpc
+}
+
+// ServerInfo returns the information about the FreeIPA server recorded while
+// configuring the provider.
+func (p *Provider) ServerInfo() utils.ServerInfo {
+	return p.serverInfo.Info()
+}
+
+// GetKeytab generates new keys for the principal and returns them as a
+// keytab, authenticating with the Kerberos credentials of the provider.
+func (p *Provider) GetKeytab(ctx context.Context, principal string) ([]byte, error) {
+	s := p.settings
+
+	if s.ReadOnly {
+		return nil, fmt.Errorf("%w, refusing to generate the keys of %s", utils.ErrReadOnly, principal)
+	}
+
+	if !s.KerberosEnabled {
+		return nil, fmt.Errorf("retrieving keytabs requires kerberos_enabled")
+	}
+
+	options := utils.GetKeytabOptions{
+		Server:       s.Host,
+		Krb5ConfPath: s.Krb5ConfPath,
+		CCache:       s.KerberosCCache,
+	}
+
+	if s.pkinit() {
+		pkinit := s.pkinitOptions()
+		options.PKINIT = &pkinit
+	} else if options.CCache == "" {
+		keytabReader, err := openKeytabReader(s.KeytabPath, s.KeytabBase64, s.KeytabBase64File)
+		if err != nil {
+			return nil, err
+		}
+		defer keytabReader.Close()
+
+		if options.ClientKeytab, err = io.ReadAll(keytabReader); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.CACertificate != "" {
+		options.CACertificate = []byte(s.CACertificate)
+	} else if s.CACertificatePath != "" {
+		var err error
+		if options.CACertificate, err = os.ReadFile(s.CACertificatePath); err != nil {
+			return nil, err
+		}
+	}
+
+	return utils.GetKeytab(ctx, options, principal)
+}
+
 func NewFactory(ds []func(p *Provider) datasource.DataSource, rs []func(p *Provider) resource.Resource) func() provider.Provider {
 	return func() provider.Provider {
 		p := &Provider{}
@@ -305,7 +671,7 @@ func NewFactory(ds []func(p *Provider) datasource.DataSource, rs []func(p *Provi
 	}
 }
 
-func openKeytabReader(path, b64 string) (io.ReadCloser, error) {
+func openKeytabReader(path, b64, b64File string) (io.ReadCloser, error) {
 	if b64 != "" {
 		clean := compactBase64Whitespace(b64)
 		decoded, err := base64.StdEncoding.DecodeString(clean)
@@ -315,6 +681,14 @@ func openKeytabReader(path, b64 string) (io.ReadCloser, error) {
 		return io.NopCloser(bytes.NewReader(decoded)), nil
 	}
 
+	if b64File != "" {
+		data, err := utils.ReadKeytabFile(b64File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keytab_base64_file: %w", err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
 	if path == "" {
 		return nil, fmt.Errorf("keytab_path is empty")
 	}
@@ -326,6 +700,32 @@ func openKeytabReader(path, b64 string) (io.ReadCloser, error) {
 	return file, nil
 }
 
+// loadCACertPool builds a certificate pool from the PEM content and the PEM
+// file, returning nil when neither is set so the system roots are used.
+func loadCACertPool(pemContent, path string) (*x509.CertPool, error) {
+	if pemContent == "" && path == "" {
+		return nil, nil
+	}
+
+	pool := x509.NewCertPool()
+
+	if pemContent != "" && !pool.AppendCertsFromPEM([]byte(pemContent)) {
+		return nil, fmt.Errorf("no valid PEM certificate found in ca_certificate")
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid PEM certificate found in %s", path)
+		}
+	}
+
+	return pool, nil
+}
+
 func compactBase64Whitespace(s string) string {
 	for i := 0; i < len(s); i++ {
 		switch s[i] {
@@ -0,0 +1,271 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+var automemberTypes = []string{"group", "hostgroup"}
+
+type AutomemberDefaultGroup struct {
+	provider *provider.Provider
+}
+
+type AutomemberDefaultGroupModel struct {
+	Type         types.String `tfsdk:"type"`
+	DefaultGroup types.String `tfsdk:"automemberdefaultgroup"`
+}
+
+func (r *AutomemberDefaultGroup) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_automember_default_group"
+}
+
+func (r *AutomemberDefaultGroup) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages the group or host group receiving the entries matching no automember rule. The resource must be imported.",
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Description: "Type of the automember rules, `group` for users or `hostgroup` for hosts",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"automemberdefaultgroup": schema.StringAttribute{
+				Description: "Group or host group the entries matching no automember rule are added to, none when unset",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *AutomemberDefaultGroup) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config AutomemberDefaultGroupModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Type.IsUnknown() && !config.Type.IsNull() && !slices.Contains(automemberTypes, config.Type.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid configuration",
+			`The automember type must be either “group” or “hostgroup”.`,
+		)
+	}
+}
+
+func (r *AutomemberDefaultGroup) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AutomemberDefaultGroupModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	requireSingletonImport(
+		&resp.Diagnostics,
+		"Automember default group",
+		"default group setting of the “"+plan.Type.ValueString()+"” automember rules",
+		plan.Type.ValueString(),
+	)
+}
+
+func (r *AutomemberDefaultGroup) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AutomemberDefaultGroupModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.AutomemberDefaultGroupShowArgs{
+		Type: state.Type.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling AutomemberDefaultGroupShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().AutomemberDefaultGroupShow(args, nil)
+
+	tflog.Trace(ctx, "Called AutomemberDefaultGroupShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		// No default group is set
+		if !utils.IsNotFoundError(err) {
+			resp.Diagnostics.AddError("Failed to read automember default group", "Reason: "+err.Error())
+
+			return
+		}
+
+		state.DefaultGroup = types.StringNull()
+	} else {
+		state.DefaultGroup = automemberDefaultGroupName(res.Result.Automemberdefaultgroup)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *AutomemberDefaultGroup) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan AutomemberDefaultGroupModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.DefaultGroup.Equal(state.DefaultGroup) {
+		tflog.Debug(ctx, "Updated automember default group has no effective difference", map[string]any{
+			"type": plan.Type.ValueString(),
+		})
+	} else if plan.DefaultGroup.IsNull() {
+		if err := r.remove(ctx, plan.Type.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Failed to remove automember default group", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		args := &freeipa.AutomemberDefaultGroupSetArgs{
+			Type:                   plan.Type.ValueString(),
+			Automemberdefaultgroup: plan.DefaultGroup.ValueString(),
+		}
+
+		tflog.Trace(ctx, "Calling AutomemberDefaultGroupSet", map[string]any{
+			"args":     args,
+			"opt_args": nil,
+		})
+
+		res, err := r.provider.Client().AutomemberDefaultGroupSet(args, nil)
+
+		tflog.Trace(ctx, "Called AutomemberDefaultGroupSet", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to set automember default group", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *AutomemberDefaultGroup) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AutomemberDefaultGroupModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.DefaultGroup.IsNull() {
+		return
+	}
+
+	if err := r.remove(ctx, state.Type.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to remove automember default group", "Reason: "+err.Error())
+	}
+}
+
+func (r *AutomemberDefaultGroup) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !slices.Contains(automemberTypes, req.ID) {
+		resp.Diagnostics.AddError("Invalid ID format", "Expected ID is either “group” or “hostgroup”")
+
+		return
+	}
+
+	// The default group is read from the server after the import
+	state := AutomemberDefaultGroupModel{
+		Type:         types.StringValue(req.ID),
+		DefaultGroup: types.StringNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// remove unsets the default group of the automember rules of automemberType.
+func (r *AutomemberDefaultGroup) remove(ctx context.Context, automemberType string) error {
+	args := &freeipa.AutomemberDefaultGroupRemoveArgs{
+		Type: automemberType,
+	}
+
+	tflog.Trace(ctx, "Calling AutomemberDefaultGroupRemove", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().AutomemberDefaultGroupRemove(args, nil)
+
+	tflog.Trace(ctx, "Called AutomemberDefaultGroupRemove", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	// The default group may already be unset
+	var freeipaErr *freeipa.Error
+	if errors.As(err, &freeipaErr) && (freeipaErr.Code == freeipa.NotFoundCode || freeipaErr.Code == utils.EmptyModlistCode) {
+		return nil
+	}
+
+	return err
+}
+
+// automemberDefaultGroupName returns the name of the default group, which
+// FreeIPA may return as the DN of the group.
+func automemberDefaultGroupName(v *string) types.String {
+	if v == nil || *v == "" {
+		return types.StringNull()
+	}
+
+	name := *v
+
+	if rdn, _, ok := strings.Cut(name, ","); ok && strings.HasPrefix(strings.ToLower(rdn), "cn=") {
+		name = rdn[len("cn="):]
+	}
+
+	return types.StringValue(name)
+}
+
+func NewAutomemberDefaultGroup(p *provider.Provider) resource.Resource {
+	r := &AutomemberDefaultGroup{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewAutomemberDefaultGroup)
+}
@@ -0,0 +1,198 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type AutomemberRebuild struct {
+	provider *provider.Provider
+}
+
+type AutomemberRebuildModel struct {
+	Type     types.String `tfsdk:"type"`
+	Users    types.Set    `tfsdk:"users"`
+	Hosts    types.Set    `tfsdk:"hosts"`
+	Triggers types.Map    `tfsdk:"triggers"`
+}
+
+func (r *AutomemberRebuild) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_automember_rebuild"
+}
+
+func (r *AutomemberRebuild) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Rebuilds the automember memberships of users or hosts when the resource is created or its arguments change.",
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Description: "Rebuild the memberships of all users (`group`) or of all hosts (`hostgroup`)",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"users": schema.SetAttribute{
+				Description: "Only rebuild the memberships of these users. Conflicts with `hosts`",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"hosts": schema.SetAttribute{
+				Description: "Only rebuild the memberships of these hosts. Conflicts with `users`",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Description: "Arbitrary values, such as the IDs of automember rules, rebuilding the memberships again when they change",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AutomemberRebuild) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config AutomemberRebuildModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Type.IsNull() && config.Users.IsNull() && config.Hosts.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid configuration",
+			`At least one of “type”, “users” and “hosts” must be set.`,
+		)
+	}
+
+	if !config.Users.IsNull() && !config.Hosts.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hosts"),
+			"Invalid configuration",
+			`“users” and “hosts” cannot be set together.`,
+		)
+	}
+
+	if config.Type.IsUnknown() || config.Type.IsNull() {
+		return
+	}
+
+	switch t := config.Type.ValueString(); {
+	case !slices.Contains(automemberTypes, t):
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid configuration",
+			`The automember type must be either “group” or “hostgroup”.`,
+		)
+	case t == "group" && !config.Hosts.IsNull(), t == "hostgroup" && !config.Users.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid configuration",
+			`“users” require the “group” type and “hosts” the “hostgroup” type.`,
+		)
+	}
+}
+
+func (r *AutomemberRebuild) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AutomemberRebuildModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.AutomemberRebuildArgs{}
+
+	// The command only returns once the rebuild task completed
+	optArgs := &freeipa.AutomemberRebuildOptionalArgs{
+		Type:  plan.Type.ValueStringPointer(),
+		Users: setToStringSlicePointer(ctx, plan.Users, &resp.Diagnostics),
+		Hosts: setToStringSlicePointer(ctx, plan.Hosts, &resp.Diagnostics),
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Calling AutomemberRebuild", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().AutomemberRebuild(args, optArgs)
+
+	tflog.Trace(ctx, "Called AutomemberRebuild", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to rebuild automember memberships", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *AutomemberRebuild) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The rebuild leaves nothing to read back, the state is kept as is
+}
+
+func (r *AutomemberRebuild) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan AutomemberRebuildModel
+
+	// Every argument replaces the resource, the plan is only stored
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *AutomemberRebuild) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The memberships are left untouched, the resource is only removed from
+	// the state
+}
+
+func NewAutomemberRebuild(p *provider.Provider) resource.Resource {
+	r := &AutomemberRebuild{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewAutomemberRebuild)
+}
@@ -0,0 +1,276 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type AutomountKey struct {
+	provider *provider.Provider
+}
+
+type AutomountKeyModel struct {
+	Location    types.String `tfsdk:"automountlocationcn"`
+	Map         types.String `tfsdk:"automountmapname"`
+	Key         types.String `tfsdk:"automountkey"`
+	Information types.String `tfsdk:"automountinformation"`
+}
+
+func (r *AutomountKey) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_automount_key"
+}
+
+func (r *AutomountKey) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a key of a FreeIPA automount map.",
+		Attributes: map[string]schema.Attribute{
+			"automountlocationcn": schema.StringAttribute{
+				Description: "Automount location of the map",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"automountmapname": schema.StringAttribute{
+				Description: "Automount map the key belongs to",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"automountkey": schema.StringAttribute{
+				Description: "Automount key (e.g. a directory name, or `*` for a wildcard key)",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"automountinformation": schema.StringAttribute{
+				Description: "Mount information (e.g. `-rw,soft nfs.example.com:/exports/home/&`)",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (r *AutomountKey) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AutomountKeyModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.AutomountkeyAddArgs{
+		Automountlocationcn:          plan.Location.ValueString(),
+		Automountmapautomountmapname: plan.Map.ValueString(),
+		Automountkey:                 plan.Key.ValueString(),
+		Automountinformation:         plan.Information.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling AutomountkeyAdd", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().AutomountkeyAdd(args, nil)
+
+	tflog.Trace(ctx, "Called AutomountkeyAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create automount key", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *AutomountKey) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AutomountKeyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.AutomountkeyShowArgs{
+		Automountlocationcn:          state.Location.ValueString(),
+		Automountmapautomountmapname: state.Map.ValueString(),
+		Automountkey:                 state.Key.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling AutomountkeyShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().AutomountkeyShow(args, nil)
+
+	tflog.Trace(ctx, "Called AutomountkeyShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read automount key", "Reason: "+err.Error())
+
+		return
+	}
+
+	state.Information = types.StringValue(res.Result.Automountinformation)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *AutomountKey) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan AutomountKeyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Information.Equal(state.Information) {
+		args := &freeipa.AutomountkeyModArgs{
+			Automountlocationcn:          plan.Location.ValueString(),
+			Automountmapautomountmapname: plan.Map.ValueString(),
+			Automountkey:                 plan.Key.ValueString(),
+		}
+
+		// The current information identifies the key among homonyms
+		optArgs := &freeipa.AutomountkeyModOptionalArgs{
+			Automountinformation:    state.Information.ValueStringPointer(),
+			Newautomountinformation: plan.Information.ValueStringPointer(),
+		}
+
+		tflog.Trace(ctx, "Calling AutomountkeyMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().AutomountkeyMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called AutomountkeyMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update automount key", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated automount key has no effective difference", map[string]any{
+			"automountlocationcn": plan.Location.ValueString(),
+			"automountmapname":    plan.Map.ValueString(),
+			"automountkey":        plan.Key.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *AutomountKey) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AutomountKeyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.AutomountkeyDelArgs{
+		Automountlocationcn:          state.Location.ValueString(),
+		Automountmapautomountmapname: state.Map.ValueString(),
+		Automountkey:                 state.Key.ValueString(),
+	}
+
+	optArgs := &freeipa.AutomountkeyDelOptionalArgs{
+		Automountinformation: state.Information.ValueStringPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling AutomountkeyDel", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().AutomountkeyDel(args, optArgs)
+
+	tflog.Trace(ctx, "Called AutomountkeyDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete automount key", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *AutomountKey) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Keys may be paths, everything after the map name is the key
+	parts := strings.SplitN(req.ID, "/", 3)
+
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<location>/<map>/<key>”, got %q.", req.ID),
+		)
+
+		return
+	}
+
+	state := AutomountKeyModel{
+		Location: types.StringValue(parts[0]),
+		Map:      types.StringValue(parts[1]),
+		Key:      types.StringValue(parts[2]),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewAutomountKey(p *provider.Provider) resource.Resource {
+	r := &AutomountKey{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewAutomountKey)
+}
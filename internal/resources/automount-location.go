@@ -0,0 +1,180 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type AutomountLocation struct {
+	provider *provider.Provider
+}
+
+type AutomountLocationModel struct {
+	Name types.String `tfsdk:"cn"`
+}
+
+func (r *AutomountLocation) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_automount_location"
+}
+
+func (r *AutomountLocation) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA automount location.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Automount location name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AutomountLocation) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AutomountLocationModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.AutomountlocationAddArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling AutomountlocationAdd", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().AutomountlocationAdd(args, nil)
+
+	tflog.Trace(ctx, "Called AutomountlocationAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create automount location", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *AutomountLocation) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AutomountLocationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.AutomountlocationShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling AutomountlocationShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().AutomountlocationShow(args, nil)
+
+	tflog.Trace(ctx, "Called AutomountlocationShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read automount location", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *AutomountLocation) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// The only attribute requires replacement, there is nothing to update
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *AutomountLocation) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AutomountLocationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.AutomountlocationDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling AutomountlocationDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().AutomountlocationDel(args, nil)
+
+	tflog.Trace(ctx, "Called AutomountlocationDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete automount location", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *AutomountLocation) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := AutomountLocationModel{
+		Name: types.StringValue(req.ID),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewAutomountLocation(p *provider.Provider) resource.Resource {
+	r := &AutomountLocation{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewAutomountLocation)
+}
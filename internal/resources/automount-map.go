@@ -0,0 +1,326 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type AutomountMap struct {
+	provider *provider.Provider
+}
+
+type AutomountMapModel struct {
+	Location    types.String `tfsdk:"automountlocationcn"`
+	Name        types.String `tfsdk:"automountmapname"`
+	Description types.String `tfsdk:"description"`
+	Key         types.String `tfsdk:"key"`
+	ParentMap   types.String `tfsdk:"parentmap"`
+}
+
+func (r *AutomountMap) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_automount_map"
+}
+
+func (r *AutomountMap) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA automount map, optionally mounted in a parent map.",
+		Attributes: map[string]schema.Attribute{
+			"automountlocationcn": schema.StringAttribute{
+				Description: "Automount location of the map",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"automountmapname": schema.StringAttribute{
+				Description: "Automount map name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Automount map description",
+				Optional:    true,
+			},
+			"key": schema.StringAttribute{
+				Description: "Mount point of the map in its parent map, an absolute path in `auto.master` (`/-` for a direct map) or a relative one in other maps. The map is not mounted when unset",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"parentmap": schema.StringAttribute{
+				Description: "Map the map is mounted in. Defaults to `auto.master`",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AutomountMap) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config AutomountMapModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Key.IsNull() && !config.ParentMap.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("parentmap"),
+			"Invalid configuration",
+			`“parentmap” requires “key” to be set.`,
+		)
+	}
+}
+
+func (r *AutomountMap) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AutomountMapModel
+	var err error
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Mounted maps are created along with their key in the parent map
+	if plan.Key.IsNull() {
+		args := &freeipa.AutomountmapAddArgs{
+			Automountlocationcn: plan.Location.ValueString(),
+			Automountmapname:    plan.Name.ValueString(),
+		}
+
+		optArgs := &freeipa.AutomountmapAddOptionalArgs{
+			Description: plan.Description.ValueStringPointer(),
+		}
+
+		tflog.Trace(ctx, "Calling AutomountmapAdd", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		var res *freeipa.AutomountmapAddResult
+		res, err = r.provider.Client().AutomountmapAdd(args, optArgs)
+
+		tflog.Trace(ctx, "Called AutomountmapAdd", map[string]any{
+			"res": res,
+			"err": err,
+		})
+	} else {
+		args := &freeipa.AutomountmapAddIndirectArgs{
+			Automountlocationcn: plan.Location.ValueString(),
+			Automountmapname:    plan.Name.ValueString(),
+			Key:                 plan.Key.ValueString(),
+		}
+
+		optArgs := &freeipa.AutomountmapAddIndirectOptionalArgs{
+			Description: plan.Description.ValueStringPointer(),
+			Parentmap:   plan.ParentMap.ValueStringPointer(),
+		}
+
+		tflog.Trace(ctx, "Calling AutomountmapAddIndirect", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		var res *freeipa.AutomountmapAddIndirectResult
+		res, err = r.provider.Client().AutomountmapAddIndirect(args, optArgs)
+
+		tflog.Trace(ctx, "Called AutomountmapAddIndirect", map[string]any{
+			"res": res,
+			"err": err,
+		})
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create automount map", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *AutomountMap) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state AutomountMapModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.AutomountmapShowArgs{
+		Automountlocationcn: state.Location.ValueString(),
+		Automountmapname:    state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling AutomountmapShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().AutomountmapShow(args, nil)
+
+	tflog.Trace(ctx, "Called AutomountmapShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read automount map", "Reason: "+err.Error())
+
+		return
+	}
+
+	// The mount point lives in the parent map and is kept from the state
+	state.Description = types.StringPointerValue(res.Result.Description)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *AutomountMap) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan AutomountMapModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Description.Equal(state.Description) {
+		args := &freeipa.AutomountmapModArgs{
+			Automountlocationcn: plan.Location.ValueString(),
+			Automountmapname:    plan.Name.ValueString(),
+		}
+
+		// A null plan value is sent as an empty string to clear the attribute
+		optArgs := &freeipa.AutomountmapModOptionalArgs{
+			Description: freeipa.String(plan.Description.ValueString()),
+		}
+
+		tflog.Trace(ctx, "Calling AutomountmapMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().AutomountmapMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called AutomountmapMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update automount map", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated automount map has no effective difference", map[string]any{
+			"automountlocationcn": plan.Location.ValueString(),
+			"automountmapname":    plan.Name.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *AutomountMap) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AutomountMapModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// FreeIPA also removes the key mounting the map in its parent map
+	args := &freeipa.AutomountmapDelArgs{
+		Automountlocationcn: state.Location.ValueString(),
+		Automountmapname:    []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling AutomountmapDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().AutomountmapDel(args, nil)
+
+	tflog.Trace(ctx, "Called AutomountmapDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete automount map", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *AutomountMap) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	location, name, ok := strings.Cut(req.ID, "/")
+
+	if !ok || location == "" || name == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<location>/<map>”, got %q.", req.ID),
+		)
+
+		return
+	}
+
+	state := AutomountMapModel{
+		Location: types.StringValue(location),
+		Name:     types.StringValue(name),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewAutomountMap(p *provider.Provider) resource.Resource {
+	r := &AutomountMap{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewAutomountMap)
+}
@@ -0,0 +1,445 @@
+package resources
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Ca struct {
+	provider *provider.Provider
+}
+
+type CaModel struct {
+	Name             types.String `tfsdk:"cn"`
+	Description      types.String `tfsdk:"description"`
+	SubjectDN        types.String `tfsdk:"ipacasubjectdn"`
+	IssuerDN         types.String `tfsdk:"ipacaissuerdn"`
+	ID               types.String `tfsdk:"ipacaid"`
+	Certificate      types.String `tfsdk:"certificate"`
+	CertificateChain types.String `tfsdk:"certificate_chain"`
+}
+
+func (r *Ca) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ca"
+}
+
+func (r *Ca) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA lightweight sub-CA.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "CA name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "CA description",
+				Optional:    true,
+			},
+			"ipacasubjectdn": schema.StringAttribute{
+				Description: "Subject DN of the CA certificate",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipacaissuerdn": schema.StringAttribute{
+				Description: "Issuer DN of the CA certificate",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipacaid": schema.StringAttribute{
+				Description: "Dogtag authority ID of the CA",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"certificate": schema.StringAttribute{
+				Description: "PEM encoded CA certificate",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"certificate_chain": schema.StringAttribute{
+				Description: "PEM encoded certificate chain of the CA, from the CA certificate up to the root CA",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Ca) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan CaModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CaAddArgs{
+		Cn:             plan.Name.ValueString(),
+		Ipacasubjectdn: plan.SubjectDN.ValueString(),
+	}
+
+	optArgs := &freeipa.CaAddOptionalArgs{
+		Description: plan.Description.ValueStringPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling CaAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CaAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called CaAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create CA", "Reason: "+err.Error())
+
+		return
+	}
+
+	if err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to read created CA", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Ca) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state CaModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.read(ctx, &state); err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read CA", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Ca) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan CaModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Description.Equal(state.Description) {
+		args := &freeipa.CaModArgs{
+			Cn: plan.Name.ValueString(),
+		}
+
+		// A null plan value is sent as an empty string to clear the attribute
+		optArgs := &freeipa.CaModOptionalArgs{
+			Description: freeipa.String(plan.Description.ValueString()),
+		}
+
+		tflog.Trace(ctx, "Calling CaMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().CaMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called CaMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update CA", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated CA has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Ca) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state CaModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// FreeIPA refuses to delete enabled CAs
+	if err := r.setEnabled(ctx, state.Name.ValueString(), false); err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to disable CA", "Reason: "+err.Error())
+		}
+
+		return
+	}
+
+	args := &freeipa.CaDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling CaDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CaDel(args, nil)
+
+	tflog.Trace(ctx, "Called CaDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Failed to delete CA",
+			fmt.Sprintf("FreeIPA refused to delete CA %q, it may still have issued certificates. Reason: %s", state.Name.ValueString(), err.Error()),
+		)
+
+		// Leave the CA as it was found, it is still in the state
+		if err := r.setEnabled(ctx, state.Name.ValueString(), true); err != nil {
+			resp.Diagnostics.AddError("Failed to re-enable CA", "Reason: "+err.Error())
+		}
+	}
+}
+
+func (r *Ca) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := CaModel{
+		Name: types.StringValue(req.ID),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// read refreshes the model from the CA entry and its certificate chain.
+func (r *Ca) read(ctx context.Context, m *CaModel) error {
+	args := &freeipa.CaShowArgs{
+		Cn: m.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling CaShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CaShow(args, nil)
+
+	tflog.Trace(ctx, "Called CaShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	m.Description = types.StringPointerValue(res.Result.Description)
+	m.IssuerDN = types.StringValue(res.Result.Ipacaissuerdn)
+	m.ID = types.StringValue(res.Result.Ipacaid)
+
+	// FreeIPA normalizes the DN, keep the configured spelling when equivalent
+	if !caSameDN(m.SubjectDN.ValueString(), res.Result.Ipacasubjectdn) {
+		m.SubjectDN = types.StringValue(res.Result.Ipacasubjectdn)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(res.Result.Certificate)
+	if err != nil {
+		return fmt.Errorf("decoding CA certificate: %w", err)
+	}
+
+	m.Certificate = types.StringValue(caCertificatesPEM([][]byte{der}))
+
+	chain, err := r.chain(ctx, m.Name.ValueString())
+	if err != nil {
+		return err
+	}
+
+	m.CertificateChain = types.StringValue(caCertificatesPEM(chain))
+
+	return nil
+}
+
+// chain returns the DER encoded certificate chain of the CA.
+func (r *Ca) chain(ctx context.Context, name string) ([][]byte, error) {
+	args := &freeipa.CaShowArgs{
+		Cn: name,
+	}
+
+	optArgs := &freeipa.CaShowOptionalArgs{
+		Chain: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling CaShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CaShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called CaShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		// The chain is binary, go-freeipa fails to decode it
+		if chain, ok := utils.DecodeErrorBase64List(err, "CertificateChain"); ok {
+			return chain, nil
+		}
+
+		return nil, err
+	}
+
+	var chain [][]byte
+
+	if res.Result.CertificateChain != nil {
+		for _, c := range *res.Result.CertificateChain {
+			der, err := base64.StdEncoding.DecodeString(c)
+			if err != nil {
+				return nil, fmt.Errorf("decoding CA certificate chain: %w", err)
+			}
+
+			chain = append(chain, der)
+		}
+	}
+
+	return chain, nil
+}
+
+func (r *Ca) setEnabled(ctx context.Context, name string, enabled bool) error {
+	if enabled {
+		args := &freeipa.CaEnableArgs{
+			Cn: name,
+		}
+
+		tflog.Trace(ctx, "Calling CaEnable", map[string]any{
+			"args":     args,
+			"opt_args": nil,
+		})
+
+		res, err := r.provider.Client().CaEnable(args, nil)
+
+		tflog.Trace(ctx, "Called CaEnable", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		return err
+	}
+
+	args := &freeipa.CaDisableArgs{
+		Cn: name,
+	}
+
+	tflog.Trace(ctx, "Calling CaDisable", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CaDisable(args, nil)
+
+	tflog.Trace(ctx, "Called CaDisable", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	return err
+}
+
+func caCertificatesPEM(ders [][]byte) string {
+	var b strings.Builder
+
+	for _, der := range ders {
+		_ = pem.Encode(&b, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	return b.String()
+}
+
+// caSameDN reports whether both DNs only differ by the spacing around their
+// separators or the case of their attribute values.
+func caSameDN(a, b string) bool {
+	normalize := func(dn string) string {
+		var rdns []string
+
+		for _, rdn := range strings.Split(dn, ",") {
+			rdns = append(rdns, strings.TrimSpace(rdn))
+		}
+
+		return strings.Join(rdns, ",")
+	}
+
+	return strings.EqualFold(normalize(a), normalize(b))
+}
+
+func NewCa(p *provider.Provider) resource.Resource {
+	r := &Ca{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewCa)
+}
@@ -0,0 +1,477 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+// caaclMembershipKind describes a kind of CA ACL member. Kinds with several
+// attributes (e.g. host and hostgroup) take exactly one of them.
+type caaclMembershipKind struct {
+	name        string
+	description string
+	attributes  []caaclMembershipAttribute
+}
+
+type caaclMembershipAttribute struct {
+	name        string
+	description string
+}
+
+var caaclMembershipKinds = []caaclMembershipKind{
+	{
+		name:        "ca",
+		description: "Adds a certificate authority to a FreeIPA CA ACL.",
+		attributes:  []caaclMembershipAttribute{{"ca", "Certificate authority the ACL applies to"}},
+	},
+	{
+		name:        "profile",
+		description: "Adds a certificate profile to a FreeIPA CA ACL.",
+		attributes:  []caaclMembershipAttribute{{"certprofile", "Certificate profile the ACL applies to"}},
+	},
+	{
+		name:        "host",
+		description: "Adds a host or a host group to a FreeIPA CA ACL.",
+		attributes: []caaclMembershipAttribute{
+			{"host", "Host the ACL applies to"},
+			{"hostgroup", "Host group the ACL applies to"},
+		},
+	},
+	{
+		name:        "service",
+		description: "Adds a service to a FreeIPA CA ACL.",
+		attributes:  []caaclMembershipAttribute{{"service", "Service principal the ACL applies to"}},
+	},
+	{
+		name:        "user",
+		description: "Adds a user or a group to a FreeIPA CA ACL.",
+		attributes: []caaclMembershipAttribute{
+			{"user", "User the ACL applies to"},
+			{"group", "Group the ACL applies to"},
+		},
+	},
+}
+
+// caaclMembershipData is satisfied by both tfsdk.Plan and tfsdk.State.
+type caaclMembershipData interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+type CaaclMembership struct {
+	provider *provider.Provider
+	kind     caaclMembershipKind
+}
+
+func (r *CaaclMembership) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_caacl_" + r.kind.name + "_membership"
+}
+
+func (r *CaaclMembership) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := map[string]schema.Attribute{
+		"caacl": schema.StringAttribute{
+			Description: "CA ACL name",
+			Required:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+	}
+
+	for _, a := range r.kind.attributes {
+		attributes[a.name] = schema.StringAttribute{
+			Description: a.description,
+			Required:    len(r.kind.attributes) == 1,
+			Optional:    len(r.kind.attributes) > 1,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: r.kind.description,
+		Attributes:  attributes,
+	}
+}
+
+func (r *CaaclMembership) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	if len(r.kind.attributes) == 1 {
+		return
+	}
+
+	var names []string
+	set := 0
+
+	for _, a := range r.kind.attributes {
+		var v types.String
+
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(a.name), &v)...)
+
+		if !v.IsNull() {
+			set++
+		}
+
+		names = append(names, "“"+a.name+"”")
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if set != 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(r.kind.attributes[0].name),
+			"Invalid configuration",
+			fmt.Sprintf("Exactly one of %s must be set.", strings.Join(names, " and ")),
+		)
+	}
+}
+
+func (r *CaaclMembership) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var caacl types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("caacl"), &caacl)...)
+
+	attr, member := r.member(ctx, req.Plan, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failed, err := r.add(ctx, caacl.ValueString(), attr, member)
+
+	// Members which already belong to the ACL are reported as failures, treat
+	// them as success to keep the creation idempotent.
+	if err == nil {
+		err = utils.FailedOperationsError(failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add member to CA ACL", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *CaaclMembership) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var caacl types.String
+
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("caacl"), &caacl)...)
+
+	attr, member := r.member(ctx, req.State, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CaaclShowArgs{
+		Cn: caacl.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling CaaclShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CaaclShow(args, nil)
+
+	tflog.Trace(ctx, "Called CaaclShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		// go-freeipa cannot decode ACLs with several CAs, profiles or
+		// services, the membership cannot be checked and is kept.
+		if utils.IsCaaclMemberDecodeError(err) {
+			tflog.Warn(ctx, "Ignoring go-freeipa decode error on CaaclShow", map[string]any{
+				"err": err,
+			})
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read CA ACL", "Reason: "+err.Error())
+
+		return
+	}
+
+	if !slices.Contains(caaclMembers(&res.Result, attr), member) {
+		tflog.Debug(ctx, "Member was removed from CA ACL", map[string]any{
+			"caacl": caacl.ValueString(),
+			attr:    member,
+		})
+
+		resp.State.RemoveResource(ctx)
+	}
+}
+
+func (r *CaaclMembership) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All attributes require replacement, there is nothing to update
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *CaaclMembership) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var caacl types.String
+
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("caacl"), &caacl)...)
+
+	attr, member := r.member(ctx, req.State, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failed, err := r.remove(ctx, caacl.ValueString(), attr, member)
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove member from CA ACL", "Reason: "+err.Error())
+		}
+
+		return
+	}
+
+	// Members removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove member from CA ACL", "Reason: "+err.Error())
+	}
+}
+
+func (r *CaaclMembership) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var names []string
+
+	for _, a := range r.kind.attributes {
+		names = append(names, a.name)
+	}
+
+	// Service principals contain slashes, the member is the remainder
+	parts := strings.SplitN(req.ID, "/", 3)
+
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" || !slices.Contains(names, parts[1]) {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<caacl>/<%s>/<member>”, got %q.", strings.Join(names, "|"), req.ID),
+		)
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("caacl"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(parts[1]), parts[2])...)
+}
+
+// member returns the attribute set on the membership and its value.
+func (r *CaaclMembership) member(ctx context.Context, data caaclMembershipData, diags *diag.Diagnostics) (string, string) {
+	for _, a := range r.kind.attributes {
+		var v types.String
+
+		diags.Append(data.GetAttribute(ctx, path.Root(a.name), &v)...)
+
+		if !v.IsNull() && !v.IsUnknown() {
+			return a.name, v.ValueString()
+		}
+	}
+
+	if !diags.HasError() {
+		diags.AddError("Invalid CA ACL membership", "No member is set.")
+	}
+
+	return "", ""
+}
+
+func (r *CaaclMembership) add(ctx context.Context, caacl, attr, member string) (freeipa.FailedOperations, error) {
+	members := &[]string{member}
+
+	tflog.Trace(ctx, "Adding CA ACL member", map[string]any{
+		"caacl": caacl,
+		attr:    member,
+	})
+
+	client := r.provider.Client()
+
+	switch attr {
+	case "ca":
+		res, err := client.CaaclAddCa(&freeipa.CaaclAddCaArgs{Cn: caacl}, &freeipa.CaaclAddCaOptionalArgs{Ca: members})
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	case "certprofile":
+		res, err := client.CaaclAddProfile(&freeipa.CaaclAddProfileArgs{Cn: caacl}, &freeipa.CaaclAddProfileOptionalArgs{Certprofile: members})
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	case "host", "hostgroup":
+		optArgs := &freeipa.CaaclAddHostOptionalArgs{}
+		if attr == "host" {
+			optArgs.Host = members
+		} else {
+			optArgs.Hostgroup = members
+		}
+
+		res, err := client.CaaclAddHost(&freeipa.CaaclAddHostArgs{Cn: caacl}, optArgs)
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	case "service":
+		res, err := client.CaaclAddService(&freeipa.CaaclAddServiceArgs{Cn: caacl}, &freeipa.CaaclAddServiceOptionalArgs{Service: members})
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	case "user", "group":
+		optArgs := &freeipa.CaaclAddUserOptionalArgs{}
+		if attr == "user" {
+			optArgs.User = members
+		} else {
+			optArgs.Group = members
+		}
+
+		res, err := client.CaaclAddUser(&freeipa.CaaclAddUserArgs{Cn: caacl}, optArgs)
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	}
+
+	return nil, fmt.Errorf("unsupported CA ACL member %s", attr)
+}
+
+func (r *CaaclMembership) remove(ctx context.Context, caacl, attr, member string) (freeipa.FailedOperations, error) {
+	members := &[]string{member}
+
+	tflog.Trace(ctx, "Removing CA ACL member", map[string]any{
+		"caacl": caacl,
+		attr:    member,
+	})
+
+	client := r.provider.Client()
+
+	switch attr {
+	case "ca":
+		res, err := client.CaaclRemoveCa(&freeipa.CaaclRemoveCaArgs{Cn: caacl}, &freeipa.CaaclRemoveCaOptionalArgs{Ca: members})
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	case "certprofile":
+		res, err := client.CaaclRemoveProfile(&freeipa.CaaclRemoveProfileArgs{Cn: caacl}, &freeipa.CaaclRemoveProfileOptionalArgs{Certprofile: members})
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	case "host", "hostgroup":
+		optArgs := &freeipa.CaaclRemoveHostOptionalArgs{}
+		if attr == "host" {
+			optArgs.Host = members
+		} else {
+			optArgs.Hostgroup = members
+		}
+
+		res, err := client.CaaclRemoveHost(&freeipa.CaaclRemoveHostArgs{Cn: caacl}, optArgs)
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	case "service":
+		res, err := client.CaaclRemoveService(&freeipa.CaaclRemoveServiceArgs{Cn: caacl}, &freeipa.CaaclRemoveServiceOptionalArgs{Service: members})
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	case "user", "group":
+		optArgs := &freeipa.CaaclRemoveUserOptionalArgs{}
+		if attr == "user" {
+			optArgs.User = members
+		} else {
+			optArgs.Group = members
+		}
+
+		res, err := client.CaaclRemoveUser(&freeipa.CaaclRemoveUserArgs{Cn: caacl}, optArgs)
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	}
+
+	return nil, fmt.Errorf("unsupported CA ACL member %s", attr)
+}
+
+// caaclMembers returns the members of the CA ACL for the given attribute.
+func caaclMembers(caacl *freeipa.Caacl, attr string) []string {
+	var single *string
+	var multiple *[]string
+
+	switch attr {
+	case "ca":
+		single = caacl.IpamembercaCa
+	case "certprofile":
+		single = caacl.IpamembercertprofileCertprofile
+	case "host":
+		multiple = caacl.MemberhostHost
+	case "hostgroup":
+		multiple = caacl.MemberhostHostgroup
+	case "service":
+		single = caacl.MemberserviceService
+	case "user":
+		multiple = caacl.MemberuserUser
+	case "group":
+		multiple = caacl.MemberuserGroup
+	}
+
+	if single != nil {
+		return []string{*single}
+	}
+	if multiple != nil {
+		return *multiple
+	}
+
+	return nil
+}
+
+func newCaaclMembership(kind caaclMembershipKind) func(*provider.Provider) resource.Resource {
+	return func(p *provider.Provider) resource.Resource {
+		r := &CaaclMembership{
+			provider: p,
+			kind:     kind,
+		}
+
+		var _ resource.Resource = r
+		var _ resource.ResourceWithValidateConfig = r
+		var _ resource.ResourceWithImportState = r
+
+		return r
+	}
+}
+
+func init() {
+	for _, kind := range caaclMembershipKinds {
+		resources = append(resources, newCaaclMembership(kind))
+	}
+}
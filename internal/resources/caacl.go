@@ -0,0 +1,409 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Caacl struct {
+	provider *provider.Provider
+}
+
+type CaaclModel struct {
+	Name                types.String `tfsdk:"cn"`
+	Description         types.String `tfsdk:"description"`
+	Enabled             types.Bool   `tfsdk:"ipaenabledflag"`
+	CaCategory          types.String `tfsdk:"ipacacategory"`
+	CertprofileCategory types.String `tfsdk:"ipacertprofilecategory"`
+	UserCategory        types.String `tfsdk:"usercategory"`
+	HostCategory        types.String `tfsdk:"hostcategory"`
+	ServiceCategory     types.String `tfsdk:"servicecategory"`
+}
+
+func (r *Caacl) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_caacl"
+}
+
+func (r *Caacl) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA CA ACL, controlling which principals can get certificates from which CAs and profiles.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "CA ACL name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "CA ACL description",
+				Optional:    true,
+			},
+			"ipaenabledflag": schema.BoolAttribute{
+				Description: "Whether the CA ACL is enabled. Defaults to `true`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipacacategory": schema.StringAttribute{
+				Description: "CA category the ACL applies to (allowed value: `all`)",
+				Optional:    true,
+			},
+			"ipacertprofilecategory": schema.StringAttribute{
+				Description: "Profile category the ACL applies to (allowed value: `all`)",
+				Optional:    true,
+			},
+			"usercategory": schema.StringAttribute{
+				Description: "User category the ACL applies to (allowed value: `all`)",
+				Optional:    true,
+			},
+			"hostcategory": schema.StringAttribute{
+				Description: "Host category the ACL applies to (allowed value: `all`)",
+				Optional:    true,
+			},
+			"servicecategory": schema.StringAttribute{
+				Description: "Service category the ACL applies to (allowed value: `all`)",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *Caacl) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config CaaclModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	categories := map[string]types.String{
+		"ipacacategory":          config.CaCategory,
+		"ipacertprofilecategory": config.CertprofileCategory,
+		"usercategory":           config.UserCategory,
+		"hostcategory":           config.HostCategory,
+		"servicecategory":        config.ServiceCategory,
+	}
+
+	for attr, category := range categories {
+		if !category.IsUnknown() && !category.IsNull() && category.ValueString() != "all" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr),
+				"Invalid configuration",
+				`The only allowed category is “all”.`,
+			)
+		}
+	}
+}
+
+func (r *Caacl) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan CaaclModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CaaclAddArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.CaaclAddOptionalArgs{
+		Description:            plan.Description.ValueStringPointer(),
+		Ipacacategory:          plan.CaCategory.ValueStringPointer(),
+		Ipacertprofilecategory: plan.CertprofileCategory.ValueStringPointer(),
+		Usercategory:           plan.UserCategory.ValueStringPointer(),
+		Hostcategory:           plan.HostCategory.ValueStringPointer(),
+		Servicecategory:        plan.ServiceCategory.ValueStringPointer(),
+		NoMembers:              freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling CaaclAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CaaclAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called CaaclAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create CA ACL", "Reason: "+err.Error())
+
+		return
+	}
+
+	state := plan
+	state.Enabled = types.BoolValue(res.Result.Ipaenabledflag == nil || *res.Result.Ipaenabledflag)
+
+	// CA ACLs are created enabled
+	if !plan.Enabled.IsUnknown() && !plan.Enabled.Equal(state.Enabled) {
+		if err := r.setEnabled(ctx, plan.Name.ValueString(), plan.Enabled.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Failed to disable CA ACL", "Reason: "+err.Error())
+
+			// The ACL exists, keep it in state to not leak it
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+			return
+		}
+
+		state.Enabled = plan.Enabled
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Caacl) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state CaaclModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CaaclShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	// Members are managed by association resources
+	optArgs := &freeipa.CaaclShowOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling CaaclShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CaaclShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called CaaclShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read CA ACL", "Reason: "+err.Error())
+
+		return
+	}
+
+	state.Description = types.StringPointerValue(res.Result.Description)
+	state.Enabled = types.BoolValue(res.Result.Ipaenabledflag == nil || *res.Result.Ipaenabledflag)
+	state.CaCategory = types.StringPointerValue(res.Result.Ipacacategory)
+	state.CertprofileCategory = types.StringPointerValue(res.Result.Ipacertprofilecategory)
+	state.UserCategory = types.StringPointerValue(res.Result.Usercategory)
+	state.HostCategory = types.StringPointerValue(res.Result.Hostcategory)
+	state.ServiceCategory = types.StringPointerValue(res.Result.Servicecategory)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Caacl) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan CaaclModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CaaclModArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.CaaclModOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	stringChanges := []struct {
+		plan, state types.String
+		arg         **string
+	}{
+		{plan.Description, state.Description, &optArgs.Description},
+		{plan.CaCategory, state.CaCategory, &optArgs.Ipacacategory},
+		{plan.CertprofileCategory, state.CertprofileCategory, &optArgs.Ipacertprofilecategory},
+		{plan.UserCategory, state.UserCategory, &optArgs.Usercategory},
+		{plan.HostCategory, state.HostCategory, &optArgs.Hostcategory},
+		{plan.ServiceCategory, state.ServiceCategory, &optArgs.Servicecategory},
+	}
+
+	// A null plan value is sent as an empty string to clear the attribute
+	for _, c := range stringChanges {
+		if !c.plan.Equal(c.state) {
+			*c.arg = freeipa.String(c.plan.ValueString())
+			hasDiff = true
+		}
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling CaaclMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().CaaclMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called CaaclMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update CA ACL", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	if !plan.Enabled.IsUnknown() && !plan.Enabled.Equal(state.Enabled) {
+		if err := r.setEnabled(ctx, plan.Name.ValueString(), plan.Enabled.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Failed to update CA ACL status", "Reason: "+err.Error())
+
+			return
+		}
+
+		hasDiff = true
+	}
+
+	if !hasDiff {
+		tflog.Debug(ctx, "Updated CA ACL has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	if plan.Enabled.IsUnknown() {
+		plan.Enabled = state.Enabled
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Caacl) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state CaaclModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CaaclDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling CaaclDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CaaclDel(args, nil)
+
+	tflog.Trace(ctx, "Called CaaclDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete CA ACL", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Caacl) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := CaaclModel{
+		Name:    types.StringValue(req.ID),
+		Enabled: types.BoolNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// setEnabled enables or disables the CA ACL with the dedicated commands.
+func (r *Caacl) setEnabled(ctx context.Context, name string, enabled bool) error {
+	if enabled {
+		args := &freeipa.CaaclEnableArgs{
+			Cn: name,
+		}
+
+		tflog.Trace(ctx, "Calling CaaclEnable", map[string]any{
+			"args":     args,
+			"opt_args": nil,
+		})
+
+		res, err := r.provider.Client().CaaclEnable(args, nil)
+
+		tflog.Trace(ctx, "Called CaaclEnable", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		return err
+	}
+
+	args := &freeipa.CaaclDisableArgs{
+		Cn: name,
+	}
+
+	tflog.Trace(ctx, "Calling CaaclDisable", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CaaclDisable(args, nil)
+
+	tflog.Trace(ctx, "Called CaaclDisable", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	return err
+}
+
+func NewCaacl(p *provider.Provider) resource.Resource {
+	r := &Caacl{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewCaacl)
+}
@@ -0,0 +1,404 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type CertProfile struct {
+	provider *provider.Provider
+}
+
+type CertProfileModel struct {
+	Name        types.String `tfsdk:"cn"`
+	Description types.String `tfsdk:"description"`
+	StoreIssued types.Bool   `tfsdk:"ipacertprofilestoreissued"`
+	Config      types.String `tfsdk:"config"`
+}
+
+func (r *CertProfile) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cert_profile"
+}
+
+func (r *CertProfile) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA certificate profile.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Profile ID, it must match the `profileId` of the configuration",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Profile description",
+				Required:    true,
+			},
+			"ipacertprofilestoreissued": schema.BoolAttribute{
+				Description: "Whether the certificates issued with the profile are stored. Defaults to `true`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"config": schema.StringAttribute{
+				Description: "Dogtag profile configuration, in the raw key/value format",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (r *CertProfile) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan CertProfileModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CertprofileImportArgs{
+		Cn:          plan.Name.ValueString(),
+		Description: plan.Description.ValueString(),
+		File:        plan.Config.ValueString(),
+	}
+
+	optArgs := &freeipa.CertprofileImportOptionalArgs{
+		Ipacertprofilestoreissued: plan.StoreIssued.ValueBoolPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling CertprofileImport", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CertprofileImport(args, optArgs)
+
+	tflog.Trace(ctx, "Called CertprofileImport", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to import certificate profile", "Reason: "+err.Error())
+
+		return
+	}
+
+	if err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to read imported certificate profile", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *CertProfile) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state CertProfileModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.read(ctx, &state); err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read certificate profile", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *CertProfile) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan CertProfileModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CertprofileModArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.CertprofileModOptionalArgs{}
+	hasDiff := false
+
+	if !plan.Description.Equal(state.Description) {
+		optArgs.Description = plan.Description.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if !plan.StoreIssued.IsUnknown() && !plan.StoreIssued.Equal(state.StoreIssued) {
+		optArgs.Ipacertprofilestoreissued = plan.StoreIssued.ValueBoolPointer()
+		hasDiff = true
+	}
+
+	if !plan.Config.Equal(state.Config) {
+		optArgs.File = plan.Config.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling CertprofileMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().CertprofileMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called CertprofileMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update certificate profile", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated certificate profile has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	if err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to read updated certificate profile", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *CertProfile) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state CertProfileModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// FreeIPA silently drops the profile from the CA ACLs using it, which
+	// then stop allowing the certificates they were written for
+	acls, err := r.caacls(ctx, state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to look up the CA ACLs using certificate profile", "Reason: "+err.Error())
+
+		return
+	}
+
+	if len(acls) > 0 {
+		resp.Diagnostics.AddError(
+			"Certificate profile is still used by a CA ACL",
+			fmt.Sprintf("Certificate profile %q is used by the CA ACLs %s. Remove the profile from the CA ACLs referencing it before deleting it.", state.Name.ValueString(), strings.Join(acls, ", ")),
+		)
+
+		return
+	}
+
+	args := &freeipa.CertprofileDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling CertprofileDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CertprofileDel(args, nil)
+
+	tflog.Trace(ctx, "Called CertprofileDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.IsDependentEntryError(err) {
+			resp.Diagnostics.AddError(
+				"Certificate profile is still used by a CA ACL",
+				"Reason: "+err.Error()+". Remove the profile from the CA ACLs referencing it before deleting it.",
+			)
+
+			return
+		}
+
+		if !utils.IsNotFoundError(err) {
+			resp.Diagnostics.AddError("Failed to delete certificate profile", "Reason: "+err.Error())
+		}
+	}
+}
+
+func (r *CertProfile) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := CertProfileModel{
+		Name:        types.StringValue(req.ID),
+		StoreIssued: types.BoolNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// read refreshes the model from the profile entry and its configuration.
+func (r *CertProfile) read(ctx context.Context, m *CertProfileModel) error {
+	args := &freeipa.CertprofileShowArgs{
+		Cn: m.Name.ValueString(),
+	}
+
+	// FreeIPA only returns the configuration when asked to write it out, the
+	// file name itself is only used by the ipa command line tool
+	optArgs := &freeipa.CertprofileShowOptionalArgs{
+		Out: freeipa.String(m.Name.ValueString() + ".cfg"),
+	}
+
+	tflog.Trace(ctx, "Calling CertprofileShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CertprofileShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called CertprofileShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	m.Description = types.StringValue(res.Result.Description)
+	m.StoreIssued = types.BoolPointerValue(res.Result.Ipacertprofilestoreissued)
+
+	// Dogtag does not keep the formatting of the configuration, keep the
+	// configured one when equivalent
+	if !certProfileSameConfig(m.Config.ValueString(), res.Result.Config) {
+		m.Config = types.StringValue(res.Result.Config)
+	}
+
+	return nil
+}
+
+// caacls returns the names of the CA ACLs listing the profile.
+func (r *CertProfile) caacls(ctx context.Context, name string) ([]string, error) {
+	// go-freeipa cannot decode ACLs with several profiles
+	options := map[string]interface{}{"all": true, "sizelimit": 0}
+
+	tflog.Trace(ctx, "Calling caacl_find", map[string]any{
+		"options": options,
+	})
+
+	var res struct {
+		Result []map[string]interface{} `json:"result"`
+	}
+
+	err := r.provider.RPC().Call(ctx, "caacl_find", nil, options, &res)
+
+	tflog.Trace(ctx, "Called caacl_find", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var acls []string
+
+	for _, acl := range res.Result {
+		if slices.Contains(rpcStringValues(acl["ipamembercertprofile_certprofile"]), name) {
+			acls = append(acls, rpcStringValues(acl["cn"])...)
+		}
+	}
+
+	slices.Sort(acls)
+
+	return acls, nil
+}
+
+// certProfileSameConfig reports whether both profile configurations hold the
+// same settings, regardless of their order, blank lines and comments.
+func certProfileSameConfig(a, b string) bool {
+	settings := func(config string) []string {
+		var lines []string
+
+		for _, line := range strings.Split(config, "\n") {
+			line = strings.TrimSpace(line)
+
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			lines = append(lines, line)
+		}
+
+		slices.Sort(lines)
+
+		return lines
+	}
+
+	return slices.Equal(settings(a), settings(b))
+}
+
+// rpcStringValues returns the values of an attribute decoded from a raw
+// JSON-RPC response, which holds either a single string or a list of them.
+func rpcStringValues(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		values := make([]string, 0, len(t))
+
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				values = append(values, s)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+func NewCertProfile(p *provider.Provider) resource.Resource {
+	r := &CertProfile{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewCertProfile)
+}
@@ -0,0 +1,354 @@
+package resources
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type CertRequest struct {
+	provider *provider.Provider
+}
+
+type CertRequestModel struct {
+	Principal        types.String `tfsdk:"principal"`
+	CSR              types.String `tfsdk:"csr"`
+	ProfileID        types.String `tfsdk:"profile_id"`
+	CA               types.String `tfsdk:"cacn"`
+	RevokeOnDestroy  types.Bool   `tfsdk:"revoke_on_destroy"`
+	RevocationReason types.Int64  `tfsdk:"revocation_reason"`
+	Certificate      types.String `tfsdk:"certificate"`
+	SerialNumber     types.Int64  `tfsdk:"serial_number"`
+	ValidNotBefore   types.String `tfsdk:"valid_not_before"`
+	ValidNotAfter    types.String `tfsdk:"valid_not_after"`
+}
+
+// certRevocationReasons are the RFC 5280 reason codes accepted by CertRevoke,
+// 7 is unused.
+var certRevocationReasons = []int64{0, 1, 2, 3, 4, 5, 6, 8, 9, 10}
+
+func (r *CertRequest) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cert_request"
+}
+
+func (r *CertRequest) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Requests a certificate from the FreeIPA CA for an existing principal.",
+		Attributes: map[string]schema.Attribute{
+			"principal": schema.StringAttribute{
+				Description: "Principal the certificate is issued to (e.g. `HTTP/web.example.com`), which must already exist",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"csr": schema.StringAttribute{
+				Description: "PEM encoded certificate signing request",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"profile_id": schema.StringAttribute{
+				Description: "Certificate profile to use. Defaults to `caIPAserviceCert`",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cacn": schema.StringAttribute{
+				Description: "Name of the CA issuing the certificate. Defaults to the IPA CA",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"revoke_on_destroy": schema.BoolAttribute{
+				Description: "Revoke the certificate when the resource is destroyed. Defaults to `false`",
+				Optional:    true,
+			},
+			"revocation_reason": schema.Int64Attribute{
+				Description: "RFC 5280 reason code used to revoke the certificate. Defaults to `0` (unspecified)",
+				Optional:    true,
+			},
+			"certificate": schema.StringAttribute{
+				Description: "PEM encoded issued certificate",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"serial_number": schema.Int64Attribute{
+				Description: "Serial number of the issued certificate",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"valid_not_before": schema.StringAttribute{
+				Description: "Start of the certificate validity, in RFC 3339 format",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"valid_not_after": schema.StringAttribute{
+				Description: "End of the certificate validity, in RFC 3339 format",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *CertRequest) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config CertRequestModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.RevocationReason.IsUnknown() && !config.RevocationReason.IsNull() &&
+		!slices.Contains(certRevocationReasons, config.RevocationReason.ValueInt64()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("revocation_reason"),
+			"Invalid configuration",
+			`The revocation reason must be one of 0 to 6 or 8 to 10.`,
+		)
+	}
+}
+
+func (r *CertRequest) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan CertRequestModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CertRequestArgs{
+		Principal: plan.Principal.ValueString(),
+		Csr:       plan.CSR.ValueString(),
+	}
+
+	optArgs := &freeipa.CertRequestOptionalArgs{
+		ProfileID: plan.ProfileID.ValueStringPointer(),
+		Cacn:      plan.CA.ValueStringPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling CertRequest", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CertRequest(args, optArgs)
+
+	tflog.Trace(ctx, "Called CertRequest", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("principal"),
+				"Failed to request certificate",
+				fmt.Sprintf("The principal %q must be an existing host or service, create it before requesting a certificate. Reason: %s", plan.Principal.ValueString(), err.Error()),
+			)
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to request certificate", "Reason: "+err.Error())
+
+		return
+	}
+
+	result, ok := res.Result.(map[string]interface{})
+	if !ok {
+		resp.Diagnostics.AddError("Failed to read issued certificate", fmt.Sprintf("Reason: unexpected result type %T", res.Result))
+
+		return
+	}
+
+	der, err := utils.CertificateDER(result["certificate"])
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read issued certificate", "Reason: "+err.Error())
+
+		return
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse issued certificate", "Reason: "+err.Error())
+
+		return
+	}
+
+	// go-freeipa handles serial numbers as integers, random serial numbers do
+	// not fit
+	if !cert.SerialNumber.IsInt64() {
+		resp.Diagnostics.AddError(
+			"Unsupported certificate serial number",
+			fmt.Sprintf("The serial number %s of the issued certificate does not fit in a 64-bit integer.", cert.SerialNumber.String()),
+		)
+
+		return
+	}
+
+	plan.Certificate = types.StringValue(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	plan.SerialNumber = types.Int64Value(cert.SerialNumber.Int64())
+	plan.ValidNotBefore = types.StringValue(cert.NotBefore.UTC().Format(time.RFC3339))
+	plan.ValidNotAfter = types.StringValue(cert.NotAfter.UTC().Format(time.RFC3339))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *CertRequest) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state CertRequestModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CertShowArgs{
+		SerialNumber: int(state.SerialNumber.ValueInt64()),
+	}
+
+	optArgs := &freeipa.CertShowOptionalArgs{
+		Cacn:      state.CA.ValueStringPointer(),
+		All:       freeipa.Bool(true),
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling CertShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CertShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called CertShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		// The certificate exists, but its revocation status cannot be read
+		if utils.IsCertDatetimeDecodeError(err) {
+			tflog.Warn(ctx, "Ignoring go-freeipa decode error on CertShow", map[string]any{
+				"err": err,
+			})
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read certificate", "Reason: "+err.Error())
+
+		return
+	}
+
+	// A revoked certificate has to be issued again
+	if res.Result.Revoked != nil && *res.Result.Revoked {
+		tflog.Debug(ctx, "Certificate was revoked", map[string]any{
+			"serial_number": state.SerialNumber.ValueInt64(),
+		})
+
+		resp.State.RemoveResource(ctx)
+	}
+}
+
+func (r *CertRequest) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only the revocation settings can change, they are used on destroy
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *CertRequest) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state CertRequestModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.RevokeOnDestroy.ValueBool() {
+		return
+	}
+
+	args := &freeipa.CertRevokeArgs{
+		SerialNumber: int(state.SerialNumber.ValueInt64()),
+	}
+
+	optArgs := &freeipa.CertRevokeOptionalArgs{
+		RevocationReason: int64ToIntPointer(state.RevocationReason),
+		Cacn:             state.CA.ValueStringPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling CertRevoke", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CertRevoke(args, optArgs)
+
+	tflog.Trace(ctx, "Called CertRevoke", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to revoke certificate", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func NewCertRequest(p *provider.Provider) resource.Resource {
+	r := &CertRequest{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewCertRequest)
+}
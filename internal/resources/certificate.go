@@ -7,6 +7,7 @@ import (
 
 	"github.com/camptocamp/go-freeipa/freeipa"
 	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -118,10 +119,7 @@ func (r *Certificate) Read(ctx context.Context, req resource.ReadRequest, resp *
 		"err": err,
 	})
 	if err != nil {
-		var freeipaErr *freeipa.Error
-
-		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
-			resp.State.RemoveResource(ctx)
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
 			return
 		}
 
@@ -181,7 +179,7 @@ func (r *Certificate) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 	if err != nil {
 		var freeipaErr *freeipa.Error
-		if errors.As(err, &freeipaErr) && freeipaErr.Code != freeipa.NotFoundCode {
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
 			resp.Diagnostics.AddError("Failed to delete certificate", "Reason: "+err.Error())
 			return
 		}
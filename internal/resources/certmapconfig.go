@@ -0,0 +1,187 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Certmapconfig struct {
+	provider *provider.Provider
+}
+
+type CertmapconfigModel struct {
+	PromptUsername types.Bool `tfsdk:"ipacertmappromptusername"`
+}
+
+func (r *Certmapconfig) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certmapconfig"
+}
+
+func (r *Certmapconfig) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages the global FreeIPA certificate identity mapping configuration. The resource must be imported, unset attributes keep their current value.",
+		Attributes: map[string]schema.Attribute{
+			"ipacertmappromptusername": schema.BoolAttribute{
+				Description: "Prompt for the username when a certificate is mapped to several users",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Certmapconfig) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	requireSingletonImport(&resp.Diagnostics, "Certificate mapping configuration", "global certificate mapping configuration", singletonID)
+}
+
+func (r *Certmapconfig) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state CertmapconfigModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config, err := r.certmapconfigShow(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read certificate mapping configuration", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, certmapconfigState(config))...)
+}
+
+func (r *Certmapconfig) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan CertmapconfigModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, diags := r.apply(ctx, plan, state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Certmapconfig) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.AddWarning(
+		"Certificate mapping configuration not deleted",
+		"The global certificate mapping configuration cannot be deleted, it was only removed from the Terraform state and keeps its current settings.",
+	)
+}
+
+func (r *Certmapconfig) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !validSingletonID(&resp.Diagnostics, req.ID) {
+		return
+	}
+
+	// The configuration is read from the server after the import
+	state := CertmapconfigModel{
+		PromptUsername: types.BoolNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// apply sends the attributes of plan which differ from state and returns the
+// resulting state.
+func (r *Certmapconfig) apply(ctx context.Context, plan, state CertmapconfigModel) (CertmapconfigModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if plan.PromptUsername.IsNull() || plan.PromptUsername.IsUnknown() || plan.PromptUsername.Equal(state.PromptUsername) {
+		tflog.Debug(ctx, "Updated certificate mapping configuration has no effective difference", nil)
+
+		return state, diags
+	}
+
+	args := &freeipa.CertmapconfigModArgs{}
+	optArgs := &freeipa.CertmapconfigModOptionalArgs{
+		Ipacertmappromptusername: plan.PromptUsername.ValueBoolPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling CertmapconfigMod", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CertmapconfigMod(args, optArgs)
+
+	tflog.Trace(ctx, "Called CertmapconfigMod", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		diags.AddError("Failed to update certificate mapping configuration", "Reason: "+err.Error())
+
+		return state, diags
+	}
+
+	return certmapconfigState(&res.Result), diags
+}
+
+func (r *Certmapconfig) certmapconfigShow(ctx context.Context) (*freeipa.Certmapconfig, error) {
+	args := &freeipa.CertmapconfigShowArgs{}
+
+	tflog.Trace(ctx, "Calling CertmapconfigShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CertmapconfigShow(args, nil)
+
+	tflog.Trace(ctx, "Called CertmapconfigShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Result, nil
+}
+
+func certmapconfigState(config *freeipa.Certmapconfig) CertmapconfigModel {
+	return CertmapconfigModel{
+		PromptUsername: types.BoolValue(config.Ipacertmappromptusername != nil && *config.Ipacertmappromptusername),
+	}
+}
+
+func NewCertmapconfig(p *provider.Provider) resource.Resource {
+	r := &Certmapconfig{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewCertmapconfig)
+}
@@ -0,0 +1,465 @@
+package resources
+
+import (
+	"context"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type Certmaprule struct {
+	provider *provider.Provider
+}
+
+type CertmapruleModel struct {
+	Name             types.String `tfsdk:"cn"`
+	Description      types.String `tfsdk:"description"`
+	MapRule          types.String `tfsdk:"ipacertmapmaprule"`
+	MatchRule        types.String `tfsdk:"ipacertmapmatchrule"`
+	AssociatedDomain types.Set    `tfsdk:"associateddomain"`
+	Priority         types.Int64  `tfsdk:"ipacertmappriority"`
+	Enabled          types.Bool   `tfsdk:"ipaenabledflag"`
+}
+
+func (r *Certmaprule) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certmaprule"
+}
+
+func (r *Certmaprule) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA certificate identity mapping rule, mapping certificates to user accounts for smart card authentication.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Rule name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Rule description",
+				Optional:    true,
+			},
+			"ipacertmapmaprule": schema.StringAttribute{
+				Description: "Rule used to map the certificate with a user entry, e.g. `(ipacertmapdata=X509:<I>{issuer_dn!nss_x500}<S>{subject_dn!nss_x500})`",
+				Optional:    true,
+			},
+			"ipacertmapmatchrule": schema.StringAttribute{
+				Description: "Rule used to check whether a certificate can be used for authentication, e.g. `<ISSUER>CN=Smart Card CA,O=EXAMPLE.TEST`",
+				Optional:    true,
+			},
+			"associateddomain": schema.SetAttribute{
+				Description: "Domains where the user entries are searched for, the local domain when unset",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"ipacertmappriority": schema.Int64Attribute{
+				Description: "Rule priority, lower numbers first. Rules without priority are applied last",
+				Optional:    true,
+			},
+			"ipaenabledflag": schema.BoolAttribute{
+				Description: "Whether the rule is enabled. Defaults to `true`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Certmaprule) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config CertmapruleModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Priority.IsUnknown() && !config.Priority.IsNull() && config.Priority.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipacertmappriority"),
+			"Invalid configuration",
+			`“ipacertmappriority” cannot be negative.`,
+		)
+	}
+}
+
+func (r *Certmaprule) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan CertmapruleModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CertmapruleAddArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.CertmapruleAddOptionalArgs{
+		Description:         plan.Description.ValueStringPointer(),
+		Ipacertmapmaprule:   plan.MapRule.ValueStringPointer(),
+		Ipacertmapmatchrule: plan.MatchRule.ValueStringPointer(),
+		Associateddomain:    certmapruleDomains(ctx, plan.AssociatedDomain, &resp.Diagnostics),
+		Ipacertmappriority:  int64ToIntPointer(plan.Priority),
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Calling CertmapruleAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().CertmapruleAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called CertmapruleAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create certificate mapping rule", "Reason: "+err.Error())
+
+		return
+	}
+
+	state := plan
+	state.Enabled = types.BoolValue(res.Result.Ipaenabledflag == nil || *res.Result.Ipaenabledflag)
+
+	// Rules are created enabled
+	if !plan.Enabled.IsUnknown() && !plan.Enabled.Equal(state.Enabled) {
+		if err := r.setEnabled(ctx, plan.Name.ValueString(), plan.Enabled.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Failed to disable certificate mapping rule", "Reason: "+err.Error())
+
+			// The rule exists, keep it in state to not leak it
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+			return
+		}
+
+		state.Enabled = plan.Enabled
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Certmaprule) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state CertmapruleModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CertmapruleShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling CertmapruleShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CertmapruleShow(args, nil)
+
+	tflog.Trace(ctx, "Called CertmapruleShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read certificate mapping rule", "Reason: "+err.Error())
+
+		return
+	}
+
+	state.Description = types.StringPointerValue(res.Result.Description)
+	state.MapRule = types.StringPointerValue(res.Result.Ipacertmapmaprule)
+	state.MatchRule = types.StringPointerValue(res.Result.Ipacertmapmatchrule)
+	state.Priority = intToInt64Value(res.Result.Ipacertmappriority)
+	state.Enabled = types.BoolValue(res.Result.Ipaenabledflag == nil || *res.Result.Ipaenabledflag)
+
+	var domains []string
+
+	if res.Result.Associateddomain != nil {
+		for _, d := range *res.Result.Associateddomain {
+			domains = append(domains, utils.DnsNameValue(d))
+		}
+	}
+
+	// The domains are DNS names, keep the configured spelling when only the
+	// case or the trailing dot differ
+	var current []string
+
+	resp.Diagnostics.Append(setToStringSlice(ctx, state.AssociatedDomain, &current)...)
+
+	if !certmapruleSameDomains(current, domains) {
+		state.AssociatedDomain = stringSliceToSet(ctx, &domains, true, &resp.Diagnostics)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Certmaprule) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan CertmapruleModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CertmapruleModArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.CertmapruleModOptionalArgs{}
+
+	stringChanges := []struct {
+		plan, state types.String
+		arg         **string
+	}{
+		{plan.Description, state.Description, &optArgs.Description},
+		{plan.MapRule, state.MapRule, &optArgs.Ipacertmapmaprule},
+		{plan.MatchRule, state.MatchRule, &optArgs.Ipacertmapmatchrule},
+	}
+
+	// A null plan value is sent as an empty string to clear the attribute
+	for _, c := range stringChanges {
+		if !c.plan.Equal(c.state) {
+			*c.arg = freeipa.String(c.plan.ValueString())
+			hasDiff = true
+		}
+	}
+
+	if !plan.AssociatedDomain.Equal(state.AssociatedDomain) {
+		// An empty list clears the domains
+		domains := []interface{}{}
+
+		if d := certmapruleDomains(ctx, plan.AssociatedDomain, &resp.Diagnostics); d != nil {
+			domains = *d
+		}
+
+		optArgs.Associateddomain = &domains
+		hasDiff = true
+	}
+
+	// Integers cannot be sent empty, a removed priority is cleared with setattr
+	if !plan.Priority.Equal(state.Priority) {
+		if plan.Priority.IsNull() {
+			optArgs.Setattr = &[]string{"ipacertmappriority="}
+		} else {
+			optArgs.Ipacertmappriority = int64ToIntPointer(plan.Priority)
+		}
+		hasDiff = true
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling CertmapruleMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().CertmapruleMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called CertmapruleMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update certificate mapping rule", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	if !plan.Enabled.IsUnknown() && !plan.Enabled.Equal(state.Enabled) {
+		if err := r.setEnabled(ctx, plan.Name.ValueString(), plan.Enabled.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Failed to update certificate mapping rule status", "Reason: "+err.Error())
+
+			return
+		}
+
+		hasDiff = true
+	}
+
+	if !hasDiff {
+		tflog.Debug(ctx, "Updated certificate mapping rule has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	if plan.Enabled.IsUnknown() {
+		plan.Enabled = state.Enabled
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Certmaprule) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state CertmapruleModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.CertmapruleDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling CertmapruleDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CertmapruleDel(args, nil)
+
+	tflog.Trace(ctx, "Called CertmapruleDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil && !utils.IsNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to delete certificate mapping rule", "Reason: "+err.Error())
+	}
+}
+
+func (r *Certmaprule) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := CertmapruleModel{
+		Name:             types.StringValue(req.ID),
+		AssociatedDomain: types.SetNull(types.StringType),
+		Enabled:          types.BoolNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// setEnabled enables or disables the rule with the dedicated commands.
+func (r *Certmaprule) setEnabled(ctx context.Context, name string, enabled bool) error {
+	if enabled {
+		args := &freeipa.CertmapruleEnableArgs{
+			Cn: name,
+		}
+
+		tflog.Trace(ctx, "Calling CertmapruleEnable", map[string]any{
+			"args":     args,
+			"opt_args": nil,
+		})
+
+		res, err := r.provider.Client().CertmapruleEnable(args, nil)
+
+		tflog.Trace(ctx, "Called CertmapruleEnable", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		return err
+	}
+
+	args := &freeipa.CertmapruleDisableArgs{
+		Cn: name,
+	}
+
+	tflog.Trace(ctx, "Calling CertmapruleDisable", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().CertmapruleDisable(args, nil)
+
+	tflog.Trace(ctx, "Called CertmapruleDisable", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	return err
+}
+
+// certmapruleDomains converts the associated domains to the values sent to
+// FreeIPA, nil when the set is null or unknown.
+func certmapruleDomains(ctx context.Context, set types.Set, diags *diag.Diagnostics) *[]interface{} {
+	values := setToStringSlicePointer(ctx, set, diags)
+	if values == nil {
+		return nil
+	}
+
+	domains := make([]interface{}, len(*values))
+
+	for i, v := range *values {
+		domains[i] = v
+	}
+
+	return &domains
+}
+
+// certmapruleSameDomains reports whether both lists hold the same DNS names,
+// ignoring the case and the trailing dots.
+func certmapruleSameDomains(a, b []string) bool {
+	normalize := func(names []string) []string {
+		res := make([]string, len(names))
+
+		for i, n := range names {
+			res[i] = strings.ToLower(strings.TrimSuffix(n, "."))
+		}
+
+		slices.Sort(res)
+
+		return slices.Compact(res)
+	}
+
+	return slices.Equal(normalize(a), normalize(b))
+}
+
+func NewCertmaprule(p *provider.Provider) resource.Resource {
+	r := &Certmaprule{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewCertmaprule)
+}
@@ -0,0 +1,341 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+var configUserAuthTypes = []string{"password", "radius", "otp", "pkinit", "hardened", "idp", "passkey", "disabled"}
+
+type Config struct {
+	provider *provider.Provider
+}
+
+type ConfigModel struct {
+	DefaultLoginShell   types.String `tfsdk:"ipadefaultloginshell"`
+	HomesRootDir        types.String `tfsdk:"ipahomesrootdir"`
+	DefaultEmailDomain  types.String `tfsdk:"ipadefaultemaildomain"`
+	SelinuxUsermapOrder types.String `tfsdk:"ipaselinuxusermaporder"`
+	UserAuthType        types.Set    `tfsdk:"ipauserauthtype"`
+	MaxUsernameLength   types.Int64  `tfsdk:"ipamaxusernamelength"`
+}
+
+func (r *Config) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config"
+}
+
+func (r *Config) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages the global FreeIPA configuration. The resource must be imported, unset attributes keep their current value.",
+		Attributes: map[string]schema.Attribute{
+			"ipadefaultloginshell": schema.StringAttribute{
+				Description: "Default login shell of new users",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipahomesrootdir": schema.StringAttribute{
+				Description: "Default location of the home directories of new users",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipadefaultemaildomain": schema.StringAttribute{
+				Description: "Default e-mail domain of new users",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipaselinuxusermaporder": schema.StringAttribute{
+				Description: "Order of the SELinux users, separated by `$`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipauserauthtype": schema.SetAttribute{
+				Description: "Default authentication types of users, any of " + strings.Join(configUserAuthTypes, ", ") + ". An empty set removes them",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipamaxusernamelength": schema.Int64Attribute{
+				Description: "Maximum length of user names",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Config) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config ConfigModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.UserAuthType.IsUnknown() && !config.UserAuthType.IsNull() {
+		var authTypes []string
+
+		resp.Diagnostics.Append(config.UserAuthType.ElementsAs(ctx, &authTypes, false)...)
+
+		for _, authType := range authTypes {
+			if !slices.Contains(configUserAuthTypes, authType) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("ipauserauthtype"),
+					"Invalid configuration",
+					fmt.Sprintf("Unsupported authentication type “%s”, expected any of %s.", authType, strings.Join(configUserAuthTypes, ", ")),
+				)
+			}
+		}
+	}
+
+	if !config.MaxUsernameLength.IsUnknown() && !config.MaxUsernameLength.IsNull() &&
+		(config.MaxUsernameLength.ValueInt64() < 1 || config.MaxUsernameLength.ValueInt64() > 255) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipamaxusernamelength"),
+			"Invalid configuration",
+			`“ipamaxusernamelength” must be between 1 and 255.`,
+		)
+	}
+}
+
+func (r *Config) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	requireSingletonImport(&resp.Diagnostics, "FreeIPA configuration", "global FreeIPA configuration", singletonID)
+}
+
+func (r *Config) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ConfigModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config, err := r.configShow(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read FreeIPA configuration", "Reason: "+err.Error())
+
+		return
+	}
+
+	state, diags := configState(ctx, config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Config) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan ConfigModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config, err := r.configMod(ctx, plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update FreeIPA configuration", "Reason: "+err.Error())
+
+		return
+	}
+
+	if config == nil {
+		tflog.Debug(ctx, "Updated FreeIPA configuration has no effective difference", nil)
+
+		if config, err = r.configShow(ctx); err != nil {
+			resp.Diagnostics.AddError("Failed to read FreeIPA configuration", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	state, diags := configState(ctx, config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Config) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.AddWarning(
+		"FreeIPA configuration not deleted",
+		"The global FreeIPA configuration cannot be deleted, it was only removed from the Terraform state and keeps its current settings.",
+	)
+}
+
+func (r *Config) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !validSingletonID(&resp.Diagnostics, req.ID) {
+		return
+	}
+
+	// The configuration is read from the server after the import
+	state := ConfigModel{
+		UserAuthType: types.SetNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// configMod sends the attributes of plan which differ from state, and returns
+// a nil result when there is nothing to update.
+func (r *Config) configMod(ctx context.Context, plan, state ConfigModel) (*freeipa.Config, error) {
+	var hasDiff bool
+
+	optArgs := &freeipa.ConfigModOptionalArgs{}
+
+	if !plan.DefaultLoginShell.IsUnknown() && !plan.DefaultLoginShell.Equal(state.DefaultLoginShell) {
+		optArgs.Ipadefaultloginshell = plan.DefaultLoginShell.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if !plan.HomesRootDir.IsUnknown() && !plan.HomesRootDir.Equal(state.HomesRootDir) {
+		optArgs.Ipahomesrootdir = plan.HomesRootDir.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if !plan.DefaultEmailDomain.IsUnknown() && !plan.DefaultEmailDomain.Equal(state.DefaultEmailDomain) {
+		optArgs.Ipadefaultemaildomain = plan.DefaultEmailDomain.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if !plan.SelinuxUsermapOrder.IsUnknown() && !plan.SelinuxUsermapOrder.Equal(state.SelinuxUsermapOrder) {
+		optArgs.Ipaselinuxusermaporder = plan.SelinuxUsermapOrder.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if !plan.UserAuthType.IsUnknown() && !plan.UserAuthType.Equal(state.UserAuthType) {
+		var diags diag.Diagnostics
+
+		optArgs.Ipauserauthtype = setToStringSlicePointer(ctx, plan.UserAuthType, &diags)
+		if diags.HasError() {
+			return nil, fmt.Errorf("reading authentication types: %v", diags)
+		}
+
+		hasDiff = true
+	}
+
+	if !plan.MaxUsernameLength.IsUnknown() && !plan.MaxUsernameLength.Equal(state.MaxUsernameLength) {
+		optArgs.Ipamaxusernamelength = freeipa.Int(int(plan.MaxUsernameLength.ValueInt64()))
+		hasDiff = true
+	}
+
+	if !hasDiff {
+		return nil, nil
+	}
+
+	args := &freeipa.ConfigModArgs{}
+
+	tflog.Trace(ctx, "Calling ConfigMod", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().ConfigMod(args, optArgs)
+
+	tflog.Trace(ctx, "Called ConfigMod", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Result, nil
+}
+
+func (r *Config) configShow(ctx context.Context) (*freeipa.Config, error) {
+	args := &freeipa.ConfigShowArgs{}
+
+	tflog.Trace(ctx, "Calling ConfigShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().ConfigShow(args, nil)
+
+	tflog.Trace(ctx, "Called ConfigShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Result, nil
+}
+
+func configState(ctx context.Context, config *freeipa.Config) (ConfigModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	state := ConfigModel{
+		DefaultLoginShell:   types.StringValue(config.Ipadefaultloginshell),
+		HomesRootDir:        types.StringValue(config.Ipahomesrootdir),
+		DefaultEmailDomain:  types.StringPointerValue(config.Ipadefaultemaildomain),
+		SelinuxUsermapOrder: types.StringValue(config.Ipaselinuxusermaporder),
+		UserAuthType:        stringSliceToSet(ctx, config.Ipauserauthtype, false, &diags),
+		MaxUsernameLength:   types.Int64Value(int64(config.Ipamaxusernamelength)),
+	}
+
+	return state, diags
+}
+
+func NewConfig(p *provider.Provider) resource.Resource {
+	r := &Config{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewConfig)
+}
@@ -0,0 +1,357 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+var delegationPermissions = []string{"read", "write"}
+
+type Delegation struct {
+	provider *provider.Provider
+}
+
+type DelegationModel struct {
+	Name        types.String `tfsdk:"aciname"`
+	Attrs       types.Set    `tfsdk:"attrs"`
+	Permissions types.Set    `tfsdk:"permissions"`
+	MemberOf    types.String `tfsdk:"memberof"`
+	Group       types.String `tfsdk:"group"`
+}
+
+func (r *Delegation) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_delegation"
+}
+
+func (r *Delegation) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA delegation, allowing the members of a group to edit attributes of the members of another group.",
+		Attributes: map[string]schema.Attribute{
+			"aciname": schema.StringAttribute{
+				Description: "Delegation name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"attrs": schema.SetAttribute{
+				Description: "Attributes the acting group is allowed to edit",
+				ElementType: types.StringType,
+				Required:    true,
+			},
+			"permissions": schema.SetAttribute{
+				Description: "Permissions granted on the attributes, any of " + strings.Join(delegationPermissions, ", ") + " (defaults to `write`)",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"memberof": schema.StringAttribute{
+				Description: "Group whose members' entries can be edited",
+				Required:    true,
+			},
+			"group": schema.StringAttribute{
+				Description: "Group whose members are allowed to edit the entries",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (r *Delegation) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config DelegationModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Attrs.IsUnknown() && !config.Attrs.IsNull() && len(config.Attrs.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("attrs"),
+			"Invalid configuration",
+			`“attrs” must hold at least one attribute.`,
+		)
+	}
+
+	if !config.Permissions.IsUnknown() && !config.Permissions.IsNull() {
+		var permissions []string
+
+		resp.Diagnostics.Append(config.Permissions.ElementsAs(ctx, &permissions, false)...)
+
+		for _, permission := range permissions {
+			if !slices.Contains(delegationPermissions, permission) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("permissions"),
+					"Invalid configuration",
+					fmt.Sprintf("Unsupported permission “%s”, expected any of %s.", permission, strings.Join(delegationPermissions, ", ")),
+				)
+			}
+		}
+	}
+}
+
+func (r *Delegation) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan DelegationModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	attrs := []string{}
+
+	resp.Diagnostics.Append(plan.Attrs.ElementsAs(ctx, &attrs, false)...)
+
+	args := &freeipa.DelegationAddArgs{
+		Aciname:  plan.Name.ValueString(),
+		Attrs:    attrs,
+		Memberof: plan.MemberOf.ValueString(),
+		Group:    plan.Group.ValueString(),
+	}
+
+	optArgs := &freeipa.DelegationAddOptionalArgs{
+		Permissions: setToStringSlicePointer(ctx, plan.Permissions, &resp.Diagnostics),
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Calling DelegationAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().DelegationAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called DelegationAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create delegation", "Reason: "+err.Error())
+
+		return
+	}
+
+	plan.Permissions = stringSliceToSet(ctx, res.Result.Permissions, false, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Delegation) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state DelegationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.DelegationShowArgs{
+		Aciname: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling DelegationShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().DelegationShow(args, nil)
+
+	tflog.Trace(ctx, "Called DelegationShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read delegation", "Reason: "+err.Error())
+
+		return
+	}
+
+	// FreeIPA lower-cases attribute names, keep the configured spelling when
+	// only the case differs.
+	attrs := &res.Result.Attrs
+	if !state.Attrs.IsNull() {
+		var current []string
+
+		resp.Diagnostics.Append(state.Attrs.ElementsAs(ctx, &current, false)...)
+
+		attrs = preserveCaseInsensitive(current, *attrs)
+	}
+
+	state.Attrs = stringSliceToSet(ctx, attrs, false, &resp.Diagnostics)
+	state.Permissions = stringSliceToSet(ctx, res.Result.Permissions, false, &resp.Diagnostics)
+	state.MemberOf = types.StringValue(res.Result.Memberof)
+	state.Group = types.StringValue(res.Result.Group)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Delegation) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan DelegationModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.DelegationModArgs{
+		Aciname: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.DelegationModOptionalArgs{}
+
+	if !plan.Attrs.Equal(state.Attrs) {
+		optArgs.Attrs = setToStringSlicePointer(ctx, plan.Attrs, &resp.Diagnostics)
+		hasDiff = true
+	}
+
+	if !plan.Permissions.IsUnknown() && !plan.Permissions.Equal(state.Permissions) {
+		optArgs.Permissions = setToStringSlicePointer(ctx, plan.Permissions, &resp.Diagnostics)
+		hasDiff = true
+	}
+
+	// The groups are changed in place, the delegation keeps its name
+	if !plan.MemberOf.Equal(state.MemberOf) {
+		optArgs.Memberof = plan.MemberOf.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if !plan.Group.Equal(state.Group) {
+		optArgs.Group = plan.Group.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling DelegationMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().DelegationMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called DelegationMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update delegation", "Reason: "+err.Error())
+
+			return
+		}
+
+		plan.Permissions = stringSliceToSet(ctx, res.Result.Permissions, false, &resp.Diagnostics)
+	} else {
+		tflog.Debug(ctx, "Updated delegation has no effective difference", map[string]any{
+			"aciname": plan.Name.ValueString(),
+		})
+	}
+
+	if plan.Permissions.IsUnknown() {
+		plan.Permissions = state.Permissions
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Delegation) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state DelegationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.DelegationDelArgs{
+		Aciname: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling DelegationDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().DelegationDel(args, nil)
+
+	tflog.Trace(ctx, "Called DelegationDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete delegation", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Delegation) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := DelegationModel{
+		Name:        types.StringValue(req.ID),
+		Attrs:       types.SetNull(types.StringType),
+		Permissions: types.SetNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewDelegation(p *provider.Provider) resource.Resource {
+	r := &Delegation{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewDelegation)
+}
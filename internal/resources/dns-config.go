@@ -0,0 +1,300 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type DnsConfig struct {
+	provider *provider.Provider
+}
+
+type DnsConfigModel struct {
+	Forwarders    types.List   `tfsdk:"idnsforwarders"`
+	ForwardPolicy types.String `tfsdk:"idnsforwardpolicy"`
+	AllowSyncPTR  types.Bool   `tfsdk:"idnsallowsyncptr"`
+}
+
+func (r *DnsConfig) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_config"
+}
+
+func (r *DnsConfig) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages the global FreeIPA DNS configuration. The resource must be imported, unset attributes keep their current value.",
+		Attributes: map[string]schema.Attribute{
+			"idnsforwarders": schema.ListAttribute{
+				Description: "Global forwarders, optionally followed by ` port <port>`. An empty list removes them",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"idnsforwardpolicy": schema.StringAttribute{
+				Description: "Global forward policy: `first`, `only` or `none`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"idnsallowsyncptr": schema.BoolAttribute{
+				Description: "Allow the synchronization of forward and reverse records",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *DnsConfig) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config DnsConfigModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.ForwardPolicy.IsUnknown() && !config.ForwardPolicy.IsNull() &&
+		!slices.Contains(dnsForwardPolicies, config.ForwardPolicy.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("idnsforwardpolicy"),
+			"Invalid configuration",
+			`The forward policy must be one of “first”, “only” or “none”.`,
+		)
+	}
+}
+
+func (r *DnsConfig) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	requireSingletonImport(&resp.Diagnostics, "DNS configuration", "global DNS configuration", singletonID)
+}
+
+func (r *DnsConfig) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state DnsConfigModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config, err := r.dnsconfigShow(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read DNS configuration", "Reason: "+err.Error())
+
+		return
+	}
+
+	state, diags := dnsconfigState(ctx, state, config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *DnsConfig) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan DnsConfigModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, diags := r.apply(ctx, plan, state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *DnsConfig) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.AddWarning(
+		"DNS configuration not deleted",
+		"The global DNS configuration cannot be deleted, it was only removed from the Terraform state and keeps its current settings.",
+	)
+}
+
+func (r *DnsConfig) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !validSingletonID(&resp.Diagnostics, req.ID) {
+		return
+	}
+
+	// The configuration is read from the server after the import
+	state := DnsConfigModel{
+		Forwarders: types.ListNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// apply sends the attributes of plan which differ from state and returns the
+// resulting state.
+func (r *DnsConfig) apply(ctx context.Context, plan, state DnsConfigModel) (DnsConfigModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	config, err := r.dnsconfigMod(ctx, plan, state)
+	if err != nil {
+		diags.AddError("Failed to update DNS configuration", "Reason: "+err.Error())
+
+		return plan, diags
+	}
+
+	if config == nil {
+		tflog.Debug(ctx, "Updated DNS configuration has no effective difference", nil)
+
+		if config, err = r.dnsconfigShow(ctx); err != nil {
+			diags.AddError("Failed to read DNS configuration", "Reason: "+err.Error())
+
+			return plan, diags
+		}
+	}
+
+	// Configured values are kept as planned, only unset ones are read back
+	current, diags := dnsconfigState(ctx, plan, config)
+
+	if plan.Forwarders.IsUnknown() {
+		plan.Forwarders = current.Forwarders
+	}
+	if plan.ForwardPolicy.IsUnknown() {
+		plan.ForwardPolicy = current.ForwardPolicy
+	}
+	if plan.AllowSyncPTR.IsUnknown() {
+		plan.AllowSyncPTR = current.AllowSyncPTR
+	}
+
+	return plan, diags
+}
+
+// dnsconfigMod sends the attributes of plan which differ from state, and
+// returns a nil result when there is nothing to update.
+func (r *DnsConfig) dnsconfigMod(ctx context.Context, plan, state DnsConfigModel) (*freeipa.Dnsconfig, error) {
+	var hasDiff bool
+
+	args := &freeipa.DnsconfigModArgs{}
+	optArgs := &freeipa.DnsconfigModOptionalArgs{}
+
+	if !plan.Forwarders.IsNull() && !plan.Forwarders.IsUnknown() && !plan.Forwarders.Equal(state.Forwarders) {
+		if diags := listToStringSlicePointer(ctx, plan.Forwarders, &optArgs.Idnsforwarders); diags.HasError() {
+			return nil, fmt.Errorf("reading forwarders: %v", diags)
+		}
+
+		hasDiff = true
+	}
+
+	if !plan.ForwardPolicy.IsNull() && !plan.ForwardPolicy.IsUnknown() && !plan.ForwardPolicy.Equal(state.ForwardPolicy) {
+		optArgs.Idnsforwardpolicy = plan.ForwardPolicy.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if !plan.AllowSyncPTR.IsNull() && !plan.AllowSyncPTR.IsUnknown() && !plan.AllowSyncPTR.Equal(state.AllowSyncPTR) {
+		optArgs.Idnsallowsyncptr = plan.AllowSyncPTR.ValueBoolPointer()
+		hasDiff = true
+	}
+
+	if !hasDiff {
+		return nil, nil
+	}
+
+	tflog.Trace(ctx, "Calling DnsconfigMod", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().DnsconfigMod(args, optArgs)
+
+	tflog.Trace(ctx, "Called DnsconfigMod", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Result, nil
+}
+
+func (r *DnsConfig) dnsconfigShow(ctx context.Context) (*freeipa.Dnsconfig, error) {
+	args := &freeipa.DnsconfigShowArgs{}
+
+	tflog.Trace(ctx, "Calling DnsconfigShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().DnsconfigShow(args, nil)
+
+	tflog.Trace(ctx, "Called DnsconfigShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Result, nil
+}
+
+func dnsconfigState(ctx context.Context, current DnsConfigModel, config *freeipa.Dnsconfig) (DnsConfigModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	state := DnsConfigModel{
+		ForwardPolicy: types.StringPointerValue(config.Idnsforwardpolicy),
+		AllowSyncPTR:  types.BoolValue(config.Idnsallowsyncptr != nil && *config.Idnsallowsyncptr),
+	}
+
+	forwarders := current.Forwarders
+	if forwarders.IsUnknown() || forwarders.IsNull() {
+		forwarders = types.ListNull(types.StringType)
+	}
+
+	state.Forwarders, diags = stringSliceToList(ctx, forwarders, config.Idnsforwarders)
+
+	return state, diags
+}
+
+func NewDnsConfig(p *provider.Provider) resource.Resource {
+	r := &DnsConfig{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewDnsConfig)
+}
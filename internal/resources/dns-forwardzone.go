@@ -0,0 +1,313 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+// dnsForwardPolicies are the forward policies accepted by FreeIPA for forward
+// zones and the global configuration.
+var dnsForwardPolicies = []string{"first", "only", "none"}
+
+type DnsForwardzone struct {
+	provider *provider.Provider
+}
+
+type DnsForwardzoneModel struct {
+	Name          types.String `tfsdk:"idnsname"`
+	Forwarders    types.List   `tfsdk:"idnsforwarders"`
+	ForwardPolicy types.String `tfsdk:"idnsforwardpolicy"`
+}
+
+func (r *DnsForwardzone) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_forwardzone"
+}
+
+func (r *DnsForwardzone) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA DNS forward zone, forwarding queries for a domain to other name servers.",
+		Attributes: map[string]schema.Attribute{
+			"idnsname": schema.StringAttribute{
+				Description: "Forward zone name (e.g. `corp.example.com.`)",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"idnsforwarders": schema.ListAttribute{
+				Description: "Name servers the queries are forwarded to, optionally followed by ` port <port>`",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"idnsforwardpolicy": schema.StringAttribute{
+				Description: "Forward policy: `first`, `only` or `none`. Defaults to `first`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *DnsForwardzone) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config DnsForwardzoneModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.ForwardPolicy.IsUnknown() && !config.ForwardPolicy.IsNull() &&
+		!slices.Contains(dnsForwardPolicies, config.ForwardPolicy.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("idnsforwardpolicy"),
+			"Invalid configuration",
+			`The forward policy must be one of “first”, “only” or “none”.`,
+		)
+	}
+
+	// Forwarding is disabled with the “none” policy, any other needs servers
+	if config.Forwarders.IsNull() && !config.ForwardPolicy.IsUnknown() && config.ForwardPolicy.ValueString() != "none" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("idnsforwarders"),
+			"Invalid configuration",
+			`“idnsforwarders” must be set unless “idnsforwardpolicy” is “none”.`,
+		)
+	}
+}
+
+func (r *DnsForwardzone) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan DnsForwardzoneModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var name interface{} = plan.Name.ValueString()
+
+	args := &freeipa.DnsforwardzoneAddArgs{}
+
+	optArgs := &freeipa.DnsforwardzoneAddOptionalArgs{
+		Idnsname:          &name,
+		Idnsforwardpolicy: stringToStringPointer(plan.ForwardPolicy),
+	}
+
+	resp.Diagnostics.Append(listToStringSlicePointer(ctx, plan.Forwarders, &optArgs.Idnsforwarders)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Calling DnsforwardzoneAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().DnsforwardzoneAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called DnsforwardzoneAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create DNS forward zone", "Reason: "+err.Error())
+
+		return
+	}
+
+	plan.ForwardPolicy = types.StringPointerValue(res.Result.Idnsforwardpolicy)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *DnsForwardzone) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state DnsForwardzoneModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var name interface{} = state.Name.ValueString()
+
+	args := &freeipa.DnsforwardzoneShowArgs{}
+
+	optArgs := &freeipa.DnsforwardzoneShowOptionalArgs{
+		Idnsname: &name,
+	}
+
+	tflog.Trace(ctx, "Calling DnsforwardzoneShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().DnsforwardzoneShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called DnsforwardzoneShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read DNS forward zone", "Reason: "+err.Error())
+
+		return
+	}
+
+	var diags diag.Diagnostics
+
+	state.Forwarders, diags = stringSliceToList(ctx, state.Forwarders, res.Result.Idnsforwarders)
+	resp.Diagnostics.Append(diags...)
+
+	state.ForwardPolicy = types.StringPointerValue(res.Result.Idnsforwardpolicy)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *DnsForwardzone) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan DnsForwardzoneModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var name interface{} = plan.Name.ValueString()
+
+	args := &freeipa.DnsforwardzoneModArgs{}
+
+	optArgs := &freeipa.DnsforwardzoneModOptionalArgs{
+		Idnsname: &name,
+	}
+
+	if !plan.Forwarders.Equal(state.Forwarders) {
+		// A null plan value is sent as an empty list to clear the forwarders
+		optArgs.Idnsforwarders = &[]string{}
+
+		resp.Diagnostics.Append(listToStringSlicePointer(ctx, plan.Forwarders, &optArgs.Idnsforwarders)...)
+		hasDiff = true
+	}
+
+	if !plan.ForwardPolicy.IsUnknown() && !plan.ForwardPolicy.Equal(state.ForwardPolicy) {
+		optArgs.Idnsforwardpolicy = plan.ForwardPolicy.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling DnsforwardzoneMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().DnsforwardzoneMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called DnsforwardzoneMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update DNS forward zone", "Reason: "+err.Error())
+
+			return
+		}
+
+		plan.ForwardPolicy = types.StringPointerValue(res.Result.Idnsforwardpolicy)
+	} else {
+		tflog.Debug(ctx, "Updated DNS forward zone has no effective difference", map[string]any{
+			"idnsname": plan.Name.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *DnsForwardzone) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state DnsForwardzoneModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.DnsforwardzoneDelArgs{}
+
+	optArgs := &freeipa.DnsforwardzoneDelOptionalArgs{
+		Idnsname: &[]interface{}{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling DnsforwardzoneDel", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().DnsforwardzoneDel(args, optArgs)
+
+	tflog.Trace(ctx, "Called DnsforwardzoneDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete DNS forward zone", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *DnsForwardzone) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("idnsname"), req, resp)
+}
+
+func NewDnsForwardzone(p *provider.Provider) resource.Resource {
+	r := &DnsForwardzone{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewDnsForwardzone)
+}
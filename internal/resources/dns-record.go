@@ -3,18 +3,21 @@ package resources
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/camptocamp/go-freeipa/freeipa"
 	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
 )
 
 type DnsRecord struct {
@@ -22,12 +25,13 @@ type DnsRecord struct {
 }
 
 type DnsRecordModel struct {
-	Name     types.String `tfsdk:"idnsname"`
-	ZoneName types.String `tfsdk:"dnszoneidnsname"`
-	Class    types.String `tfsdk:"dnsclass"`
-	Type     types.String `tfsdk:"type"`
-	TTL      types.Int64  `tfsdk:"dnsttl"`
-	Records  types.Set    `tfsdk:"records"`
+	Name       types.String `tfsdk:"idnsname"`
+	ZoneName   types.String `tfsdk:"dnszoneidnsname"`
+	Class      types.String `tfsdk:"dnsclass"`
+	Type       types.String `tfsdk:"type"`
+	TTL        types.Int64  `tfsdk:"dnsttl"`
+	Records    types.Set    `tfsdk:"records"`
+	ReverseTTL types.Bool   `tfsdk:"reverse_ttl"`
 }
 
 func (r *DnsRecord) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -39,13 +43,15 @@ func (r *DnsRecord) Schema(ctx context.Context, req resource.SchemaRequest, resp
 		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"idnsname": schema.StringAttribute{
-				Required: true,
+				Required:    true,
+				Description: "Record name, relative to the zone (use `@` for the zone apex)",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"dnszoneidnsname": schema.StringAttribute{
-				Required: true,
+				Required:    true,
+				Description: "Zone name",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -55,22 +61,67 @@ func (r *DnsRecord) Schema(ctx context.Context, req resource.SchemaRequest, resp
 				DeprecationMessage: "Only “IN” DNS class is supported.",
 			},
 			"type": schema.StringAttribute{
-				Required: true,
+				Required:    true,
+				Description: "Record type, one of " + strings.Join(utils.DnsRecordTypes, ", "),
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"dnsttl": schema.Int64Attribute{
-				Optional: true,
+				Optional:    true,
+				Description: "Time to live of the records",
 			},
 			"records": schema.SetAttribute{
 				ElementType: types.StringType,
 				Required:    true,
+				Description: "Records of the given type, other record types of the same name are left untouched",
+			},
+			"reverse_ttl": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Also give the PTR records of the addresses of A and AAAA records the time to live of the records",
 			},
 		},
 	}
 }
 
+func (r *DnsRecord) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config DnsRecordModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Class.IsUnknown() && !config.Class.IsNull() && !strings.EqualFold(config.Class.ValueString(), "IN") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dnsclass"),
+			"Invalid configuration",
+			fmt.Sprintf("Unsupported DNS class “%s”, FreeIPA only serves records of the “IN” class.", config.Class.ValueString()),
+		)
+	}
+
+	if config.Type.IsUnknown() || config.Type.IsNull() {
+		return
+	}
+
+	if config.ReverseTTL.ValueBool() && config.Type.ValueString() != "A" && config.Type.ValueString() != "AAAA" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("reverse_ttl"),
+			"Invalid configuration",
+			"“reverse_ttl” can only be set for A and AAAA records.",
+		)
+	}
+
+	if !slices.Contains(utils.DnsRecordTypes, config.Type.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid configuration",
+			fmt.Sprintf("Unsupported record type “%s”, expected one of %s.", config.Type.ValueString(), strings.Join(utils.DnsRecordTypes, ", ")),
+		)
+	}
+}
+
 func (r *DnsRecord) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan, state DnsRecordModel
 
@@ -146,6 +197,10 @@ func (r *DnsRecord) Create(ctx context.Context, req resource.CreateRequest, resp
 		return
 	}
 
+	if plan.ReverseTTL.ValueBool() && !plan.TTL.IsNull() {
+		r.setReverseTTL(ctx, records, plan.TTL, &resp.Diagnostics)
+	}
+
 	state = plan
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
@@ -184,11 +239,7 @@ func (r *DnsRecord) Read(ctx context.Context, req resource.ReadRequest, resp *re
 	})
 
 	if err != nil {
-		var freeipaErr *freeipa.Error
-
-		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
-			resp.State.RemoveResource(ctx)
-
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
 			return
 		}
 
@@ -204,40 +255,32 @@ func (r *DnsRecord) Read(ctx context.Context, req resource.ReadRequest, resp *re
 		*ttl = int64(*res.Result.Dnsttl)
 	}
 
-	var records *[]string
+	records := utils.DnsRecordValues(&res.Result, state.Type.ValueString())
 
-	switch state.Type.ValueString() {
-	case "A":
-		records = res.Result.Arecord
-	case "AAAA":
-		records = res.Result.Aaaarecord
-	case "CNAME":
-		records = res.Result.Cnamerecord
-	case "MX":
-		records = res.Result.Mxrecord
-	case "NS":
-		records = res.Result.Nsrecord
-	case "PTR":
-		records = res.Result.Ptrrecord
-	case "SRV":
-		records = res.Result.Srvrecord
-	case "TXT":
-		records = res.Result.Txtrecord
-	case "SSHFP":
-		records = res.Result.Sshfprecord
+	if records == nil || len(*records) == 0 {
+		tflog.Debug(ctx, "DNS record has no record of the managed type left", map[string]any{
+			"name":      state.Name.ValueString(),
+			"zone_name": state.ZoneName.ValueString(),
+			"type":      state.Type.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	var current []string
+
+	if !state.Records.IsNull() && !state.Records.IsUnknown() {
+		resp.Diagnostics.Append(state.Records.ElementsAs(ctx, &current, false)...)
 	}
 
 	var diags diag.Diagnostics
 
 	state.TTL = types.Int64PointerValue(ttl)
+	state.Records, diags = types.SetValueFrom(ctx, types.StringType, preserveDnsRecordValues(state.Type.ValueString(), current, *records))
 
-	if records != nil {
-		state.Records, diags = types.SetValueFrom(ctx, types.StringType, *records)
-
-		resp.Diagnostics.Append(diags...)
-	} else {
-		state.Records = types.SetValueMust(types.StringType, []attr.Value{})
-	}
+	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -259,13 +302,6 @@ func (r *DnsRecord) Update(ctx context.Context, req resource.UpdateRequest, resp
 
 	var zone any = plan.ZoneName.ValueString()
 
-	var ttl *int
-
-	if !plan.TTL.IsNull() {
-		ttl = new(int)
-		*ttl = int(plan.TTL.ValueInt64())
-	}
-
 	var records []string
 
 	resp.Diagnostics.Append(plan.Records.ElementsAs(ctx, &records, false)...)
@@ -280,32 +316,44 @@ func (r *DnsRecord) Update(ctx context.Context, req resource.UpdateRequest, resp
 
 	optArgs := &freeipa.DnsrecordModOptionalArgs{
 		Dnszoneidnsname: &zone,
-		Dnsttl:          ttl,
 		All:             freeipa.Bool(true),
 	}
 
-	switch plan.Type.ValueString() {
-	case "A":
-		optArgs.Arecord = &records
-	case "AAAA":
-		optArgs.Aaaarecord = &records
-	case "CNAME":
-		optArgs.Cnamerecord = &records
-	case "MX":
-		optArgs.Mxrecord = &records
-	case "NS":
-		optArgs.Nsrecord = &records
-	case "PTR":
-		optArgs.Ptrrecord = &records
-	case "SRV":
-		optArgs.Srvrecord = &records
-	case "TXT":
-		optArgs.Txtrecord = &records
-	case "SSHFP":
-		optArgs.Sshfprecord = &records
+	// The records are only sent when they change so that a TTL change does
+	// not rewrite them.
+	if !plan.Records.Equal(state.Records) {
+		switch plan.Type.ValueString() {
+		case "A":
+			optArgs.Arecord = &records
+		case "AAAA":
+			optArgs.Aaaarecord = &records
+		case "CNAME":
+			optArgs.Cnamerecord = &records
+		case "MX":
+			optArgs.Mxrecord = &records
+		case "NS":
+			optArgs.Nsrecord = &records
+		case "PTR":
+			optArgs.Ptrrecord = &records
+		case "SRV":
+			optArgs.Srvrecord = &records
+		case "TXT":
+			optArgs.Txtrecord = &records
+		case "SSHFP":
+			optArgs.Sshfprecord = &records
+		}
+		hasDiff = true
 	}
 
-	hasDiff = !plan.TTL.Equal(state.TTL) || !plan.Records.Equal(state.Records)
+	// Integers cannot be sent empty, a removed TTL is cleared with setattr
+	if !plan.TTL.Equal(state.TTL) {
+		if plan.TTL.IsNull() {
+			optArgs.Setattr = &[]string{"dnsttl="}
+		} else {
+			optArgs.Dnsttl = int64ToIntPointer(plan.TTL)
+		}
+		hasDiff = true
+	}
 
 	if hasDiff {
 		tflog.Trace(ctx, "Calling DnsrecordMod", map[string]any{
@@ -333,6 +381,10 @@ func (r *DnsRecord) Update(ctx context.Context, req resource.UpdateRequest, resp
 		})
 	}
 
+	if plan.ReverseTTL.ValueBool() && (hasDiff || !state.ReverseTTL.ValueBool()) {
+		r.setReverseTTL(ctx, records, plan.TTL, &resp.Diagnostics)
+	}
+
 	state = plan
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
@@ -349,15 +401,43 @@ func (r *DnsRecord) Delete(ctx context.Context, req resource.DeleteRequest, resp
 
 	var zone any = state.ZoneName.ValueString()
 
-	deleteAllRecords := true
+	var records []string
+
+	resp.Diagnostics.Append(state.Records.ElementsAs(ctx, &records, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	args := &freeipa.DnsrecordDelArgs{
 		Idnsname: state.Name.ValueString(),
 	}
 
+	// Only the records of the managed type are deleted so that other record
+	// types sharing the same name are kept.
 	optArgs := &freeipa.DnsrecordDelOptionalArgs{
 		Dnszoneidnsname: &zone,
-		DelAll:          &deleteAllRecords,
+	}
+
+	switch state.Type.ValueString() {
+	case "A":
+		optArgs.Arecord = &records
+	case "AAAA":
+		optArgs.Aaaarecord = &records
+	case "CNAME":
+		optArgs.Cnamerecord = &records
+	case "MX":
+		optArgs.Mxrecord = &records
+	case "NS":
+		optArgs.Nsrecord = &records
+	case "PTR":
+		optArgs.Ptrrecord = &records
+	case "SRV":
+		optArgs.Srvrecord = &records
+	case "TXT":
+		optArgs.Txtrecord = &records
+	case "SSHFP":
+		optArgs.Sshfprecord = &records
 	}
 
 	tflog.Trace(ctx, "Calling DnsrecordDel", map[string]any{
@@ -375,14 +455,130 @@ func (r *DnsRecord) Delete(ctx context.Context, req resource.DeleteRequest, resp
 	if err != nil {
 		var freeipaErr *freeipa.Error
 
-		if errors.As(err, &freeipaErr) && freeipaErr.Code != freeipa.NotFoundCode {
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
 			resp.Diagnostics.AddError("Failed to delete DNS record", "Reason: "+err.Error())
 
 			return
 		}
 	}
+}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+// setReverseTTL gives the PTR records of addresses the time to live ttl,
+// clearing it when ttl is null. The forward records are already applied, so
+// failures are only reported as warnings.
+func (r *DnsRecord) setReverseTTL(ctx context.Context, addresses []string, ttl types.Int64, diagnostics *diag.Diagnostics) {
+	zones, err := r.reverseZones(ctx)
+	if err != nil {
+		diagnostics.AddWarning("Failed to set the TTL of PTR records", "Reason: "+err.Error())
+
+		return
+	}
+
+	for _, addr := range addresses {
+		name, ok := utils.ReverseDnsName(addr)
+		if !ok {
+			continue
+		}
+
+		// The longest matching zone holds the PTR record
+		var zone string
+
+		for _, z := range zones {
+			if strings.HasSuffix(strings.ToLower(name), "."+z) && len(z) > len(zone) {
+				zone = z
+			}
+		}
+
+		if zone == "" {
+			diagnostics.AddWarning(
+				"PTR record TTL not set",
+				fmt.Sprintf("No FreeIPA reverse zone holds the PTR record of %s.", addr),
+			)
+
+			continue
+		}
+
+		var zoneName any = zone
+
+		args := &freeipa.DnsrecordModArgs{
+			Idnsname: utils.RelativeDnsName(name, zone),
+		}
+
+		optArgs := &freeipa.DnsrecordModOptionalArgs{
+			Dnszoneidnsname: &zoneName,
+		}
+
+		if ttl.IsNull() {
+			optArgs.Setattr = &[]string{"dnsttl="}
+		} else {
+			optArgs.Dnsttl = int64ToIntPointer(ttl)
+		}
+
+		tflog.Trace(ctx, "Calling DnsrecordMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().DnsrecordMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called DnsrecordMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			var freeipaErr *freeipa.Error
+
+			switch {
+			case errors.As(err, &freeipaErr) && freeipaErr.Code == utils.EmptyModlistCode:
+				// The PTR record already has the TTL
+			case errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode:
+				diagnostics.AddWarning(
+					"PTR record TTL not set",
+					fmt.Sprintf("The PTR record of %s does not exist in the “%s” zone.", addr, zone),
+				)
+			default:
+				diagnostics.AddWarning("Failed to set the TTL of PTR records", fmt.Sprintf("Reason for %s: %s", addr, err.Error()))
+			}
+		}
+	}
+}
+
+// reverseZones returns the lower case absolute names of the reverse zones.
+func (r *DnsRecord) reverseZones(ctx context.Context) ([]string, error) {
+	args := &freeipa.DnszoneFindArgs{}
+
+	optArgs := &freeipa.DnszoneFindOptionalArgs{
+		Sizelimit: freeipa.Int(0),
+	}
+
+	tflog.Trace(ctx, "Calling DnszoneFind", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().DnszoneFind("arpa", args, optArgs)
+
+	tflog.Trace(ctx, "Called DnszoneFind", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []string
+
+	for _, z := range res.Result {
+		name := strings.ToLower(utils.AbsoluteDnsName(utils.DnsNameValue(z.Idnsname)))
+
+		if strings.HasSuffix(name, ".arpa.") {
+			zones = append(zones, name)
+		}
+	}
+
+	return zones, nil
 }
 
 func (r *DnsRecord) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -457,6 +653,7 @@ func NewDnsRecord(p *provider.Provider) resource.Resource {
 	}
 
 	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
 	var _ resource.ResourceWithImportState = r
 	var _ resource.ResourceWithUpgradeState = r
 
@@ -466,3 +663,42 @@ func NewDnsRecord(p *provider.Provider) resource.Resource {
 func init() {
 	resources = append(resources, NewDnsRecord)
 }
+
+// preserveDnsRecordValues returns the actual records, keeping the configured
+// representation of values which FreeIPA returns in an equivalent form.
+func preserveDnsRecordValues(recordType string, current, actual []string) []string {
+	known := make(map[string]string, len(current))
+
+	for _, v := range current {
+		known[normalizeDnsRecordValue(recordType, v)] = v
+	}
+
+	values := make([]string, len(actual))
+
+	for i, v := range actual {
+		if c, ok := known[normalizeDnsRecordValue(recordType, v)]; ok {
+			values[i] = c
+		} else {
+			values[i] = v
+		}
+	}
+
+	return values
+}
+
+func normalizeDnsRecordValue(recordType, value string) string {
+	value = strings.TrimSpace(value)
+
+	switch recordType {
+	case "TXT":
+		if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+			value = value[1 : len(value)-1]
+		}
+
+		return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(value)
+	case "CNAME", "MX", "NS", "PTR", "SRV":
+		return strings.TrimSuffix(strings.ToLower(value), ".")
+	}
+
+	return value
+}
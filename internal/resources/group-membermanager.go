@@ -0,0 +1,261 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type GroupMembermanager struct {
+	provider *provider.Provider
+}
+
+type GroupMembermanagerModel struct {
+	Name  types.String `tfsdk:"cn"`
+	User  types.String `tfsdk:"user"`
+	Group types.String `tfsdk:"group"`
+}
+
+func (r *GroupMembermanager) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_membermanager"
+}
+
+func (r *GroupMembermanager) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Allows a user or the members of a group to manage the members of a FreeIPA group.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Name of the managed group",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user": schema.StringAttribute{
+				Description: "User allowed to manage the members of the group",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"group": schema.StringAttribute{
+				Description: "Group whose members are allowed to manage the members of the group",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GroupMembermanager) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config GroupMembermanagerModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.User.IsNull() == config.Group.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user"),
+			"Invalid configuration",
+			`Exactly one of “user” and “group” must be set.`,
+		)
+	}
+}
+
+func (r *GroupMembermanager) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan GroupMembermanagerModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.GroupAddMemberManagerArgs{
+		Cn: plan.Name.ValueString(),
+	}
+	optArgs := &freeipa.GroupAddMemberManagerOptionalArgs{}
+
+	if !plan.User.IsNull() {
+		optArgs.User = &[]string{plan.User.ValueString()}
+	} else {
+		optArgs.Group = &[]string{plan.Group.ValueString()}
+	}
+
+	tflog.Trace(ctx, "Calling GroupAddMemberManager", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().GroupAddMemberManager(args, optArgs)
+	tflog.Trace(ctx, "Called GroupAddMemberManager", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	// Member managers which already manage the group are reported as
+	// failures, treat them as success to keep the creation idempotent.
+	if err == nil {
+		err = utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add group member manager", "Reason: "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *GroupMembermanager) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state GroupMembermanagerModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.GroupShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+	optArgs := &freeipa.GroupShowOptionalArgs{}
+
+	res, withoutMembers, err := utils.GroupShow(ctx, r.provider.Client(), args, optArgs)
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read group", "Reason: "+err.Error())
+		return
+	}
+
+	// The member managers cannot be checked without the member attributes,
+	// keep the state as is.
+	if withoutMembers {
+		return
+	}
+
+	attr, member, managers := "user", state.User.ValueString(), res.Result.MembermanagerUser
+	if state.User.IsNull() {
+		attr, member, managers = "group", state.Group.ValueString(), res.Result.MembermanagerGroup
+	}
+
+	if !slices.Contains(utils.SplitMembermanagers(managers), member) {
+		tflog.Debug(ctx, "Member manager was removed from group", map[string]any{
+			"cn": state.Name.ValueString(),
+			attr: member,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *GroupMembermanager) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All attributes require replacement, there is nothing to update
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *GroupMembermanager) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state GroupMembermanagerModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.GroupRemoveMemberManagerArgs{
+		Cn: state.Name.ValueString(),
+	}
+	optArgs := &freeipa.GroupRemoveMemberManagerOptionalArgs{}
+
+	if !state.User.IsNull() {
+		optArgs.User = &[]string{state.User.ValueString()}
+	} else {
+		optArgs.Group = &[]string{state.Group.ValueString()}
+	}
+
+	tflog.Trace(ctx, "Calling GroupRemoveMemberManager", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().GroupRemoveMemberManager(args, optArgs)
+	tflog.Trace(ctx, "Called GroupRemoveMemberManager", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove group member manager", "Reason: "+err.Error())
+		}
+		return
+	}
+
+	// Member managers removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove group member manager", "Reason: "+err.Error())
+	}
+}
+
+func (r *GroupMembermanager) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.SplitN(req.ID, "/", 3)
+
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" || (parts[1] != "user" && parts[1] != "group") {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<group>/<user|group>/<member manager>”, got %q.", req.ID),
+		)
+		return
+	}
+
+	state := GroupMembermanagerModel{
+		Name:  types.StringValue(parts[0]),
+		User:  types.StringNull(),
+		Group: types.StringNull(),
+	}
+
+	if parts[1] == "user" {
+		state.User = types.StringValue(parts[2])
+	} else {
+		state.Group = types.StringValue(parts[2])
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewGroupMembermanager(p *provider.Provider) resource.Resource {
+	r := &GroupMembermanager{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewGroupMembermanager)
+}
@@ -0,0 +1,775 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+const groupMembershipAdditive = "additive"
+
+// groupMembershipBatchSize is the default number of members added or removed
+// per call, which keeps each call of huge groups within the request timeout.
+const groupMembershipBatchSize = 1000
+
+var groupMembershipModes = []string{"authoritative", groupMembershipAdditive}
+
+type GroupMembership struct {
+	provider *provider.Provider
+}
+
+type GroupMembershipModel struct {
+	Name         types.String `tfsdk:"cn"`
+	MemberUsers  types.Set    `tfsdk:"member_users"`
+	MemberGroups types.Set    `tfsdk:"member_groups"`
+	External     types.Set    `tfsdk:"ipaexternalmember"`
+	Mode         types.String `tfsdk:"mode"`
+	BatchSize    types.Int64  `tfsdk:"batch_size"`
+	Timeouts     types.Object `tfsdk:"timeouts"`
+}
+
+// groupMembershipOperations are the operations whose timeout is configurable,
+// reading the members being a single call.
+var groupMembershipOperations = []string{"create", "update", "delete"}
+
+// additive reports whether only the configured members are managed.
+func (m GroupMembershipModel) additive() bool {
+	return m.Mode.ValueString() == groupMembershipAdditive
+}
+
+// batchSize returns the number of members added or removed per call.
+func (m GroupMembershipModel) batchSize() int {
+	if m.BatchSize.IsNull() || m.BatchSize.IsUnknown() {
+		return groupMembershipBatchSize
+	}
+
+	return int(m.BatchSize.ValueInt64())
+}
+
+// groupMembers are the direct user, group and external members of a group.
+// The external members are SIDs when read from FreeIPA, SIDs or names of
+// trusted domain users and groups when configured.
+type groupMembers struct {
+	users    []string
+	groups   []string
+	external []string
+}
+
+func (m groupMembers) empty() bool {
+	return len(m.users) == 0 && len(m.groups) == 0 && len(m.external) == 0
+}
+
+func (r *GroupMembership) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_membership"
+}
+
+func (r *GroupMembership) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages the user, group and external members of a FreeIPA group, either the full list or only the configured members.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Group name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"member_users": schema.SetAttribute{
+				Description: "Users members of the group. Users added outside of Terraform are removed in authoritative mode, the user members are not managed when unset",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"member_groups": schema.SetAttribute{
+				Description: "Groups members of the group. Groups added outside of Terraform are removed in authoritative mode, the group members are not managed when unset",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"ipaexternalmember": schema.SetAttribute{
+				Description: "External members of the group, users and groups of trusted domains given by SID or by name such as `jdoe@AD.EXAMPLE.COM`. The group must be external. External members added outside of Terraform are removed in authoritative mode, the external members are not managed when unset",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"mode": schema.StringAttribute{
+				Description: "Membership mode, one of " + strings.Join(groupMembershipModes, ", ") + ". In `authoritative` mode the members added outside of Terraform are removed, in `additive` mode only the configured members are added, tracked and removed. Defaults to `authoritative`",
+				Optional:    true,
+			},
+			"batch_size": schema.Int64Attribute{
+				Description: "Maximum number of members added or removed per call. Each batch is committed on its own, so an interrupted apply of a huge group resumes with the remaining members. Defaults to `1000`",
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(groupMembershipOperations...),
+		},
+	}
+}
+
+func (r *GroupMembership) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config GroupMembershipModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.MemberUsers.IsNull() && config.MemberGroups.IsNull() && config.External.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("member_users"),
+			"Invalid configuration",
+			`At least one of “member_users”, “member_groups” and “ipaexternalmember” must be set.`,
+		)
+	}
+
+	if !config.Mode.IsUnknown() && !config.Mode.IsNull() && !slices.Contains(groupMembershipModes, config.Mode.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("mode"),
+			"Invalid configuration",
+			fmt.Sprintf("Unsupported membership mode “%s”, expected one of %s.", config.Mode.ValueString(), strings.Join(groupMembershipModes, ", ")),
+		)
+	}
+
+	if !config.BatchSize.IsUnknown() && !config.BatchSize.IsNull() && config.BatchSize.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("batch_size"),
+			"Invalid configuration",
+			`“batch_size” must be at least 1.`,
+		)
+	}
+
+	validateTimeouts(config.Timeouts, &resp.Diagnostics)
+}
+
+func (r *GroupMembership) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan GroupMembershipModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts, "create")
+	defer cancel()
+
+	resp.Diagnostics.Append(r.apply(ctx, plan, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *GroupMembership) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state GroupMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	actual, err := r.members(ctx, state.Name.ValueString())
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read group members", "Reason: "+err.Error())
+
+		return
+	}
+
+	// Unset attributes are not managed, the members of their kind are ignored.
+	// In additive mode, only the configured members which are still in the
+	// group are tracked.
+	kinds := []struct {
+		attr   *types.Set
+		actual []string
+	}{
+		{&state.MemberUsers, actual.users},
+		{&state.MemberGroups, actual.groups},
+	}
+
+	for _, k := range kinds {
+		if k.attr.IsNull() {
+			continue
+		}
+
+		members := k.actual
+
+		if state.additive() {
+			var configured []string
+
+			resp.Diagnostics.Append(setToStringSlice(ctx, *k.attr, &configured)...)
+
+			members = trackedMembers(configured, k.actual)
+		}
+
+		*k.attr = stringSliceToSet(ctx, &members, false, &resp.Diagnostics)
+	}
+
+	if !state.External.IsNull() {
+		var configured []string
+
+		resp.Diagnostics.Append(setToStringSlice(ctx, state.External, &configured)...)
+
+		// The configured names are kept for the SIDs they resolve to
+		found, extra := matchExternalMembers(configured, actual.external, r.resolveSIDs(ctx, actual.external))
+
+		external := found
+		if !state.additive() {
+			external = append(found, extra...)
+		}
+
+		state.External = stringSliceToSet(ctx, &external, false, &resp.Diagnostics)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *GroupMembership) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan GroupMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, plan.Timeouts, "update")
+	defer cancel()
+
+	resp.Diagnostics.Append(r.apply(ctx, plan, &state)...)
+
+	// The batches committed before the failure are picked up by the next
+	// refresh, the prior state lets an additive membership resume its removals
+	if resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+		return
+	}
+
+	// Members of a kind which is no longer managed are left in the group
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *GroupMembership) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state GroupMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var members groupMembers
+
+	resp.Diagnostics.Append(setToStringSlice(ctx, state.MemberUsers, &members.users)...)
+	resp.Diagnostics.Append(setToStringSlice(ctx, state.MemberGroups, &members.groups)...)
+	resp.Diagnostics.Append(setToStringSlice(ctx, state.External, &members.external)...)
+
+	if resp.Diagnostics.HasError() || members.empty() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, state.Timeouts, "delete")
+	defer cancel()
+
+	err := r.inBatches(ctx, "remove", state.Name.ValueString(), members, state.batchSize(), r.removeMembers)
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove group members", "Reason: "+err.Error())
+		}
+	}
+}
+
+func (r *GroupMembership) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Both kinds are imported, the one which is not configured stops being
+	// managed on the next apply. The mode is authoritative until an additive
+	// configuration is applied, which then removes no member.
+	state := GroupMembershipModel{
+		Name:         types.StringValue(req.ID),
+		MemberUsers:  types.SetValueMust(types.StringType, nil),
+		MemberGroups: types.SetValueMust(types.StringType, nil),
+		External:     types.SetValueMust(types.StringType, nil),
+		Timeouts:     timeoutsNull(groupMembershipOperations...),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// apply reads the current members of the group and sends a single add and a
+// single remove call with the members which differ from plan. In additive
+// mode, only the members which were removed from the prior state, when it
+// was additive too, are removed.
+func (r *GroupMembership) apply(ctx context.Context, plan GroupMembershipModel, state *GroupMembershipModel) (diags diag.Diagnostics) {
+	name := plan.Name.ValueString()
+
+	actual, err := r.members(ctx, name)
+	if err != nil {
+		diags.AddError("Failed to read group members", "Reason: "+err.Error())
+
+		return
+	}
+
+	// Members leaving an authoritative membership, or an import, were never
+	// owned by an additive one and are left in the group
+	var prior GroupMembershipModel
+	if state != nil && state.additive() {
+		prior = *state
+	}
+
+	// Kinds which are not managed are kept as they are
+	desired := actual
+
+	kinds := []struct {
+		desired     *[]string
+		plan, prior types.Set
+	}{
+		{&desired.users, plan.MemberUsers, prior.MemberUsers},
+		{&desired.groups, plan.MemberGroups, prior.MemberGroups},
+	}
+
+	for _, k := range kinds {
+		if k.plan.IsNull() {
+			continue
+		}
+
+		var configured []string
+
+		diags.Append(setToStringSlice(ctx, k.plan, &configured)...)
+
+		if plan.additive() {
+			var removed []string
+
+			diags.Append(setToStringSlice(ctx, k.prior, &removed)...)
+
+			_, removed = utils.SetDiff(removed, slices.Clone(configured))
+			configured = additiveMembers(*k.desired, configured, removed)
+		}
+
+		*k.desired = configured
+	}
+
+	if diags.HasError() {
+		return
+	}
+
+	toAdd, toRemove := groupMembershipDelta(actual, desired)
+
+	// External members are stored as SIDs, the configured names are matched
+	// with the SIDs they resolve to
+	if !plan.External.IsNull() {
+		var configured []string
+
+		diags.Append(setToStringSlice(ctx, plan.External, &configured)...)
+
+		if diags.HasError() {
+			return
+		}
+
+		names := r.resolveSIDs(ctx, actual.external)
+		found, extra := matchExternalMembers(configured, actual.external, names)
+
+		toAdd.external, _ = utils.SetDiff(found, configured)
+		toRemove.external = extra
+
+		if plan.additive() {
+			var removed []string
+
+			diags.Append(setToStringSlice(ctx, prior.External, &removed)...)
+
+			if diags.HasError() {
+				return
+			}
+
+			_, removed = utils.SetDiff(removed, slices.Clone(configured))
+
+			// The removed names are matched with the SIDs still in the group
+			toRemove.external, _ = matchExternalMembers(removed, actual.external, names)
+		}
+	}
+
+	if toAdd.empty() && toRemove.empty() {
+		tflog.Debug(ctx, "Updated group membership has no effective difference", map[string]any{
+			"cn": name,
+		})
+
+		return
+	}
+
+	if !toAdd.empty() {
+		if err := r.inBatches(ctx, "add", name, toAdd, plan.batchSize(), r.addMembers); err != nil {
+			diags.AddError("Failed to add group members", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	if !toRemove.empty() {
+		if err := r.inBatches(ctx, "remove", name, toRemove, plan.batchSize(), r.removeMembers); err != nil {
+			diags.AddError("Failed to remove group members", "Reason: "+err.Error())
+		}
+	}
+
+	return
+}
+
+// members returns the direct user and group members of the group.
+func (r *GroupMembership) members(ctx context.Context, name string) (groupMembers, error) {
+	args := &freeipa.GroupShowArgs{
+		Cn: name,
+	}
+	optArgs := &freeipa.GroupShowOptionalArgs{}
+
+	res, withoutMembers, err := utils.GroupShow(ctx, r.provider.Client(), args, optArgs)
+	if err != nil {
+		return groupMembers{}, err
+	}
+
+	if withoutMembers {
+		return groupMembers{}, errors.New("go-freeipa cannot decode the members of the group")
+	}
+
+	var members groupMembers
+
+	if res.Result.MemberUser != nil {
+		members.users = *res.Result.MemberUser
+	}
+	if res.Result.MemberGroup != nil {
+		members.groups = *res.Result.MemberGroup
+	}
+	if res.Result.Ipaexternalmember != nil {
+		members.external = *res.Result.Ipaexternalmember
+	}
+
+	return members, nil
+}
+
+// resolveSIDs returns the names of the trusted domain users and groups with
+// the given SIDs, by upper case SID. SIDs which cannot be resolved, e.g. while
+// the trusted domain controllers are unreachable, have no name.
+func (r *GroupMembership) resolveSIDs(ctx context.Context, sids []string) map[string]string {
+	names := map[string]string{}
+
+	if len(sids) == 0 {
+		return names
+	}
+
+	args := &freeipa.TrustResolveArgs{
+		Sids: sids,
+	}
+
+	tflog.Trace(ctx, "Calling TrustResolve", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().TrustResolve(args, nil)
+
+	tflog.Trace(ctx, "Called TrustResolve", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		tflog.Warn(ctx, "Failed to resolve external members", map[string]any{
+			"err": err.Error(),
+		})
+
+		return names
+	}
+
+	for _, entry := range res.Result {
+		e, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		sid, name := rpcStringValues(e["sid"]), rpcStringValues(e["name"])
+		if len(sid) > 0 && len(name) > 0 {
+			names[strings.ToUpper(sid[0])] = name[0]
+		}
+	}
+
+	return names
+}
+
+// inBatches calls fn with batches of at most size members, reporting the
+// progress. It stops at the first failed batch, the previous ones are kept.
+func (r *GroupMembership) inBatches(ctx context.Context, action, name string, members groupMembers, size int, fn func(context.Context, string, groupMembers) error) error {
+	batches := groupMembersBatches(members, size)
+
+	for i, batch := range batches {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("interrupted after %d of %d batches: %w", i, len(batches), err)
+		}
+
+		if err := fn(ctx, name, batch); err != nil {
+			if len(batches) == 1 {
+				return err
+			}
+
+			return fmt.Errorf("batch %d of %d: %w", i+1, len(batches), err)
+		}
+
+		tflog.Debug(ctx, "Group membership batch applied", map[string]any{
+			"cn":      name,
+			"action":  action,
+			"batch":   i + 1,
+			"batches": len(batches),
+			"members": len(batch.users) + len(batch.groups) + len(batch.external),
+		})
+	}
+
+	return nil
+}
+
+// addMembers adds the members to the group with a call bound to ctx, so that
+// the timeout of the operation applies to each batch.
+func (r *GroupMembership) addMembers(ctx context.Context, name string, members groupMembers) error {
+	var res freeipa.GroupAddMemberResult
+
+	if err := r.changeMembers(ctx, "group_add_member", name, members, &res); err != nil {
+		return err
+	}
+
+	// Members added concurrently are reported as failures, ignore them
+	return utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember)
+}
+
+// removeMembers removes the members from the group with a call bound to ctx.
+func (r *GroupMembership) removeMembers(ctx context.Context, name string, members groupMembers) error {
+	var res freeipa.GroupRemoveMemberResult
+
+	if err := r.changeMembers(ctx, "group_remove_member", name, members, &res); err != nil {
+		return err
+	}
+
+	// Members removed out-of-band are reported as failures, ignore them
+	return utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry)
+}
+
+// changeMembers sends method, group_add_member or group_remove_member, with
+// the members. go-freeipa does not bind its calls to a context, they are sent
+// with the RPC client instead.
+func (r *GroupMembership) changeMembers(ctx context.Context, method, name string, members groupMembers, res interface{}) error {
+	args := []interface{}{name}
+
+	options := map[string]interface{}{
+		"no_members": true,
+	}
+
+	if len(members.users) > 0 {
+		options["user"] = members.users
+	}
+	if len(members.groups) > 0 {
+		options["group"] = members.groups
+	}
+	if len(members.external) > 0 {
+		options["ipaexternalmember"] = members.external
+	}
+
+	tflog.Trace(ctx, "Calling "+method, map[string]any{
+		"args":    args,
+		"options": options,
+	})
+
+	err := r.provider.RPC().Call(ctx, method, args, options, res)
+
+	tflog.Trace(ctx, "Called "+method, map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	return err
+}
+
+// groupMembershipDelta returns the members to add and to remove to go from the
+// actual members of a group to the desired ones.
+func groupMembershipDelta(actual, desired groupMembers) (toAdd, toRemove groupMembers) {
+	toAdd.users, toRemove.users = utils.SetDiff(slices.Clone(actual.users), slices.Clone(desired.users))
+	toAdd.groups, toRemove.groups = utils.SetDiff(slices.Clone(actual.groups), slices.Clone(desired.groups))
+
+	return
+}
+
+// groupMembersBatches splits the members into batches of at most size
+// members, users first, then groups and external members.
+func groupMembersBatches(members groupMembers, size int) []groupMembers {
+	var batches []groupMembers
+
+	var batch groupMembers
+	count := 0
+
+	kinds := []struct {
+		members []string
+		batch   *[]string
+	}{
+		{members.users, &batch.users},
+		{members.groups, &batch.groups},
+		{members.external, &batch.external},
+	}
+
+	for _, k := range kinds {
+		for _, m := range k.members {
+			*k.batch = append(*k.batch, m)
+			count++
+
+			if count == size {
+				batches = append(batches, batch)
+				batch = groupMembers{}
+				count = 0
+			}
+		}
+	}
+
+	if count > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// additiveMembers returns the actual members of a kind managed in additive
+// mode, without the removed members and with the configured ones.
+func additiveMembers(actual, configured, removed []string) []string {
+	skip := map[string]bool{}
+
+	for _, m := range append(slices.Clone(removed), configured...) {
+		skip[m] = true
+	}
+
+	members := []string{}
+
+	for _, m := range actual {
+		if !skip[m] {
+			members = append(members, m)
+		}
+	}
+
+	return append(members, configured...)
+}
+
+// trackedMembers returns the configured members which are actual members,
+// which is all that an additive membership reports.
+func trackedMembers(configured, actual []string) []string {
+	present := map[string]bool{}
+
+	for _, m := range actual {
+		present[m] = true
+	}
+
+	members := []string{}
+
+	for _, m := range configured {
+		if present[m] {
+			members = append(members, m)
+		}
+	}
+
+	return members
+}
+
+// matchExternalMembers pairs the configured external members with the SIDs
+// of the group, given the names the SIDs resolve to. It returns the
+// configured members found in the group and the SIDs matching none of them.
+// Configured names left unmatched while some SIDs cannot be resolved are
+// assumed to be these SIDs, which are then neither reported nor removed.
+func matchExternalMembers(configured, sids []string, names map[string]string) (found, extra []string) {
+	found = []string{}
+	extra = []string{}
+
+	var unresolved []string
+
+	matched := map[string]bool{}
+
+	for _, sid := range sids {
+		name := names[strings.ToUpper(sid)]
+
+		i := slices.IndexFunc(configured, func(c string) bool {
+			return !matched[c] && (strings.EqualFold(c, sid) || name != "" && strings.EqualFold(c, name))
+		})
+
+		switch {
+		case i >= 0:
+			matched[configured[i]] = true
+			found = append(found, configured[i])
+		case name == "":
+			unresolved = append(unresolved, sid)
+		default:
+			extra = append(extra, sid)
+		}
+	}
+
+	var unmatched []string
+
+	for _, c := range configured {
+		if !matched[c] && !isSID(c) {
+			unmatched = append(unmatched, c)
+		}
+	}
+
+	if len(unresolved) > 0 && len(unmatched) > 0 {
+		found = append(found, unmatched...)
+	} else {
+		extra = append(extra, unresolved...)
+	}
+
+	return
+}
+
+// isSID reports whether member is a SID rather than a name.
+func isSID(member string) bool {
+	return strings.HasPrefix(strings.ToUpper(member), "S-1-")
+}
+
+// setToStringSlice converts a set attribute to a slice, leaving target
+// untouched when the set is null or unknown.
+func setToStringSlice(ctx context.Context, set types.Set, target *[]string) diag.Diagnostics {
+	if set.IsNull() || set.IsUnknown() {
+		return nil
+	}
+
+	return set.ElementsAs(ctx, target, false)
+}
+
+func NewGroupMembership(p *provider.Provider) resource.Resource {
+	r := &GroupMembership{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewGroupMembership)
+}
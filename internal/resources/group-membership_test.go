@@ -0,0 +1,300 @@
+package resources
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestGroupMembershipDelta(t *testing.T) {
+	cases := []struct {
+		name            string
+		actual, desired groupMembers
+		toAdd, toRemove groupMembers
+	}{
+		{
+			name:    "no difference",
+			actual:  groupMembers{users: []string{"alice", "bob"}, groups: []string{"admins"}},
+			desired: groupMembers{users: []string{"bob", "alice"}, groups: []string{"admins"}},
+		},
+		{
+			name:    "new group",
+			desired: groupMembers{users: []string{"alice", "bob"}, groups: []string{"admins"}},
+			toAdd:   groupMembers{users: []string{"alice", "bob"}, groups: []string{"admins"}},
+		},
+		{
+			name:     "only the delta",
+			actual:   groupMembers{users: []string{"alice", "bob", "carol"}, groups: []string{"admins", "ops"}},
+			desired:  groupMembers{users: []string{"alice", "carol", "dave"}, groups: []string{"ops"}},
+			toAdd:    groupMembers{users: []string{"dave"}},
+			toRemove: groupMembers{users: []string{"bob"}, groups: []string{"admins"}},
+		},
+		{
+			name:     "all removed",
+			actual:   groupMembers{users: []string{"alice"}, groups: []string{"admins"}},
+			desired:  groupMembers{},
+			toRemove: groupMembers{users: []string{"alice"}, groups: []string{"admins"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := groupMembers{users: append([]string(nil), c.actual.users...), groups: append([]string(nil), c.actual.groups...)}
+
+			toAdd, toRemove := groupMembershipDelta(c.actual, c.desired)
+
+			if !reflect.DeepEqual(toAdd, c.toAdd) {
+				t.Errorf("members to add: got %+v, want %+v", toAdd, c.toAdd)
+			}
+			if !reflect.DeepEqual(toRemove, c.toRemove) {
+				t.Errorf("members to remove: got %+v, want %+v", toRemove, c.toRemove)
+			}
+			if !reflect.DeepEqual(c.actual, actual) {
+				t.Errorf("actual members were modified: got %+v, want %+v", c.actual, actual)
+			}
+		})
+	}
+}
+
+func TestGroupMembershipDeltaLargeGroup(t *testing.T) {
+	var actual, desired groupMembers
+
+	for i := 0; i < 500; i++ {
+		user := fmt.Sprintf("user%03d", i)
+
+		actual.users = append(actual.users, user)
+		if i != 42 {
+			desired.users = append(desired.users, user)
+		}
+	}
+	desired.users = append(desired.users, "newcomer")
+
+	toAdd, toRemove := groupMembershipDelta(actual, desired)
+
+	if want := (groupMembers{users: []string{"newcomer"}}); !reflect.DeepEqual(toAdd, want) {
+		t.Errorf("members to add: got %+v, want %+v", toAdd, want)
+	}
+	if want := (groupMembers{users: []string{actual.users[42]}}); !reflect.DeepEqual(toRemove, want) {
+		t.Errorf("members to remove: got %+v, want %+v", toRemove, want)
+	}
+}
+
+func TestMatchExternalMembers(t *testing.T) {
+	const (
+		adminsSID = "S-1-5-21-1111-2222-3333-512"
+		jdoeSID   = "S-1-5-21-1111-2222-3333-1104"
+		otherSID  = "S-1-5-21-1111-2222-3333-1105"
+	)
+
+	cases := []struct {
+		name         string
+		configured   []string
+		sids         []string
+		names        map[string]string
+		found, extra []string
+	}{
+		{
+			name:       "by SID",
+			configured: []string{strings.ToLower(adminsSID)},
+			sids:       []string{adminsSID},
+			found:      []string{strings.ToLower(adminsSID)},
+			extra:      []string{},
+		},
+		{
+			name:       "by name",
+			configured: []string{"JDoe@AD.EXAMPLE.COM"},
+			sids:       []string{jdoeSID, otherSID},
+			names:      map[string]string{jdoeSID: "jdoe@ad.example.com", otherSID: "other@ad.example.com"},
+			found:      []string{"JDoe@AD.EXAMPLE.COM"},
+			extra:      []string{otherSID},
+		},
+		{
+			name:       "not added yet",
+			configured: []string{"jdoe@ad.example.com", adminsSID},
+			sids:       []string{otherSID},
+			names:      map[string]string{otherSID: "other@ad.example.com"},
+			found:      []string{},
+			extra:      []string{otherSID},
+		},
+		{
+			name:       "unresolved",
+			configured: []string{"jdoe@ad.example.com"},
+			sids:       []string{jdoeSID},
+			found:      []string{"jdoe@ad.example.com"},
+			extra:      []string{},
+		},
+		{
+			name:       "unresolved without configured names",
+			configured: []string{adminsSID},
+			sids:       []string{adminsSID, jdoeSID},
+			found:      []string{adminsSID},
+			extra:      []string{jdoeSID},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			found, extra := matchExternalMembers(c.configured, c.sids, c.names)
+
+			if !reflect.DeepEqual(found, c.found) {
+				t.Errorf("found members: got %v, want %v", found, c.found)
+			}
+			if !reflect.DeepEqual(extra, c.extra) {
+				t.Errorf("extra members: got %v, want %v", extra, c.extra)
+			}
+		})
+	}
+}
+
+func TestAdditiveMembers(t *testing.T) {
+	actual := []string{"alice", "bob", "manual"}
+
+	// Members added outside of Terraform are kept, the removed ones are not
+	got := additiveMembers(actual, []string{"alice", "carol"}, []string{"bob"})
+
+	toAdd, toRemove := groupMembershipDelta(groupMembers{users: actual}, groupMembers{users: got})
+
+	if want := (groupMembers{users: []string{"carol"}}); !reflect.DeepEqual(toAdd, want) {
+		t.Errorf("members to add: got %+v, want %+v", toAdd, want)
+	}
+	if want := (groupMembers{users: []string{"bob"}}); !reflect.DeepEqual(toRemove, want) {
+		t.Errorf("members to remove: got %+v, want %+v", toRemove, want)
+	}
+}
+
+func TestTrackedMembers(t *testing.T) {
+	got := trackedMembers([]string{"alice", "carol"}, []string{"alice", "bob", "manual"})
+
+	if want := []string{"alice"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tracked members: got %v, want %v", got, want)
+	}
+}
+
+func TestGroupMembersBatches(t *testing.T) {
+	members := groupMembers{
+		users:    []string{"alice", "bob", "carol"},
+		groups:   []string{"admins", "ops"},
+		external: []string{"jdoe@ad.example.com"},
+	}
+
+	got := groupMembersBatches(members, 4)
+
+	want := []groupMembers{
+		{users: []string{"alice", "bob", "carol"}, groups: []string{"admins"}},
+		{groups: []string{"ops"}, external: []string{"jdoe@ad.example.com"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("batches: got %+v, want %+v", got, want)
+	}
+
+	if got := groupMembersBatches(members, groupMembershipBatchSize); len(got) != 1 || !reflect.DeepEqual(got[0], members) {
+		t.Errorf("single batch: got %+v, want %+v", got, members)
+	}
+}
+
+func TestGroupMembershipTimeouts(t *testing.T) {
+	shown := func() (int, string) {
+		return http.StatusOK, `{"result":{"result":{"cn":["developers"]},"value":"developers","summary":null},"error":null}`
+	}
+
+	added := func() (int, string) {
+		return http.StatusOK, `{"result":{"result":{"cn":["developers"]},"failed":{"member":{"user":[],"group":[]}},"completed":1},"error":null}`
+	}
+
+	// The call outlives the timeout of the operation
+	slow := func() (int, string) {
+		time.Sleep(500 * time.Millisecond)
+
+		return added()
+	}
+
+	timeoutsType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"create": tftypes.String,
+		"update": tftypes.String,
+		"delete": tftypes.String,
+	}}
+
+	timeouts := func(create string) tftypes.Value {
+		return testObject(timeoutsType, map[string]tftypes.Value{"create": tftypes.NewValue(tftypes.String, create)})
+	}
+
+	cases := map[string]struct {
+		add      func() (int, string)
+		timeouts tftypes.Value
+		failed   bool
+	}{
+		"default":         {added, tftypes.NewValue(timeoutsType, nil), false},
+		"within timeout":  {added, timeouts("1m"), false},
+		"timeout reached": {slow, timeouts("50ms"), true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			server, fake, schemas := testProviderServer(t, map[string]func() (int, string){
+				"group_show":       shown,
+				"group_add_member": c.add,
+			}, nil)
+
+			start := time.Now()
+
+			_, diags := testApply(t, server, schemas, "freeipa_group_membership", nil, map[string]tftypes.Value{
+				"cn":           tftypes.NewValue(tftypes.String, "developers"),
+				"member_users": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "jdoe")}),
+				"timeouts":     c.timeouts,
+			})
+
+			if !fake.called("group_add_member") {
+				t.Fatal("group_add_member was not called")
+			}
+
+			if c.failed && (!hasError(diags, "Failed to add group members") || time.Since(start) >= 500*time.Millisecond) {
+				t.Errorf("got diagnostics %v after %s, want the call interrupted by the timeout", diags, time.Since(start))
+			}
+
+			if !c.failed && len(diags) != 0 {
+				t.Errorf("unexpected diagnostics: %v", diags)
+			}
+		})
+	}
+}
+
+func TestGroupMembershipValidateConfigTimeouts(t *testing.T) {
+	server, _, _ := testProviderServer(t, nil, nil)
+
+	cases := map[string]struct {
+		create  string
+		invalid bool
+	}{
+		"duration":    {"30m", false},
+		"no unit":     {"30", true},
+		"not a value": {"soon", true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			diags := testValidate(t, server, "freeipa_group_membership", map[string]tftypes.Value{
+				"cn":           tftypes.NewValue(tftypes.String, "developers"),
+				"member_users": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{}),
+				"timeouts": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+					"create": tftypes.String,
+					"update": tftypes.String,
+					"delete": tftypes.String,
+				}}, map[string]tftypes.Value{
+					"create": tftypes.NewValue(tftypes.String, c.create),
+					"update": tftypes.NewValue(tftypes.String, nil),
+					"delete": tftypes.NewValue(tftypes.String, nil),
+				}),
+			})
+
+			if got := hasError(diags, "Invalid configuration"); got != c.invalid {
+				t.Errorf("got diagnostics %v, want errors: %t", diags, c.invalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type GroupNestedMembership struct {
+	provider *provider.Provider
+}
+
+type GroupNestedMembershipModel struct {
+	Name        types.String `tfsdk:"cn"`
+	MemberGroup types.String `tfsdk:"member_group"`
+}
+
+func (r *GroupNestedMembership) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_nested_membership"
+}
+
+func (r *GroupNestedMembership) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Adds a FreeIPA group to another group, independently from the user members of the group.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Name of the group the member group is added to",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"member_group": schema.StringAttribute{
+				Description: "Name of the group added as member",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GroupNestedMembership) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config GroupNestedMembershipModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Name.IsUnknown() || config.MemberGroup.IsUnknown() {
+		return
+	}
+
+	if strings.EqualFold(config.Name.ValueString(), config.MemberGroup.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("member_group"),
+			"Invalid configuration",
+			`A group cannot be a member of itself, “member_group” must differ from “cn”.`,
+		)
+	}
+}
+
+func (r *GroupNestedMembership) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan GroupNestedMembershipModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+	member := plan.MemberGroup.ValueString()
+
+	// FreeIPA refuses membership cycles, look for one beforehand to report the
+	// groups involved
+	cycle, err := r.isMember(ctx, member, name, true)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read group memberships", "Reason: "+err.Error())
+
+		return
+	}
+
+	if cycle {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("member_group"),
+			"Group membership cycle",
+			fmt.Sprintf("Group %q is already a direct or indirect member of group %q, adding %q to %q would create a cycle.", name, member, member, name),
+		)
+
+		return
+	}
+
+	args := &freeipa.GroupAddMemberArgs{
+		Cn: name,
+	}
+	optArgs := &freeipa.GroupAddMemberOptionalArgs{
+		Group:     &[]string{member},
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling GroupAddMember", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().GroupAddMember(args, optArgs)
+
+	tflog.Trace(ctx, "Called GroupAddMember", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	// Groups which already belong to the group are reported as failures,
+	// treat them as success to keep the creation idempotent. Other failures,
+	// such as a cycle created concurrently, leave the group unchanged.
+	if err == nil {
+		err = utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add group to group", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *GroupNestedMembership) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state GroupNestedMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	isMember, err := r.isMember(ctx, state.Name.ValueString(), state.MemberGroup.ValueString(), false)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read group memberships", "Reason: "+err.Error())
+
+		return
+	}
+
+	// Also covers groups deleted out-of-band
+	if !isMember {
+		tflog.Debug(ctx, "Group was removed from group", map[string]any{
+			"cn":           state.Name.ValueString(),
+			"member_group": state.MemberGroup.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *GroupNestedMembership) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan GroupNestedMembershipModel
+
+	// All attributes require replacement, there is nothing to update
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *GroupNestedMembership) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state GroupNestedMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.GroupRemoveMemberArgs{
+		Cn: state.Name.ValueString(),
+	}
+	optArgs := &freeipa.GroupRemoveMemberOptionalArgs{
+		Group:     &[]string{state.MemberGroup.ValueString()},
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling GroupRemoveMember", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().GroupRemoveMember(args, optArgs)
+
+	tflog.Trace(ctx, "Called GroupRemoveMember", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove group from group", "Reason: "+err.Error())
+		}
+
+		return
+	}
+
+	// Groups removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove group from group", "Reason: "+err.Error())
+	}
+}
+
+func (r *GroupNestedMembership) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	name, member, ok := strings.Cut(req.ID, "/")
+
+	if !ok || name == "" || member == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<cn>/<member_group>”, got %q.", req.ID),
+		)
+
+		return
+	}
+
+	state := GroupNestedMembershipModel{
+		Name:        types.StringValue(name),
+		MemberGroup: types.StringValue(member),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// isMember reports whether member is a member of the group name, directly
+// or, when indirect is set, through other groups. Missing groups are members
+// of none.
+func (r *GroupNestedMembership) isMember(ctx context.Context, name, member string, indirect bool) (bool, error) {
+	// The groups are searched by their members, reading the group itself may
+	// fail to decode its member managers
+	args := &freeipa.GroupFindArgs{}
+	optArgs := &freeipa.GroupFindOptionalArgs{
+		PkeyOnly: freeipa.Bool(true),
+	}
+
+	if indirect {
+		// memberOf holds the indirect memberships as well
+		optArgs.Cn = &member
+		optArgs.InGroup = &[]string{name}
+	} else {
+		optArgs.Cn = &name
+		optArgs.Group = &[]string{member}
+	}
+
+	tflog.Trace(ctx, "Calling GroupFind", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().GroupFind("", args, optArgs)
+
+	tflog.Trace(ctx, "Called GroupFind", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	// The cn option does not only match whole names
+	expected := name
+	if indirect {
+		expected = member
+	}
+
+	return slices.ContainsFunc(res.Result, func(group freeipa.Group) bool {
+		return strings.EqualFold(group.Cn, expected)
+	}), nil
+}
+
+func NewGroupNestedMembership(p *provider.Provider) resource.Resource {
+	r := &GroupNestedMembership{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewGroupNestedMembership)
+}
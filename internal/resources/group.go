@@ -3,15 +3,17 @@ package resources
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/camptocamp/go-freeipa/freeipa"
 	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
 	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -37,32 +39,45 @@ func (r *Group) Schema(ctx context.Context, req resource.SchemaRequest, resp *re
 		Version: 0,
 		Attributes: map[string]schema.Attribute{
 			"cn": schema.StringAttribute{
-				Required: true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
+				Description: "Group name. Changing it renames the group in place, keeping its members",
+				Required:    true,
 			},
 			"description": schema.StringAttribute{
 				Optional: true,
 			},
 			"gidnumber": schema.Int64Attribute{
-				Optional: true,
+				Description: "GID number (assigned by FreeIPA when not set)",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"nonposix": schema.BoolAttribute{
-				Description: "Create as a non-POSIX group",
+				Description: "Create as a non-POSIX group. Changing it replaces the group",
 				Optional:    true,
 				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
+					boolplanmodifier.RequiresReplaceIf(nonPosixRequiresReplace, "Requires replacement when changed, unset and false being equivalent", "Requires replacement when changed, unset and `false` being equivalent"),
 				},
 			},
 			"external": schema.BoolAttribute{
 				Description: "Allow adding external non-IPA members from trusted domains",
 				Optional:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
 		},
 	}
 }
 
+// nonPosixRequiresReplace replaces the group when nonposix is changed in
+// either direction, unset and false being equivalent, e.g. after an import.
+// A GID number FreeIPA assigns on its own does not replace the group.
+func nonPosixRequiresReplace(ctx context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = req.PlanValue.ValueBool() != req.StateValue.ValueBool()
+}
+
 func (r *Group) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan, state GroupModel
 
@@ -71,19 +86,32 @@ func (r *Group) Create(ctx context.Context, req resource.CreateRequest, resp *re
 		return
 	}
 
-	args := &freeipa.GroupAddArgs{
-		Cn: plan.Name.ValueString(),
+	// FreeIPA only reports a duplicated GID number late and cryptically, the
+	// group holding it is looked up first
+	if !plan.GID.IsNull() && !plan.GID.IsUnknown() {
+		owner, err := r.gidNumberOwner(ctx, int(plan.GID.ValueInt64()))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to check GID number", "Reason: "+err.Error())
+			return
+		}
+
+		if owner != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("gidnumber"),
+				"GID number already in use",
+				fmt.Sprintf("The GID number %d is already used by the group “%s”.", plan.GID.ValueInt64(), owner),
+			)
+			return
+		}
 	}
 
-	var gid *int
-	if !plan.GID.IsNull() {
-		gid = new(int)
-		*gid = int(plan.GID.ValueInt64())
+	args := &freeipa.GroupAddArgs{
+		Cn: plan.Name.ValueString(),
 	}
 
 	optArgs := &freeipa.GroupAddOptionalArgs{
 		Description: plan.Description.ValueStringPointer(),
-		Gidnumber:   gid,
+		Gidnumber:   int64ToIntPointer(plan.GID),
 		Nonposix:    plan.NonPosix.ValueBoolPointer(),
 		External:    plan.External.ValueBoolPointer(),
 	}
@@ -113,6 +141,12 @@ func (r *Group) Create(ctx context.Context, req resource.CreateRequest, resp *re
 
 	state = plan
 
+	if res != nil {
+		state.GID = intToInt64Value(res.Result.Gidnumber)
+	} else if state.GID.IsUnknown() {
+		state.GID = types.Int64Null()
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
@@ -129,29 +163,17 @@ func (r *Group) Read(ctx context.Context, req resource.ReadRequest, resp *resour
 	}
 	optArgs := &freeipa.GroupShowOptionalArgs{}
 
-	tflog.Trace(ctx, "Calling GroupShow", map[string]any{
-		"args":     args,
-		"opt_args": optArgs,
-	})
-
-	res, err := r.provider.Client().GroupShow(args, optArgs)
-	tflog.Trace(ctx, "Called GroupShow", map[string]any{
-		"res": res,
-		"err": err,
-	})
+	res, _, err := utils.GroupShow(ctx, r.provider.Client(), args, optArgs)
 	if err != nil {
-		var freeipaErr *freeipa.Error
-
-		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
-			resp.State.RemoveResource(ctx)
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
 			return
 		}
 
 		if utils.IsMembermanagerGroupDecodeError(err) {
-			tflog.Warn(ctx, "Ignoring go-freeipa MembermanagerGroup decode error on GroupShow", map[string]any{
-				"err": err.Error(),
-				"cn":  state.Name.ValueString(),
-			})
+			resp.Diagnostics.AddWarning(
+				"Group could not be refreshed",
+				"go-freeipa failed to decode the membership manager attributes of group “"+state.Name.ValueString()+"”, the last known state is kept. Reason: "+err.Error(),
+			)
 			return
 		}
 
@@ -159,12 +181,8 @@ func (r *Group) Read(ctx context.Context, req resource.ReadRequest, resp *resour
 		return
 	}
 
-	var gid *int64
-	if res.Result.Gidnumber != nil {
-		gid = new(int64)
-		*gid = int64(*res.Result.Gidnumber)
-	}
-	state.GID = types.Int64PointerValue(gid)
+	state.Description = types.StringPointerValue(res.Result.Description)
+	state.GID = intToInt64Value(res.Result.Gidnumber)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -183,23 +201,27 @@ func (r *Group) Update(ctx context.Context, req resource.UpdateRequest, resp *re
 		return
 	}
 
-	var gid *int
-	if !plan.GID.IsNull() {
-		gid = new(int)
-		*gid = int(plan.GID.ValueInt64())
+	args := &freeipa.GroupModArgs{
+		Cn: state.Name.ValueString(),
 	}
+	optArgs := &freeipa.GroupModOptionalArgs{}
 
-	args := &freeipa.GroupModArgs{
-		Cn: plan.Name.ValueString(),
+	if !plan.Name.Equal(state.Name) {
+		optArgs.Rename = plan.Name.ValueStringPointer()
+		hasDiff = true
 	}
-	optArgs := &freeipa.GroupModOptionalArgs{
-		Description: plan.Description.ValueStringPointer(),
-		Gidnumber:   gid,
-		External:    plan.External.ValueBoolPointer(),
+
+	if !plan.Description.Equal(state.Description) {
+		optArgs.Description = freeipa.String(plan.Description.ValueString())
+		hasDiff = true
 	}
 
-	hasDiff = !plan.GID.Equal(state.GID) || !plan.Description.Equal(state.Description) ||
-		!plan.External.Equal(state.External)
+	if !plan.GID.Equal(state.GID) && !plan.GID.IsUnknown() {
+		optArgs.Gidnumber = int64ToIntPointer(plan.GID)
+		hasDiff = true
+	}
+
+	var res *freeipa.GroupModResult
 
 	if hasDiff {
 		tflog.Trace(ctx, "Calling GroupMod", map[string]any{
@@ -207,7 +229,9 @@ func (r *Group) Update(ctx context.Context, req resource.UpdateRequest, resp *re
 			"opt_args": optArgs,
 		})
 
-		res, err := r.provider.Client().GroupMod(args, optArgs)
+		var err error
+
+		res, err = r.provider.Client().GroupMod(args, optArgs)
 		tflog.Trace(ctx, "Called GroupMod", map[string]any{
 			"res": res,
 			"err": err,
@@ -224,16 +248,21 @@ func (r *Group) Update(ctx context.Context, req resource.UpdateRequest, resp *re
 			}
 		}
 	} else {
-		tflog.Debug(ctx, "Updated DNS record has no effective difference", map[string]any{
+		tflog.Debug(ctx, "Updated group has no effective difference", map[string]any{
 			"name":        plan.Name.ValueString(),
 			"description": plan.Description.ValueString(),
 			"gid":         plan.GID.ValueInt64(),
-			"external":    plan.External.ValueBool(),
 		})
 	}
 
 	state = plan
 
+	if res != nil {
+		state.GID = intToInt64Value(res.Result.Gidnumber)
+	} else if state.GID.IsUnknown() {
+		state.GID = types.Int64Null()
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
@@ -263,7 +292,7 @@ func (r *Group) Delete(ctx context.Context, req resource.DeleteRequest, resp *re
 
 	if err != nil {
 		var freeipaErr *freeipa.Error
-		if errors.As(err, &freeipaErr) && freeipaErr.Code != freeipa.NotFoundCode {
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
 			if utils.IsMembermanagerGroupDecodeError(err) {
 				tflog.Warn(ctx, "Ignoring go-freeipa MembermanagerGroup decode error on GroupDel", map[string]any{
 					"err": err.Error(),
@@ -287,6 +316,43 @@ func (r *Group) ImportState(ctx context.Context, req resource.ImportStateRequest
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
+// gidNumberOwner returns the name of the group holding the GID number gid,
+// including the private groups of users, or an empty string when it is free.
+func (r *Group) gidNumberOwner(ctx context.Context, gid int) (string, error) {
+	for _, private := range []bool{false, true} {
+		args := &freeipa.GroupFindArgs{}
+
+		optArgs := &freeipa.GroupFindOptionalArgs{
+			Gidnumber: freeipa.Int(gid),
+			Private:   freeipa.Bool(private),
+			NoMembers: freeipa.Bool(true),
+			Sizelimit: freeipa.Int(1),
+		}
+
+		tflog.Trace(ctx, "Calling GroupFind", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().GroupFind("", args, optArgs)
+
+		tflog.Trace(ctx, "Called GroupFind", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			return "", err
+		}
+
+		if len(res.Result) > 0 {
+			return res.Result[0].Cn, nil
+		}
+	}
+
+	return "", nil
+}
+
 func NewGroup(p *provider.Provider) resource.Resource {
 	r := &Group{
 		provider: p,
@@ -0,0 +1,65 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestGroupPlanNonPosix(t *testing.T) {
+	name := tftypes.NewValue(tftypes.String, "developers")
+	gid := tftypes.NewValue(tftypes.Number, 1500)
+
+	cases := map[string]struct {
+		prior, config map[string]tftypes.Value
+		replace       bool
+	}{
+		"set on a POSIX group": {
+			prior:   map[string]tftypes.Value{"cn": name, "gidnumber": gid},
+			config:  map[string]tftypes.Value{"cn": name, "nonposix": tftypes.NewValue(tftypes.Bool, true)},
+			replace: true,
+		},
+		"unset on a non-POSIX group": {
+			prior:   map[string]tftypes.Value{"cn": name, "nonposix": tftypes.NewValue(tftypes.Bool, true)},
+			config:  map[string]tftypes.Value{"cn": name},
+			replace: true,
+		},
+		"set to false on a non-POSIX group": {
+			prior:   map[string]tftypes.Value{"cn": name, "nonposix": tftypes.NewValue(tftypes.Bool, true)},
+			config:  map[string]tftypes.Value{"cn": name, "nonposix": tftypes.NewValue(tftypes.Bool, false)},
+			replace: true,
+		},
+		"false and unset": {
+			prior:  map[string]tftypes.Value{"cn": name, "gidnumber": gid, "nonposix": tftypes.NewValue(tftypes.Bool, false)},
+			config: map[string]tftypes.Value{"cn": name},
+		},
+		// FreeIPA assigns a GID number to a group promoted outside of Terraform
+		"GID number assigned by FreeIPA": {
+			prior:  map[string]tftypes.Value{"cn": name, "gidnumber": gid, "nonposix": tftypes.NewValue(tftypes.Bool, true)},
+			config: map[string]tftypes.Value{"cn": name, "nonposix": tftypes.NewValue(tftypes.Bool, true)},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			server, _, schemas := testProviderServer(t, nil, nil)
+
+			resp := testPlan(t, server, schemas, "freeipa_group", c.prior, c.config)
+
+			for _, d := range resp.Diagnostics {
+				t.Errorf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+			}
+
+			replace := false
+			for _, p := range resp.RequiresReplace {
+				if p.Equal(tftypes.NewAttributePath().WithAttributeName("nonposix")) {
+					replace = true
+				}
+			}
+
+			if replace != c.replace {
+				t.Errorf("got replacement %t, want %t", replace, c.replace)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type HbacServiceGroupMembership struct {
+	provider *provider.Provider
+}
+
+type HbacServiceGroupMembershipModel struct {
+	Group   types.String `tfsdk:"group"`
+	Service types.String `tfsdk:"service"`
+}
+
+func (r *HbacServiceGroupMembership) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hbac_service_group_membership"
+}
+
+func (r *HbacServiceGroupMembership) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Adds an HBAC service to a FreeIPA HBAC service group.",
+		Attributes: map[string]schema.Attribute{
+			"group": schema.StringAttribute{
+				Description: "HBAC service group name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service": schema.StringAttribute{
+				Description: "HBAC service added to the group",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *HbacServiceGroupMembership) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan HbacServiceGroupMembershipModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbacsvcgroupAddMemberArgs{
+		Cn: plan.Group.ValueString(),
+	}
+	optArgs := &freeipa.HbacsvcgroupAddMemberOptionalArgs{
+		Hbacsvc: &[]string{plan.Service.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling HbacsvcgroupAddMember", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HbacsvcgroupAddMember(args, optArgs)
+	tflog.Trace(ctx, "Called HbacsvcgroupAddMember", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	// Services which already belong to the group are reported as failures,
+	// treat them as success to keep the creation idempotent.
+	if err == nil {
+		err = utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add HBAC service to group", "Reason: "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *HbacServiceGroupMembership) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state HbacServiceGroupMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbacsvcgroupShowArgs{
+		Cn: state.Group.ValueString(),
+	}
+	optArgs := &freeipa.HbacsvcgroupShowOptionalArgs{}
+
+	tflog.Trace(ctx, "Calling HbacsvcgroupShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HbacsvcgroupShow(args, optArgs)
+	tflog.Trace(ctx, "Called HbacsvcgroupShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read HBAC service group", "Reason: "+err.Error())
+		return
+	}
+
+	// FreeIPA lower-cases the service names
+	isMember := func(service string) bool {
+		return strings.EqualFold(service, state.Service.ValueString())
+	}
+
+	if res.Result.MemberHbacsvc == nil || !slices.ContainsFunc(*res.Result.MemberHbacsvc, isMember) {
+		tflog.Debug(ctx, "HBAC service was removed from group", map[string]any{
+			"group":   state.Group.ValueString(),
+			"service": state.Service.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *HbacServiceGroupMembership) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan HbacServiceGroupMembershipModel
+
+	// All attributes require replacement, there is nothing to update
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *HbacServiceGroupMembership) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state HbacServiceGroupMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbacsvcgroupRemoveMemberArgs{
+		Cn: state.Group.ValueString(),
+	}
+	optArgs := &freeipa.HbacsvcgroupRemoveMemberOptionalArgs{
+		Hbacsvc: &[]string{state.Service.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling HbacsvcgroupRemoveMember", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HbacsvcgroupRemoveMember(args, optArgs)
+	tflog.Trace(ctx, "Called HbacsvcgroupRemoveMember", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove HBAC service from group", "Reason: "+err.Error())
+		}
+		return
+	}
+
+	// Services removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove HBAC service from group", "Reason: "+err.Error())
+	}
+}
+
+func (r *HbacServiceGroupMembership) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	group, service, ok := strings.Cut(req.ID, "/")
+
+	if !ok || group == "" || service == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<group>/<service>”, got %q.", req.ID),
+		)
+		return
+	}
+
+	state := HbacServiceGroupMembershipModel{
+		Group:   types.StringValue(group),
+		Service: types.StringValue(service),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewHbacServiceGroupMembership(p *provider.Provider) resource.Resource {
+	r := &HbacServiceGroupMembership{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewHbacServiceGroupMembership)
+}
@@ -0,0 +1,233 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type HbacServiceGroup struct {
+	provider *provider.Provider
+}
+
+type HbacServiceGroupModel struct {
+	Name        types.String `tfsdk:"cn"`
+	Description types.String `tfsdk:"description"`
+}
+
+func (r *HbacServiceGroup) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hbac_service_group"
+}
+
+func (r *HbacServiceGroup) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA HBAC service group, which can be added to HBAC rules instead of its services.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "HBAC service group name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "HBAC service group description",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *HbacServiceGroup) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan HbacServiceGroupModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbacsvcgroupAddArgs{
+		Cn: plan.Name.ValueString(),
+	}
+	optArgs := &freeipa.HbacsvcgroupAddOptionalArgs{
+		Description: plan.Description.ValueStringPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling HbacsvcgroupAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HbacsvcgroupAdd(args, optArgs)
+	tflog.Trace(ctx, "Called HbacsvcgroupAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create HBAC service group", "Reason: "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *HbacServiceGroup) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state HbacServiceGroupModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbacsvcgroupShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+	// Services are managed by association resources
+	optArgs := &freeipa.HbacsvcgroupShowOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling HbacsvcgroupShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HbacsvcgroupShow(args, optArgs)
+	tflog.Trace(ctx, "Called HbacsvcgroupShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read HBAC service group", "Reason: "+err.Error())
+		return
+	}
+
+	// FreeIPA lower-cases the names, keep the configured spelling
+	if !strings.EqualFold(state.Name.ValueString(), res.Result.Cn) {
+		state.Name = types.StringValue(res.Result.Cn)
+	}
+	state.Description = types.StringPointerValue(res.Result.Description)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *HbacServiceGroup) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan HbacServiceGroupModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Description.Equal(state.Description) {
+		args := &freeipa.HbacsvcgroupModArgs{
+			Cn: plan.Name.ValueString(),
+		}
+		optArgs := &freeipa.HbacsvcgroupModOptionalArgs{
+			Description: freeipa.String(plan.Description.ValueString()),
+		}
+
+		tflog.Trace(ctx, "Calling HbacsvcgroupMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().HbacsvcgroupMod(args, optArgs)
+		tflog.Trace(ctx, "Called HbacsvcgroupMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update HBAC service group", "Reason: "+err.Error())
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated HBAC service group has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *HbacServiceGroup) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state HbacServiceGroupModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbacsvcgroupDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling HbacsvcgroupDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().HbacsvcgroupDel(args, nil)
+	tflog.Trace(ctx, "Called HbacsvcgroupDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if utils.IsDependentEntryError(err) {
+			resp.Diagnostics.AddError(
+				"HBAC service group is still used by an HBAC rule",
+				"Reason: "+err.Error()+". Remove the service group from the HBAC rules referencing it before deleting it.",
+			)
+			return
+		}
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete HBAC service group", "Reason: "+err.Error())
+			return
+		}
+	}
+}
+
+func (r *HbacServiceGroup) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := HbacServiceGroupModel{
+		Name:        types.StringValue(req.ID),
+		Description: types.StringNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewHbacServiceGroup(p *provider.Provider) resource.Resource {
+	r := &HbacServiceGroup{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewHbacServiceGroup)
+}
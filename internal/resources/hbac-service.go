@@ -0,0 +1,233 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type HbacService struct {
+	provider *provider.Provider
+}
+
+type HbacServiceModel struct {
+	Name        types.String `tfsdk:"cn"`
+	Description types.String `tfsdk:"description"`
+}
+
+func (r *HbacService) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hbac_service"
+}
+
+func (r *HbacService) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA HBAC service, the PAM service name HBAC rules grant access to.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "PAM service name, e.g. `sshd`",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "HBAC service description",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *HbacService) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan HbacServiceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbacsvcAddArgs{
+		Cn: plan.Name.ValueString(),
+	}
+	optArgs := &freeipa.HbacsvcAddOptionalArgs{
+		Description: plan.Description.ValueStringPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling HbacsvcAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HbacsvcAdd(args, optArgs)
+	tflog.Trace(ctx, "Called HbacsvcAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create HBAC service", "Reason: "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *HbacService) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state HbacServiceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbacsvcShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+	// Service groups are managed by association resources
+	optArgs := &freeipa.HbacsvcShowOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling HbacsvcShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HbacsvcShow(args, optArgs)
+	tflog.Trace(ctx, "Called HbacsvcShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read HBAC service", "Reason: "+err.Error())
+		return
+	}
+
+	// FreeIPA lower-cases the names, keep the configured spelling
+	if !strings.EqualFold(state.Name.ValueString(), res.Result.Cn) {
+		state.Name = types.StringValue(res.Result.Cn)
+	}
+	state.Description = types.StringPointerValue(res.Result.Description)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *HbacService) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan HbacServiceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Description.Equal(state.Description) {
+		args := &freeipa.HbacsvcModArgs{
+			Cn: plan.Name.ValueString(),
+		}
+		optArgs := &freeipa.HbacsvcModOptionalArgs{
+			Description: freeipa.String(plan.Description.ValueString()),
+		}
+
+		tflog.Trace(ctx, "Calling HbacsvcMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().HbacsvcMod(args, optArgs)
+		tflog.Trace(ctx, "Called HbacsvcMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update HBAC service", "Reason: "+err.Error())
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated HBAC service has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *HbacService) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state HbacServiceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HbacsvcDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling HbacsvcDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().HbacsvcDel(args, nil)
+	tflog.Trace(ctx, "Called HbacsvcDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if utils.IsDependentEntryError(err) {
+			resp.Diagnostics.AddError(
+				"HBAC service is still used by an HBAC rule",
+				"Reason: "+err.Error()+". Remove the service from the HBAC rules referencing it before deleting it.",
+			)
+			return
+		}
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete HBAC service", "Reason: "+err.Error())
+			return
+		}
+	}
+}
+
+func (r *HbacService) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := HbacServiceModel{
+		Name:        types.StringValue(req.ID),
+		Description: types.StringNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewHbacService(p *provider.Provider) resource.Resource {
+	r := &HbacService{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewHbacService)
+}
@@ -0,0 +1,229 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type HostManagedbyHost struct {
+	provider *provider.Provider
+}
+
+type HostManagedbyHostModel struct {
+	Host          types.String `tfsdk:"host"`
+	ManagedbyHost types.String `tfsdk:"managedby_host"`
+}
+
+func (r *HostManagedbyHost) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host_managedby_host"
+}
+
+func (r *HostManagedbyHost) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Allows a FreeIPA host to manage another host, e.g. to retrieve its keytab.",
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				Description: "FQDN of the managed host",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"managedby_host": schema.StringAttribute{
+				Description: "FQDN of the host allowed to manage the host",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *HostManagedbyHost) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan HostManagedbyHostModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HostAddManagedbyArgs{
+		Fqdn: plan.Host.ValueString(),
+	}
+	optArgs := &freeipa.HostAddManagedbyOptionalArgs{
+		Host: &[]string{plan.ManagedbyHost.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling HostAddManagedby", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HostAddManagedby(args, optArgs)
+	tflog.Trace(ctx, "Called HostAddManagedby", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	// Hosts which already manage the host are reported as failures, treat
+	// them as success to keep the creation idempotent.
+	if err == nil {
+		err = utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add host managed by host", "Reason: "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *HostManagedbyHost) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state HostManagedbyHostModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HostShowArgs{
+		Fqdn: state.Host.ValueString(),
+	}
+	optArgs := &freeipa.HostShowOptionalArgs{}
+
+	tflog.Trace(ctx, "Calling HostShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HostShow(args, optArgs)
+	tflog.Trace(ctx, "Called HostShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read host", "Reason: "+err.Error())
+		return
+	}
+
+	// FreeIPA lower-cases host names
+	isManager := func(host string) bool {
+		return strings.EqualFold(host, state.ManagedbyHost.ValueString())
+	}
+
+	if res.Result.ManagedbyHost == nil || !slices.ContainsFunc(*res.Result.ManagedbyHost, isManager) {
+		tflog.Debug(ctx, "Host no longer manages host", map[string]any{
+			"host":           state.Host.ValueString(),
+			"managedby_host": state.ManagedbyHost.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *HostManagedbyHost) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan HostManagedbyHostModel
+
+	// All attributes require replacement, there is nothing to update
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *HostManagedbyHost) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state HostManagedbyHostModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.HostRemoveManagedbyArgs{
+		Fqdn: state.Host.ValueString(),
+	}
+	optArgs := &freeipa.HostRemoveManagedbyOptionalArgs{
+		Host: &[]string{state.ManagedbyHost.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling HostRemoveManagedby", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HostRemoveManagedby(args, optArgs)
+	tflog.Trace(ctx, "Called HostRemoveManagedby", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove host managed by host", "Reason: "+err.Error())
+		}
+		return
+	}
+
+	// Hosts removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove host managed by host", "Reason: "+err.Error())
+	}
+}
+
+func (r *HostManagedbyHost) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	host, managedbyHost, ok := strings.Cut(req.ID, "/")
+
+	if !ok || host == "" || managedbyHost == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<host>/<managedby host>”, got %q.", req.ID),
+		)
+		return
+	}
+
+	state := HostManagedbyHostModel{
+		Host:          types.StringValue(host),
+		ManagedbyHost: types.StringValue(managedbyHost),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewHostManagedbyHost(p *provider.Provider) resource.Resource {
+	r := &HostManagedbyHost{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewHostManagedbyHost)
+}
@@ -29,6 +29,17 @@ type HostModel struct {
 	RandomPassword types.String `tfsdk:"randompassword"`
 	ManagedByHosts types.Set    `tfsdk:"managedby_hosts"`
 	Force          types.Bool   `tfsdk:"force"`
+	IPAddress      types.String `tfsdk:"ip_address"`
+	OSVersion      types.String `tfsdk:"nsosversion"`
+	Locality       types.String `tfsdk:"l"`
+	UserClass      types.Set    `tfsdk:"userclass"`
+	UpdateDNS      types.Bool   `tfsdk:"updatedns"`
+	SSHPublicKeys  types.List   `tfsdk:"ipasshpubkey"`
+	ManageSSHKeys  types.Bool   `tfsdk:"manage_ssh_keys"`
+	DisableTrigger types.String `tfsdk:"disable_trigger"`
+	Unprovision    types.Bool   `tfsdk:"unprovision_on_destroy"`
+	HasKeytab      types.Bool   `tfsdk:"has_keytab"`
+	HasPassword    types.Bool   `tfsdk:"has_password"`
 }
 
 func (r *Host) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -49,7 +60,8 @@ func (r *Host) Schema(ctx context.Context, req resource.SchemaRequest, resp *res
 				Optional: true,
 			},
 			"random": schema.BoolAttribute{
-				Optional: true,
+				Optional:    true,
+				Description: "Generate a random one-time enrollment password",
 			},
 			"userpassword": schema.StringAttribute{
 				Optional:  true,
@@ -63,9 +75,60 @@ func (r *Host) Schema(ctx context.Context, req resource.SchemaRequest, resp *res
 				ElementType: types.StringType,
 				Optional:    true,
 				Computed:    true,
+				Description: "Hosts allowed to manage the host. Defaults to the host itself on creation, the current hosts are kept when unset afterwards",
 			},
 			"force": schema.BoolAttribute{
-				Optional: true,
+				Optional:    true,
+				Description: "Force host name even if not in DNS",
+			},
+			"ip_address": schema.StringAttribute{
+				Optional:    true,
+				Description: "IP address of the host, used to create its DNS A/AAAA record on creation",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"nsosversion": schema.StringAttribute{
+				Optional:    true,
+				Description: "Host operating system and version",
+			},
+			"l": schema.StringAttribute{
+				Optional:    true,
+				Description: "Host locality (e.g. “Baltimore, MD”)",
+			},
+			"userclass": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Host category (semantics placed on this attribute are for local interpretation)",
+			},
+			"updatedns": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Remove the DNS records of the host when it is deleted",
+			},
+			"ipasshpubkey": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "SSH public keys, compared without their options and comment. Keys added outside of Terraform (e.g. by `ipa-client-install`) are kept unless `manage_ssh_keys` is set",
+			},
+			"manage_ssh_keys": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Manage the exact set of SSH public keys of the host, removing the keys which are not in `ipasshpubkey`. Defaults to false",
+			},
+			"disable_trigger": schema.StringAttribute{
+				Optional:    true,
+				Description: "Arbitrary value, the host is disabled whenever it changes to a non-null value: its keytab is removed and its certificates revoked. Setting it on creation does not disable the new host",
+			},
+			"unprovision_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Disable the host instead of deleting it on destroy, keeping the host entry. Defaults to false",
+			},
+			"has_keytab": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the host has a keytab, i.e. is enrolled",
+			},
+			"has_password": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the host has an enrollment password",
 			},
 		},
 	}
@@ -133,12 +196,19 @@ func (r *Host) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, r
 		}
 	}
 
+	// Unset managers default to the host itself on creation and are left
+	// untouched afterwards, so that freeipa_host_managedby_host resources can
+	// manage them
 	if config.ManagedByHosts.IsNull() {
-		var diags diag.Diagnostics
+		if isCreation {
+			var diags diag.Diagnostics
 
-		plan.ManagedByHosts, diags = types.SetValue(types.StringType, []attr.Value{plan.Fqdn})
+			plan.ManagedByHosts, diags = types.SetValue(types.StringType, []attr.Value{plan.Fqdn})
 
-		resp.Diagnostics.Append(diags...)
+			resp.Diagnostics.Append(diags...)
+		} else {
+			plan.ManagedByHosts = state.ManagedByHosts
+		}
 	}
 
 	if resp.Diagnostics.HasError() {
@@ -174,9 +244,26 @@ func (r *Host) Create(ctx context.Context, req resource.CreateRequest, resp *res
 		Random:       plan.Random.ValueBoolPointer(),
 		Userpassword: plan.UserPassword.ValueStringPointer(),
 		Force:        plan.Force.ValueBoolPointer(),
+		IPAddress:    plan.IPAddress.ValueStringPointer(),
+		Nsosversion:  plan.OSVersion.ValueStringPointer(),
+		L:            plan.Locality.ValueStringPointer(),
 		All:          freeipa.Bool(true),
 	}
 
+	if !plan.UserClass.IsNull() {
+		userClass := []string{}
+
+		resp.Diagnostics.Append(plan.UserClass.ElementsAs(ctx, &userClass, false)...)
+
+		optArgs.Userclass = &userClass
+	}
+
+	resp.Diagnostics.Append(listToStringSlicePointer(ctx, plan.SSHPublicKeys, &optArgs.Ipasshpubkey)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Trace(ctx, "Calling HostAdd", map[string]any{
 		"args":     args,
 		"opt_args": optArgs,
@@ -201,8 +288,17 @@ func (r *Host) Create(ctx context.Context, req resource.CreateRequest, resp *res
 		return
 	}
 
+	hasKeytab, hasPassword, diags := r.credentials(ctx, plan.Fqdn.ValueString())
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	state = plan
 	state.RandomPassword = types.StringPointerValue(res.Result.Randompassword)
+	state.HasKeytab = hasKeytab
+	state.HasPassword = hasPassword
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
@@ -220,7 +316,9 @@ func (r *Host) Read(ctx context.Context, req resource.ReadRequest, resp *resourc
 		Fqdn: state.Fqdn.ValueString(),
 	}
 
-	optArgs := &freeipa.HostShowOptionalArgs{}
+	optArgs := &freeipa.HostShowOptionalArgs{
+		All: freeipa.Bool(true),
+	}
 
 	tflog.Trace(ctx, "Calling HostShow", map[string]any{
 		"args":     args,
@@ -235,11 +333,7 @@ func (r *Host) Read(ctx context.Context, req resource.ReadRequest, resp *resourc
 	})
 
 	if err != nil {
-		var freeipaErr *freeipa.Error
-
-		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
-			resp.State.RemoveResource(ctx)
-
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
 			return
 		}
 
@@ -251,6 +345,18 @@ func (r *Host) Read(ctx context.Context, req resource.ReadRequest, resp *resourc
 	var diags diag.Diagnostics
 
 	state.Description = types.StringPointerValue(res.Result.Description)
+	state.OSVersion = types.StringPointerValue(res.Result.Nsosversion)
+	state.Locality = types.StringPointerValue(res.Result.L)
+	state.HasKeytab = types.BoolValue(res.Result.HasKeytab != nil && *res.Result.HasKeytab)
+	state.HasPassword = types.BoolValue(res.Result.HasPassword != nil && *res.Result.HasPassword)
+
+	if userClass := res.Result.Userclass; userClass != nil {
+		state.UserClass, diags = types.SetValueFrom(ctx, types.StringType, *userClass)
+
+		resp.Diagnostics.Append(diags...)
+	} else if !state.UserClass.IsNull() {
+		state.UserClass = types.SetValueMust(types.StringType, []attr.Value{})
+	}
 
 	if managedByHosts := res.Result.ManagedbyHost; managedByHosts != nil {
 		state.ManagedByHosts, diags = types.SetValueFrom(ctx, types.StringType, *managedByHosts)
@@ -260,6 +366,9 @@ func (r *Host) Read(ctx context.Context, req resource.ReadRequest, resp *resourc
 		state.ManagedByHosts = types.SetValueMust(types.StringType, []attr.Value{})
 	}
 
+	state.SSHPublicKeys, diags = sshPubKeysToList(ctx, state.SSHPublicKeys, res.Result.Ipasshpubkey, state.ManageSSHKeys.ValueBool())
+	resp.Diagnostics.Append(diags...)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -292,11 +401,38 @@ func (r *Host) Update(ctx context.Context, req resource.UpdateRequest, resp *res
 	}
 
 	optArgs := &freeipa.HostModOptionalArgs{
-		Description: plan.Description.ValueStringPointer(),
-		All:         freeipa.Bool(true),
+		All: freeipa.Bool(true),
+	}
+
+	stringChanges := []struct {
+		plan, state types.String
+		arg         **string
+	}{
+		{plan.Description, state.Description, &optArgs.Description},
+		{plan.OSVersion, state.OSVersion, &optArgs.Nsosversion},
+		{plan.Locality, state.Locality, &optArgs.L},
 	}
 
-	hasDiff = !plan.Description.Equal(state.Description)
+	// A null plan value is sent as an empty string to clear the attribute
+	for _, c := range stringChanges {
+		if !c.plan.Equal(c.state) && !c.plan.IsUnknown() {
+			*c.arg = freeipa.String(c.plan.ValueString())
+			hasDiff = true
+		}
+	}
+
+	if !plan.UserClass.Equal(state.UserClass) {
+		userClass := []string{}
+
+		resp.Diagnostics.Append(plan.UserClass.ElementsAs(ctx, &userClass, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		optArgs.Userclass = &userClass
+		hasDiff = true
+	}
 
 	// Do not regenerate a new enrollment password if not requested
 	if !plan.Random.Equal(state.Random) && plan.Random.ValueBool() {
@@ -310,6 +446,47 @@ func (r *Host) Update(ctx context.Context, req resource.UpdateRequest, resp *res
 		optArgs.Userpassword = plan.UserPassword.ValueStringPointer()
 	}
 
+	// The keys are replaced as a whole, the current ones are read first to
+	// keep those managed outside of Terraform
+	if !plan.SSHPublicKeys.Equal(state.SSHPublicKeys) || !plan.ManageSSHKeys.Equal(state.ManageSSHKeys) {
+		showArgs := &freeipa.HostShowArgs{
+			Fqdn: plan.Fqdn.ValueString(),
+		}
+
+		showOptArgs := &freeipa.HostShowOptionalArgs{
+			All: freeipa.Bool(true),
+		}
+
+		tflog.Trace(ctx, "Calling HostShow", map[string]any{
+			"args":     showArgs,
+			"opt_args": showOptArgs,
+		})
+
+		res, err := r.provider.Client().HostShow(showArgs, showOptArgs)
+
+		tflog.Trace(ctx, "Called HostShow", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read host", "Reason: "+err.Error())
+
+			return
+		}
+
+		var diags diag.Diagnostics
+
+		optArgs.Ipasshpubkey, diags = sshPubKeysArg(ctx, plan.SSHPublicKeys, state.SSHPublicKeys, plan.ManageSSHKeys.ValueBool(), res.Result.Ipasshpubkey)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		hasDiff = hasDiff || optArgs.Ipasshpubkey != nil
+	}
+
 	randomPassword := plan.RandomPassword
 
 	if hasDiff {
@@ -344,12 +521,27 @@ func (r *Host) Update(ctx context.Context, req resource.UpdateRequest, resp *res
 		resp.Diagnostics.Append(r.updateManagedByHosts(ctx, plan.Fqdn.ValueString(), currentManagedByHosts, desiredManagedByHosts)...)
 	}
 
+	if !plan.DisableTrigger.IsNull() && !plan.DisableTrigger.Equal(state.DisableTrigger) {
+		resp.Diagnostics.Append(r.disable(ctx, plan.Fqdn.ValueString())...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Disabling the host or setting an enrollment password changes its
+	// credentials, they are read once all changes are applied
+	hasKeytab, hasPassword, diags := r.credentials(ctx, plan.Fqdn.ValueString())
+	resp.Diagnostics.Append(diags...)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	state = plan
 	state.RandomPassword = randomPassword
+	state.HasKeytab = hasKeytab
+	state.HasPassword = hasPassword
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
@@ -363,13 +555,21 @@ func (r *Host) Delete(ctx context.Context, req resource.DeleteRequest, resp *res
 		return
 	}
 
+	if state.Unprovision.ValueBool() {
+		resp.Diagnostics.Append(r.disable(ctx, state.Fqdn.ValueString())...)
+
+		return
+	}
+
 	args := &freeipa.HostDelArgs{
 		Fqdn: []string{
 			state.Fqdn.ValueString(),
 		},
 	}
 
-	optArgs := &freeipa.HostDelOptionalArgs{}
+	optArgs := &freeipa.HostDelOptionalArgs{
+		Updatedns: state.UpdateDNS.ValueBoolPointer(),
+	}
 
 	tflog.Trace(ctx, "Calling HostDel", map[string]any{
 		"args":     args,
@@ -386,20 +586,21 @@ func (r *Host) Delete(ctx context.Context, req resource.DeleteRequest, resp *res
 	if err != nil {
 		var freeipaErr *freeipa.Error
 
-		if errors.As(err, &freeipaErr) && freeipaErr.Code != freeipa.NotFoundCode {
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
 			resp.Diagnostics.AddError("Failed to delete host", "Reason: "+err.Error())
 
 			return
 		}
 	}
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
 func (r *Host) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	state := HostModel{
-		Fqdn:   types.StringValue(req.ID),
-		Random: types.BoolValue(true),
+		Fqdn:           types.StringValue(req.ID),
+		Random:         types.BoolValue(true),
+		ManagedByHosts: types.SetNull(types.StringType),
+		UserClass:      types.SetNull(types.StringType),
+		SSHPublicKeys:  types.ListNull(types.StringType),
 	}
 
 	resp.Diagnostics.AddWarning(
@@ -449,6 +650,8 @@ func (r *Host) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrade
 					RandomPassword: oldState.RandomPassword,
 					ManagedByHosts: types.SetNull(types.StringType),
 					Force:          oldState.Force,
+					UserClass:      types.SetNull(types.StringType),
+					SSHPublicKeys:  types.ListNull(types.StringType),
 				}
 
 				resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
@@ -475,6 +678,68 @@ func init() {
 	resources = append(resources, NewHost)
 }
 
+// disable removes the keytab of the host and revokes its certificates.
+// Disabling a host which is already disabled or was deleted succeeds.
+func (r *Host) disable(ctx context.Context, fqdn string) (diags diag.Diagnostics) {
+	args := &freeipa.HostDisableArgs{
+		Fqdn: fqdn,
+	}
+
+	tflog.Trace(ctx, "Calling HostDisable", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().HostDisable(args, nil)
+
+	tflog.Trace(ctx, "Called HostDisable", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && (freeipaErr.Code == freeipa.NotFoundCode || freeipaErr.Code == utils.AlreadyInactiveCode) {
+			return
+		}
+
+		diags.AddError("Failed to disable host", "Reason: "+err.Error())
+	}
+
+	return
+}
+
+// credentials reads whether the host has a keytab and an enrollment password.
+func (r *Host) credentials(ctx context.Context, fqdn string) (hasKeytab, hasPassword types.Bool, diags diag.Diagnostics) {
+	args := &freeipa.HostShowArgs{
+		Fqdn: fqdn,
+	}
+
+	tflog.Trace(ctx, "Calling HostShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().HostShow(args, nil)
+
+	tflog.Trace(ctx, "Called HostShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		diags.AddError("Failed to read host", "Reason: "+err.Error())
+
+		return
+	}
+
+	hasKeytab = types.BoolValue(res.Result.HasKeytab != nil && *res.Result.HasKeytab)
+	hasPassword = types.BoolValue(res.Result.HasPassword != nil && *res.Result.HasPassword)
+
+	return
+}
+
 func (r *Host) updateManagedByHosts(ctx context.Context, fqdn string, actualHosts, desiredHosts []string) (diags diag.Diagnostics) {
 	hostsToAdd, hostsToRemove := utils.SetDiff(actualHosts, desiredHosts)
 
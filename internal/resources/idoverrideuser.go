@@ -0,0 +1,356 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type IdoverrideUser struct {
+	provider *provider.Provider
+}
+
+type IdoverrideUserModel struct {
+	Idview        types.String `tfsdk:"idview"`
+	Anchor        types.String `tfsdk:"ipaanchoruuid"`
+	Description   types.String `tfsdk:"description"`
+	UID           types.String `tfsdk:"uid"`
+	UIDNumber     types.Int64  `tfsdk:"uidnumber"`
+	GIDNumber     types.Int64  `tfsdk:"gidnumber"`
+	Gecos         types.String `tfsdk:"gecos"`
+	HomeDirectory types.String `tfsdk:"homedirectory"`
+	LoginShell    types.String `tfsdk:"loginshell"`
+}
+
+func (r *IdoverrideUser) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_idoverrideuser"
+}
+
+func (r *IdoverrideUser) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Overrides the POSIX attributes of a user in a FreeIPA ID view.",
+		Attributes: map[string]schema.Attribute{
+			"idview": schema.StringAttribute{
+				Description: "ID view the override belongs to",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipaanchoruuid": schema.StringAttribute{
+				Description: "User the override applies to",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Override description",
+				Optional:    true,
+			},
+			"uid": schema.StringAttribute{
+				Description: "User login",
+				Optional:    true,
+			},
+			"uidnumber": schema.Int64Attribute{
+				Description: "User ID number",
+				Optional:    true,
+			},
+			"gidnumber": schema.Int64Attribute{
+				Description: "Group ID number",
+				Optional:    true,
+			},
+			"gecos": schema.StringAttribute{
+				Description: "GECOS field",
+				Optional:    true,
+			},
+			"homedirectory": schema.StringAttribute{
+				Description: "Home directory",
+				Optional:    true,
+			},
+			"loginshell": schema.StringAttribute{
+				Description: "Login shell",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *IdoverrideUser) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan IdoverrideUserModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdoverrideuserAddArgs{
+		Idviewcn:      plan.Idview.ValueString(),
+		Ipaanchoruuid: plan.Anchor.ValueString(),
+	}
+
+	optArgs := &freeipa.IdoverrideuserAddOptionalArgs{
+		Description:   plan.Description.ValueStringPointer(),
+		UID:           plan.UID.ValueStringPointer(),
+		Uidnumber:     int64ToIntPointer(plan.UIDNumber),
+		Gidnumber:     int64ToIntPointer(plan.GIDNumber),
+		Gecos:         plan.Gecos.ValueStringPointer(),
+		Homedirectory: plan.HomeDirectory.ValueStringPointer(),
+		Loginshell:    plan.LoginShell.ValueStringPointer(),
+		NoMembers:     freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling IdoverrideuserAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().IdoverrideuserAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called IdoverrideuserAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create user ID override", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *IdoverrideUser) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state IdoverrideUserModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdoverrideuserShowArgs{
+		Idviewcn:      state.Idview.ValueString(),
+		Ipaanchoruuid: state.Anchor.ValueString(),
+	}
+
+	optArgs := &freeipa.IdoverrideuserShowOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling IdoverrideuserShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().IdoverrideuserShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called IdoverrideuserShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read user ID override", "Reason: "+err.Error())
+
+		return
+	}
+
+	state.Description = types.StringPointerValue(res.Result.Description)
+	state.UID = types.StringPointerValue(res.Result.UID)
+	state.UIDNumber = intToInt64Value(res.Result.Uidnumber)
+	state.GIDNumber = intToInt64Value(res.Result.Gidnumber)
+	state.Gecos = types.StringPointerValue(res.Result.Gecos)
+	state.HomeDirectory = types.StringPointerValue(res.Result.Homedirectory)
+	state.LoginShell = types.StringPointerValue(res.Result.Loginshell)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *IdoverrideUser) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan IdoverrideUserModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdoverrideuserModArgs{
+		Idviewcn:      plan.Idview.ValueString(),
+		Ipaanchoruuid: plan.Anchor.ValueString(),
+	}
+
+	optArgs := &freeipa.IdoverrideuserModOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	stringChanges := []struct {
+		plan, state types.String
+		arg         **string
+	}{
+		{plan.Description, state.Description, &optArgs.Description},
+		{plan.UID, state.UID, &optArgs.UID},
+		{plan.Gecos, state.Gecos, &optArgs.Gecos},
+		{plan.HomeDirectory, state.HomeDirectory, &optArgs.Homedirectory},
+		{plan.LoginShell, state.LoginShell, &optArgs.Loginshell},
+	}
+
+	// A null plan value is sent as an empty string to clear the attribute
+	for _, c := range stringChanges {
+		if !c.plan.Equal(c.state) {
+			*c.arg = freeipa.String(c.plan.ValueString())
+			hasDiff = true
+		}
+	}
+
+	intChanges := []struct {
+		plan, state types.Int64
+		arg         **int
+		attr        string
+	}{
+		{plan.UIDNumber, state.UIDNumber, &optArgs.Uidnumber, "uidnumber"},
+		{plan.GIDNumber, state.GIDNumber, &optArgs.Gidnumber, "gidnumber"},
+	}
+
+	var clearedAttrs []string
+
+	// Integers cannot be sent empty, removed ones are cleared with setattr
+	for _, c := range intChanges {
+		if c.plan.Equal(c.state) {
+			continue
+		}
+
+		if c.plan.IsNull() {
+			clearedAttrs = append(clearedAttrs, c.attr+"=")
+		} else {
+			*c.arg = int64ToIntPointer(c.plan)
+		}
+		hasDiff = true
+	}
+
+	if len(clearedAttrs) > 0 {
+		optArgs.Setattr = &clearedAttrs
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling IdoverrideuserMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().IdoverrideuserMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called IdoverrideuserMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update user ID override", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated user ID override has no effective difference", map[string]any{
+			"idview":        plan.Idview.ValueString(),
+			"ipaanchoruuid": plan.Anchor.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *IdoverrideUser) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state IdoverrideUserModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdoverrideuserDelArgs{
+		Idviewcn:      state.Idview.ValueString(),
+		Ipaanchoruuid: []string{state.Anchor.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling IdoverrideuserDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().IdoverrideuserDel(args, nil)
+
+	tflog.Trace(ctx, "Called IdoverrideuserDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete user ID override", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *IdoverrideUser) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idview, anchor, ok := strings.Cut(req.ID, "/")
+
+	if !ok || idview == "" || anchor == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<idview>/<user>”, got %q.", req.ID),
+		)
+
+		return
+	}
+
+	state := IdoverrideUserModel{
+		Idview:    types.StringValue(idview),
+		Anchor:    types.StringValue(anchor),
+		UIDNumber: types.Int64Null(),
+		GIDNumber: types.Int64Null(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewIdoverrideUser(p *provider.Provider) resource.Resource {
+	r := &IdoverrideUser{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewIdoverrideUser)
+}
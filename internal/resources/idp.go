@@ -0,0 +1,428 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+var idpProviders = []string{"google", "github", "microsoft", "keycloak", "okta"}
+
+type Idp struct {
+	provider *provider.Provider
+}
+
+type IdpModel struct {
+	Name             types.String `tfsdk:"cn"`
+	Provider         types.String `tfsdk:"ipaidpprovider"`
+	Org              types.String `tfsdk:"ipaidporg"`
+	BaseURL          types.String `tfsdk:"ipaidpbaseurl"`
+	ClientID         types.String `tfsdk:"ipaidpclientid"`
+	ClientSecret     types.String `tfsdk:"ipaidpclientsecret"`
+	AuthEndpoint     types.String `tfsdk:"ipaidpauthendpoint"`
+	DevAuthEndpoint  types.String `tfsdk:"ipaidpdevauthendpoint"`
+	TokenEndpoint    types.String `tfsdk:"ipaidptokenendpoint"`
+	UserInfoEndpoint types.String `tfsdk:"ipaidpuserinfoendpoint"`
+	KeysEndpoint     types.String `tfsdk:"ipaidpkeysendpoint"`
+	IssuerURL        types.String `tfsdk:"ipaidpissuerurl"`
+	Scope            types.String `tfsdk:"ipaidpscope"`
+	Sub              types.String `tfsdk:"ipaidpsub"`
+}
+
+func (r *Idp) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_idp"
+}
+
+func (r *Idp) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	// The endpoints are filled in by FreeIPA from the provider template
+	computedString := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{
+			Description: description,
+			Optional:    true,
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA external identity provider (OAuth 2.0), which users with the `idp` authentication type are authenticated against.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Identity provider name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipaidpprovider": schema.StringAttribute{
+				Description: "Template the endpoints are derived from, one of " + strings.Join(idpProviders, ", ") + ". It is only used on creation, changing it replaces the identity provider",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipaidporg": schema.StringAttribute{
+				Description: "Organization of the template, e.g. the Microsoft tenant or the Keycloak realm. Changing it replaces the identity provider",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipaidpbaseurl": schema.StringAttribute{
+				Description: "Base URL of the template, for the Keycloak and Okta providers. Changing it replaces the identity provider",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipaidpclientid": schema.StringAttribute{
+				Description: "OAuth 2.0 client ID",
+				Required:    true,
+			},
+			"ipaidpclientsecret": schema.StringAttribute{
+				Description: "OAuth 2.0 client secret. FreeIPA never returns it, it is only sent when created or changed in the configuration",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"ipaidpauthendpoint":     computedString("Authorization endpoint URL"),
+			"ipaidpdevauthendpoint":  computedString("Device authorization endpoint URL"),
+			"ipaidptokenendpoint":    computedString("Token endpoint URL"),
+			"ipaidpuserinfoendpoint": computedString("User information endpoint URL"),
+			"ipaidpkeysendpoint":     computedString("JWKS endpoint URL"),
+			"ipaidpissuerurl":        computedString("Issuer URL"),
+			"ipaidpscope":            computedString("OAuth 2.0 scope requested for the users"),
+			"ipaidpsub":              computedString("Claim of the user information identifying the users"),
+		},
+	}
+}
+
+func (r *Idp) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config IdpModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Provider.IsUnknown() {
+		return
+	}
+
+	if !config.Provider.IsNull() {
+		if !slices.Contains(idpProviders, config.Provider.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ipaidpprovider"),
+				"Invalid configuration",
+				fmt.Sprintf("Unsupported identity provider template “%s”, expected one of %s.", config.Provider.ValueString(), strings.Join(idpProviders, ", ")),
+			)
+		}
+
+		return
+	}
+
+	for _, attr := range []struct {
+		name  string
+		value types.String
+	}{
+		{"ipaidporg", config.Org},
+		{"ipaidpbaseurl", config.BaseURL},
+	} {
+		if !attr.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr.name),
+				"Invalid configuration",
+				fmt.Sprintf("“%s” can only be set with “ipaidpprovider”.", attr.name),
+			)
+		}
+	}
+
+	// Without a template FreeIPA needs the endpoints of the device
+	// authorization grant
+	for _, attr := range []struct {
+		name  string
+		value types.String
+	}{
+		{"ipaidpdevauthendpoint", config.DevAuthEndpoint},
+		{"ipaidptokenendpoint", config.TokenEndpoint},
+	} {
+		if attr.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr.name),
+				"Invalid configuration",
+				fmt.Sprintf("“%s” must be set when “ipaidpprovider” is not.", attr.name),
+			)
+		}
+	}
+}
+
+func (r *Idp) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan IdpModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdpAddArgs{
+		Cn:             plan.Name.ValueString(),
+		Ipaidpclientid: plan.ClientID.ValueString(),
+	}
+
+	optArgs := &freeipa.IdpAddOptionalArgs{
+		Ipaidpprovider:         plan.Provider.ValueStringPointer(),
+		Ipaidporg:              plan.Org.ValueStringPointer(),
+		Ipaidpbaseurl:          plan.BaseURL.ValueStringPointer(),
+		Ipaidpclientsecret:     plan.ClientSecret.ValueStringPointer(),
+		Ipaidpauthendpoint:     stringToStringPointer(plan.AuthEndpoint),
+		Ipaidpdevauthendpoint:  stringToStringPointer(plan.DevAuthEndpoint),
+		Ipaidptokenendpoint:    stringToStringPointer(plan.TokenEndpoint),
+		Ipaidpuserinfoendpoint: stringToStringPointer(plan.UserInfoEndpoint),
+		Ipaidpkeysendpoint:     stringToStringPointer(plan.KeysEndpoint),
+		Ipaidpissuerurl:        stringToStringPointer(plan.IssuerURL),
+		Ipaidpscope:            stringToStringPointer(plan.Scope),
+		Ipaidpsub:              stringToStringPointer(plan.Sub),
+	}
+
+	// The secret is not logged
+	logOptArgs := *optArgs
+	logOptArgs.Ipaidpclientsecret = nil
+
+	tflog.Trace(ctx, "Calling IdpAdd", map[string]any{
+		"args":     args,
+		"opt_args": &logOptArgs,
+	})
+
+	res, err := r.provider.Client().IdpAdd(args, optArgs)
+
+	// The results may hold the secret, they are not logged
+	tflog.Trace(ctx, "Called IdpAdd", map[string]any{
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create identity provider", "Reason: "+err.Error())
+
+		return
+	}
+
+	state := plan
+	idpSetComputed(&state, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Idp) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state IdpModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdpShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling IdpShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().IdpShow(args, nil)
+
+	tflog.Trace(ctx, "Called IdpShow", map[string]any{
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read identity provider", "Reason: "+err.Error())
+
+		return
+	}
+
+	// The secret and the template arguments are kept as configured, FreeIPA
+	// does not return them
+	state.ClientID = types.StringValue(res.Result.Ipaidpclientid)
+	idpSetComputed(&state, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Idp) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan IdpModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdpModArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.IdpModOptionalArgs{}
+
+	if !plan.ClientID.Equal(state.ClientID) {
+		optArgs.Ipaidpclientid = plan.ClientID.ValueStringPointer()
+		hasDiff = true
+	}
+
+	// A removed secret is sent as an empty string to clear it
+	if !plan.ClientSecret.Equal(state.ClientSecret) {
+		optArgs.Ipaidpclientsecret = freeipa.String(plan.ClientSecret.ValueString())
+		hasDiff = true
+	}
+
+	stringChanges := []struct {
+		plan, state types.String
+		arg         **string
+	}{
+		{plan.AuthEndpoint, state.AuthEndpoint, &optArgs.Ipaidpauthendpoint},
+		{plan.DevAuthEndpoint, state.DevAuthEndpoint, &optArgs.Ipaidpdevauthendpoint},
+		{plan.TokenEndpoint, state.TokenEndpoint, &optArgs.Ipaidptokenendpoint},
+		{plan.UserInfoEndpoint, state.UserInfoEndpoint, &optArgs.Ipaidpuserinfoendpoint},
+		{plan.KeysEndpoint, state.KeysEndpoint, &optArgs.Ipaidpkeysendpoint},
+		{plan.IssuerURL, state.IssuerURL, &optArgs.Ipaidpissuerurl},
+		{plan.Scope, state.Scope, &optArgs.Ipaidpscope},
+		{plan.Sub, state.Sub, &optArgs.Ipaidpsub},
+	}
+
+	for _, c := range stringChanges {
+		if !c.plan.Equal(c.state) && !c.plan.IsUnknown() {
+			*c.arg = freeipa.String(c.plan.ValueString())
+			hasDiff = true
+		}
+	}
+
+	state = plan
+
+	if hasDiff {
+		logOptArgs := *optArgs
+		logOptArgs.Ipaidpclientsecret = nil
+
+		tflog.Trace(ctx, "Calling IdpMod", map[string]any{
+			"args":     args,
+			"opt_args": &logOptArgs,
+		})
+
+		res, err := r.provider.Client().IdpMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called IdpMod", map[string]any{
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update identity provider", "Reason: "+err.Error())
+
+			return
+		}
+
+		idpSetComputed(&state, &res.Result)
+	} else {
+		tflog.Debug(ctx, "Updated identity provider has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Idp) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state IdpModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdpDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling IdpDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().IdpDel(args, nil)
+
+	tflog.Trace(ctx, "Called IdpDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete identity provider", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Idp) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The secret and the template arguments are unknown to the imported state
+	state := IdpModel{
+		Name: types.StringValue(req.ID),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// idpSetComputed sets the attributes of state FreeIPA may derive from the
+// provider template.
+func idpSetComputed(state *IdpModel, idp *freeipa.Idp) {
+	state.AuthEndpoint = types.StringPointerValue(idp.Ipaidpauthendpoint)
+	state.DevAuthEndpoint = types.StringPointerValue(idp.Ipaidpdevauthendpoint)
+	state.TokenEndpoint = types.StringPointerValue(idp.Ipaidptokenendpoint)
+	state.UserInfoEndpoint = types.StringPointerValue(idp.Ipaidpuserinfoendpoint)
+	state.KeysEndpoint = types.StringPointerValue(idp.Ipaidpkeysendpoint)
+	state.IssuerURL = types.StringPointerValue(idp.Ipaidpissuerurl)
+	state.Scope = types.StringPointerValue(idp.Ipaidpscope)
+	state.Sub = types.StringPointerValue(idp.Ipaidpsub)
+}
+
+func NewIdp(p *provider.Provider) resource.Resource {
+	r := &Idp{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewIdp)
+}
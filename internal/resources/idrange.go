@@ -0,0 +1,406 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+const idrangeTypeLocal = "ipa-local"
+
+// idrangeTypes maps the range types displayed by FreeIPA to the values it
+// takes as input.
+var idrangeTypes = map[string]string{
+	"local domain range":                                 idrangeTypeLocal,
+	"Active Directory domain range":                      "ipa-ad-trust",
+	"Active Directory trust range with POSIX attributes": "ipa-ad-trust-posix",
+	idrangeTypeLocal:                                     idrangeTypeLocal,
+	"ipa-ad-trust":                                       "ipa-ad-trust",
+	"ipa-ad-trust-posix":                                 "ipa-ad-trust-posix",
+}
+
+type Idrange struct {
+	provider *provider.Provider
+}
+
+type IdrangeModel struct {
+	Name             types.String `tfsdk:"cn"`
+	BaseID           types.Int64  `tfsdk:"ipabaseid"`
+	Size             types.Int64  `tfsdk:"ipaidrangesize"`
+	BaseRID          types.Int64  `tfsdk:"ipabaserid"`
+	SecondaryBaseRID types.Int64  `tfsdk:"ipasecondarybaserid"`
+	Type             types.String `tfsdk:"iparangetype"`
+	TrustedDomainSID types.String `tfsdk:"ipanttrusteddomainsid"`
+}
+
+func (r *Idrange) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_idrange"
+}
+
+func (r *Idrange) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA ID range.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Range name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipabaseid": schema.Int64Attribute{
+				Description: "First Posix ID of the range",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"ipaidrangesize": schema.Int64Attribute{
+				Description: "Number of IDs in the range",
+				Required:    true,
+			},
+			"ipabaserid": schema.Int64Attribute{
+				Description: "First RID of the corresponding RID range",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipasecondarybaserid": schema.Int64Attribute{
+				Description: "First RID of the secondary RID range, only for local ranges",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"iparangetype": schema.StringAttribute{
+				Description: "Range type (`ipa-local`, `ipa-ad-trust` or `ipa-ad-trust-posix`). Defaults to `ipa-local`, or `ipa-ad-trust` when a trusted domain SID is set",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipanttrusteddomainsid": schema.StringAttribute{
+				Description: "SID of the trusted Active Directory domain, only for Active Directory ranges",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Idrange) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config IdrangeModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Type.IsUnknown() || config.TrustedDomainSID.IsUnknown() {
+		return
+	}
+
+	if !config.Type.IsNull() {
+		if !slices.Contains([]string{idrangeTypeLocal, "ipa-ad-trust", "ipa-ad-trust-posix"}, config.Type.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("iparangetype"),
+				"Invalid configuration",
+				`The range type must be one of “ipa-local”, “ipa-ad-trust” and “ipa-ad-trust-posix”.`,
+			)
+
+			return
+		}
+	}
+
+	local := config.Type.ValueString() == idrangeTypeLocal || (config.Type.IsNull() && config.TrustedDomainSID.IsNull())
+
+	if !local && !config.SecondaryBaseRID.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipasecondarybaserid"),
+			"Invalid configuration",
+			`The secondary base RID can only be set for “ipa-local” ranges.`,
+		)
+	}
+
+	if local && !config.TrustedDomainSID.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipanttrusteddomainsid"),
+			"Invalid configuration",
+			`The trusted domain SID cannot be set for “ipa-local” ranges.`,
+		)
+	}
+
+	if !local && config.TrustedDomainSID.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipanttrusteddomainsid"),
+			"Invalid configuration",
+			`The trusted domain SID must be set for Active Directory ranges.`,
+		)
+	}
+}
+
+func (r *Idrange) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan IdrangeModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdrangeAddArgs{
+		Cn:             plan.Name.ValueString(),
+		Ipabaseid:      int(plan.BaseID.ValueInt64()),
+		Ipaidrangesize: int(plan.Size.ValueInt64()),
+	}
+
+	optArgs := &freeipa.IdrangeAddOptionalArgs{
+		Ipabaserid:            int64ToIntPointer(plan.BaseRID),
+		Ipasecondarybaserid:   int64ToIntPointer(plan.SecondaryBaseRID),
+		Iparangetype:          stringToStringPointer(plan.Type),
+		Ipanttrusteddomainsid: plan.TrustedDomainSID.ValueStringPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling IdrangeAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().IdrangeAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called IdrangeAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create ID range", "Reason: "+err.Error())
+
+		return
+	}
+
+	state := plan
+	idrangeComputedState(&state, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Idrange) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state IdrangeModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdrangeShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling IdrangeShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().IdrangeShow(args, nil)
+
+	tflog.Trace(ctx, "Called IdrangeShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read ID range", "Reason: "+err.Error())
+
+		return
+	}
+
+	state.BaseID = types.Int64Value(int64(res.Result.Ipabaseid))
+	state.Size = types.Int64Value(int64(res.Result.Ipaidrangesize))
+	state.TrustedDomainSID = types.StringPointerValue(res.Result.Ipanttrusteddomainsid)
+	idrangeComputedState(&state, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Idrange) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan IdrangeModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdrangeModArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.IdrangeModOptionalArgs{}
+
+	intChanges := []struct {
+		plan, state types.Int64
+		arg         **int
+	}{
+		{plan.Size, state.Size, &optArgs.Ipaidrangesize},
+		{plan.BaseRID, state.BaseRID, &optArgs.Ipabaserid},
+		{plan.SecondaryBaseRID, state.SecondaryBaseRID, &optArgs.Ipasecondarybaserid},
+	}
+
+	for _, c := range intChanges {
+		if !c.plan.IsNull() && !c.plan.IsUnknown() && !c.plan.Equal(c.state) {
+			*c.arg = int64ToIntPointer(c.plan)
+			hasDiff = true
+		}
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling IdrangeMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().IdrangeMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called IdrangeMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update ID range", "Reason: "+err.Error())
+
+			return
+		}
+
+		idrangeComputedState(&plan, &res.Result)
+	} else {
+		tflog.Debug(ctx, "Updated ID range has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	if plan.BaseRID.IsUnknown() {
+		plan.BaseRID = state.BaseRID
+	}
+	if plan.SecondaryBaseRID.IsUnknown() {
+		plan.SecondaryBaseRID = state.SecondaryBaseRID
+	}
+	if plan.Type.IsUnknown() {
+		plan.Type = state.Type
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Idrange) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state IdrangeModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.IdrangeDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling IdrangeDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().IdrangeDel(args, nil)
+
+	tflog.Trace(ctx, "Called IdrangeDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete ID range", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Idrange) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := IdrangeModel{
+		Name:             types.StringValue(req.ID),
+		BaseID:           types.Int64Null(),
+		Size:             types.Int64Null(),
+		BaseRID:          types.Int64Null(),
+		SecondaryBaseRID: types.Int64Null(),
+		Type:             types.StringNull(),
+		TrustedDomainSID: types.StringNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// idrangeComputedState sets the attributes FreeIPA defaults from the range
+// returned by the server.
+func idrangeComputedState(state *IdrangeModel, idrange *freeipa.Idrange) {
+	state.BaseRID = intToInt64Value(idrange.Ipabaserid)
+	state.SecondaryBaseRID = intToInt64Value(idrange.Ipasecondarybaserid)
+
+	if idrange.Iparangetype != nil {
+		// Unknown types are kept as displayed, which shows up as a diff
+		if t, ok := idrangeTypes[*idrange.Iparangetype]; ok {
+			state.Type = types.StringValue(t)
+		} else {
+			state.Type = types.StringValue(*idrange.Iparangetype)
+		}
+	} else {
+		state.Type = types.StringNull()
+	}
+}
+
+func NewIdrange(p *provider.Provider) resource.Resource {
+	r := &Idrange{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewIdrange)
+}
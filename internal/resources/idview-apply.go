@@ -0,0 +1,458 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type IdviewApply struct {
+	provider *provider.Provider
+}
+
+type IdviewApplyModel struct {
+	Idview     types.String `tfsdk:"idview"`
+	Hosts      types.Set    `tfsdk:"hosts"`
+	Hostgroups types.Set    `tfsdk:"hostgroups"`
+}
+
+func (r *IdviewApply) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_idview_apply"
+}
+
+func (r *IdviewApply) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Applies a FreeIPA ID view to hosts.",
+		Attributes: map[string]schema.Attribute{
+			"idview": schema.StringAttribute{
+				Description: "ID view applied to the hosts",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hosts": schema.SetAttribute{
+				Description: "Hosts the ID view is applied to",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"hostgroups": schema.SetAttribute{
+				Description: "Host groups whose member hosts the ID view is applied to",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *IdviewApply) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config IdviewApplyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Hosts.IsNull() && config.Hostgroups.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hosts"),
+			"Invalid configuration",
+			`At least one of “hosts” and “hostgroups” must be set.`,
+		)
+	}
+}
+
+func (r *IdviewApply) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan IdviewApplyModel
+	var hosts, hostgroups []string
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(plan.Hosts.ElementsAs(ctx, &hosts, false)...)
+	resp.Diagnostics.Append(plan.Hostgroups.ElementsAs(ctx, &hostgroups, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.apply(ctx, plan.Idview.ValueString(), hosts, hostgroups); err != nil {
+		resp.Diagnostics.AddError("Failed to apply ID view", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *IdviewApply) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state IdviewApplyModel
+	var hosts, appliedHosts []string
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(state.Hosts.ElementsAs(ctx, &hosts, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, host := range hosts {
+		idview, err := r.hostIdview(ctx, host)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read host", "Reason: "+err.Error())
+
+			return
+		}
+
+		if idview == state.Idview.ValueString() {
+			appliedHosts = append(appliedHosts, host)
+		}
+	}
+
+	// Host groups are expanded to their members when the view is applied, the
+	// groups themselves cannot be read back.
+	if len(appliedHosts) == 0 && len(state.Hostgroups.Elements()) == 0 {
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if len(appliedHosts) != len(hosts) {
+		var diags diag.Diagnostics
+
+		state.Hosts, diags = types.SetValueFrom(ctx, types.StringType, appliedHosts)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *IdviewApply) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan IdviewApplyModel
+	var stateHosts, planHosts, stateHostgroups, planHostgroups []string
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(state.Hosts.ElementsAs(ctx, &stateHosts, false)...)
+	resp.Diagnostics.Append(plan.Hosts.ElementsAs(ctx, &planHosts, false)...)
+	resp.Diagnostics.Append(state.Hostgroups.ElementsAs(ctx, &stateHostgroups, false)...)
+	resp.Diagnostics.Append(plan.Hostgroups.ElementsAs(ctx, &planHostgroups, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, hostsToRemove := utils.SetDiff(slices.Clone(stateHosts), slices.Clone(planHosts))
+	_, hostgroupsToRemove := utils.SetDiff(slices.Clone(stateHostgroups), slices.Clone(planHostgroups))
+
+	if err := r.unapply(ctx, plan.Idview.ValueString(), hostsToRemove, hostgroupsToRemove); err != nil {
+		resp.Diagnostics.AddError("Failed to unapply ID view", "Reason: "+err.Error())
+
+		return
+	}
+
+	// Applying is idempotent, hosts shared with removed host groups get the
+	// view back.
+	if err := r.apply(ctx, plan.Idview.ValueString(), planHosts, planHostgroups); err != nil {
+		resp.Diagnostics.AddError("Failed to apply ID view", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *IdviewApply) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state IdviewApplyModel
+	var hosts, hostgroups []string
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(state.Hosts.ElementsAs(ctx, &hosts, false)...)
+	resp.Diagnostics.Append(state.Hostgroups.ElementsAs(ctx, &hostgroups, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.unapply(ctx, state.Idview.ValueString(), hosts, hostgroups); err != nil {
+		resp.Diagnostics.AddError("Failed to unapply ID view", "Reason: "+err.Error())
+	}
+}
+
+func (r *IdviewApply) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idview, rawHosts, ok := strings.Cut(req.ID, "/")
+
+	if !ok || idview == "" || rawHosts == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<idview>/<host>[,<host>...]”, got %q.", req.ID),
+		)
+
+		return
+	}
+
+	hosts, diags := types.SetValueFrom(ctx, types.StringType, strings.Split(rawHosts, ","))
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := IdviewApplyModel{
+		Idview:     types.StringValue(idview),
+		Hosts:      hosts,
+		Hostgroups: types.SetNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// apply applies the ID view to the hosts and the members of the host groups.
+// go-freeipa cannot decode the failures of idview_apply, the hosts are checked
+// afterwards instead.
+func (r *IdviewApply) apply(ctx context.Context, idview string, hosts, hostgroups []string) error {
+	if len(hosts) == 0 && len(hostgroups) == 0 {
+		return nil
+	}
+
+	args := &freeipa.IdviewApplyArgs{
+		Cn: idview,
+	}
+
+	optArgs := &freeipa.IdviewApplyOptionalArgs{}
+	if len(hosts) > 0 {
+		optArgs.Host = &hosts
+	}
+	if len(hostgroups) > 0 {
+		optArgs.Hostgroup = &hostgroups
+	}
+
+	tflog.Trace(ctx, "Calling IdviewApply", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().IdviewApply(args, optArgs)
+
+	tflog.Trace(ctx, "Called IdviewApply", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil && !utils.IsFailedOperationsDecodeError(err) {
+		return err
+	}
+
+	targets, err := r.targetHosts(ctx, hosts, hostgroups)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+
+	for _, host := range targets {
+		applied, err := r.hostIdview(ctx, host)
+		if err != nil {
+			return err
+		}
+
+		if applied != idview {
+			failed = append(failed, host)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("ID view %s could not be applied to hosts %s", idview, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// unapply removes the ID view from the hosts and the members of the host
+// groups. Hosts with another view applied are left untouched.
+func (r *IdviewApply) unapply(ctx context.Context, idview string, hosts, hostgroups []string) error {
+	targets, err := r.targetHosts(ctx, hosts, hostgroups)
+	if err != nil {
+		return err
+	}
+
+	var appliedHosts []string
+
+	for _, host := range targets {
+		applied, err := r.hostIdview(ctx, host)
+		if err != nil {
+			return err
+		}
+
+		if applied == idview {
+			appliedHosts = append(appliedHosts, host)
+		}
+	}
+
+	if len(appliedHosts) == 0 {
+		return nil
+	}
+
+	args := &freeipa.IdviewUnapplyArgs{}
+
+	optArgs := &freeipa.IdviewUnapplyOptionalArgs{
+		Host: &appliedHosts,
+	}
+
+	tflog.Trace(ctx, "Calling IdviewUnapply", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().IdviewUnapply(args, optArgs)
+
+	tflog.Trace(ctx, "Called IdviewUnapply", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil && !utils.IsFailedOperationsDecodeError(err) {
+		return err
+	}
+
+	return nil
+}
+
+// targetHosts returns the hosts along with the direct and indirect members of
+// the host groups. Missing host groups are skipped.
+func (r *IdviewApply) targetHosts(ctx context.Context, hosts, hostgroups []string) ([]string, error) {
+	targets := slices.Clone(hosts)
+
+	for _, hostgroup := range hostgroups {
+		args := &freeipa.HostgroupShowArgs{
+			Cn: hostgroup,
+		}
+
+		tflog.Trace(ctx, "Calling HostgroupShow", map[string]any{
+			"args":     args,
+			"opt_args": nil,
+		})
+
+		res, err := r.provider.Client().HostgroupShow(args, nil)
+
+		tflog.Trace(ctx, "Called HostgroupShow", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			var freeipaErr *freeipa.Error
+
+			if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, members := range []*[]string{res.Result.MemberHost, res.Result.MemberindirectHost} {
+			if members != nil {
+				targets = append(targets, *members...)
+			}
+		}
+	}
+
+	slices.Sort(targets)
+
+	return slices.Compact(targets), nil
+}
+
+// hostIdview returns the ID view applied to the host, empty when there is none
+// or the host does not exist.
+func (r *IdviewApply) hostIdview(ctx context.Context, fqdn string) (string, error) {
+	args := &freeipa.HostShowArgs{
+		Fqdn: fqdn,
+	}
+
+	optArgs := &freeipa.HostShowOptionalArgs{
+		All:       freeipa.Bool(true),
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling HostShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().HostShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called HostShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	if res.Result.Ipaassignedidview == nil {
+		return "", nil
+	}
+
+	return *res.Result.Ipaassignedidview, nil
+}
+
+func NewIdviewApply(p *provider.Provider) resource.Resource {
+	r := &IdviewApply{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewIdviewApply)
+}
@@ -0,0 +1,325 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultServiceWeight is the SRV record weight of the servers which do not
+// set one.
+const defaultServiceWeight = 100
+
+type Location struct {
+	provider *provider.Provider
+}
+
+type LocationModel struct {
+	Name          types.String `tfsdk:"idnsname"`
+	Description   types.String `tfsdk:"description"`
+	Servers       types.Set    `tfsdk:"servers_server"`
+	ServerWeights types.Map    `tfsdk:"server_weights"`
+}
+
+func (r *Location) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_location"
+}
+
+func (r *Location) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA server location, used by clients to discover the nearest servers through DNS.",
+		Attributes: map[string]schema.Attribute{
+			"idnsname": schema.StringAttribute{
+				Description: "Location name, a relative DNS name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Location description",
+				Optional:    true,
+			},
+			"servers_server": schema.SetAttribute{
+				Description: "Servers assigned to the location, see `freeipa_server_location`",
+				ElementType: types.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"server_weights": schema.MapAttribute{
+				Description: "SRV record weights of the servers of the location, by server name",
+				ElementType: types.Int64Type,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Location) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan LocationModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.LocationAddArgs{
+		Idnsname: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.LocationAddOptionalArgs{
+		Description: plan.Description.ValueStringPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling LocationAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().LocationAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called LocationAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create location", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(r.readServers(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Location) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state LocationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.LocationShowArgs{
+		Idnsname: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling LocationShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().LocationShow(args, nil)
+
+	tflog.Trace(ctx, "Called LocationShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read location", "Reason: "+err.Error())
+
+		return
+	}
+
+	if name := utils.DnsNameValue(res.Result.Idnsname); name != "" {
+		state.Name = types.StringValue(name)
+	}
+	state.Description = types.StringPointerValue(res.Result.Description)
+
+	resp.Diagnostics.Append(r.readServers(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Location) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan LocationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Description.Equal(state.Description) {
+		args := &freeipa.LocationModArgs{
+			Idnsname: plan.Name.ValueString(),
+		}
+
+		optArgs := &freeipa.LocationModOptionalArgs{
+			Description: freeipa.String(plan.Description.ValueString()),
+		}
+
+		tflog.Trace(ctx, "Calling LocationMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().LocationMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called LocationMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update location", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated location has no effective difference", map[string]any{
+			"idnsname": plan.Name.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(r.readServers(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Location) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state LocationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.LocationDelArgs{
+		Idnsname: []interface{}{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling LocationDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().LocationDel(args, nil)
+
+	tflog.Trace(ctx, "Called LocationDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil && !utils.IsNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to delete location", "Reason: "+err.Error())
+	}
+}
+
+func (r *Location) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := LocationModel{
+		Name:          types.StringValue(req.ID),
+		Description:   types.StringNull(),
+		Servers:       types.SetNull(types.StringType),
+		ServerWeights: types.MapNull(types.Int64Type),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// readServers sets the servers of the location and their SRV record weights,
+// which FreeIPA stores on the servers themselves.
+func (r *Location) readServers(ctx context.Context, state *LocationModel) (diags diag.Diagnostics) {
+	args := &freeipa.ServerFindArgs{}
+
+	optArgs := &freeipa.ServerFindOptionalArgs{
+		InLocation: &[]interface{}{state.Name.ValueString()},
+		Sizelimit:  freeipa.Int(0),
+	}
+
+	tflog.Trace(ctx, "Calling ServerFind", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().ServerFind("", args, optArgs)
+
+	tflog.Trace(ctx, "Called ServerFind", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		diags.AddError("Failed to read location servers", "Reason: "+err.Error())
+
+		return
+	}
+
+	servers := []string{}
+	weights := map[string]int64{}
+
+	for _, server := range res.Result {
+		weight := int64(defaultServiceWeight)
+		if server.Ipaserviceweight != nil {
+			weight = int64(*server.Ipaserviceweight)
+		}
+
+		servers = append(servers, server.Cn)
+		weights[server.Cn] = weight
+	}
+
+	var d diag.Diagnostics
+
+	state.Servers, d = types.SetValueFrom(ctx, types.StringType, servers)
+	diags.Append(d...)
+
+	state.ServerWeights, d = types.MapValueFrom(ctx, types.Int64Type, weights)
+	diags.Append(d...)
+
+	return
+}
+
+func NewLocation(p *provider.Provider) resource.Resource {
+	r := &Location{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewLocation)
+}
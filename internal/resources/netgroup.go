@@ -0,0 +1,317 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Netgroup struct {
+	provider *provider.Provider
+}
+
+type NetgroupModel struct {
+	Name          types.String `tfsdk:"cn"`
+	Description   types.String `tfsdk:"description"`
+	NisDomainName types.String `tfsdk:"nisdomainname"`
+	UserCategory  types.String `tfsdk:"usercategory"`
+	HostCategory  types.String `tfsdk:"hostcategory"`
+}
+
+func (r *Netgroup) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_netgroup"
+}
+
+func (r *Netgroup) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA NIS netgroup.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Netgroup name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Netgroup description",
+				Optional:    true,
+			},
+			"nisdomainname": schema.StringAttribute{
+				Description: "NIS domain name (defaults to the IPA domain)",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"usercategory": schema.StringAttribute{
+				Description: "User category the netgroup applies to (allowed value: `all`)",
+				Optional:    true,
+			},
+			"hostcategory": schema.StringAttribute{
+				Description: "Host category the netgroup applies to (allowed value: `all`)",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *Netgroup) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config NetgroupModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	categories := map[string]types.String{
+		"usercategory": config.UserCategory,
+		"hostcategory": config.HostCategory,
+	}
+
+	for attr, category := range categories {
+		if !category.IsUnknown() && !category.IsNull() && category.ValueString() != "all" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr),
+				"Invalid configuration",
+				`The only allowed category is “all”.`,
+			)
+		}
+	}
+}
+
+func (r *Netgroup) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan NetgroupModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.NetgroupAddArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.NetgroupAddOptionalArgs{
+		Description:   plan.Description.ValueStringPointer(),
+		Nisdomainname: stringToStringPointer(plan.NisDomainName),
+		Usercategory:  plan.UserCategory.ValueStringPointer(),
+		Hostcategory:  plan.HostCategory.ValueStringPointer(),
+		NoMembers:     freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling NetgroupAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().NetgroupAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called NetgroupAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create netgroup", "Reason: "+err.Error())
+
+		return
+	}
+
+	state := plan
+	state.NisDomainName = types.StringPointerValue(res.Result.Nisdomainname)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Netgroup) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state NetgroupModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.NetgroupShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	// Members and external hosts are managed by association resources
+	optArgs := &freeipa.NetgroupShowOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling NetgroupShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().NetgroupShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called NetgroupShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read netgroup", "Reason: "+err.Error())
+
+		return
+	}
+
+	state.Description = types.StringPointerValue(res.Result.Description)
+	state.NisDomainName = types.StringPointerValue(res.Result.Nisdomainname)
+	state.UserCategory = types.StringPointerValue(res.Result.Usercategory)
+	state.HostCategory = types.StringPointerValue(res.Result.Hostcategory)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Netgroup) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan NetgroupModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.NetgroupModArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.NetgroupModOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	stringChanges := []struct {
+		plan, state types.String
+		arg         **string
+	}{
+		{plan.Description, state.Description, &optArgs.Description},
+		{plan.NisDomainName, state.NisDomainName, &optArgs.Nisdomainname},
+		{plan.UserCategory, state.UserCategory, &optArgs.Usercategory},
+		{plan.HostCategory, state.HostCategory, &optArgs.Hostcategory},
+	}
+
+	// A null plan value is sent as an empty string to clear the attribute
+	for _, c := range stringChanges {
+		if !c.plan.Equal(c.state) && !c.plan.IsUnknown() {
+			*c.arg = freeipa.String(c.plan.ValueString())
+			hasDiff = true
+		}
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling NetgroupMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().NetgroupMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called NetgroupMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update netgroup", "Reason: "+err.Error())
+
+			return
+		}
+
+		plan.NisDomainName = types.StringPointerValue(res.Result.Nisdomainname)
+	} else {
+		tflog.Debug(ctx, "Updated netgroup has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	if plan.NisDomainName.IsUnknown() {
+		plan.NisDomainName = state.NisDomainName
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Netgroup) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state NetgroupModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.NetgroupDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling NetgroupDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().NetgroupDel(args, nil)
+
+	tflog.Trace(ctx, "Called NetgroupDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete netgroup", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Netgroup) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := NetgroupModel{
+		Name: types.StringValue(req.ID),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewNetgroup(p *provider.Provider) resource.Resource {
+	r := &Netgroup{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewNetgroup)
+}
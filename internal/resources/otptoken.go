@@ -0,0 +1,445 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type OTPToken struct {
+	provider *provider.Provider
+}
+
+type OTPTokenModel struct {
+	ID          types.String `tfsdk:"ipatokenuniqueid"`
+	Type        types.String `tfsdk:"type"`
+	Description types.String `tfsdk:"description"`
+	Owner       types.String `tfsdk:"ipatokenowner"`
+	Algorithm   types.String `tfsdk:"ipatokenotpalgorithm"`
+	Digits      types.Int64  `tfsdk:"ipatokenotpdigits"`
+	TimeStep    types.Int64  `tfsdk:"ipatokentotptimestep"`
+	Disabled    types.Bool   `tfsdk:"ipatokendisabled"`
+	Key         types.String `tfsdk:"ipatokenotpkey"`
+	URI         types.String `tfsdk:"uri"`
+}
+
+func (r *OTPToken) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_otptoken"
+}
+
+func (r *OTPToken) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA OTP token.",
+		Attributes: map[string]schema.Attribute{
+			"ipatokenuniqueid": schema.StringAttribute{
+				Description: "Unique ID of the token, generated by FreeIPA when not set",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Description: "Type of the token (`totp` or `hotp`). Defaults to `totp`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Token description",
+				Optional:    true,
+			},
+			"ipatokenowner": schema.StringAttribute{
+				Description: "User the token is assigned to. Defaults to the user the provider is connected as",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipatokenotpalgorithm": schema.StringAttribute{
+				Description: "Token hash algorithm (`sha1`, `sha256`, `sha384` or `sha512`). Defaults to `sha1`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipatokenotpdigits": schema.Int64Attribute{
+				Description: "Number of digits of the generated codes (6 or 8). Defaults to 6",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"ipatokentotptimestep": schema.Int64Attribute{
+				Description: "Length of the TOTP time window (in seconds). Defaults to 30",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"ipatokendisabled": schema.BoolAttribute{
+				Description: "Whether the token is disabled",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipatokenotpkey": schema.StringAttribute{
+				Description: "Token secret (Base32). Only known when the token is created by Terraform",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"uri": schema.StringAttribute{
+				Description: "`otpauth://` URI to enroll the token in an authenticator application, usually rendered as a QR code. Only known when the token is created by Terraform",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *OTPToken) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config OTPTokenModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Type.IsUnknown() && !config.Type.IsNull() {
+		tokenType := strings.ToLower(config.Type.ValueString())
+
+		if tokenType != "totp" && tokenType != "hotp" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("type"),
+				"Invalid configuration",
+				`The token type must be “totp” or “hotp”.`,
+			)
+		}
+
+		if tokenType == "hotp" && !config.TimeStep.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ipatokentotptimestep"),
+				"Invalid configuration",
+				`“ipatokentotptimestep” only applies to TOTP tokens.`,
+			)
+		}
+	}
+
+	if !config.Algorithm.IsUnknown() && !config.Algorithm.IsNull() &&
+		!slices.Contains([]string{"sha1", "sha256", "sha384", "sha512"}, config.Algorithm.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipatokenotpalgorithm"),
+			"Invalid configuration",
+			`The algorithm must be one of “sha1”, “sha256”, “sha384” or “sha512”.`,
+		)
+	}
+
+	if !config.Digits.IsUnknown() && !config.Digits.IsNull() && config.Digits.ValueInt64() != 6 && config.Digits.ValueInt64() != 8 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipatokenotpdigits"),
+			"Invalid configuration",
+			`The number of digits must be 6 or 8.`,
+		)
+	}
+}
+
+func (r *OTPToken) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan OTPTokenModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.OtptokenAddArgs{}
+
+	optArgs := &freeipa.OtptokenAddOptionalArgs{
+		Description:          plan.Description.ValueStringPointer(),
+		Ipatokenowner:        stringToStringPointer(plan.Owner),
+		Ipatokenotpalgorithm: stringToStringPointer(plan.Algorithm),
+		Ipatokenotpdigits:    int64ToIntPointer(plan.Digits),
+		Ipatokentotptimestep: int64ToIntPointer(plan.TimeStep),
+		NoQrcode:             freeipa.Bool(true),
+	}
+
+	if !plan.Disabled.IsUnknown() {
+		optArgs.Ipatokendisabled = plan.Disabled.ValueBoolPointer()
+	}
+
+	if !plan.Type.IsUnknown() {
+		optArgs.Type = freeipa.String(strings.ToLower(plan.Type.ValueString()))
+	}
+
+	// An empty ID lets FreeIPA generate one
+	id := plan.ID.ValueString()
+
+	tflog.Trace(ctx, "Calling OtptokenAdd", map[string]any{
+		"ipatokenuniqueid": id,
+		"args":             args,
+		"opt_args":         optArgs,
+	})
+
+	res, err := r.provider.Client().OtptokenAdd(id, args, optArgs)
+
+	// The result holds the token secret, it is not logged
+	tflog.Trace(ctx, "Called OtptokenAdd", map[string]any{
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create OTP token", "Reason: "+err.Error())
+
+		return
+	}
+
+	state := plan
+	state.ID = types.StringValue(res.Result.Ipatokenuniqueid)
+	state.URI = types.StringPointerValue(res.Result.URI)
+	state.Key = types.StringPointerValue(res.Result.Ipatokenotpkey)
+
+	// The secret is only part of the enrollment URI in some FreeIPA versions
+	if state.Key.IsNull() && res.Result.URI != nil {
+		if u, err := url.Parse(*res.Result.URI); err == nil && u.Query().Get("secret") != "" {
+			state.Key = types.StringValue(u.Query().Get("secret"))
+		}
+	}
+
+	otpTokenStateFromResult(&state, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *OTPToken) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state OTPTokenModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.OtptokenShowArgs{
+		Ipatokenuniqueid: state.ID.ValueString(),
+	}
+
+	optArgs := &freeipa.OtptokenShowOptionalArgs{
+		All: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling OtptokenShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().OtptokenShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called OtptokenShow", map[string]any{
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read OTP token", "Reason: "+err.Error())
+
+		return
+	}
+
+	// The secret and the URI are only returned on creation, they are kept
+	// from the state.
+	state.Description = types.StringPointerValue(res.Result.Description)
+	otpTokenStateFromResult(&state, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *OTPToken) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan OTPTokenModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.OtptokenModArgs{
+		Ipatokenuniqueid: state.ID.ValueString(),
+	}
+
+	optArgs := &freeipa.OtptokenModOptionalArgs{}
+
+	if !plan.Description.Equal(state.Description) {
+		optArgs.Description = freeipa.String(plan.Description.ValueString())
+		hasDiff = true
+	}
+
+	if !plan.Owner.Equal(state.Owner) && !plan.Owner.IsUnknown() {
+		optArgs.Ipatokenowner = freeipa.String(plan.Owner.ValueString())
+		hasDiff = true
+	}
+
+	if !plan.Disabled.Equal(state.Disabled) && !plan.Disabled.IsUnknown() {
+		optArgs.Ipatokendisabled = plan.Disabled.ValueBoolPointer()
+		hasDiff = true
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling OtptokenMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().OtptokenMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called OtptokenMod", map[string]any{
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update OTP token", "Reason: "+err.Error())
+
+			return
+		}
+
+		otpTokenStateFromResult(&plan, &res.Result)
+	} else {
+		tflog.Debug(ctx, "Updated OTP token has no effective difference", map[string]any{
+			"ipatokenuniqueid": state.ID.ValueString(),
+		})
+	}
+
+	if plan.Owner.IsUnknown() {
+		plan.Owner = state.Owner
+	}
+
+	if plan.Disabled.IsUnknown() {
+		plan.Disabled = state.Disabled
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *OTPToken) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state OTPTokenModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.OtptokenDelArgs{
+		Ipatokenuniqueid: []string{state.ID.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling OtptokenDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().OtptokenDel(args, nil)
+
+	tflog.Trace(ctx, "Called OtptokenDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete OTP token", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *OTPToken) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := OTPTokenModel{
+		ID:        types.StringValue(req.ID),
+		Type:      types.StringNull(),
+		Owner:     types.StringNull(),
+		Algorithm: types.StringNull(),
+		Digits:    types.Int64Null(),
+		TimeStep:  types.Int64Null(),
+		Disabled:  types.BoolNull(),
+		Key:       types.StringNull(),
+		URI:       types.StringNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// otpTokenStateFromResult copies the attributes FreeIPA returns for a token,
+// keeping the configured case of the token type.
+func otpTokenStateFromResult(state *OTPTokenModel, token *freeipa.Otptoken) {
+	if token.Type != nil && !strings.EqualFold(state.Type.ValueString(), *token.Type) {
+		state.Type = types.StringValue(strings.ToLower(*token.Type))
+	} else if token.Type == nil && state.Type.IsUnknown() {
+		state.Type = types.StringNull()
+	}
+
+	state.Owner = types.StringPointerValue(token.Ipatokenowner)
+	state.Algorithm = types.StringPointerValue(token.Ipatokenotpalgorithm)
+	state.Digits = intToInt64Value(token.Ipatokenotpdigits)
+	state.TimeStep = intToInt64Value(token.Ipatokentotptimestep)
+	state.Disabled = types.BoolValue(token.Ipatokendisabled != nil && *token.Ipatokendisabled)
+}
+
+func NewOTPToken(p *provider.Provider) resource.Resource {
+	r := &OTPToken{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewOTPToken)
+}
@@ -0,0 +1,504 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	permissionRights    = []string{"read", "search", "compare", "write", "add", "delete", "all"}
+	permissionBindTypes = []string{"permission", "all", "anonymous", "self"}
+)
+
+type Permission struct {
+	provider *provider.Provider
+}
+
+type PermissionModel struct {
+	Name         types.String `tfsdk:"cn"`
+	Rights       types.Set    `tfsdk:"ipapermright"`
+	Attrs        types.Set    `tfsdk:"attrs"`
+	BindType     types.String `tfsdk:"ipapermbindruletype"`
+	Location     types.String `tfsdk:"ipapermlocation"`
+	TargetFilter types.Set    `tfsdk:"ipapermtargetfilter"`
+	Target       types.String `tfsdk:"ipapermtarget"`
+	TargetGroup  types.String `tfsdk:"targetgroup"`
+	MemberOf     types.Set    `tfsdk:"memberof"`
+	Type         types.String `tfsdk:"type"`
+}
+
+func (r *Permission) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_permission"
+}
+
+func (r *Permission) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA RBAC permission.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Permission name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipapermright": schema.SetAttribute{
+				Description: "Rights granted by the permission, any of " + strings.Join(permissionRights, ", "),
+				ElementType: types.StringType,
+				Required:    true,
+			},
+			"attrs": schema.SetAttribute{
+				Description: "Attributes the permission applies to",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"ipapermbindruletype": schema.StringAttribute{
+				Description: "Bind rule type, one of " + strings.Join(permissionBindTypes, ", ") + " (defaults to `permission`)",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipapermlocation": schema.StringAttribute{
+				Description: "Subtree the permission applies to (computed from `type` when not set)",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipapermtargetfilter": schema.SetAttribute{
+				Description: "LDAP filters the target entries must match. The object class filter implied by `type` is not included",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"ipapermtarget": schema.StringAttribute{
+				Description: "DN of the entries the permission applies to",
+				Optional:    true,
+			},
+			"targetgroup": schema.StringAttribute{
+				Description: "User group the permission applies to",
+				Optional:    true,
+			},
+			"memberof": schema.SetAttribute{
+				Description: "Target members of the given groups",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: "Type of the entries the permission applies to (e.g. `user`, `group`, `host`)",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *Permission) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config PermissionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Rights.IsUnknown() && !config.Rights.IsNull() {
+		var rights []string
+
+		resp.Diagnostics.Append(config.Rights.ElementsAs(ctx, &rights, true)...)
+
+		for _, right := range rights {
+			if !slices.Contains(permissionRights, right) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("ipapermright"),
+					"Invalid configuration",
+					fmt.Sprintf("Unsupported right “%s”, expected any of %s.", right, strings.Join(permissionRights, ", ")),
+				)
+			}
+		}
+	}
+
+	if !config.BindType.IsUnknown() && !config.BindType.IsNull() && !slices.Contains(permissionBindTypes, config.BindType.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipapermbindruletype"),
+			"Invalid configuration",
+			fmt.Sprintf("Unsupported bind rule type “%s”, expected one of %s.", config.BindType.ValueString(), strings.Join(permissionBindTypes, ", ")),
+		)
+	}
+}
+
+func (r *Permission) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan PermissionModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.PermissionAddArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.PermissionAddOptionalArgs{
+		Ipapermright:        setToStringSlicePointer(ctx, plan.Rights, &resp.Diagnostics),
+		Attrs:               setToStringSlicePointer(ctx, plan.Attrs, &resp.Diagnostics),
+		Ipapermbindruletype: plan.BindType.ValueStringPointer(),
+		Ipapermlocation:     plan.Location.ValueStringPointer(),
+		Ipapermtarget:       plan.Target.ValueStringPointer(),
+		Targetgroup:         plan.TargetGroup.ValueStringPointer(),
+		Memberof:            setToStringSlicePointer(ctx, plan.MemberOf, &resp.Diagnostics),
+		Type:                plan.Type.ValueStringPointer(),
+		All:                 freeipa.Bool(true),
+	}
+
+	// The object class filter implied by the type is managed by FreeIPA, only
+	// the additional filters are sent in that case.
+	if plan.Type.IsNull() {
+		optArgs.Ipapermtargetfilter = setToStringSlicePointer(ctx, plan.TargetFilter, &resp.Diagnostics)
+	} else {
+		optArgs.Extratargetfilter = setToStringSlicePointer(ctx, plan.TargetFilter, &resp.Diagnostics)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Calling PermissionAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().PermissionAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called PermissionAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create permission", "Reason: "+err.Error())
+
+		return
+	}
+
+	state := plan
+	state.BindType = types.StringValue(res.Result.Ipapermbindruletype)
+	state.Location = types.StringPointerValue(res.Result.Ipapermlocation)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Permission) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state PermissionModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.PermissionShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	// Privileges are managed by freeipa_privilege_permission_membership
+	optArgs := &freeipa.PermissionShowOptionalArgs{
+		All:       freeipa.Bool(true),
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling PermissionShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().PermissionShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called PermissionShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read permission", "Reason: "+err.Error())
+
+		return
+	}
+
+	permission := res.Result
+
+	state.BindType = types.StringValue(permission.Ipapermbindruletype)
+	state.Location = types.StringPointerValue(permission.Ipapermlocation)
+	state.Target = types.StringPointerValue(permission.Ipapermtarget)
+	state.TargetGroup = types.StringPointerValue(permission.Targetgroup)
+	state.Type = types.StringPointerValue(permission.Type)
+
+	state.Rights = stringSliceToSet(ctx, permission.Ipapermright, false, &resp.Diagnostics)
+	state.MemberOf = stringSliceToSet(ctx, permission.Memberof, state.MemberOf.IsNull(), &resp.Diagnostics)
+
+	// FreeIPA lower-cases attribute names, keep the configured spelling when
+	// only the case differs.
+	attrs := permission.Attrs
+	if attrs != nil && !state.Attrs.IsNull() {
+		var current []string
+
+		resp.Diagnostics.Append(state.Attrs.ElementsAs(ctx, &current, false)...)
+
+		attrs = preserveCaseInsensitive(current, *attrs)
+	}
+	state.Attrs = stringSliceToSet(ctx, attrs, state.Attrs.IsNull(), &resp.Diagnostics)
+
+	targetFilter := permission.Ipapermtargetfilter
+	if permission.Type != nil {
+		targetFilter = permission.Extratargetfilter
+	}
+	state.TargetFilter = stringSliceToSet(ctx, targetFilter, state.TargetFilter.IsNull(), &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Permission) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan PermissionModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.PermissionModArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.PermissionModOptionalArgs{
+		All: freeipa.Bool(true),
+	}
+
+	stringChanges := []struct {
+		plan, state types.String
+		arg         **string
+	}{
+		{plan.BindType, state.BindType, &optArgs.Ipapermbindruletype},
+		{plan.Location, state.Location, &optArgs.Ipapermlocation},
+		{plan.Target, state.Target, &optArgs.Ipapermtarget},
+		{plan.TargetGroup, state.TargetGroup, &optArgs.Targetgroup},
+		{plan.Type, state.Type, &optArgs.Type},
+	}
+
+	// A null plan value is sent as an empty string to clear the attribute
+	for _, c := range stringChanges {
+		if !c.plan.Equal(c.state) && !c.plan.IsUnknown() {
+			*c.arg = freeipa.String(c.plan.ValueString())
+			hasDiff = true
+		}
+	}
+
+	targetFilterArg := &optArgs.Ipapermtargetfilter
+	if !plan.Type.IsNull() {
+		targetFilterArg = &optArgs.Extratargetfilter
+	}
+
+	setChanges := []struct {
+		plan, state types.Set
+		arg         **[]string
+	}{
+		{plan.Rights, state.Rights, &optArgs.Ipapermright},
+		{plan.Attrs, state.Attrs, &optArgs.Attrs},
+		{plan.MemberOf, state.MemberOf, &optArgs.Memberof},
+		{plan.TargetFilter, state.TargetFilter, targetFilterArg},
+	}
+
+	// Changing the type changes the implied object class filter, always send
+	// the additional filters again in that case.
+	typeChanged := !plan.Type.Equal(state.Type)
+
+	for _, c := range setChanges {
+		if !c.plan.Equal(c.state) || (typeChanged && c.arg == targetFilterArg) {
+			values := []string{}
+
+			resp.Diagnostics.Append(c.plan.ElementsAs(ctx, &values, false)...)
+
+			*c.arg = &values
+			hasDiff = true
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling PermissionMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().PermissionMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called PermissionMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update permission", "Reason: "+err.Error())
+
+			return
+		}
+
+		plan.BindType = types.StringValue(res.Result.Ipapermbindruletype)
+		plan.Location = types.StringPointerValue(res.Result.Ipapermlocation)
+	} else {
+		tflog.Debug(ctx, "Updated permission has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Permission) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state PermissionModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.PermissionDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling PermissionDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().PermissionDel(args, nil)
+
+	tflog.Trace(ctx, "Called PermissionDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete permission", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Permission) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := PermissionModel{
+		Name:         types.StringValue(req.ID),
+		Rights:       types.SetNull(types.StringType),
+		Attrs:        types.SetNull(types.StringType),
+		TargetFilter: types.SetNull(types.StringType),
+		MemberOf:     types.SetNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewPermission(p *provider.Provider) resource.Resource {
+	r := &Permission{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewPermission)
+}
+
+// setToStringSlicePointer converts an optional set attribute to the pointer
+// expected by go-freeipa optional arguments, nil when the set is null.
+func setToStringSlicePointer(ctx context.Context, set types.Set, diags *diag.Diagnostics) *[]string {
+	if set.IsNull() || set.IsUnknown() {
+		return nil
+	}
+
+	values := []string{}
+
+	diags.Append(set.ElementsAs(ctx, &values, false)...)
+
+	return &values
+}
+
+// stringSliceToSet converts a multi-valued attribute returned by FreeIPA to a
+// set. Missing values are exposed as a null set when nullIfEmpty is set, to
+// match unset optional attributes, and as an empty set otherwise.
+func stringSliceToSet(ctx context.Context, values *[]string, nullIfEmpty bool, diags *diag.Diagnostics) types.Set {
+	if values == nil || len(*values) == 0 {
+		if nullIfEmpty {
+			return types.SetNull(types.StringType)
+		}
+
+		return types.SetValueMust(types.StringType, []attr.Value{})
+	}
+
+	set, d := types.SetValueFrom(ctx, types.StringType, *values)
+
+	diags.Append(d...)
+
+	return set
+}
+
+// preserveCaseInsensitive returns actual with the elements which only differ
+// by case from an element of current replaced by the current spelling.
+func preserveCaseInsensitive(current, actual []string) *[]string {
+	res := make([]string, len(actual))
+
+	for i, a := range actual {
+		res[i] = a
+
+		for _, c := range current {
+			if strings.EqualFold(a, c) {
+				res[i] = c
+
+				break
+			}
+		}
+	}
+
+	return &res
+}
@@ -0,0 +1,224 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type PrivilegePermissionMembership struct {
+	provider *provider.Provider
+}
+
+type PrivilegePermissionMembershipModel struct {
+	Privilege  types.String `tfsdk:"privilege"`
+	Permission types.String `tfsdk:"permission"`
+}
+
+func (r *PrivilegePermissionMembership) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_privilege_permission_membership"
+}
+
+func (r *PrivilegePermissionMembership) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Adds a permission to a FreeIPA RBAC privilege.",
+		Attributes: map[string]schema.Attribute{
+			"privilege": schema.StringAttribute{
+				Description: "Privilege name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"permission": schema.StringAttribute{
+				Description: "Permission added to the privilege",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *PrivilegePermissionMembership) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan PrivilegePermissionMembershipModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.PrivilegeAddPermissionArgs{
+		Cn: plan.Privilege.ValueString(),
+	}
+	optArgs := &freeipa.PrivilegeAddPermissionOptionalArgs{
+		Permission: &[]string{plan.Permission.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling PrivilegeAddPermission", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().PrivilegeAddPermission(args, optArgs)
+	tflog.Trace(ctx, "Called PrivilegeAddPermission", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	// Permissions which already belong to the privilege are reported as
+	// failures, treat them as success to keep the creation idempotent.
+	if err == nil {
+		err = utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add permission to privilege", "Reason: "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *PrivilegePermissionMembership) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state PrivilegePermissionMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.PrivilegeShowArgs{
+		Cn: state.Privilege.ValueString(),
+	}
+	optArgs := &freeipa.PrivilegeShowOptionalArgs{}
+
+	tflog.Trace(ctx, "Calling PrivilegeShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().PrivilegeShow(args, optArgs)
+	tflog.Trace(ctx, "Called PrivilegeShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read privilege", "Reason: "+err.Error())
+		return
+	}
+
+	if res.Result.MemberofPermission == nil || !slices.Contains(*res.Result.MemberofPermission, state.Permission.ValueString()) {
+		tflog.Debug(ctx, "Permission was removed from privilege", map[string]any{
+			"privilege":  state.Privilege.ValueString(),
+			"permission": state.Permission.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *PrivilegePermissionMembership) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan PrivilegePermissionMembershipModel
+
+	// All attributes require replacement, there is nothing to update
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *PrivilegePermissionMembership) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state PrivilegePermissionMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.PrivilegeRemovePermissionArgs{
+		Cn: state.Privilege.ValueString(),
+	}
+	optArgs := &freeipa.PrivilegeRemovePermissionOptionalArgs{
+		Permission: &[]string{state.Permission.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling PrivilegeRemovePermission", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().PrivilegeRemovePermission(args, optArgs)
+	tflog.Trace(ctx, "Called PrivilegeRemovePermission", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove permission from privilege", "Reason: "+err.Error())
+		}
+		return
+	}
+
+	// Permissions removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove permission from privilege", "Reason: "+err.Error())
+	}
+}
+
+func (r *PrivilegePermissionMembership) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	privilege, permission, ok := strings.Cut(req.ID, "/")
+
+	if !ok || privilege == "" || permission == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<privilege>/<permission>”, got %q.", req.ID),
+		)
+		return
+	}
+
+	state := PrivilegePermissionMembershipModel{
+		Privilege:  types.StringValue(privilege),
+		Permission: types.StringValue(permission),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewPrivilegePermissionMembership(p *provider.Provider) resource.Resource {
+	r := &PrivilegePermissionMembership{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewPrivilegePermissionMembership)
+}
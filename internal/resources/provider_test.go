@@ -0,0 +1,333 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeFreeIPA is a FreeIPA server accepting any login and answering the
+// JSON-RPC calls with their responses, recording their methods. The other
+// calls succeed with an empty result.
+type fakeFreeIPA struct {
+	responses map[string]func() (status int, body string)
+
+	mu      sync.Mutex
+	methods []string
+}
+
+func (f *fakeFreeIPA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/session/login_password") {
+		http.SetCookie(w, &http.Cookie{Name: "ipa_session", Value: "session", Path: "/ipa"})
+
+		return
+	}
+
+	var rpc struct {
+		Method string `json:"method"`
+	}
+
+	data, _ := io.ReadAll(r.Body)
+	_ = json.Unmarshal(data, &rpc)
+
+	f.mu.Lock()
+	f.methods = append(f.methods, rpc.Method)
+	f.mu.Unlock()
+
+	respond, ok := f.responses[rpc.Method]
+	if !ok {
+		w.Write([]byte(`{"result":{"result":{},"summary":null},"error":null,"principal":"admin@EXAMPLE.TEST","version":"4.11.1"}`))
+
+		return
+	}
+
+	status, body := respond()
+
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+// called reports whether the JSON-RPC method reached the server.
+func (f *fakeFreeIPA) called(method string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, m := range f.methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// freeipaError is the response of FreeIPA to a call failing with code.
+func freeipaError(code int, name string) (int, string) {
+	return http.StatusOK, fmt.Sprintf(`{"result":null,"error":{"code":%d,"name":"%s","message":"%s"},"principal":"admin@EXAMPLE.TEST","version":"4.11.1"}`, code, name, name)
+}
+
+// testObject returns the value of type typ holding values, the other
+// attributes being null.
+func testObject(typ tftypes.Type, values map[string]tftypes.Value) tftypes.Value {
+	object := typ.(tftypes.Object)
+
+	attrs := make(map[string]tftypes.Value, len(object.AttributeTypes))
+	for name, t := range object.AttributeTypes {
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+		} else {
+			attrs[name] = tftypes.NewValue(t, nil)
+		}
+	}
+
+	return tftypes.NewValue(object, attrs)
+}
+
+// testProviderServer returns the framework provider configured with config to
+// manage the resources on a fake FreeIPA server answering with responses.
+func testProviderServer(t *testing.T, responses map[string]func() (int, string), config map[string]tftypes.Value) (tfprotov5.ProviderServer, *fakeFreeIPA, *tfprotov5.GetProviderSchemaResponse) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	fake := &fakeFreeIPA{responses: responses}
+
+	ts := httptest.NewTLSServer(fake)
+	t.Cleanup(ts.Close)
+
+	server, err := providerserver.NewProtocol5WithError(provider.NewFactory(nil, Resources())())()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]tftypes.Value{
+		"host":             tftypes.NewValue(tftypes.String, strings.TrimPrefix(ts.URL, "https://")),
+		"username":         tftypes.NewValue(tftypes.String, "admin"),
+		"password":         tftypes.NewValue(tftypes.String, "secret"),
+		"insecure":         tftypes.NewValue(tftypes.Bool, true),
+		"kerberos_enabled": tftypes.NewValue(tftypes.Bool, false),
+		"max_retries":      tftypes.NewValue(tftypes.Number, 0),
+	}
+	for name, v := range config {
+		values[name] = v
+	}
+
+	providerType := schemas.Provider.ValueType()
+
+	dv, err := tfprotov5.NewDynamicValue(providerType, testObject(providerType, values))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &dv})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("configuring the provider: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	return server, fake, schemas
+}
+
+// testApply applies the change of the resource of type typeName from the
+// state holding prior to the planned state holding planned, a nil map being a
+// null value, and returns the new state and the diagnostics of the provider.
+func testApply(t *testing.T, server tfprotov5.ProviderServer, schemas *tfprotov5.GetProviderSchemaResponse, typeName string, prior, planned map[string]tftypes.Value) (tftypes.Value, []*tfprotov5.Diagnostic) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	typ := schemas.ResourceSchemas[typeName].ValueType()
+
+	values := make([]tfprotov5.DynamicValue, 2)
+	for i, v := range []map[string]tftypes.Value{prior, planned} {
+		value := tftypes.NewValue(typ, nil)
+		if v != nil {
+			value = testObject(typ, v)
+		}
+
+		dv, err := tfprotov5.NewDynamicValue(typ, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values[i] = dv
+	}
+
+	resp, err := server.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   &values[0],
+		PlannedState: &values[1],
+		Config:       &values[1],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var state tftypes.Value
+	if resp.NewState != nil {
+		if state, err = resp.NewState.Unmarshal(typ); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return state, resp.Diagnostics
+}
+
+// testDestroy destroys the resource of type typeName whose state holds
+// values, returning the new state and the diagnostics of the provider.
+func testDestroy(t *testing.T, server tfprotov5.ProviderServer, schemas *tfprotov5.GetProviderSchemaResponse, typeName string, values map[string]tftypes.Value) (tftypes.Value, []*tfprotov5.Diagnostic) {
+	t.Helper()
+
+	return testApply(t, server, schemas, typeName, values, nil)
+}
+
+// testPlan plans the change of the resource of type typeName from the state
+// holding prior to the configuration holding config, the computed attributes
+// missing from config being proposed with their prior value.
+func testPlan(t *testing.T, server tfprotov5.ProviderServer, schemas *tfprotov5.GetProviderSchemaResponse, typeName string, prior, config map[string]tftypes.Value) *tfprotov5.PlanResourceChangeResponse {
+	t.Helper()
+
+	ctx := context.Background()
+
+	typ := schemas.ResourceSchemas[typeName].ValueType()
+
+	proposed := map[string]tftypes.Value{}
+	for _, a := range schemas.ResourceSchemas[typeName].Block.Attributes {
+		if v, ok := config[a.Name]; ok {
+			proposed[a.Name] = v
+		} else if v, ok := prior[a.Name]; ok && a.Computed {
+			proposed[a.Name] = v
+		}
+	}
+
+	values := make([]tfprotov5.DynamicValue, 3)
+	for i, v := range []map[string]tftypes.Value{prior, config, proposed} {
+		dv, err := tfprotov5.NewDynamicValue(typ, testObject(typ, v))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values[i] = dv
+	}
+
+	resp, err := server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       &values[0],
+		Config:           &values[1],
+		ProposedNewState: &values[2],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp
+}
+
+// testValidate validates the configuration of the resource of type typeName
+// holding config and returns the diagnostics of the provider.
+func testValidate(t *testing.T, server tfprotov5.ProviderServer, typeName string, config map[string]tftypes.Value) []*tfprotov5.Diagnostic {
+	t.Helper()
+
+	ctx := context.Background()
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typ := schemas.ResourceSchemas[typeName].ValueType()
+
+	dv, err := tfprotov5.NewDynamicValue(typ, testObject(typ, config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.ValidateResourceTypeConfig(ctx, &tfprotov5.ValidateResourceTypeConfigRequest{
+		TypeName: typeName,
+		Config:   &dv,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.Diagnostics
+}
+
+// hasError reports whether diags holds an error diagnostic summarized as
+// summary.
+func hasError(diags []*tfprotov5.Diagnostic, summary string) bool {
+	for _, d := range diags {
+		if d.Severity == tfprotov5.DiagnosticSeverityError && d.Summary == summary {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestReadOnlyDestroy(t *testing.T) {
+	cases := map[string]struct {
+		method  string
+		summary string
+		values  map[string]tftypes.Value
+	}{
+		"freeipa_user": {"user_del", "Failed to delete user", map[string]tftypes.Value{
+			"uid":       tftypes.NewValue(tftypes.String, "jdoe"),
+			"givenname": tftypes.NewValue(tftypes.String, "John"),
+			"sn":        tftypes.NewValue(tftypes.String, "Doe"),
+		}},
+		"freeipa_group": {"group_del", "Failed to delete group", map[string]tftypes.Value{
+			"cn": tftypes.NewValue(tftypes.String, "developers"),
+		}},
+		"freeipa_host": {"host_del", "Failed to delete host", map[string]tftypes.Value{
+			"fqdn": tftypes.NewValue(tftypes.String, "web.example.test"),
+		}},
+	}
+
+	for typeName, c := range cases {
+		t.Run(typeName, func(t *testing.T) {
+			server, fake, schemas := testProviderServer(t, nil, map[string]tftypes.Value{
+				"read_only": tftypes.NewValue(tftypes.Bool, true),
+			})
+
+			state, diags := testDestroy(t, server, schemas, typeName, c.values)
+
+			if fake.called(c.method) {
+				t.Errorf("%s reached the server", c.method)
+			}
+
+			// The refused deletion must not be mistaken for an entry already gone
+			if !hasError(diags, c.summary) || state.IsNull() {
+				t.Errorf("got diagnostics %v and state %s, want an error and the resource kept", diags, state)
+			}
+
+			for _, d := range diags {
+				if d.Summary == c.summary && !strings.Contains(d.Detail, utils.ErrReadOnly.Error()) {
+					t.Errorf("got detail %q, want the read-only refusal", d.Detail)
+				}
+			}
+		})
+	}
+}
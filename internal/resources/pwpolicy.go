@@ -0,0 +1,383 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// globalPwpolicyName is the name of the password policy applying to users
+// without a group policy. It always exists and cannot be deleted.
+const globalPwpolicyName = "global_policy"
+
+type Pwpolicy struct {
+	provider *provider.Provider
+}
+
+type PwpolicyModel struct {
+	Name               types.String `tfsdk:"cn"`
+	MaxPwdLife         types.Int64  `tfsdk:"krbmaxpwdlife"`
+	MinPwdLife         types.Int64  `tfsdk:"krbminpwdlife"`
+	PwdHistoryLength   types.Int64  `tfsdk:"krbpwdhistorylength"`
+	PwdMinDiffChars    types.Int64  `tfsdk:"krbpwdmindiffchars"`
+	PwdMinLength       types.Int64  `tfsdk:"krbpwdminlength"`
+	PwdMaxFailure      types.Int64  `tfsdk:"krbpwdmaxfailure"`
+	PwdLockoutDuration types.Int64  `tfsdk:"krbpwdlockoutduration"`
+	CosPriority        types.Int64  `tfsdk:"cospriority"`
+}
+
+func (r *Pwpolicy) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pwpolicy"
+}
+
+func pwpolicyInt64Attribute(description string) schema.Int64Attribute {
+	return schema.Int64Attribute{
+		Description: description,
+		Optional:    true,
+		Computed:    true,
+		PlanModifiers: []planmodifier.Int64{
+			int64planmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+func (r *Pwpolicy) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA password policy.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Group the policy applies to, or `global_policy` for the global policy",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"krbmaxpwdlife":         pwpolicyInt64Attribute("Maximum password lifetime (in days)"),
+			"krbminpwdlife":         pwpolicyInt64Attribute("Minimum password lifetime (in hours)"),
+			"krbpwdhistorylength":   pwpolicyInt64Attribute("Password history size"),
+			"krbpwdmindiffchars":    pwpolicyInt64Attribute("Minimum number of character classes"),
+			"krbpwdminlength":       pwpolicyInt64Attribute("Minimum length of password"),
+			"krbpwdmaxfailure":      pwpolicyInt64Attribute("Consecutive failures before lockout"),
+			"krbpwdlockoutduration": pwpolicyInt64Attribute("Period for which lockout is enforced (in seconds)"),
+			"cospriority": schema.Int64Attribute{
+				Description: "Priority of the policy, lower values take precedence. Required for group policies, not allowed for the global policy",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *Pwpolicy) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config PwpolicyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() || config.Name.IsUnknown() {
+		return
+	}
+
+	if config.Name.ValueString() == globalPwpolicyName {
+		if !config.CosPriority.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("cospriority"),
+				"Invalid configuration",
+				`The global password policy has no priority, cospriority must not be set.`,
+			)
+		}
+	} else if config.CosPriority.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("cospriority"),
+			"Missing priority",
+			`cospriority is required for group password policies.`,
+		)
+	}
+}
+
+func (r *Pwpolicy) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan PwpolicyModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The global policy always exists, it is only updated
+	if plan.Name.ValueString() == globalPwpolicyName {
+		res, err := r.pwpolicyMod(ctx, plan, PwpolicyModel{})
+		if err == nil && res == nil {
+			res, err = r.pwpolicyShow(ctx, globalPwpolicyName)
+		}
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update global password policy", "Reason: "+err.Error())
+
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, pwpolicyState(plan.Name, res))...)
+
+		return
+	}
+
+	args := &freeipa.PwpolicyAddArgs{
+		Cn:          plan.Name.ValueString(),
+		Cospriority: int(plan.CosPriority.ValueInt64()),
+	}
+
+	optArgs := &freeipa.PwpolicyAddOptionalArgs{
+		Krbmaxpwdlife:         int64ToIntPointer(plan.MaxPwdLife),
+		Krbminpwdlife:         int64ToIntPointer(plan.MinPwdLife),
+		Krbpwdhistorylength:   int64ToIntPointer(plan.PwdHistoryLength),
+		Krbpwdmindiffchars:    int64ToIntPointer(plan.PwdMinDiffChars),
+		Krbpwdminlength:       int64ToIntPointer(plan.PwdMinLength),
+		Krbpwdmaxfailure:      int64ToIntPointer(plan.PwdMaxFailure),
+		Krbpwdlockoutduration: int64ToIntPointer(plan.PwdLockoutDuration),
+	}
+
+	tflog.Trace(ctx, "Calling PwpolicyAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().PwpolicyAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called PwpolicyAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create password policy", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, pwpolicyState(plan.Name, &res.Result))...)
+}
+
+func (r *Pwpolicy) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state PwpolicyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	res, err := r.pwpolicyShow(ctx, state.Name.ValueString())
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read password policy", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, pwpolicyState(state.Name, res))...)
+}
+
+func (r *Pwpolicy) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan PwpolicyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	res, err := r.pwpolicyMod(ctx, plan, state)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update password policy", "Reason: "+err.Error())
+
+		return
+	}
+
+	if res == nil {
+		tflog.Debug(ctx, "Updated password policy has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, pwpolicyState(plan.Name, res))...)
+}
+
+func (r *Pwpolicy) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state PwpolicyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Name.ValueString() == globalPwpolicyName {
+		resp.Diagnostics.AddWarning(
+			"Global password policy not deleted",
+			"The global password policy cannot be deleted, it was only removed from the Terraform state and keeps its current settings.",
+		)
+
+		return
+	}
+
+	args := &freeipa.PwpolicyDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling PwpolicyDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().PwpolicyDel(args, nil)
+
+	tflog.Trace(ctx, "Called PwpolicyDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete password policy", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Pwpolicy) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("cn"), req, resp)
+}
+
+// pwpolicyMod sends the attributes of plan which differ from state, and
+// returns a nil result when there is nothing to update.
+func (r *Pwpolicy) pwpolicyMod(ctx context.Context, plan, state PwpolicyModel) (*freeipa.Pwpolicy, error) {
+	var hasDiff bool
+
+	args := &freeipa.PwpolicyModArgs{}
+	optArgs := &freeipa.PwpolicyModOptionalArgs{}
+
+	intChanges := []struct {
+		plan, state types.Int64
+		arg         **int
+	}{
+		{plan.MaxPwdLife, state.MaxPwdLife, &optArgs.Krbmaxpwdlife},
+		{plan.MinPwdLife, state.MinPwdLife, &optArgs.Krbminpwdlife},
+		{plan.PwdHistoryLength, state.PwdHistoryLength, &optArgs.Krbpwdhistorylength},
+		{plan.PwdMinDiffChars, state.PwdMinDiffChars, &optArgs.Krbpwdmindiffchars},
+		{plan.PwdMinLength, state.PwdMinLength, &optArgs.Krbpwdminlength},
+		{plan.PwdMaxFailure, state.PwdMaxFailure, &optArgs.Krbpwdmaxfailure},
+		{plan.PwdLockoutDuration, state.PwdLockoutDuration, &optArgs.Krbpwdlockoutduration},
+		{plan.CosPriority, state.CosPriority, &optArgs.Cospriority},
+	}
+
+	for _, c := range intChanges {
+		if !c.plan.IsNull() && !c.plan.IsUnknown() && !c.plan.Equal(c.state) {
+			*c.arg = int64ToIntPointer(c.plan)
+			hasDiff = true
+		}
+	}
+
+	if !hasDiff {
+		return nil, nil
+	}
+
+	tflog.Trace(ctx, "Calling PwpolicyMod", map[string]any{
+		"cn":       plan.Name.ValueString(),
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().PwpolicyMod(plan.Name.ValueString(), args, optArgs)
+
+	tflog.Trace(ctx, "Called PwpolicyMod", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Result, nil
+}
+
+func (r *Pwpolicy) pwpolicyShow(ctx context.Context, name string) (*freeipa.Pwpolicy, error) {
+	args := &freeipa.PwpolicyShowArgs{}
+
+	tflog.Trace(ctx, "Calling PwpolicyShow", map[string]any{
+		"cn":       name,
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().PwpolicyShow(name, args, nil)
+
+	tflog.Trace(ctx, "Called PwpolicyShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Result, nil
+}
+
+func pwpolicyState(name types.String, policy *freeipa.Pwpolicy) PwpolicyModel {
+	state := PwpolicyModel{
+		Name:               name,
+		MaxPwdLife:         intToInt64Value(policy.Krbmaxpwdlife),
+		MinPwdLife:         intToInt64Value(policy.Krbminpwdlife),
+		PwdHistoryLength:   intToInt64Value(policy.Krbpwdhistorylength),
+		PwdMinDiffChars:    intToInt64Value(policy.Krbpwdmindiffchars),
+		PwdMinLength:       intToInt64Value(policy.Krbpwdminlength),
+		PwdMaxFailure:      intToInt64Value(policy.Krbpwdmaxfailure),
+		PwdLockoutDuration: intToInt64Value(policy.Krbpwdlockoutduration),
+		CosPriority:        types.Int64Null(),
+	}
+
+	if name.ValueString() != globalPwpolicyName {
+		state.CosPriority = types.Int64Value(int64(policy.Cospriority))
+	}
+
+	return state
+}
+
+func NewPwpolicy(p *provider.Provider) resource.Resource {
+	r := &Pwpolicy{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewPwpolicy)
+}
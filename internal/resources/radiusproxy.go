@@ -0,0 +1,352 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Radiusproxy struct {
+	provider *provider.Provider
+}
+
+type RadiusproxyModel struct {
+	Name             types.String `tfsdk:"cn"`
+	Description      types.String `tfsdk:"description"`
+	Server           types.String `tfsdk:"ipatokenradiusserver"`
+	Secret           types.String `tfsdk:"ipatokenradiussecret"`
+	Timeout          types.Int64  `tfsdk:"ipatokenradiustimeout"`
+	Retries          types.Int64  `tfsdk:"ipatokenradiusretries"`
+	UserMapAttribute types.String `tfsdk:"ipatokenusermapattribute"`
+}
+
+func (r *Radiusproxy) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_radiusproxy"
+}
+
+func (r *Radiusproxy) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA RADIUS proxy server, which users with the `radius` authentication type are authenticated against.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "RADIUS proxy server name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "RADIUS proxy server description",
+				Optional:    true,
+			},
+			"ipatokenradiusserver": schema.StringAttribute{
+				Description: "Address of the RADIUS server, as `host` or `host:port`",
+				Required:    true,
+			},
+			"ipatokenradiussecret": schema.StringAttribute{
+				Description: "Shared secret of the RADIUS server. FreeIPA never returns it, it is only sent when created or changed in the configuration",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"ipatokenradiustimeout": schema.Int64Attribute{
+				Description: "Time to wait for an answer of the RADIUS server (in seconds). Defaults to 5",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipatokenradiusretries": schema.Int64Attribute{
+				Description: "Number of times a request is sent again to the RADIUS server (0 to 10). Defaults to 3",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipatokenusermapattribute": schema.StringAttribute{
+				Description: "User attribute sent as the RADIUS user name, when the user has no RADIUS user name",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *Radiusproxy) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config RadiusproxyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Timeout.IsUnknown() && !config.Timeout.IsNull() && config.Timeout.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipatokenradiustimeout"),
+			"Invalid configuration",
+			`“ipatokenradiustimeout” must be at least 1.`,
+		)
+	}
+
+	if !config.Retries.IsUnknown() && !config.Retries.IsNull() &&
+		(config.Retries.ValueInt64() < 0 || config.Retries.ValueInt64() > 10) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipatokenradiusretries"),
+			"Invalid configuration",
+			`“ipatokenradiusretries” must be between 0 and 10.`,
+		)
+	}
+}
+
+func (r *Radiusproxy) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RadiusproxyModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.RadiusproxyAddArgs{
+		Cn:                   plan.Name.ValueString(),
+		Ipatokenradiusserver: plan.Server.ValueString(),
+		Ipatokenradiussecret: plan.Secret.ValueString(),
+	}
+
+	optArgs := &freeipa.RadiusproxyAddOptionalArgs{
+		Description:              plan.Description.ValueStringPointer(),
+		Ipatokenradiustimeout:    int64ToIntPointer(plan.Timeout),
+		Ipatokenradiusretries:    int64ToIntPointer(plan.Retries),
+		Ipatokenusermapattribute: plan.UserMapAttribute.ValueStringPointer(),
+	}
+
+	// The args hold the secret, only the names are logged
+	tflog.Trace(ctx, "Calling RadiusproxyAdd", map[string]any{
+		"cn":                   args.Cn,
+		"ipatokenradiusserver": args.Ipatokenradiusserver,
+		"opt_args":             optArgs,
+	})
+
+	res, err := r.provider.Client().RadiusproxyAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called RadiusproxyAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create RADIUS proxy server", "Reason: "+err.Error())
+
+		return
+	}
+
+	state := plan
+	radiusproxySetComputed(&state, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Radiusproxy) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RadiusproxyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.RadiusproxyShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling RadiusproxyShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().RadiusproxyShow(args, nil)
+
+	tflog.Trace(ctx, "Called RadiusproxyShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read RADIUS proxy server", "Reason: "+err.Error())
+
+		return
+	}
+
+	// The secret is kept as configured, it cannot be compared with FreeIPA
+	state.Description = types.StringPointerValue(res.Result.Description)
+	state.Server = types.StringValue(res.Result.Ipatokenradiusserver)
+	state.UserMapAttribute = types.StringPointerValue(res.Result.Ipatokenusermapattribute)
+	radiusproxySetComputed(&state, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Radiusproxy) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan RadiusproxyModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.RadiusproxyModArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.RadiusproxyModOptionalArgs{}
+
+	if !plan.Description.Equal(state.Description) {
+		optArgs.Description = freeipa.String(plan.Description.ValueString())
+		hasDiff = true
+	}
+
+	if !plan.Server.Equal(state.Server) {
+		optArgs.Ipatokenradiusserver = plan.Server.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if !plan.Secret.Equal(state.Secret) {
+		optArgs.Ipatokenradiussecret = plan.Secret.ValueStringPointer()
+		hasDiff = true
+	}
+
+	if !plan.Timeout.IsUnknown() && !plan.Timeout.Equal(state.Timeout) {
+		optArgs.Ipatokenradiustimeout = int64ToIntPointer(plan.Timeout)
+		hasDiff = true
+	}
+
+	if !plan.Retries.IsUnknown() && !plan.Retries.Equal(state.Retries) {
+		optArgs.Ipatokenradiusretries = int64ToIntPointer(plan.Retries)
+		hasDiff = true
+	}
+
+	if !plan.UserMapAttribute.Equal(state.UserMapAttribute) {
+		optArgs.Ipatokenusermapattribute = freeipa.String(plan.UserMapAttribute.ValueString())
+		hasDiff = true
+	}
+
+	state = plan
+
+	if hasDiff {
+		// The secret is not logged
+		logOptArgs := *optArgs
+		logOptArgs.Ipatokenradiussecret = nil
+
+		tflog.Trace(ctx, "Calling RadiusproxyMod", map[string]any{
+			"args":     args,
+			"opt_args": &logOptArgs,
+		})
+
+		res, err := r.provider.Client().RadiusproxyMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called RadiusproxyMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update RADIUS proxy server", "Reason: "+err.Error())
+
+			return
+		}
+
+		radiusproxySetComputed(&state, &res.Result)
+	} else {
+		tflog.Debug(ctx, "Updated RADIUS proxy server has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Radiusproxy) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state RadiusproxyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.RadiusproxyDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling RadiusproxyDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().RadiusproxyDel(args, nil)
+
+	tflog.Trace(ctx, "Called RadiusproxyDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete RADIUS proxy server", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Radiusproxy) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The secret is unknown to the imported state, the next apply sends it
+	state := RadiusproxyModel{
+		Name: types.StringValue(req.ID),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func radiusproxySetComputed(state *RadiusproxyModel, proxy *freeipa.Radiusproxy) {
+	state.Timeout = intToInt64Value(proxy.Ipatokenradiustimeout)
+	state.Retries = intToInt64Value(proxy.Ipatokenradiusretries)
+}
+
+func NewRadiusproxy(p *provider.Provider) resource.Resource {
+	r := &Radiusproxy{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewRadiusproxy)
+}
@@ -2,9 +2,14 @@ package resources
 
 import (
 	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 )
 
+// singletonID is the import ID of the resources managing a global FreeIPA
+// configuration, which always exists and cannot be created.
+const singletonID = "global"
+
 var (
 	resources []func(p *provider.Provider) resource.Resource
 )
@@ -12,3 +17,25 @@ var (
 func Resources() []func(p *provider.Provider) resource.Resource {
 	return resources
 }
+
+// requireSingletonImport fails the creation of a resource managing the global
+// configuration described by what, which must be imported with the given ID
+// instead.
+func requireSingletonImport(diags *diag.Diagnostics, title, what, id string) {
+	diags.AddError(
+		title+" must be imported",
+		"The "+what+" always exists and cannot be created. Import it with `terraform import` or an `import` block, using the “"+id+"” ID, before managing it.",
+	)
+}
+
+// validSingletonID reports whether id imports a global configuration, adding
+// an error otherwise.
+func validSingletonID(diags *diag.Diagnostics, id string) bool {
+	if id != singletonID {
+		diags.AddError("Invalid ID format", "Expected ID is “"+singletonID+"”")
+
+		return false
+	}
+
+	return true
+}
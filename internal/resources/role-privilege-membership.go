@@ -0,0 +1,224 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+type RolePrivilegeMembership struct {
+	provider *provider.Provider
+}
+
+type RolePrivilegeMembershipModel struct {
+	Role      types.String `tfsdk:"role"`
+	Privilege types.String `tfsdk:"privilege"`
+}
+
+func (r *RolePrivilegeMembership) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role_privilege_membership"
+}
+
+func (r *RolePrivilegeMembership) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Adds a privilege to a FreeIPA RBAC role.",
+		Attributes: map[string]schema.Attribute{
+			"role": schema.StringAttribute{
+				Description: "Role name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"privilege": schema.StringAttribute{
+				Description: "Privilege added to the role",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *RolePrivilegeMembership) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RolePrivilegeMembershipModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.RoleAddPrivilegeArgs{
+		Cn: plan.Role.ValueString(),
+	}
+	optArgs := &freeipa.RoleAddPrivilegeOptionalArgs{
+		Privilege: &[]string{plan.Privilege.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling RoleAddPrivilege", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().RoleAddPrivilege(args, optArgs)
+	tflog.Trace(ctx, "Called RoleAddPrivilege", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	// Privileges which already belong to the role are reported as failures,
+	// treat them as success to keep the creation idempotent.
+	if err == nil {
+		err = utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add privilege to role", "Reason: "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *RolePrivilegeMembership) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RolePrivilegeMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.RoleShowArgs{
+		Cn: state.Role.ValueString(),
+	}
+	optArgs := &freeipa.RoleShowOptionalArgs{}
+
+	tflog.Trace(ctx, "Calling RoleShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().RoleShow(args, optArgs)
+	tflog.Trace(ctx, "Called RoleShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read role", "Reason: "+err.Error())
+		return
+	}
+
+	if res.Result.MemberofPrivilege == nil || !slices.Contains(*res.Result.MemberofPrivilege, state.Privilege.ValueString()) {
+		tflog.Debug(ctx, "Privilege was removed from role", map[string]any{
+			"role":      state.Role.ValueString(),
+			"privilege": state.Privilege.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *RolePrivilegeMembership) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan RolePrivilegeMembershipModel
+
+	// All attributes require replacement, there is nothing to update
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *RolePrivilegeMembership) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state RolePrivilegeMembershipModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.RoleRemovePrivilegeArgs{
+		Cn: state.Role.ValueString(),
+	}
+	optArgs := &freeipa.RoleRemovePrivilegeOptionalArgs{
+		Privilege: &[]string{state.Privilege.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling RoleRemovePrivilege", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().RoleRemovePrivilege(args, optArgs)
+	tflog.Trace(ctx, "Called RoleRemovePrivilege", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove privilege from role", "Reason: "+err.Error())
+		}
+		return
+	}
+
+	// Privileges removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove privilege from role", "Reason: "+err.Error())
+	}
+}
+
+func (r *RolePrivilegeMembership) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	role, privilege, ok := strings.Cut(req.ID, "/")
+
+	if !ok || role == "" || privilege == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<role>/<privilege>”, got %q.", req.ID),
+		)
+		return
+	}
+
+	state := RolePrivilegeMembershipModel{
+		Role:      types.StringValue(role),
+		Privilege: types.StringValue(privilege),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewRolePrivilegeMembership(p *provider.Provider) resource.Resource {
+	r := &RolePrivilegeMembership{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewRolePrivilegeMembership)
+}
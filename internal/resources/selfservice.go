@@ -0,0 +1,332 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+var selfservicePermissions = []string{"read", "write"}
+
+type Selfservice struct {
+	provider *provider.Provider
+}
+
+type SelfserviceModel struct {
+	Name        types.String `tfsdk:"aciname"`
+	Attrs       types.Set    `tfsdk:"attrs"`
+	Permissions types.Set    `tfsdk:"permissions"`
+}
+
+func (r *Selfservice) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_selfservice"
+}
+
+func (r *Selfservice) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA self-service permission, allowing users to edit attributes of their own entry.",
+		Attributes: map[string]schema.Attribute{
+			"aciname": schema.StringAttribute{
+				Description: "Self-service permission name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"attrs": schema.SetAttribute{
+				Description: "Attributes users are allowed to edit",
+				ElementType: types.StringType,
+				Required:    true,
+			},
+			"permissions": schema.SetAttribute{
+				Description: "Permissions granted on the attributes, any of " + strings.Join(selfservicePermissions, ", ") + " (defaults to `write`)",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Selfservice) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config SelfserviceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Attrs.IsUnknown() && !config.Attrs.IsNull() && len(config.Attrs.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("attrs"),
+			"Invalid configuration",
+			`“attrs” must hold at least one attribute.`,
+		)
+	}
+
+	if !config.Permissions.IsUnknown() && !config.Permissions.IsNull() {
+		var permissions []string
+
+		resp.Diagnostics.Append(config.Permissions.ElementsAs(ctx, &permissions, false)...)
+
+		for _, permission := range permissions {
+			if !slices.Contains(selfservicePermissions, permission) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("permissions"),
+					"Invalid configuration",
+					fmt.Sprintf("Unsupported permission “%s”, expected any of %s.", permission, strings.Join(selfservicePermissions, ", ")),
+				)
+			}
+		}
+	}
+}
+
+func (r *Selfservice) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SelfserviceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	attrs := []string{}
+
+	resp.Diagnostics.Append(plan.Attrs.ElementsAs(ctx, &attrs, false)...)
+
+	args := &freeipa.SelfserviceAddArgs{
+		Aciname: plan.Name.ValueString(),
+		Attrs:   attrs,
+	}
+
+	optArgs := &freeipa.SelfserviceAddOptionalArgs{
+		Permissions: setToStringSlicePointer(ctx, plan.Permissions, &resp.Diagnostics),
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Calling SelfserviceAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().SelfserviceAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called SelfserviceAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create self-service permission", "Reason: "+err.Error())
+
+		return
+	}
+
+	plan.Permissions = stringSliceToSet(ctx, res.Result.Permissions, false, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Selfservice) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SelfserviceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.SelfserviceShowArgs{
+		Aciname: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling SelfserviceShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().SelfserviceShow(args, nil)
+
+	tflog.Trace(ctx, "Called SelfserviceShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read self-service permission", "Reason: "+err.Error())
+
+		return
+	}
+
+	// FreeIPA lower-cases attribute names, keep the configured spelling when
+	// only the case differs.
+	attrs := &res.Result.Attrs
+	if !state.Attrs.IsNull() {
+		var current []string
+
+		resp.Diagnostics.Append(state.Attrs.ElementsAs(ctx, &current, false)...)
+
+		attrs = preserveCaseInsensitive(current, *attrs)
+	}
+
+	state.Attrs = stringSliceToSet(ctx, attrs, false, &resp.Diagnostics)
+	state.Permissions = stringSliceToSet(ctx, res.Result.Permissions, false, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Selfservice) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan SelfserviceModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.SelfserviceModArgs{
+		Aciname: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.SelfserviceModOptionalArgs{}
+
+	if !plan.Attrs.Equal(state.Attrs) {
+		optArgs.Attrs = setToStringSlicePointer(ctx, plan.Attrs, &resp.Diagnostics)
+		hasDiff = true
+	}
+
+	if !plan.Permissions.IsUnknown() && !plan.Permissions.Equal(state.Permissions) {
+		optArgs.Permissions = setToStringSlicePointer(ctx, plan.Permissions, &resp.Diagnostics)
+		hasDiff = true
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling SelfserviceMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().SelfserviceMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called SelfserviceMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update self-service permission", "Reason: "+err.Error())
+
+			return
+		}
+
+		plan.Permissions = stringSliceToSet(ctx, res.Result.Permissions, false, &resp.Diagnostics)
+	} else {
+		tflog.Debug(ctx, "Updated self-service permission has no effective difference", map[string]any{
+			"aciname": plan.Name.ValueString(),
+		})
+	}
+
+	if plan.Permissions.IsUnknown() {
+		plan.Permissions = state.Permissions
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Selfservice) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state SelfserviceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.SelfserviceDelArgs{
+		Aciname: state.Name.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling SelfserviceDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().SelfserviceDel(args, nil)
+
+	tflog.Trace(ctx, "Called SelfserviceDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete self-service permission", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Selfservice) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := SelfserviceModel{
+		Name:        types.StringValue(req.ID),
+		Attrs:       types.SetNull(types.StringType),
+		Permissions: types.SetNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewSelfservice(p *provider.Provider) resource.Resource {
+	r := &Selfservice{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewSelfservice)
+}
@@ -0,0 +1,313 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type ServerLocation struct {
+	provider *provider.Provider
+}
+
+type ServerLocationModel struct {
+	Server   types.String `tfsdk:"cn"`
+	Location types.String `tfsdk:"ipalocation_location"`
+	Weight   types.Int64  `tfsdk:"ipaserviceweight"`
+}
+
+func (r *ServerLocation) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_location"
+}
+
+func (r *ServerLocation) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Assigns a FreeIPA server to a location. Destroying the resource removes the server from its location and keeps its weight.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Server hostname",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipalocation_location": schema.StringAttribute{
+				Description: "Location of the server, see `freeipa_location`",
+				Required:    true,
+			},
+			"ipaserviceweight": schema.Int64Attribute{
+				Description: "Weight of the server in the SRV records of its location. Defaults to 100",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ServerLocation) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config ServerLocationModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Weight.IsUnknown() && !config.Weight.IsNull() && (config.Weight.ValueInt64() < 0 || config.Weight.ValueInt64() > 65535) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipaserviceweight"),
+			"Invalid configuration",
+			`“ipaserviceweight” must be between 0 and 65535.`,
+		)
+	}
+}
+
+func (r *ServerLocation) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ServerLocationModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var location interface{} = plan.Location.ValueString()
+
+	args := &freeipa.ServerModArgs{
+		Cn: plan.Server.ValueString(),
+	}
+
+	optArgs := &freeipa.ServerModOptionalArgs{
+		IpalocationLocation: &location,
+		Ipaserviceweight:    int64ToIntPointer(plan.Weight),
+	}
+
+	if err := r.serverMod(ctx, args, optArgs); err != nil {
+		resp.Diagnostics.AddError("Failed to set server location", "Reason: "+err.Error())
+
+		return
+	}
+
+	if err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to read server location", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *ServerLocation) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ServerLocationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.read(ctx, &state); err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read server location", "Reason: "+err.Error())
+
+		return
+	}
+
+	if state.Location.IsNull() {
+		tflog.Debug(ctx, "Server no longer has a location", map[string]any{
+			"cn": state.Server.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *ServerLocation) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan ServerLocationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.ServerModArgs{
+		Cn: plan.Server.ValueString(),
+	}
+
+	optArgs := &freeipa.ServerModOptionalArgs{}
+	hasDiff := false
+
+	if !strings.EqualFold(plan.Location.ValueString(), state.Location.ValueString()) {
+		var location interface{} = plan.Location.ValueString()
+
+		optArgs.IpalocationLocation = &location
+		hasDiff = true
+	}
+
+	if !plan.Weight.IsUnknown() && !plan.Weight.Equal(state.Weight) {
+		optArgs.Ipaserviceweight = int64ToIntPointer(plan.Weight)
+		hasDiff = true
+	}
+
+	if hasDiff {
+		if err := r.serverMod(ctx, args, optArgs); err != nil {
+			resp.Diagnostics.AddError("Failed to update server location", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated server location has no effective difference", map[string]any{
+			"cn": plan.Server.ValueString(),
+		})
+	}
+
+	if err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to read server location", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *ServerLocation) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ServerLocationModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An empty location removes the server from its location
+	var location interface{} = ""
+
+	args := &freeipa.ServerModArgs{
+		Cn: state.Server.ValueString(),
+	}
+
+	optArgs := &freeipa.ServerModOptionalArgs{
+		IpalocationLocation: &location,
+	}
+
+	// A server without location leaves nothing to modify
+	if err := r.serverMod(ctx, args, optArgs); err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || (freeipaErr.Code != freeipa.NotFoundCode && freeipaErr.Code != utils.EmptyModlistCode) {
+			resp.Diagnostics.AddError("Failed to remove server location", "Reason: "+err.Error())
+		}
+	}
+}
+
+func (r *ServerLocation) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := ServerLocationModel{
+		Server:   types.StringValue(req.ID),
+		Location: types.StringNull(),
+		Weight:   types.Int64Null(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// read refreshes the location and the weight of the server, the location is
+// null when the server has none.
+func (r *ServerLocation) read(ctx context.Context, m *ServerLocationModel) error {
+	args := &freeipa.ServerShowArgs{
+		Cn: m.Server.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling ServerShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().ServerShow(args, nil)
+
+	tflog.Trace(ctx, "Called ServerShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	configured := m.Location
+
+	m.Location = types.StringNull()
+	if res.Result.IpalocationLocation != nil {
+		location := utils.DnsNameValue(*res.Result.IpalocationLocation)
+
+		// Keep the configured case of the location name
+		if strings.EqualFold(location, configured.ValueString()) {
+			m.Location = configured
+		} else if location != "" {
+			m.Location = types.StringValue(location)
+		}
+	}
+
+	m.Weight = types.Int64Value(defaultServiceWeight)
+	if res.Result.Ipaserviceweight != nil {
+		m.Weight = types.Int64Value(int64(*res.Result.Ipaserviceweight))
+	}
+
+	return nil
+}
+
+func (r *ServerLocation) serverMod(ctx context.Context, args *freeipa.ServerModArgs, optArgs *freeipa.ServerModOptionalArgs) error {
+	tflog.Trace(ctx, "Calling ServerMod", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().ServerMod(args, optArgs)
+
+	tflog.Trace(ctx, "Called ServerMod", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	return err
+}
+
+func NewServerLocation(p *provider.Provider) resource.Resource {
+	r := &ServerLocation{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewServerLocation)
+}
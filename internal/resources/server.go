@@ -0,0 +1,408 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Server struct {
+	provider *provider.Provider
+}
+
+type ServerModel struct {
+	Name     types.String `tfsdk:"cn"`
+	Location types.String `tfsdk:"ipalocation_location"`
+	Weight   types.Int64  `tfsdk:"ipaserviceweight"`
+	Hidden   types.Bool   `tfsdk:"hidden"`
+	Roles    types.Set    `tfsdk:"enabled_role_servrole"`
+}
+
+func (r *Server) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server"
+}
+
+func (r *Server) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages the configuration of an existing FreeIPA server. The server is neither created nor deleted, unset attributes keep their current value.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Server hostname",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipalocation_location": schema.StringAttribute{
+				Description: "Location of the server, see `freeipa_location`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipaserviceweight": schema.Int64Attribute{
+				Description: "Weight of the server in the SRV records of its location",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"hidden": schema.BoolAttribute{
+				Description: "Whether the server is a hidden replica, which is not advertised to clients in DNS",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"enabled_role_servrole": schema.SetAttribute{
+				Description: "Roles enabled on the server",
+				ElementType: types.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Server) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config ServerModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Weight.IsUnknown() && !config.Weight.IsNull() && (config.Weight.ValueInt64() < 0 || config.Weight.ValueInt64() > 65535) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipaserviceweight"),
+			"Invalid configuration",
+			`“ipaserviceweight” must be between 0 and 65535.`,
+		)
+	}
+}
+
+func (r *Server) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ServerModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Servers are only added by installing replicas, the existing entry is
+	// updated from its current value
+	current := ServerModel{
+		Name:     plan.Name,
+		Location: types.StringNull(),
+	}
+
+	if err := r.read(ctx, &current); err != nil {
+		if utils.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(
+				"Server not found",
+				fmt.Sprintf("No server named %q exists. The freeipa_server resource only manages the servers installed as replicas.", plan.Name.ValueString()),
+			)
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read server", "Reason: "+err.Error())
+
+		return
+	}
+
+	state, diags := r.apply(ctx, plan, current)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Server) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ServerModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.read(ctx, &state); err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read server", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Server) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan ServerModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, diags := r.apply(ctx, plan, state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Server) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.AddWarning(
+		"Server not deleted",
+		"FreeIPA servers are only removed by uninstalling them, the server was only removed from the Terraform state and keeps its current settings.",
+	)
+}
+
+func (r *Server) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := ServerModel{
+		Name:     types.StringValue(req.ID),
+		Location: types.StringNull(),
+		Weight:   types.Int64Null(),
+		Hidden:   types.BoolNull(),
+		Roles:    types.SetNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// apply sends the attributes of plan which differ from state and returns the
+// resulting state.
+func (r *Server) apply(ctx context.Context, plan, state ServerModel) (ServerModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	args := &freeipa.ServerModArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.ServerModOptionalArgs{}
+	hasDiff := false
+
+	if !plan.Location.IsUnknown() && !plan.Location.IsNull() && !strings.EqualFold(plan.Location.ValueString(), state.Location.ValueString()) {
+		var location interface{} = plan.Location.ValueString()
+
+		optArgs.IpalocationLocation = &location
+		hasDiff = true
+	}
+
+	if !plan.Weight.IsUnknown() && !plan.Weight.IsNull() && !plan.Weight.Equal(state.Weight) {
+		optArgs.Ipaserviceweight = int64ToIntPointer(plan.Weight)
+		hasDiff = true
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling ServerMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().ServerMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called ServerMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			diags.AddError("Failed to update server", "Reason: "+err.Error())
+
+			return state, diags
+		}
+	}
+
+	// Hiding a replica is not part of server-mod, it is toggled on its own
+	if !plan.Hidden.IsUnknown() && !plan.Hidden.IsNull() && !plan.Hidden.Equal(state.Hidden) {
+		stateArgs := &freeipa.ServerStateArgs{
+			Cn:    plan.Name.ValueString(),
+			State: "enabled",
+		}
+
+		if plan.Hidden.ValueBool() {
+			stateArgs.State = "hidden"
+		}
+
+		tflog.Trace(ctx, "Calling ServerState", map[string]any{
+			"args":     stateArgs,
+			"opt_args": nil,
+		})
+
+		res, err := r.provider.Client().ServerState(stateArgs, nil)
+
+		tflog.Trace(ctx, "Called ServerState", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			diags.AddError("Failed to update server state", "Reason: "+err.Error())
+
+			return state, diags
+		}
+
+		hasDiff = true
+	}
+
+	if !hasDiff {
+		tflog.Debug(ctx, "Updated server has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	result := plan
+
+	if err := r.read(ctx, &result); err != nil {
+		diags.AddError("Failed to read updated server", "Reason: "+err.Error())
+	}
+
+	return result, diags
+}
+
+// read refreshes the settings of the server, keeping the configured case of
+// its location.
+func (r *Server) read(ctx context.Context, m *ServerModel) error {
+	args := &freeipa.ServerShowArgs{
+		Cn: m.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.ServerShowOptionalArgs{
+		All: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling ServerShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().ServerShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called ServerShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	server := res.Result
+
+	location := ""
+	if server.IpalocationLocation != nil {
+		location = utils.DnsNameValue(*server.IpalocationLocation)
+	}
+
+	switch {
+	case location == "":
+		m.Location = types.StringNull()
+	case !strings.EqualFold(location, m.Location.ValueString()):
+		m.Location = types.StringValue(location)
+	}
+
+	m.Weight = types.Int64Value(defaultServiceWeight)
+	if server.Ipaserviceweight != nil {
+		m.Weight = types.Int64Value(int64(*server.Ipaserviceweight))
+	}
+
+	roles := []attr.Value{}
+	if server.EnabledRoleServrole != nil {
+		for _, role := range *server.EnabledRoleServrole {
+			roles = append(roles, types.StringValue(role))
+		}
+	}
+
+	m.Roles = types.SetValueMust(types.StringType, roles)
+
+	hidden, err := r.hidden(ctx, m.Name.ValueString())
+	if err != nil {
+		return err
+	}
+
+	m.Hidden = types.BoolValue(hidden)
+
+	return nil
+}
+
+// hidden reports whether the server has hidden roles, which FreeIPA only
+// exposes through the server roles.
+func (r *Server) hidden(ctx context.Context, name string) (bool, error) {
+	args := &freeipa.ServerRoleFindArgs{}
+
+	optArgs := &freeipa.ServerRoleFindOptionalArgs{
+		ServerServer: freeipa.String(name),
+		Status:       freeipa.String("hidden"),
+	}
+
+	tflog.Trace(ctx, "Calling ServerRoleFind", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().ServerRoleFind("", args, optArgs)
+
+	tflog.Trace(ctx, "Called ServerRoleFind", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return res.Count > 0, nil
+}
+
+func NewServer(p *provider.Provider) resource.Resource {
+	r := &Server{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewServer)
+}
@@ -0,0 +1,256 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type ServiceManagedbyHost struct {
+	provider *provider.Provider
+}
+
+type ServiceManagedbyHostModel struct {
+	Service       types.String `tfsdk:"service"`
+	ManagedbyHost types.String `tfsdk:"managedby_host"`
+}
+
+func (r *ServiceManagedbyHost) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_managedby_host"
+}
+
+func (r *ServiceManagedbyHost) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Allows a FreeIPA host to manage a service, e.g. to retrieve its keytab.",
+		Attributes: map[string]schema.Attribute{
+			"service": schema.StringAttribute{
+				Description: "Principal name of the managed service (e.g. `HTTP/web.example.com`)",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"managedby_host": schema.StringAttribute{
+				Description: "FQDN of the host allowed to manage the service",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ServiceManagedbyHost) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ServiceManagedbyHostModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.ServiceAddHostArgs{
+		Krbcanonicalname: plan.Service.ValueString(),
+	}
+	optArgs := &freeipa.ServiceAddHostOptionalArgs{
+		Host: &[]string{plan.ManagedbyHost.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling ServiceAddHost", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().ServiceAddHost(args, optArgs)
+	tflog.Trace(ctx, "Called ServiceAddHost", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	// Hosts which already manage the service are reported as failures,
+	// treat them as success to keep the creation idempotent.
+	if err == nil {
+		err = utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add service managed by host", "Reason: "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *ServiceManagedbyHost) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ServiceManagedbyHostModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// go-freeipa cannot decode services managed by more than one host, the
+	// service is read without its members and the host is looked up by
+	// searching the service among the ones it manages
+	args := &freeipa.ServiceShowArgs{
+		Krbcanonicalname: state.Service.ValueString(),
+	}
+	optArgs := &freeipa.ServiceShowOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling ServiceShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().ServiceShow(args, optArgs)
+	tflog.Trace(ctx, "Called ServiceShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read service", "Reason: "+err.Error())
+		return
+	}
+
+	findArgs := &freeipa.ServiceFindArgs{}
+	findOptArgs := &freeipa.ServiceFindOptionalArgs{
+		Krbcanonicalname: freeipa.String(res.Result.Krbcanonicalname),
+		ManByHost:        &[]string{state.ManagedbyHost.ValueString()},
+		PkeyOnly:         freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling ServiceFind", map[string]any{
+		"args":     findArgs,
+		"opt_args": findOptArgs,
+	})
+
+	findRes, err := r.provider.Client().ServiceFind("", findArgs, findOptArgs)
+	tflog.Trace(ctx, "Called ServiceFind", map[string]any{
+		"res": findRes,
+		"err": err,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read service", "Reason: "+err.Error())
+		return
+	}
+
+	if findRes.Count == 0 {
+		tflog.Debug(ctx, "Host no longer manages service", map[string]any{
+			"service":        state.Service.ValueString(),
+			"managedby_host": state.ManagedbyHost.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *ServiceManagedbyHost) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ServiceManagedbyHostModel
+
+	// All attributes require replacement, there is nothing to update
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *ServiceManagedbyHost) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ServiceManagedbyHostModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.ServiceRemoveHostArgs{
+		Krbcanonicalname: state.Service.ValueString(),
+	}
+	optArgs := &freeipa.ServiceRemoveHostOptionalArgs{
+		Host: &[]string{state.ManagedbyHost.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling ServiceRemoveHost", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().ServiceRemoveHost(args, optArgs)
+	tflog.Trace(ctx, "Called ServiceRemoveHost", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove service managed by host", "Reason: "+err.Error())
+		}
+		return
+	}
+
+	// Hosts removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove service managed by host", "Reason: "+err.Error())
+	}
+}
+
+func (r *ServiceManagedbyHost) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Service principals hold a slash, the host follows the last one
+	i := strings.LastIndex(req.ID, "/")
+
+	var service, managedbyHost string
+	if i >= 0 {
+		service, managedbyHost = req.ID[:i], req.ID[i+1:]
+	}
+
+	if !strings.Contains(service, "/") || managedbyHost == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<service>/<managedby host>”, e.g. “HTTP/web.example.com/proxy.example.com”, got %q.", req.ID),
+		)
+		return
+	}
+
+	state := ServiceManagedbyHostModel{
+		Service:       types.StringValue(service),
+		ManagedbyHost: types.StringValue(managedbyHost),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewServiceManagedbyHost(p *provider.Provider) resource.Resource {
+	r := &ServiceManagedbyHost{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewServiceManagedbyHost)
+}
@@ -2,13 +2,21 @@ package resources
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 
 	"github.com/camptocamp/go-freeipa/freeipa"
 	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -19,9 +27,14 @@ type Service struct {
 }
 
 type ServiceModel struct {
-	KrbHostname   types.String `tfsdk:"krb_hostname"`
-	Force         types.Bool   `tfsdk:"force"`
-	SkipHostCheck types.Bool   `tfsdk:"skip_host_check"`
+	KrbHostname      types.String `tfsdk:"krb_hostname"`
+	KrbCanonicalName types.String `tfsdk:"krbcanonicalname"`
+	Force            types.Bool   `tfsdk:"force"`
+	SkipHostCheck    types.Bool   `tfsdk:"skip_host_check"`
+	ManagedByHosts   types.Set    `tfsdk:"managedby_hosts"`
+	UserCertificate  types.Set    `tfsdk:"usercertificate"`
+	RetrieveKeytab   types.Bool   `tfsdk:"retrieve_keytab"`
+	Keytab           types.String `tfsdk:"keytab"`
 }
 
 func (r *Service) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -39,6 +52,13 @@ func (r *Service) Schema(ctx context.Context, req resource.SchemaRequest, resp *
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"krbcanonicalname": schema.StringAttribute{
+				Description: "Canonical principal name of the service, including the realm",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"force": schema.BoolAttribute{
 				Description: "Force force principal name even if host not in DNS",
 				Optional:    true,
@@ -47,10 +67,71 @@ func (r *Service) Schema(ctx context.Context, req resource.SchemaRequest, resp *
 				Description: "Skip host check force service to be created even when host object does not exist to manage it",
 				Optional:    true,
 			},
+			"managedby_hosts": schema.SetAttribute{
+				Description: "Hosts allowed to manage the service. FreeIPA adds the host of the principal by default",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"usercertificate": schema.SetAttribute{
+				Description: "Certificates of the service, base64 encoded DER or PEM, compared by their DER encoding. Certificates added outside of Terraform, such as those issued by the FreeIPA CA, are kept",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"retrieve_keytab": schema.BoolAttribute{
+				Description: "Generate the keys of the service when it is created and export them in `keytab`. Requires Kerberos authentication and `ipa-getkeytab`",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplaceIf(retrieveKeytabRequiresReplace, "Requires replacement when set to true, the keys being generated on creation", "Requires replacement when set to `true`, the keys being generated on creation"),
+				},
+			},
+			"keytab": schema.StringAttribute{
+				Description: "Base64 encoded keytab of the service, generated on creation when `retrieve_keytab` is true",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
 
+// retrieveKeytabRequiresReplace replaces the service only when retrieve_keytab
+// becomes true, unset and false being equivalent, e.g. after an import.
+func retrieveKeytabRequiresReplace(ctx context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = req.PlanValue.ValueBool()
+}
+
+func (r *Service) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config ServiceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.UserCertificate.IsUnknown() && !config.UserCertificate.IsNull() {
+		var certificates []string
+
+		resp.Diagnostics.Append(config.UserCertificate.ElementsAs(ctx, &certificates, false)...)
+
+		for _, c := range certificates {
+			if _, err := utils.ParseCertificate(c); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("usercertificate"),
+					"Invalid configuration",
+					fmt.Sprintf("Invalid certificate: %s.", err.Error()),
+				)
+			}
+		}
+	}
+}
+
 func (r *Service) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan, state ServiceModel
 
@@ -84,7 +165,64 @@ func (r *Service) Create(ctx context.Context, req resource.CreateRequest, resp *
 		return
 	}
 
+	var managedByHosts []string
+	if res.Result.ManagedbyHost != "" {
+		managedByHosts = []string{res.Result.ManagedbyHost}
+	}
+
 	state = plan
+	state.KrbCanonicalName = types.StringValue(res.Result.Krbcanonicalname)
+	state.Keytab = types.StringNull()
+
+	if plan.ManagedByHosts.IsUnknown() {
+		var diags diag.Diagnostics
+
+		state.ManagedByHosts, diags = types.SetValueFrom(ctx, types.StringType, managedByHosts)
+		resp.Diagnostics.Append(diags...)
+	} else {
+		var desiredManagedByHosts []string
+
+		resp.Diagnostics.Append(plan.ManagedByHosts.ElementsAs(ctx, &desiredManagedByHosts, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(r.updateManagedByHosts(ctx, plan.KrbHostname.ValueString(), managedByHosts, desiredManagedByHosts)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.UserCertificate.IsNull() {
+		if _, err := r.applyCertificates(ctx, plan.KrbHostname.ValueString(), plan.UserCertificate, types.SetNull(types.StringType)); err != nil {
+			resp.Diagnostics.AddError("Failed to add service certificates", "Reason: "+err.Error())
+
+			// The service exists, keep it in the state so that it is not leaked
+			state.UserCertificate = types.SetNull(types.StringType)
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+			return
+		}
+	}
+
+	if plan.RetrieveKeytab.ValueBool() {
+		tflog.Trace(ctx, "Retrieving service keytab", map[string]any{
+			"principal": res.Result.Krbcanonicalname,
+		})
+
+		keytab, err := r.provider.GetKeytab(ctx, res.Result.Krbcanonicalname)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to retrieve service keytab", "Reason: "+err.Error())
+
+			// The service is tainted and created again with a new keytab
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+			return
+		}
+
+		state.Keytab = types.StringValue(base64.StdEncoding.EncodeToString(keytab))
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
@@ -100,7 +238,9 @@ func (r *Service) Read(ctx context.Context, req resource.ReadRequest, resp *reso
 	args := &freeipa.ServiceShowArgs{
 		Krbcanonicalname: state.KrbHostname.ValueString(),
 	}
-	optArgs := &freeipa.ServiceShowOptionalArgs{}
+	optArgs := &freeipa.ServiceShowOptionalArgs{
+		All: freeipa.Bool(true),
+	}
 
 	tflog.Trace(ctx, "Calling ServiceShow", map[string]any{
 		"args":     args,
@@ -113,10 +253,16 @@ func (r *Service) Read(ctx context.Context, req resource.ReadRequest, resp *reso
 		"err": err,
 	})
 	if err != nil {
-		var freeipaErr *freeipa.Error
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
 
-		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
-			resp.State.RemoveResource(ctx)
+		// go-freeipa cannot decode services managed by more than one host,
+		// keep the known state instead of failing the whole plan.
+		if utils.IsManagedbyHostDecodeError(err) {
+			tflog.Warn(ctx, "Ignoring go-freeipa ManagedbyHost decode error on ServiceShow", map[string]any{
+				"err": err,
+			})
 			return
 		}
 
@@ -124,6 +270,19 @@ func (r *Service) Read(ctx context.Context, req resource.ReadRequest, resp *reso
 		return
 	}
 
+	state.KrbCanonicalName = types.StringValue(res.Result.Krbcanonicalname)
+
+	var diags diag.Diagnostics
+
+	state.UserCertificate, diags = certificatesToSet(ctx, state.UserCertificate, res.Result.Usercertificate)
+	resp.Diagnostics.Append(diags...)
+
+	if res.Result.ManagedbyHost != "" {
+		state.ManagedByHosts = types.SetValueMust(types.StringType, []attr.Value{types.StringValue(res.Result.ManagedbyHost)})
+	} else {
+		state.ManagedByHosts = types.SetValueMust(types.StringType, []attr.Value{})
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
@@ -137,34 +296,46 @@ func (r *Service) Update(ctx context.Context, req resource.UpdateRequest, resp *
 		return
 	}
 
-	args := &freeipa.ServiceModArgs{
-		Krbcanonicalname: plan.KrbHostname.ValueString(),
-	}
-	optArgs := &freeipa.ServiceModOptionalArgs{}
+	if !plan.ManagedByHosts.Equal(state.ManagedByHosts) && !plan.ManagedByHosts.IsUnknown() {
+		var currentManagedByHosts, desiredManagedByHosts []string
 
-	hasDiff = !plan.KrbHostname.Equal(state.KrbHostname)
+		resp.Diagnostics.Append(state.ManagedByHosts.ElementsAs(ctx, &currentManagedByHosts, false)...)
+		resp.Diagnostics.Append(plan.ManagedByHosts.ElementsAs(ctx, &desiredManagedByHosts, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 
-	if hasDiff {
-		tflog.Trace(ctx, "Calling ServiceMod", map[string]any{
-			"args":     args,
-			"opt_args": optArgs,
-		})
+		resp.Diagnostics.Append(r.updateManagedByHosts(ctx, plan.KrbHostname.ValueString(), currentManagedByHosts, desiredManagedByHosts)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 
-		res, err := r.provider.Client().ServiceMod(args, optArgs)
-		tflog.Trace(ctx, "Called ServiceMod", map[string]any{
-			"res": res,
-			"err": err,
-		})
+		hasDiff = true
+	}
+
+	if !plan.UserCertificate.Equal(state.UserCertificate) {
+		changed, err := r.applyCertificates(ctx, plan.KrbHostname.ValueString(), plan.UserCertificate, state.UserCertificate)
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to update service", "Reason: "+err.Error())
+			resp.Diagnostics.AddError("Failed to update service certificates", "Reason: "+err.Error())
+
 			return
 		}
-	} else {
+
+		hasDiff = hasDiff || changed
+	}
+
+	if !hasDiff {
 		tflog.Debug(ctx, "Updated service has no effective difference", map[string]any{
 			"krb_hostname": plan.KrbHostname.ValueString(),
 		})
 	}
 
+	plan.KrbCanonicalName = state.KrbCanonicalName
+	plan.Keytab = state.Keytab
+	if plan.ManagedByHosts.IsUnknown() {
+		plan.ManagedByHosts = state.ManagedByHosts
+	}
+
 	state = plan
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
@@ -196,18 +367,21 @@ func (r *Service) Delete(ctx context.Context, req resource.DeleteRequest, resp *
 
 	if err != nil {
 		var freeipaErr *freeipa.Error
-		if errors.As(err, &freeipaErr) && freeipaErr.Code != freeipa.NotFoundCode {
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
 			resp.Diagnostics.AddError("Failed to delete service", "Reason: "+err.Error())
 			return
 		}
 	}
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
 func (r *Service) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	state := ServiceModel{
-		KrbHostname: types.StringValue(req.ID),
+		KrbHostname:      types.StringValue(req.ID),
+		KrbCanonicalName: types.StringNull(),
+		ManagedByHosts:   types.SetNull(types.StringType),
+		UserCertificate:  types.SetNull(types.StringType),
+		RetrieveKeytab:   types.BoolNull(),
+		Keytab:           types.StringNull(),
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
@@ -219,6 +393,7 @@ func NewService(p *provider.Provider) resource.Resource {
 	}
 
 	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
 	var _ resource.ResourceWithImportState = r
 
 	return r
@@ -227,3 +402,126 @@ func NewService(p *provider.Provider) resource.Resource {
 func init() {
 	resources = append(resources, NewService)
 }
+
+// applyCertificates adds the certificates of plan missing from state to the
+// service and removes those of state missing from plan, and reports whether
+// the service changed. Certificates are compared by their DER encoding.
+func (r *Service) applyCertificates(ctx context.Context, principal string, plan, state types.Set) (bool, error) {
+	added, removed, err := certificateChanges(ctx, plan, state)
+	if err != nil {
+		return false, err
+	}
+
+	var freeipaErr *freeipa.Error
+
+	if len(removed) > 0 {
+		args := &freeipa.ServiceRemoveCertArgs{
+			Krbcanonicalname: principal,
+			Usercertificate:  removed,
+		}
+
+		tflog.Trace(ctx, "Calling ServiceRemoveCert", map[string]any{
+			"args":     args,
+			"opt_args": nil,
+		})
+
+		res, err := r.provider.Client().ServiceRemoveCert(args, nil)
+
+		tflog.Trace(ctx, "Called ServiceRemoveCert", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		// Certificates already removed outside of Terraform are gone anyway
+		if err != nil && (!errors.As(err, &freeipaErr) || freeipaErr.Code != utils.AttrValueNotFoundCode) {
+			return false, err
+		}
+	}
+
+	if len(added) > 0 {
+		args := &freeipa.ServiceAddCertArgs{
+			Krbcanonicalname: principal,
+			Usercertificate:  added,
+		}
+
+		tflog.Trace(ctx, "Calling ServiceAddCert", map[string]any{
+			"args":     args,
+			"opt_args": nil,
+		})
+
+		res, err := r.provider.Client().ServiceAddCert(args, nil)
+
+		tflog.Trace(ctx, "Called ServiceAddCert", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		// The service may already hold the certificates
+		if err != nil && (!errors.As(err, &freeipaErr) || freeipaErr.Code != utils.EmptyModlistCode) {
+			return false, err
+		}
+	}
+
+	return len(added) > 0 || len(removed) > 0, nil
+}
+
+func (r *Service) updateManagedByHosts(ctx context.Context, principal string, actualHosts, desiredHosts []string) (diags diag.Diagnostics) {
+	hostsToAdd, hostsToRemove := utils.SetDiff(actualHosts, desiredHosts)
+
+	if len(hostsToAdd) > 0 {
+		args := &freeipa.ServiceAddHostArgs{
+			Krbcanonicalname: principal,
+		}
+		optArgs := &freeipa.ServiceAddHostOptionalArgs{
+			Host: &hostsToAdd,
+		}
+
+		tflog.Trace(ctx, "Calling ServiceAddHost", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().ServiceAddHost(args, optArgs)
+		tflog.Trace(ctx, "Called ServiceAddHost", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err == nil {
+			err = utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember)
+		}
+		if err != nil {
+			diags.AddError("Failed to add service managed by hosts", "Reason: "+err.Error())
+			return
+		}
+	}
+
+	if len(hostsToRemove) > 0 {
+		args := &freeipa.ServiceRemoveHostArgs{
+			Krbcanonicalname: principal,
+		}
+		optArgs := &freeipa.ServiceRemoveHostOptionalArgs{
+			Host: &hostsToRemove,
+		}
+
+		tflog.Trace(ctx, "Calling ServiceRemoveHost", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().ServiceRemoveHost(args, optArgs)
+		tflog.Trace(ctx, "Called ServiceRemoveHost", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err == nil {
+			err = utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry)
+		}
+		if err != nil {
+			diags.AddError("Failed to remove service managed by hosts", "Reason: "+err.Error())
+		}
+	}
+
+	return
+}
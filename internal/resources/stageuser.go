@@ -0,0 +1,635 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Stageuser struct {
+	provider *provider.Provider
+}
+
+type StageuserModel struct {
+	UID           types.String `tfsdk:"uid"`
+	GivenName     types.String `tfsdk:"givenname"`
+	Surname       types.String `tfsdk:"sn"`
+	FullName      types.String `tfsdk:"cn"`
+	DisplayName   types.String `tfsdk:"displayname"`
+	HomeDirectory types.String `tfsdk:"homedirectory"`
+	LoginShell    types.String `tfsdk:"loginshell"`
+	Mail          types.List   `tfsdk:"mail"`
+	Activate      types.Bool   `tfsdk:"activate"`
+}
+
+func (r *Stageuser) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stageuser"
+}
+
+func (r *Stageuser) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA stage user, which can be activated into an active user.",
+		Attributes: map[string]schema.Attribute{
+			"uid": schema.StringAttribute{
+				Description: "User login",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"givenname": schema.StringAttribute{
+				Description: "First name",
+				Required:    true,
+			},
+			"sn": schema.StringAttribute{
+				Description: "Last name",
+				Required:    true,
+			},
+			"cn": schema.StringAttribute{
+				Description: "Full name",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"displayname": schema.StringAttribute{
+				Description: "Display name",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"homedirectory": schema.StringAttribute{
+				Description: "Home directory",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"loginshell": schema.StringAttribute{
+				Description: "Login shell",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"mail": schema.ListAttribute{
+				Description: "Email addresses",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"activate": schema.BoolAttribute{
+				Description: "Whether the stage user is activated into an active user. An activated user cannot be staged back",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *Stageuser) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var state, plan StageuserModel
+
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Activate.ValueBool() && !plan.Activate.ValueBool() && !plan.Activate.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("activate"),
+			"Invalid configuration",
+			`The user is already active, “activate” cannot be unset.`,
+		)
+	}
+}
+
+func (r *Stageuser) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan StageuserModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.StageuserAddArgs{
+		Givenname: plan.GivenName.ValueString(),
+		Sn:        plan.Surname.ValueString(),
+	}
+
+	optArgs := &freeipa.StageuserAddOptionalArgs{
+		UID:           plan.UID.ValueStringPointer(),
+		Cn:            stringToStringPointer(plan.FullName),
+		Displayname:   stringToStringPointer(plan.DisplayName),
+		Homedirectory: stringToStringPointer(plan.HomeDirectory),
+		Loginshell:    stringToStringPointer(plan.LoginShell),
+		All:           freeipa.Bool(true),
+	}
+
+	resp.Diagnostics.Append(listToStringSlicePointer(ctx, plan.Mail, &optArgs.Mail)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Calling StageuserAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().StageuserAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called StageuserAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create stage user", "Reason: "+err.Error())
+
+		return
+	}
+
+	state := plan
+	user := res.Result
+
+	resp.Diagnostics.Append(stageuserComputedState(ctx, &state, &user.Cn, user.Displayname, user.Homedirectory, user.Loginshell, user.Mail)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.Activate.ValueBool() {
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+		return
+	}
+
+	// Save the stage user first, a failed activation is retried on the next
+	// apply.
+	staged := state
+	staged.Activate = types.BoolValue(false)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, staged)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.activate(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Stageuser) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state StageuserModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.Activate.ValueBool() {
+		args := &freeipa.StageuserShowArgs{}
+
+		optArgs := &freeipa.StageuserShowOptionalArgs{
+			UID: state.UID.ValueStringPointer(),
+			All: freeipa.Bool(true),
+		}
+
+		tflog.Trace(ctx, "Calling StageuserShow", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().StageuserShow(args, optArgs)
+
+		tflog.Trace(ctx, "Called StageuserShow", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err == nil {
+			user := res.Result
+
+			state.GivenName = types.StringValue(user.Givenname)
+			state.Surname = types.StringValue(user.Sn)
+			resp.Diagnostics.Append(stageuserComputedState(ctx, &state, &user.Cn, user.Displayname, user.Homedirectory, user.Loginshell, user.Mail)...)
+
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+			return
+		}
+
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to read stage user", "Reason: "+err.Error())
+
+			return
+		}
+
+		// The stage user may have been activated outside of Terraform, it is
+		// then tracked as an active user.
+		tflog.Debug(ctx, "Stage user not found, looking for an active user", map[string]any{
+			"uid": state.UID.ValueString(),
+		})
+	}
+
+	found, diags := r.readUser(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	state.Activate = types.BoolValue(true)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Stageuser) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan StageuserModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var givenName, surname, fullName, displayName, homeDirectory, loginShell *string
+	var mail *[]string
+
+	stringChanges := []struct {
+		plan, state types.String
+		arg         **string
+	}{
+		{plan.GivenName, state.GivenName, &givenName},
+		{plan.Surname, state.Surname, &surname},
+		{plan.FullName, state.FullName, &fullName},
+		{plan.DisplayName, state.DisplayName, &displayName},
+		{plan.HomeDirectory, state.HomeDirectory, &homeDirectory},
+		{plan.LoginShell, state.LoginShell, &loginShell},
+	}
+
+	for _, c := range stringChanges {
+		if !c.plan.Equal(c.state) && !c.plan.IsUnknown() {
+			*c.arg = freeipa.String(c.plan.ValueString())
+			hasDiff = true
+		}
+	}
+
+	if !plan.Mail.Equal(state.Mail) && !plan.Mail.IsUnknown() {
+		values := []string{}
+
+		resp.Diagnostics.Append(plan.Mail.ElementsAs(ctx, &values, false)...)
+
+		mail = &values
+		hasDiff = true
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	newState := plan
+
+	switch {
+	case !hasDiff:
+		tflog.Debug(ctx, "Updated stage user has no effective difference", map[string]any{
+			"uid": plan.UID.ValueString(),
+		})
+	case state.Activate.ValueBool():
+		args := &freeipa.UserModArgs{}
+
+		optArgs := &freeipa.UserModOptionalArgs{
+			UID:           plan.UID.ValueStringPointer(),
+			Givenname:     givenName,
+			Sn:            surname,
+			Cn:            fullName,
+			Displayname:   displayName,
+			Homedirectory: homeDirectory,
+			Loginshell:    loginShell,
+			Mail:          mail,
+			All:           freeipa.Bool(true),
+		}
+
+		tflog.Trace(ctx, "Calling UserMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().UserMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called UserMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update user", "Reason: "+err.Error())
+
+			return
+		}
+
+		user := res.Result
+
+		resp.Diagnostics.Append(stageuserComputedState(ctx, &newState, user.Cn, user.Displayname, user.Homedirectory, user.Loginshell, user.Mail)...)
+	default:
+		args := &freeipa.StageuserModArgs{}
+
+		optArgs := &freeipa.StageuserModOptionalArgs{
+			UID:           plan.UID.ValueStringPointer(),
+			Givenname:     givenName,
+			Sn:            surname,
+			Cn:            fullName,
+			Displayname:   displayName,
+			Homedirectory: homeDirectory,
+			Loginshell:    loginShell,
+			Mail:          mail,
+			All:           freeipa.Bool(true),
+		}
+
+		tflog.Trace(ctx, "Calling StageuserMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().StageuserMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called StageuserMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update stage user", "Reason: "+err.Error())
+
+			return
+		}
+
+		user := res.Result
+
+		resp.Diagnostics.Append(stageuserComputedState(ctx, &newState, &user.Cn, user.Displayname, user.Homedirectory, user.Loginshell, user.Mail)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Activate.ValueBool() && !state.Activate.ValueBool() {
+		// Keep the modifications if the activation fails
+		staged := newState
+		staged.Activate = state.Activate
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, staged)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(r.activate(ctx, &newState)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *Stageuser) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state StageuserModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var err error
+
+	// Once activated, the resource tracks the active user
+	if state.Activate.ValueBool() {
+		args := &freeipa.UserDelArgs{}
+
+		optArgs := &freeipa.UserDelOptionalArgs{
+			UID: &[]string{state.UID.ValueString()},
+		}
+
+		tflog.Trace(ctx, "Calling UserDel", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		var res *freeipa.UserDelResult
+
+		res, err = r.provider.Client().UserDel(args, optArgs)
+
+		tflog.Trace(ctx, "Called UserDel", map[string]any{
+			"res": res,
+			"err": err,
+		})
+	} else {
+		args := &freeipa.StageuserDelArgs{}
+
+		optArgs := &freeipa.StageuserDelOptionalArgs{
+			UID: &[]string{state.UID.ValueString()},
+		}
+
+		tflog.Trace(ctx, "Calling StageuserDel", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		var res *freeipa.StageuserDelResult
+
+		res, err = r.provider.Client().StageuserDel(args, optArgs)
+
+		tflog.Trace(ctx, "Called StageuserDel", map[string]any{
+			"res": res,
+			"err": err,
+		})
+	}
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete stage user", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Stageuser) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := StageuserModel{
+		UID:  types.StringValue(req.ID),
+		Mail: types.ListNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// activate promotes the stage user into the active users and reads the active
+// user back.
+func (r *Stageuser) activate(ctx context.Context, state *StageuserModel) (diags diag.Diagnostics) {
+	args := &freeipa.StageuserActivateArgs{}
+
+	optArgs := &freeipa.StageuserActivateOptionalArgs{
+		UID: state.UID.ValueStringPointer(),
+	}
+
+	tflog.Trace(ctx, "Calling StageuserActivate", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().StageuserActivate(args, optArgs)
+
+	tflog.Trace(ctx, "Called StageuserActivate", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		diags.AddError("Failed to activate stage user", "Reason: "+err.Error())
+
+		return
+	}
+
+	found, d := r.readUser(ctx, state)
+	diags.Append(d...)
+
+	if !found && !diags.HasError() {
+		diags.AddError("Failed to activate stage user", "The activated user cannot be found.")
+	}
+
+	return
+}
+
+// readUser reads the active user into state, reporting whether it exists.
+func (r *Stageuser) readUser(ctx context.Context, state *StageuserModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	args := &freeipa.UserShowArgs{}
+
+	optArgs := &freeipa.UserShowOptionalArgs{
+		UID: state.UID.ValueStringPointer(),
+		All: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling UserShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().UserShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called UserShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			return false, diags
+		}
+
+		diags.AddError("Failed to read user", "Reason: "+err.Error())
+
+		return false, diags
+	}
+
+	user := res.Result
+
+	state.GivenName = types.StringPointerValue(user.Givenname)
+	state.Surname = types.StringValue(user.Sn)
+	diags.Append(stageuserComputedState(ctx, state, user.Cn, user.Displayname, user.Homedirectory, user.Loginshell, user.Mail)...)
+
+	return true, diags
+}
+
+// stageuserComputedState copies the attributes FreeIPA may derive on its own
+// into state. Stage and active users hold the same attributes in different
+// types.
+func stageuserComputedState(ctx context.Context, state *StageuserModel, fullName, displayName, homeDirectory, loginShell *string, mail *[]string) (diags diag.Diagnostics) {
+	state.FullName = types.StringPointerValue(fullName)
+	state.DisplayName = types.StringPointerValue(displayName)
+	state.HomeDirectory = types.StringPointerValue(homeDirectory)
+	state.LoginShell = types.StringPointerValue(loginShell)
+
+	if mail != nil {
+		var d diag.Diagnostics
+
+		state.Mail, d = types.ListValueFrom(ctx, types.StringType, *mail)
+		diags.Append(d...)
+	} else {
+		state.Mail = types.ListValueMust(types.StringType, []attr.Value{})
+	}
+
+	return
+}
+
+func NewStageuser(p *provider.Provider) resource.Resource {
+	r := &Stageuser{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithModifyPlan = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewStageuser)
+}
@@ -0,0 +1,417 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Subid struct {
+	provider *provider.Provider
+}
+
+type SubidModel struct {
+	ID          types.String `tfsdk:"ipauniqueid"`
+	Owner       types.String `tfsdk:"ipaowner"`
+	Description types.String `tfsdk:"description"`
+	UIDNumber   types.Int64  `tfsdk:"ipasubuidnumber"`
+	UIDCount    types.Int64  `tfsdk:"ipasubuidcount"`
+	GIDNumber   types.Int64  `tfsdk:"ipasubgidnumber"`
+	GIDCount    types.Int64  `tfsdk:"ipasubgidcount"`
+}
+
+func (r *Subid) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_subid"
+}
+
+func (r *Subid) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages the subordinate ID range of a FreeIPA user, assigned by FreeIPA.",
+		Attributes: map[string]schema.Attribute{
+			"ipauniqueid": schema.StringAttribute{
+				Description: "Unique ID of the subordinate ID range",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipaowner": schema.StringAttribute{
+				Description: "User owning the subordinate ID range",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Subordinate ID range description",
+				Optional:    true,
+			},
+			"ipasubuidnumber": schema.Int64Attribute{
+				Description: "First subordinate UID of the range",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipasubuidcount": schema.Int64Attribute{
+				Description: "Number of subordinate UIDs of the range",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipasubgidnumber": schema.Int64Attribute{
+				Description: "First subordinate GID of the range",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipasubgidcount": schema.Int64Attribute{
+				Description: "Number of subordinate GIDs of the range",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Subid) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SubidModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A user holds a single range, the one assigned outside of Terraform (e.g.
+	// by `ipa subid-generate`) is adopted instead of failing
+	subid, err := r.subidFindByOwner(ctx, plan.Owner.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read subordinate IDs", "Reason: "+err.Error())
+
+		return
+	}
+
+	if subid != nil {
+		tflog.Debug(ctx, "Adopting existing subordinate ID range", map[string]any{
+			"ipaowner":    plan.Owner.ValueString(),
+			"ipauniqueid": subid.Ipauniqueid,
+		})
+
+		if !plan.Description.Equal(types.StringPointerValue(subid.Description)) {
+			if subid, err = r.subidMod(ctx, subid.Ipauniqueid, plan.Description); err != nil {
+				resp.Diagnostics.AddError("Failed to update subordinate ID range", "Reason: "+err.Error())
+
+				return
+			}
+		}
+	} else {
+		args := &freeipa.SubidAddArgs{
+			Ipaowner: plan.Owner.ValueString(),
+		}
+
+		optArgs := &freeipa.SubidAddOptionalArgs{
+			Description: plan.Description.ValueStringPointer(),
+		}
+
+		tflog.Trace(ctx, "Calling SubidAdd", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		// FreeIPA assigns the unique ID and the ranges of "autogenerate" entries
+		res, err := r.provider.Client().SubidAdd("autogenerate", args, optArgs)
+
+		tflog.Trace(ctx, "Called SubidAdd", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to create subordinate ID range", "Reason: "+err.Error())
+
+			return
+		}
+
+		subid = &res.Result
+	}
+
+	state := subidState(subid)
+	state.Owner = plan.Owner
+	state.Description = plan.Description
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Subid) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state SubidModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.SubidShowArgs{
+		Ipauniqueid: state.ID.ValueString(),
+	}
+
+	tflog.Trace(ctx, "Calling SubidShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().SubidShow(args, nil)
+
+	tflog.Trace(ctx, "Called SubidShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read subordinate ID range", "Reason: "+err.Error())
+
+		return
+	}
+
+	state = subidState(&res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Subid) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan SubidModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Description.Equal(state.Description) {
+		if _, err := r.subidMod(ctx, state.ID.ValueString(), plan.Description); err != nil {
+			resp.Diagnostics.AddError("Failed to update subordinate ID range", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated subordinate ID range has no effective difference", map[string]any{
+			"ipauniqueid": state.ID.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Subid) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state SubidModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.SubidDelArgs{
+		Ipauniqueid: []string{state.ID.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling SubidDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().SubidDel(args, nil)
+
+	tflog.Trace(ctx, "Called SubidDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete subordinate ID range", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Subid) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id := req.ID
+
+	// A subordinate ID is resolved to the range holding it
+	if number, err := strconv.Atoi(req.ID); err == nil {
+		var matchErr error
+
+		if id, matchErr = r.subidMatch(ctx, number); matchErr != nil {
+			resp.Diagnostics.AddError("Failed to match subordinate ID", "Reason: "+matchErr.Error())
+
+			return
+		}
+	}
+
+	state := SubidModel{
+		ID: types.StringValue(id),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Subid) subidMod(ctx context.Context, id string, description types.String) (*freeipa.Subid, error) {
+	args := &freeipa.SubidModArgs{
+		Ipauniqueid: id,
+	}
+
+	// A null description is sent as an empty string to clear it
+	optArgs := &freeipa.SubidModOptionalArgs{
+		Description: freeipa.String(description.ValueString()),
+	}
+
+	tflog.Trace(ctx, "Calling SubidMod", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().SubidMod(args, optArgs)
+
+	tflog.Trace(ctx, "Called SubidMod", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Result, nil
+}
+
+// subidFindByOwner returns the subordinate ID range of owner, nil when the
+// user has none.
+func (r *Subid) subidFindByOwner(ctx context.Context, owner string) (*freeipa.Subid, error) {
+	args := &freeipa.SubidFindArgs{}
+
+	optArgs := &freeipa.SubidFindOptionalArgs{
+		Ipaowner: freeipa.String(owner),
+	}
+
+	tflog.Trace(ctx, "Calling SubidFind", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().SubidFind("", args, optArgs)
+
+	tflog.Trace(ctx, "Called SubidFind", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Result) == 0 {
+		return nil, nil
+	}
+
+	return &res.Result[0], nil
+}
+
+// subidMatch returns the unique ID of the subordinate ID range holding the
+// subordinate UID number.
+func (r *Subid) subidMatch(ctx context.Context, number int) (string, error) {
+	args := &freeipa.SubidMatchArgs{
+		Ipasubuidnumber: number,
+	}
+
+	optArgs := &freeipa.SubidMatchOptionalArgs{
+		PkeyOnly: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling SubidMatch", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().SubidMatch("", args, optArgs)
+
+	tflog.Trace(ctx, "Called SubidMatch", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	// go-freeipa does not decode the matched entries
+	for _, entry := range res.Result {
+		if m, ok := entry.(map[string]interface{}); ok {
+			switch v := m["ipauniqueid"].(type) {
+			case string:
+				return v, nil
+			case []interface{}:
+				if len(v) > 0 {
+					if id, ok := v[0].(string); ok {
+						return id, nil
+					}
+				}
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no subordinate ID range holds the subordinate UID %d", number)
+}
+
+func subidState(subid *freeipa.Subid) SubidModel {
+	return SubidModel{
+		ID:          types.StringValue(subid.Ipauniqueid),
+		Owner:       types.StringValue(subid.Ipaowner),
+		Description: types.StringPointerValue(subid.Description),
+		UIDNumber:   intToInt64Value(subid.Ipasubuidnumber),
+		UIDCount:    intToInt64Value(subid.Ipasubuidcount),
+		GIDNumber:   intToInt64Value(subid.Ipasubgidnumber),
+		GIDCount:    intToInt64Value(subid.Ipasubgidcount),
+	}
+}
+
+func NewSubid(p *provider.Provider) resource.Resource {
+	r := &Subid{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewSubid)
+}
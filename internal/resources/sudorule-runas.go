@@ -0,0 +1,460 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+// sudoRuleRunasCategory is the attribute of the association which sets the
+// run-as category of the sudo rule instead of adding a member.
+const sudoRuleRunasCategory = "category"
+
+// sudoRuleRunasKind describes the run-as users or the run-as groups of a sudo
+// rule. Each association takes exactly one of the member attributes or the
+// category.
+type sudoRuleRunasKind struct {
+	name        string
+	description string
+	attributes  []sudoRuleRunasAttribute
+}
+
+type sudoRuleRunasAttribute struct {
+	name        string
+	description string
+}
+
+var sudoRuleRunasKinds = []sudoRuleRunasKind{
+	{
+		name:        "runasuser",
+		description: "Adds a run-as user or group to a FreeIPA sudo rule, or lets the rule run commands as any user.",
+		attributes: []sudoRuleRunasAttribute{
+			{"user", "User the commands may be run as. Can be an external user (local user of the IPA clients)"},
+			{"group", "Group whose members the commands may be run as. Can be an external group (local group of the IPA clients)"},
+			{sudoRuleRunasCategory, "Run-as user category of the sudo rule (allowed value: `all`)"},
+		},
+	},
+	{
+		name:        "runasgroup",
+		description: "Adds a run-as group to a FreeIPA sudo rule, or lets the rule run commands as any group.",
+		attributes: []sudoRuleRunasAttribute{
+			{"group", "Group the commands may be run as. Can be an external group (local group of the IPA clients)"},
+			{sudoRuleRunasCategory, "Run-as group category of the sudo rule (allowed value: `all`)"},
+		},
+	},
+}
+
+type SudoRuleRunas struct {
+	provider *provider.Provider
+	kind     sudoRuleRunasKind
+}
+
+func (r *SudoRuleRunas) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sudo_rule_" + r.kind.name
+}
+
+func (r *SudoRuleRunas) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := map[string]schema.Attribute{
+		"sudorule": schema.StringAttribute{
+			Description: "Sudo rule name",
+			Required:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+	}
+
+	for _, a := range r.kind.attributes {
+		attributes[a.name] = schema.StringAttribute{
+			Description: a.description,
+			Optional:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: r.kind.description,
+		Attributes:  attributes,
+	}
+}
+
+func (r *SudoRuleRunas) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var names []string
+	set := 0
+
+	for _, a := range r.kind.attributes {
+		var v types.String
+
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(a.name), &v)...)
+
+		if !v.IsNull() {
+			set++
+		}
+
+		if a.name == sudoRuleRunasCategory && !v.IsUnknown() && !v.IsNull() && v.ValueString() != "all" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(a.name),
+				"Invalid configuration",
+				`The only allowed category is “all”.`,
+			)
+		}
+
+		names = append(names, "“"+a.name+"”")
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if set != 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(r.kind.attributes[0].name),
+			"Invalid configuration",
+			fmt.Sprintf("Exactly one of %s must be set.", strings.Join(names, ", ")),
+		)
+	}
+}
+
+func (r *SudoRuleRunas) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var sudorule types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("sudorule"), &sudorule)...)
+
+	attr, member := r.member(ctx, req.Plan, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var failed freeipa.FailedOperations
+	var err error
+
+	if attr == sudoRuleRunasCategory {
+		err = r.setCategory(ctx, sudorule.ValueString(), "all")
+	} else {
+		failed, err = r.add(ctx, sudorule.ValueString(), attr, member)
+	}
+
+	// Members which already belong to the rule are reported as failures, treat
+	// them as success to keep the creation idempotent.
+	if err == nil {
+		err = utils.FailedOperationsError(failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add "+r.kind.name+" to sudo rule", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *SudoRuleRunas) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var sudorule types.String
+
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("sudorule"), &sudorule)...)
+
+	attr, member := r.member(ctx, req.State, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.SudoruleShowArgs{
+		Cn: sudorule.ValueString(),
+	}
+
+	optArgs := &freeipa.SudoruleShowOptionalArgs{
+		All: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling SudoruleShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().SudoruleShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called SudoruleShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read sudo rule", "Reason: "+err.Error())
+
+		return
+	}
+
+	if !slices.Contains(r.members(&res.Result, attr), member) {
+		tflog.Debug(ctx, "Run-as member was removed from sudo rule", map[string]any{
+			"sudorule": sudorule.ValueString(),
+			attr:       member,
+		})
+
+		resp.State.RemoveResource(ctx)
+	}
+}
+
+func (r *SudoRuleRunas) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All attributes require replacement, there is nothing to update
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *SudoRuleRunas) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var sudorule types.String
+
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("sudorule"), &sudorule)...)
+
+	attr, member := r.member(ctx, req.State, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var failed freeipa.FailedOperations
+	var err error
+
+	if attr == sudoRuleRunasCategory {
+		err = r.setCategory(ctx, sudorule.ValueString(), "")
+	} else {
+		failed, err = r.remove(ctx, sudorule.ValueString(), attr, member)
+	}
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove "+r.kind.name+" from sudo rule", "Reason: "+err.Error())
+		}
+
+		return
+	}
+
+	// Members removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove "+r.kind.name+" from sudo rule", "Reason: "+err.Error())
+	}
+}
+
+func (r *SudoRuleRunas) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var names []string
+
+	for _, a := range r.kind.attributes {
+		names = append(names, a.name)
+	}
+
+	parts := strings.SplitN(req.ID, "/", 3)
+
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" || !slices.Contains(names, parts[1]) {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<sudorule>/<%s>/<member>”, got %q.", strings.Join(names, "|"), req.ID),
+		)
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("sudorule"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(parts[1]), parts[2])...)
+}
+
+// member returns the attribute set on the association and its value.
+func (r *SudoRuleRunas) member(ctx context.Context, data caaclMembershipData, diags *diag.Diagnostics) (string, string) {
+	for _, a := range r.kind.attributes {
+		var v types.String
+
+		diags.Append(data.GetAttribute(ctx, path.Root(a.name), &v)...)
+
+		if !v.IsNull() && !v.IsUnknown() {
+			return a.name, v.ValueString()
+		}
+	}
+
+	if !diags.HasError() {
+		diags.AddError("Invalid sudo rule "+r.kind.name, "No member or category is set.")
+	}
+
+	return "", ""
+}
+
+// setCategory sets the run-as category of the sudo rule, an empty category
+// clears it. Setting the category it already has is not an error.
+func (r *SudoRuleRunas) setCategory(ctx context.Context, sudorule, category string) error {
+	args := &freeipa.SudoruleModArgs{
+		Cn: sudorule,
+	}
+
+	optArgs := &freeipa.SudoruleModOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+
+	if r.kind.name == "runasuser" {
+		optArgs.Ipasudorunasusercategory = &category
+	} else {
+		optArgs.Ipasudorunasgroupcategory = &category
+	}
+
+	tflog.Trace(ctx, "Calling SudoruleMod", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().SudoruleMod(args, optArgs)
+
+	tflog.Trace(ctx, "Called SudoruleMod", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	var freeipaErr *freeipa.Error
+	if errors.As(err, &freeipaErr) && freeipaErr.Code == utils.EmptyModlistCode {
+		return nil
+	}
+
+	return err
+}
+
+func (r *SudoRuleRunas) add(ctx context.Context, sudorule, attr, member string) (freeipa.FailedOperations, error) {
+	members := &[]string{member}
+
+	tflog.Trace(ctx, "Adding sudo rule "+r.kind.name, map[string]any{
+		"sudorule": sudorule,
+		attr:       member,
+	})
+
+	client := r.provider.Client()
+
+	if r.kind.name == "runasuser" {
+		optArgs := &freeipa.SudoruleAddRunasuserOptionalArgs{}
+		if attr == "user" {
+			optArgs.User = members
+		} else {
+			optArgs.Group = members
+		}
+
+		res, err := client.SudoruleAddRunasuser(&freeipa.SudoruleAddRunasuserArgs{Cn: sudorule}, optArgs)
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	}
+
+	res, err := client.SudoruleAddRunasgroup(&freeipa.SudoruleAddRunasgroupArgs{Cn: sudorule}, &freeipa.SudoruleAddRunasgroupOptionalArgs{Group: members})
+	if err != nil {
+		return nil, err
+	}
+	return res.Failed, nil
+}
+
+func (r *SudoRuleRunas) remove(ctx context.Context, sudorule, attr, member string) (freeipa.FailedOperations, error) {
+	members := &[]string{member}
+
+	tflog.Trace(ctx, "Removing sudo rule "+r.kind.name, map[string]any{
+		"sudorule": sudorule,
+		attr:       member,
+	})
+
+	client := r.provider.Client()
+
+	if r.kind.name == "runasuser" {
+		optArgs := &freeipa.SudoruleRemoveRunasuserOptionalArgs{}
+		if attr == "user" {
+			optArgs.User = members
+		} else {
+			optArgs.Group = members
+		}
+
+		res, err := client.SudoruleRemoveRunasuser(&freeipa.SudoruleRemoveRunasuserArgs{Cn: sudorule}, optArgs)
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	}
+
+	res, err := client.SudoruleRemoveRunasgroup(&freeipa.SudoruleRemoveRunasgroupArgs{Cn: sudorule}, &freeipa.SudoruleRemoveRunasgroupOptionalArgs{Group: members})
+	if err != nil {
+		return nil, err
+	}
+	return res.Failed, nil
+}
+
+// members returns the values of the sudo rule for the given attribute,
+// including the external users and groups.
+func (r *SudoRuleRunas) members(rule *freeipa.Sudorule, attr string) []string {
+	var lists []*[]string
+	var category *string
+
+	if r.kind.name == "runasuser" {
+		switch attr {
+		case "user":
+			lists = []*[]string{rule.IpasudorunasUser, rule.Ipasudorunasextuser}
+		case "group":
+			lists = []*[]string{rule.IpasudorunasGroup, rule.Ipasudorunasextusergroup}
+		}
+		category = rule.Ipasudorunasusercategory
+	} else {
+		lists = []*[]string{rule.IpasudorunasgroupGroup, rule.Ipasudorunasextgroup}
+		category = rule.Ipasudorunasgroupcategory
+	}
+
+	if attr == sudoRuleRunasCategory {
+		if category != nil {
+			return []string{*category}
+		}
+
+		return nil
+	}
+
+	var members []string
+
+	for _, l := range lists {
+		if l != nil {
+			members = append(members, *l...)
+		}
+	}
+
+	return members
+}
+
+func newSudoRuleRunas(kind sudoRuleRunasKind) func(*provider.Provider) resource.Resource {
+	return func(p *provider.Provider) resource.Resource {
+		r := &SudoRuleRunas{
+			provider: p,
+			kind:     kind,
+		}
+
+		var _ resource.Resource = r
+		var _ resource.ResourceWithValidateConfig = r
+		var _ resource.ResourceWithImportState = r
+
+		return r
+	}
+}
+
+func init() {
+	for _, kind := range sudoRuleRunasKinds {
+		resources = append(resources, newSudoRuleRunas(kind))
+	}
+}
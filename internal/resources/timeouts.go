@@ -0,0 +1,84 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultTimeout is the time an operation may take when its timeout is not
+// configured, the default of the resources using the legacy SDK.
+const defaultTimeout = 20 * time.Minute
+
+// timeoutsBlock returns the `timeouts` block of the operations, durations
+// such as `30s` or `2h` like the ones of the resources using the legacy SDK.
+func timeoutsBlock(operations ...string) schema.SingleNestedBlock {
+	attrs := make(map[string]schema.Attribute, len(operations))
+
+	for _, o := range operations {
+		attrs[o] = schema.StringAttribute{
+			Description: fmt.Sprintf("Time the %s may take, a duration such as `30s` or `2h`. Defaults to `%s`", o, defaultTimeout),
+			Optional:    true,
+		}
+	}
+
+	return schema.SingleNestedBlock{
+		Description: "Timeouts of the operations",
+		Attributes:  attrs,
+	}
+}
+
+// timeoutsNull returns the value of a `timeouts` block which is not
+// configured, e.g. in an imported state.
+func timeoutsNull(operations ...string) types.Object {
+	attrTypes := make(map[string]attr.Type, len(operations))
+
+	for _, o := range operations {
+		attrTypes[o] = types.StringType
+	}
+
+	return types.ObjectNull(attrTypes)
+}
+
+// validateTimeouts reports the timeouts which are not durations.
+func validateTimeouts(timeouts types.Object, diags *diag.Diagnostics) {
+	if timeouts.IsNull() || timeouts.IsUnknown() {
+		return
+	}
+
+	for o, v := range timeouts.Attributes() {
+		s, ok := v.(types.String)
+		if !ok || s.IsNull() || s.IsUnknown() {
+			continue
+		}
+
+		if _, err := time.ParseDuration(s.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("timeouts").AtName(o),
+				"Invalid configuration",
+				fmt.Sprintf("The “%s” timeout is not a duration: %s.", o, err.Error()),
+			)
+		}
+	}
+}
+
+// withTimeout returns ctx bound to the timeout of operation in timeouts, or
+// to defaultTimeout when it is not configured.
+func withTimeout(ctx context.Context, timeouts types.Object, operation string) (context.Context, context.CancelFunc) {
+	timeout := defaultTimeout
+
+	if s, ok := timeouts.Attributes()[operation].(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+		// The configuration is validated beforehand
+		if d, err := time.ParseDuration(s.ValueString()); err == nil {
+			timeout = d
+		}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,420 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	topologySuffixes   = []string{"domain", "ca"}
+	topologyDirections = []string{"both", "left-right", "right-left"}
+)
+
+type Topologysegment struct {
+	provider *provider.Provider
+}
+
+type TopologysegmentModel struct {
+	Suffix    types.String `tfsdk:"topologysuffixcn"`
+	Name      types.String `tfsdk:"cn"`
+	LeftNode  types.String `tfsdk:"iparepltoposegmentleftnode"`
+	RightNode types.String `tfsdk:"iparepltoposegmentrightnode"`
+	Direction types.String `tfsdk:"iparepltoposegmentdirection"`
+	Enabled   types.Bool   `tfsdk:"nsds5replicaenabled"`
+	Timeout   types.Int64  `tfsdk:"nsds5replicatimeout"`
+}
+
+func (r *Topologysegment) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_topologysegment"
+}
+
+func (r *Topologysegment) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA replication topology segment between two servers.",
+		Attributes: map[string]schema.Attribute{
+			"topologysuffixcn": schema.StringAttribute{
+				Description: "Replicated suffix, one of " + strings.Join(topologySuffixes, ", "),
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cn": schema.StringAttribute{
+				Description: "Segment name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"iparepltoposegmentleftnode": schema.StringAttribute{
+				Description: "Hostname of the left replication node",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"iparepltoposegmentrightnode": schema.StringAttribute{
+				Description: "Hostname of the right replication node",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"iparepltoposegmentdirection": schema.StringAttribute{
+				Description: "Replication direction, one of " + strings.Join(topologyDirections, ", ") + ". Defaults to `both`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"nsds5replicaenabled": schema.BoolAttribute{
+				Description: "Whether replication is enabled on the segment. Defaults to `true`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"nsds5replicatimeout": schema.Int64Attribute{
+				Description: "Number of seconds outbound LDAP operations wait for a response from the remote replica",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *Topologysegment) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config TopologysegmentModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Suffix.IsUnknown() && !slices.Contains(topologySuffixes, config.Suffix.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("topologysuffixcn"),
+			"Invalid configuration",
+			fmt.Sprintf("Unsupported suffix “%s”, expected one of %s.", config.Suffix.ValueString(), strings.Join(topologySuffixes, ", ")),
+		)
+	}
+
+	if !config.Direction.IsUnknown() && !config.Direction.IsNull() && !slices.Contains(topologyDirections, config.Direction.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("iparepltoposegmentdirection"),
+			"Invalid configuration",
+			fmt.Sprintf("Unsupported direction “%s”, expected one of %s.", config.Direction.ValueString(), strings.Join(topologyDirections, ", ")),
+		)
+	}
+
+	if !config.LeftNode.IsUnknown() && !config.RightNode.IsUnknown() && strings.EqualFold(config.LeftNode.ValueString(), config.RightNode.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("iparepltoposegmentrightnode"),
+			"Invalid configuration",
+			`“iparepltoposegmentleftnode” and “iparepltoposegmentrightnode” must be different servers.`,
+		)
+	}
+
+	if !config.Timeout.IsUnknown() && !config.Timeout.IsNull() && config.Timeout.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("nsds5replicatimeout"),
+			"Invalid configuration",
+			`“nsds5replicatimeout” cannot be negative.`,
+		)
+	}
+}
+
+func (r *Topologysegment) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan TopologysegmentModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.TopologysegmentAddArgs{
+		Topologysuffixcn:            plan.Suffix.ValueString(),
+		Iparepltoposegmentleftnode:  plan.LeftNode.ValueString(),
+		Iparepltoposegmentrightnode: plan.RightNode.ValueString(),
+	}
+
+	optArgs := &freeipa.TopologysegmentAddOptionalArgs{
+		Cn:                          plan.Name.ValueStringPointer(),
+		Iparepltoposegmentdirection: stringToStringPointer(plan.Direction),
+		Nsds5replicatimeout:         int64ToIntPointer(plan.Timeout),
+	}
+
+	if !plan.Enabled.IsUnknown() && !plan.Enabled.IsNull() {
+		optArgs.Nsds5replicaenabled = freeipa.String(topologyEnabledValue(plan.Enabled.ValueBool()))
+	}
+
+	tflog.Trace(ctx, "Calling TopologysegmentAdd", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().TopologysegmentAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called TopologysegmentAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create topology segment", "Reason: "+err.Error())
+
+		return
+	}
+
+	topologysegmentState(&plan, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Topologysegment) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state TopologysegmentModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.TopologysegmentShowArgs{
+		Topologysuffixcn: state.Suffix.ValueString(),
+	}
+
+	optArgs := &freeipa.TopologysegmentShowOptionalArgs{
+		Cn:  state.Name.ValueStringPointer(),
+		All: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling TopologysegmentShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().TopologysegmentShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called TopologysegmentShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read topology segment", "Reason: "+err.Error())
+
+		return
+	}
+
+	topologysegmentState(&state, &res.Result)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Topologysegment) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan TopologysegmentModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.TopologysegmentModArgs{
+		Topologysuffixcn: plan.Suffix.ValueString(),
+	}
+
+	optArgs := &freeipa.TopologysegmentModOptionalArgs{
+		Cn: plan.Name.ValueStringPointer(),
+	}
+
+	hasDiff := false
+
+	if !plan.Enabled.IsUnknown() && !plan.Enabled.Equal(state.Enabled) {
+		optArgs.Nsds5replicaenabled = freeipa.String(topologyEnabledValue(plan.Enabled.ValueBool()))
+		hasDiff = true
+	}
+
+	// Integers cannot be sent empty, a removed timeout is cleared with setattr
+	if !plan.Timeout.Equal(state.Timeout) {
+		if plan.Timeout.IsNull() {
+			optArgs.Setattr = &[]string{"nsds5replicatimeout="}
+		} else {
+			optArgs.Nsds5replicatimeout = int64ToIntPointer(plan.Timeout)
+		}
+
+		hasDiff = true
+	}
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling TopologysegmentMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().TopologysegmentMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called TopologysegmentMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update topology segment", "Reason: "+err.Error())
+
+			return
+		}
+
+		topologysegmentState(&plan, &res.Result)
+	} else {
+		tflog.Debug(ctx, "Updated topology segment has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+
+		plan.Enabled = state.Enabled
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Topologysegment) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state TopologysegmentModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.TopologysegmentDelArgs{
+		Topologysuffixcn: state.Suffix.ValueString(),
+	}
+
+	optArgs := &freeipa.TopologysegmentDelOptionalArgs{
+		Cn: &[]string{state.Name.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling TopologysegmentDel", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().TopologysegmentDel(args, optArgs)
+
+	tflog.Trace(ctx, "Called TopologysegmentDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			return
+		}
+
+		// FreeIPA refuses to remove the segments which would disconnect a
+		// replica from the topology
+		if errors.As(err, &freeipaErr) && (freeipaErr.Code == freeipa.ValidationErrorCode || strings.Contains(freeipaErr.Message, "disconnects topology")) {
+			resp.Diagnostics.AddError(
+				"Topology segment removal would disconnect a replica",
+				"Reason: "+err.Error()+". Connect the servers of the segment through another segment before removing it.",
+			)
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to delete topology segment", "Reason: "+err.Error())
+	}
+}
+
+func (r *Topologysegment) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	suffix, name, ok := strings.Cut(req.ID, "/")
+
+	if !ok || suffix == "" || name == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<suffix>/<segment>”, got %q.", req.ID),
+		)
+
+		return
+	}
+
+	state := TopologysegmentModel{
+		Suffix:    types.StringValue(suffix),
+		Name:      types.StringValue(name),
+		Direction: types.StringNull(),
+		Enabled:   types.BoolNull(),
+		Timeout:   types.Int64Null(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// topologysegmentState copies segment into state, keeping the configured case
+// of the node hostnames.
+func topologysegmentState(state *TopologysegmentModel, segment *freeipa.Topologysegment) {
+	state.Name = types.StringValue(segment.Cn)
+
+	if !strings.EqualFold(state.LeftNode.ValueString(), segment.Iparepltoposegmentleftnode) {
+		state.LeftNode = types.StringValue(segment.Iparepltoposegmentleftnode)
+	}
+
+	if !strings.EqualFold(state.RightNode.ValueString(), segment.Iparepltoposegmentrightnode) {
+		state.RightNode = types.StringValue(segment.Iparepltoposegmentrightnode)
+	}
+
+	state.Direction = types.StringValue(segment.Iparepltoposegmentdirection)
+	state.Enabled = types.BoolValue(segment.Nsds5replicaenabled == nil || strings.EqualFold(*segment.Nsds5replicaenabled, "on"))
+	state.Timeout = intToInt64Value(segment.Nsds5replicatimeout)
+}
+
+func topologyEnabledValue(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+
+	return "off"
+}
+
+func NewTopologysegment(p *provider.Provider) resource.Resource {
+	r := &Topologysegment{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewTopologysegment)
+}
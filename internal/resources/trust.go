@@ -0,0 +1,516 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// trustTypeAD is the only trust type supported by FreeIPA
+	trustTypeAD = "ad"
+
+	// trustDirectionTwoWay is the direction FreeIPA reports for bidirectional
+	// trusts
+	trustDirectionTwoWay = "Two-way trust"
+)
+
+type Trust struct {
+	provider *provider.Provider
+}
+
+type TrustModel struct {
+	Realm              types.String `tfsdk:"cn"`
+	TrustType          types.String `tfsdk:"trust_type"`
+	RealmAdmin         types.String `tfsdk:"realm_admin"`
+	RealmPassword      types.String `tfsdk:"realm_passwd"`
+	RealmServer        types.String `tfsdk:"realm_server"`
+	TrustSecret        types.String `tfsdk:"trust_secret"`
+	Bidirectional      types.Bool   `tfsdk:"bidirectional"`
+	RangeType          types.String `tfsdk:"range_type"`
+	SIDBlacklistIn     types.List   `tfsdk:"ipantsidblacklistincoming"`
+	SIDBlacklistOut    types.List   `tfsdk:"ipantsidblacklistoutgoing"`
+	AdditionalSuffixes types.List   `tfsdk:"ipantadditionalsuffixes"`
+	FlatName           types.String `tfsdk:"ipantflatname"`
+	DomainSID          types.String `tfsdk:"ipanttrusteddomainsid"`
+	Direction          types.String `tfsdk:"trustdirection"`
+	Status             types.String `tfsdk:"truststatus"`
+}
+
+func (r *Trust) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_trust"
+}
+
+func (r *Trust) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a trust between FreeIPA and an Active Directory domain.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Realm name of the trusted domain",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"trust_type": schema.StringAttribute{
+				Description: "Trust type, only `ad` is supported. Defaults to `ad`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"realm_admin": schema.StringAttribute{
+				Description: "Active Directory domain administrator used to establish the trust",
+				Optional:    true,
+			},
+			"realm_passwd": schema.StringAttribute{
+				Description: "Password of the Active Directory domain administrator. It is only sent to FreeIPA on creation and never read back",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"realm_server": schema.StringAttribute{
+				Description: "Domain controller of the Active Directory domain, discovered by DNS when unset",
+				Optional:    true,
+			},
+			"trust_secret": schema.StringAttribute{
+				Description: "Shared secret of a trust created beforehand on the Active Directory side. It is only sent to FreeIPA on creation and never read back",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"bidirectional": schema.BoolAttribute{
+				Description: "Establish a two-way trust, allowing Active Directory to look up FreeIPA users and groups. Defaults to `false`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"range_type": schema.StringAttribute{
+				Description: "Type of the ID range created for the trusted domain (`ipa-ad-trust` or `ipa-ad-trust-posix`), detected by FreeIPA when unset",
+				Optional:    true,
+			},
+			"ipantsidblacklistincoming": schema.ListAttribute{
+				Description: "SIDs filtered out of the authentication data coming from the trusted domain",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipantsidblacklistoutgoing": schema.ListAttribute{
+				Description: "SIDs filtered out of the authentication data sent to the trusted domain",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipantadditionalsuffixes": schema.ListAttribute{
+				Description: "Additional UPN suffixes of the trusted domain",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipantflatname": schema.StringAttribute{
+				Description: "NetBIOS name of the trusted domain",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipanttrusteddomainsid": schema.StringAttribute{
+				Description: "SID of the trusted domain",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"trustdirection": schema.StringAttribute{
+				Description: "Direction of the trust as reported by FreeIPA",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"truststatus": schema.StringAttribute{
+				Description: "Status of the trust as reported by FreeIPA",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *Trust) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config TrustModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.TrustType.IsUnknown() && !config.TrustType.IsNull() && config.TrustType.ValueString() != trustTypeAD {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("trust_type"),
+			"Invalid configuration",
+			`The trust type must be “ad”.`,
+		)
+	}
+
+	if !config.RangeType.IsUnknown() && !config.RangeType.IsNull() &&
+		!slices.Contains([]string{"ipa-ad-trust", "ipa-ad-trust-posix"}, config.RangeType.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("range_type"),
+			"Invalid configuration",
+			`The range type must be one of “ipa-ad-trust” and “ipa-ad-trust-posix”.`,
+		)
+	}
+
+	if config.RealmPassword.IsUnknown() || config.TrustSecret.IsUnknown() {
+		return
+	}
+
+	if config.RealmPassword.IsNull() == config.TrustSecret.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("realm_passwd"),
+			"Invalid configuration",
+			`Exactly one of “realm_passwd” and “trust_secret” must be set.`,
+		)
+	}
+
+	if !config.RealmAdmin.IsNull() && config.RealmPassword.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("realm_admin"),
+			"Invalid configuration",
+			`“realm_admin” requires “realm_passwd” to be set.`,
+		)
+	}
+}
+
+func (r *Trust) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan TrustModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.TrustType.IsUnknown() {
+		plan.TrustType = types.StringValue(trustTypeAD)
+	}
+
+	args := &freeipa.TrustAddArgs{
+		Cn: plan.Realm.ValueString(),
+	}
+
+	optArgs := &freeipa.TrustAddOptionalArgs{
+		TrustType:     plan.TrustType.ValueStringPointer(),
+		RealmAdmin:    plan.RealmAdmin.ValueStringPointer(),
+		RealmPasswd:   plan.RealmPassword.ValueStringPointer(),
+		RealmServer:   plan.RealmServer.ValueStringPointer(),
+		TrustSecret:   plan.TrustSecret.ValueStringPointer(),
+		RangeType:     plan.RangeType.ValueStringPointer(),
+		Bidirectional: plan.Bidirectional.ValueBoolPointer(),
+	}
+
+	// The credentials are kept out of the logs
+	tflog.Trace(ctx, "Calling TrustAdd", map[string]any{
+		"args": args,
+		"opt_args": map[string]any{
+			"trust_type":    optArgs.TrustType,
+			"realm_admin":   optArgs.RealmAdmin,
+			"realm_server":  optArgs.RealmServer,
+			"range_type":    optArgs.RangeType,
+			"bidirectional": optArgs.Bidirectional,
+		},
+	})
+
+	res, err := r.provider.Client().TrustAdd(args, optArgs)
+
+	tflog.Trace(ctx, "Called TrustAdd", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create trust", "Reason: "+err.Error())
+
+		return
+	}
+
+	// The SID blacklists and suffixes cannot be set by TrustAdd
+	trust, diags := r.trustMod(ctx, plan, TrustModel{})
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if trust == nil {
+		if trust, err = r.trustShow(ctx, plan.Realm.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Failed to read trust", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(trustState(ctx, &plan, trust)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Trust) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state TrustModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	trust, err := r.trustShow(ctx, state.Realm.ValueString())
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read trust", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(trustState(ctx, &state, trust)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Trust) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan TrustModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The credentials, domain controller and range type are only used to
+	// establish the trust, changing them has no effect on the existing trust.
+	trust, diags := r.trustMod(ctx, plan, state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if trust == nil {
+		tflog.Debug(ctx, "Updated trust has no effective difference", map[string]any{
+			"cn": plan.Realm.ValueString(),
+		})
+
+		var err error
+
+		if trust, err = r.trustShow(ctx, plan.Realm.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Failed to read trust", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(trustState(ctx, &plan, trust)...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Trust) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state TrustModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.TrustDelArgs{
+		Cn: []string{state.Realm.ValueString()},
+	}
+
+	tflog.Trace(ctx, "Calling TrustDel", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().TrustDel(args, nil)
+
+	tflog.Trace(ctx, "Called TrustDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete trust", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Trust) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The only trust type is known, the other creation arguments cannot be
+	// read back and are taken from the configuration on the next apply.
+	state := TrustModel{
+		Realm:              types.StringValue(req.ID),
+		TrustType:          types.StringValue(trustTypeAD),
+		SIDBlacklistIn:     types.ListNull(types.StringType),
+		SIDBlacklistOut:    types.ListNull(types.StringType),
+		AdditionalSuffixes: types.ListNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// trustMod sends the modifiable attributes of plan which differ from state,
+// and returns a nil result when there is nothing to update.
+func (r *Trust) trustMod(ctx context.Context, plan, state TrustModel) (*freeipa.Trust, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var hasDiff bool
+
+	args := &freeipa.TrustModArgs{
+		Cn: plan.Realm.ValueString(),
+	}
+	optArgs := &freeipa.TrustModOptionalArgs{}
+
+	if !plan.SIDBlacklistIn.IsUnknown() && !plan.SIDBlacklistIn.IsNull() && !plan.SIDBlacklistIn.Equal(state.SIDBlacklistIn) {
+		diags.Append(listToStringSlicePointer(ctx, plan.SIDBlacklistIn, &optArgs.Ipantsidblacklistincoming)...)
+		hasDiff = true
+	}
+
+	if !plan.SIDBlacklistOut.IsUnknown() && !plan.SIDBlacklistOut.IsNull() && !plan.SIDBlacklistOut.Equal(state.SIDBlacklistOut) {
+		diags.Append(listToStringSlicePointer(ctx, plan.SIDBlacklistOut, &optArgs.Ipantsidblacklistoutgoing)...)
+		hasDiff = true
+	}
+
+	if !plan.AdditionalSuffixes.IsUnknown() && !plan.AdditionalSuffixes.IsNull() && !plan.AdditionalSuffixes.Equal(state.AdditionalSuffixes) {
+		diags.Append(listToStringSlicePointer(ctx, plan.AdditionalSuffixes, &optArgs.Ipantadditionalsuffixes)...)
+		hasDiff = true
+	}
+
+	if diags.HasError() || !hasDiff {
+		return nil, diags
+	}
+
+	tflog.Trace(ctx, "Calling TrustMod", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().TrustMod(args, optArgs)
+
+	tflog.Trace(ctx, "Called TrustMod", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		diags.AddError("Failed to update trust", "Reason: "+err.Error())
+
+		return nil, diags
+	}
+
+	return &res.Result, diags
+}
+
+func (r *Trust) trustShow(ctx context.Context, realm string) (*freeipa.Trust, error) {
+	args := &freeipa.TrustShowArgs{
+		Cn: realm,
+	}
+
+	tflog.Trace(ctx, "Calling TrustShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().TrustShow(args, nil)
+
+	tflog.Trace(ctx, "Called TrustShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Result, nil
+}
+
+// trustState sets the attributes of state read back from trust. The
+// credentials are never returned by FreeIPA and are kept as configured.
+func trustState(ctx context.Context, state *TrustModel, trust *freeipa.Trust) diag.Diagnostics {
+	var diags, d diag.Diagnostics
+
+	state.FlatName = types.StringValue(trust.Ipantflatname)
+	state.DomainSID = types.StringValue(trust.Ipanttrusteddomainsid)
+	state.Direction = types.StringValue(trust.Trustdirection)
+	state.Status = types.StringValue(trust.Truststatus)
+	state.Bidirectional = types.BoolValue(trust.Trustdirection == trustDirectionTwoWay)
+
+	state.SIDBlacklistIn, d = stringSliceToList(ctx, knownList(state.SIDBlacklistIn), trust.Ipantsidblacklistincoming)
+	diags.Append(d...)
+	state.SIDBlacklistOut, d = stringSliceToList(ctx, knownList(state.SIDBlacklistOut), trust.Ipantsidblacklistoutgoing)
+	diags.Append(d...)
+	state.AdditionalSuffixes, d = stringSliceToList(ctx, knownList(state.AdditionalSuffixes), trust.Ipantadditionalsuffixes)
+	diags.Append(d...)
+
+	return diags
+}
+
+// knownList returns list, or a null list when it is unknown.
+func knownList(list types.List) types.List {
+	if list.IsUnknown() {
+		return types.ListNull(types.StringType)
+	}
+
+	return list
+}
+
+func NewTrust(p *provider.Provider) resource.Resource {
+	r := &Trust{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewTrust)
+}
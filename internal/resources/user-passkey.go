@@ -0,0 +1,252 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+const passkeyMappingPrefix = "passkey:"
+
+type UserPasskey struct {
+	provider *provider.Provider
+}
+
+type UserPasskeyModel struct {
+	UID     types.String `tfsdk:"uid"`
+	Passkey types.String `tfsdk:"ipapasskey"`
+}
+
+func (r *UserPasskey) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_passkey"
+}
+
+func (r *UserPasskey) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Maps a passkey registered by a FreeIPA user to the user account. Only the server-side mapping is managed, the credential stays on the authenticator.",
+		Attributes: map[string]schema.Attribute{
+			"uid": schema.StringAttribute{
+				Description: "User login",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipapasskey": schema.StringAttribute{
+				Description: "Passkey mapping, as `passkey:<credential ID>,<public key>` reported by `sssctl passkey-register`",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *UserPasskey) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config UserPasskeyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Passkey.IsUnknown() || config.Passkey.IsNull() {
+		return
+	}
+
+	if !strings.HasPrefix(config.Passkey.ValueString(), passkeyMappingPrefix) || !strings.Contains(config.Passkey.ValueString(), ",") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipapasskey"),
+			"Invalid configuration",
+			`“ipapasskey” must be a passkey mapping of the form “passkey:<credential ID>,<public key>”.`,
+		)
+	}
+}
+
+func (r *UserPasskey) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan UserPasskeyModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := []interface{}{plan.UID.ValueString(), []string{plan.Passkey.ValueString()}}
+
+	tflog.Trace(ctx, "Calling user_add_passkey", map[string]any{
+		"args": args,
+	})
+
+	err := r.provider.RPC().Call(ctx, "user_add_passkey", args, nil, nil)
+
+	tflog.Trace(ctx, "Called user_add_passkey", map[string]any{
+		"err": err,
+	})
+
+	// A mapping the user already holds leaves the entry unchanged, treat it as
+	// success to keep the creation idempotent.
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != utils.EmptyModlistCode {
+			resp.Diagnostics.AddError("Failed to add user passkey", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *UserPasskey) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state UserPasskeyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// go-freeipa does not decode the passkeys of a user
+	args := []interface{}{state.UID.ValueString()}
+	options := map[string]interface{}{"all": true}
+
+	tflog.Trace(ctx, "Calling user_show", map[string]any{
+		"args":    args,
+		"options": options,
+	})
+
+	var res struct {
+		Result struct {
+			Ipapasskey []string `json:"ipapasskey"`
+		} `json:"result"`
+	}
+
+	err := r.provider.RPC().Call(ctx, "user_show", args, options, &res)
+
+	tflog.Trace(ctx, "Called user_show", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read user", "Reason: "+err.Error())
+
+		return
+	}
+
+	if !slices.Contains(res.Result.Ipapasskey, state.Passkey.ValueString()) {
+		tflog.Debug(ctx, "User no longer holds passkey", map[string]any{
+			"uid": state.UID.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *UserPasskey) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan UserPasskeyModel
+
+	// All attributes require replacement, there is nothing to update
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *UserPasskey) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state UserPasskeyModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := []interface{}{state.UID.ValueString(), []string{state.Passkey.ValueString()}}
+
+	tflog.Trace(ctx, "Calling user_remove_passkey", map[string]any{
+		"args": args,
+	})
+
+	err := r.provider.RPC().Call(ctx, "user_remove_passkey", args, nil, nil)
+
+	tflog.Trace(ctx, "Called user_remove_passkey", map[string]any{
+		"err": err,
+	})
+
+	// Users and mappings removed out-of-band are ignored
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || (freeipaErr.Code != freeipa.NotFoundCode && freeipaErr.Code != utils.AttrValueNotFoundCode) {
+			resp.Diagnostics.AddError("Failed to remove user passkey", "Reason: "+err.Error())
+		}
+	}
+}
+
+func (r *UserPasskey) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The mapping holds base64 data which may contain slashes, user logins
+	// cannot
+	uid, passkey, ok := strings.Cut(req.ID, "/")
+
+	if !ok || uid == "" || passkey == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<uid>/<passkey mapping>”, got %q.", req.ID),
+		)
+
+		return
+	}
+
+	state := UserPasskeyModel{
+		UID:     types.StringValue(uid),
+		Passkey: types.StringValue(passkey),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func NewUserPasskey(p *provider.Provider) resource.Resource {
+	r := &UserPasskey{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewUserPasskey)
+}
@@ -0,0 +1,2267 @@
+package resources
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+var userAuthTypes = []string{"password", "radius", "otp", "pkinit", "hardened", "idp", "passkey"}
+
+type User struct {
+	provider *provider.Provider
+}
+
+type UserModel struct {
+	UID               types.String `tfsdk:"uid"`
+	GivenName         types.String `tfsdk:"givenname"`
+	Surname           types.String `tfsdk:"sn"`
+	FullName          types.String `tfsdk:"cn"`
+	DisplayName       types.String `tfsdk:"displayname"`
+	Initials          types.String `tfsdk:"initials"`
+	Gecos             types.String `tfsdk:"gecos"`
+	HomeDirectory     types.String `tfsdk:"homedirectory"`
+	LoginShell        types.String `tfsdk:"loginshell"`
+	Mail              types.List   `tfsdk:"mail"`
+	TelephoneNumber   types.List   `tfsdk:"telephonenumber"`
+	Mobile            types.List   `tfsdk:"mobile"`
+	Title             types.String `tfsdk:"title"`
+	Manager           types.Set    `tfsdk:"manager"`
+	EmployeeNumber    types.String `tfsdk:"employeenumber"`
+	EmployeeType      types.String `tfsdk:"employeetype"`
+	DepartmentNumber  types.Set    `tfsdk:"departmentnumber"`
+	PreferredLanguage types.String `tfsdk:"preferredlanguage"`
+	OrgUnit           types.String `tfsdk:"ou"`
+	Street            types.String `tfsdk:"street"`
+	City              types.String `tfsdk:"l"`
+	Province          types.String `tfsdk:"st"`
+	PostalCode        types.String `tfsdk:"postalcode"`
+	UserPassword      types.String `tfsdk:"userpassword"`
+	Random            types.Bool   `tfsdk:"random"`
+	RandomPassword    types.String `tfsdk:"randompassword"`
+	PrincipalName     types.Set    `tfsdk:"krbprincipalname"`
+	PrincipalExpiry   types.String `tfsdk:"krbprincipalexpiration"`
+	PasswordExpiry    types.String `tfsdk:"krbpasswordexpiration"`
+	CarLicense        types.List   `tfsdk:"carlicense"`
+	UIDNumber         types.Int64  `tfsdk:"uidnumber"`
+	GIDNumber         types.Int64  `tfsdk:"gidnumber"`
+	AccountLocked     types.Bool   `tfsdk:"nsaccountlock"`
+	SSHPublicKeys     types.List   `tfsdk:"ipasshpubkey"`
+	ManageSSHKeys     types.Bool   `tfsdk:"manage_ssh_keys"`
+	CertMapData       types.Set    `tfsdk:"ipacertmapdata"`
+	ManageCertMap     types.Bool   `tfsdk:"manage_certmapdata"`
+	UserAuthType      types.Set    `tfsdk:"ipauserauthtype"`
+	UserClass         types.Set    `tfsdk:"userclass"`
+	UserCertificate   types.Set    `tfsdk:"usercertificate"`
+	RadiusProxy       types.String `tfsdk:"ipatokenradiusconfiglink"`
+	RadiusUsername    types.String `tfsdk:"ipatokenradiususername"`
+	IdpConfigLink     types.String `tfsdk:"ipaidpconfiglink"`
+	IdpSub            types.String `tfsdk:"ipaidpsub"`
+	Preserve          types.Bool   `tfsdk:"preserve"`
+	Undelete          types.Bool   `tfsdk:"undelete"`
+}
+
+// UserCertMapDataModel is a certificate mapping entry, given either as raw
+// mapping data, as a certificate or as an issuer and subject pair.
+type UserCertMapDataModel struct {
+	Data        types.String `tfsdk:"data"`
+	Certificate types.String `tfsdk:"certificate"`
+	Issuer      types.String `tfsdk:"issuer"`
+	Subject     types.String `tfsdk:"subject"`
+}
+
+var userCertMapDataType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"data":        types.StringType,
+		"certificate": types.StringType,
+		"issuer":      types.StringType,
+		"subject":     types.StringType,
+	},
+}
+
+// emptyCertMapData is the value of ipacertmapdata without entries, blocks
+// being empty rather than null when they are not configured.
+func emptyCertMapData() types.Set {
+	return types.SetValueMust(userCertMapDataType, []attr.Value{})
+}
+
+func (r *User) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (r *User) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+		Attributes: map[string]schema.Attribute{
+			"uid": schema.StringAttribute{
+				Description: "User login. Changing it renames the user in place, keeping its group memberships",
+				Required:    true,
+			},
+			"givenname": schema.StringAttribute{
+				Description: "First name",
+				Required:    true,
+			},
+			"sn": schema.StringAttribute{
+				Description: "Last name",
+				Required:    true,
+			},
+			"cn": schema.StringAttribute{
+				Description: "Full name",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"displayname": schema.StringAttribute{
+				Description: "Display name",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"initials": schema.StringAttribute{
+				Description: "Initials",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"gecos": schema.StringAttribute{
+				Description: "GECOS field",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"homedirectory": schema.StringAttribute{
+				Description: "Home directory",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"loginshell": schema.StringAttribute{
+				Description: "Login shell",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"mail": schema.ListAttribute{
+				Description: "Email addresses",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"telephonenumber": schema.ListAttribute{
+				Description: "Telephone numbers",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"mobile": schema.ListAttribute{
+				Description: "Mobile telephone numbers",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"title": schema.StringAttribute{
+				Description: "Job title",
+				Optional:    true,
+			},
+			"manager": schema.SetAttribute{
+				Description: "Logins of the managers of the user, which must exist. An empty set removes them",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"employeenumber": schema.StringAttribute{
+				Description: "Employee number",
+				Optional:    true,
+			},
+			"employeetype": schema.StringAttribute{
+				Description: "Employee type, e.g. `contractor`",
+				Optional:    true,
+			},
+			"departmentnumber": schema.SetAttribute{
+				Description: "Department numbers. An empty set removes them",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"preferredlanguage": schema.StringAttribute{
+				Description: "Preferred language, as an `Accept-Language` value such as `en-US`",
+				Optional:    true,
+			},
+			"ou": schema.StringAttribute{
+				Description: "Organisational unit",
+				Optional:    true,
+			},
+			"street": schema.StringAttribute{
+				Description: "Street address",
+				Optional:    true,
+			},
+			"l": schema.StringAttribute{
+				Description: "City",
+				Optional:    true,
+			},
+			"st": schema.StringAttribute{
+				Description: "State/Province",
+				Optional:    true,
+			},
+			"postalcode": schema.StringAttribute{
+				Description: "ZIP code",
+				Optional:    true,
+			},
+			"userpassword": schema.StringAttribute{
+				Description: "User password. It is only sent to FreeIPA on creation or when changed.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"random": schema.BoolAttribute{
+				Description: "Generate a random user password on creation, or when set to true afterwards. Conflicts with `userpassword`",
+				Optional:    true,
+			},
+			"randompassword": schema.StringAttribute{
+				Description: "Password generated by FreeIPA when `random` is set",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"krbprincipalname": schema.SetAttribute{
+				Description: "Kerberos principal aliases of the user, the realm being added by FreeIPA when omitted",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"krbprincipalexpiration": schema.StringAttribute{
+				Description: "Kerberos principal expiration, as an RFC3339 timestamp such as `2049-12-31T23:59:59Z`",
+				Optional:    true,
+			},
+			"krbpasswordexpiration": schema.StringAttribute{
+				Description: "User password expiration, as an RFC3339 timestamp such as `2049-12-31T23:59:59Z`",
+				Optional:    true,
+			},
+			"carlicense": schema.ListAttribute{
+				Description: "Car licenses",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"uidnumber": schema.Int64Attribute{
+				Description: "User ID number (assigned by FreeIPA when not set)",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"gidnumber": schema.Int64Attribute{
+				Description: "Group ID number (assigned by FreeIPA when not set)",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"nsaccountlock": schema.BoolAttribute{
+				Description: "Whether the account is disabled. The account is locked and unlocked with the user-disable and user-enable commands, leaving the other attributes untouched",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ipasshpubkey": schema.ListAttribute{
+				Description: "SSH public keys, compared without their options and comment. Keys added outside of Terraform are kept unless `manage_ssh_keys` is set",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"manage_ssh_keys": schema.BoolAttribute{
+				Description: "Manage the exact set of SSH public keys of the user, removing the keys which are not in `ipasshpubkey`. Defaults to false",
+				Optional:    true,
+			},
+			"manage_certmapdata": schema.BoolAttribute{
+				Description: "Manage the exact set of certificate mapping data of the user, removing the entries which are not in `ipacertmapdata`. Defaults to false",
+				Optional:    true,
+			},
+			"ipauserauthtype": schema.SetAttribute{
+				Description: "Authentication types allowed for the user, any of " + strings.Join(userAuthTypes, ", ") + ". The global default applies when empty, an empty set removes them",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"userclass": schema.SetAttribute{
+				Description: "User categories, free-form values such as used by automember rules. An empty set removes them",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"usercertificate": schema.SetAttribute{
+				Description: "Certificates of the user, base64 encoded DER or PEM, compared by their DER encoding. Certificates added outside of Terraform, such as those issued by the FreeIPA CA, are kept",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"ipatokenradiusconfiglink": schema.StringAttribute{
+				Description: "RADIUS proxy server the user is authenticated against, see `freeipa_radiusproxy`",
+				Optional:    true,
+			},
+			"ipatokenradiususername": schema.StringAttribute{
+				Description: "User name sent to the RADIUS proxy server, defaults to the user login",
+				Optional:    true,
+			},
+			"ipaidpconfiglink": schema.StringAttribute{
+				Description: "External identity provider the user is authenticated against, see `freeipa_idp`",
+				Optional:    true,
+			},
+			"ipaidpsub": schema.StringAttribute{
+				Description: "Identifier of the user at the external identity provider, defaults to the user login",
+				Optional:    true,
+			},
+			"preserve": schema.BoolAttribute{
+				Description: "Preserve the user when the resource is destroyed, moving it to the preserved users instead of deleting it permanently. Defaults to false",
+				Optional:    true,
+			},
+			"undelete": schema.BoolAttribute{
+				Description: "Restore a preserved user with the same login instead of failing to create the user, its attributes are then updated to match the configuration. Defaults to false",
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"ipacertmapdata": schema.SetNestedBlock{
+				Description: "Certificate mapping data binding smart card certificates to the user. Entries added outside of Terraform are kept unless `manage_certmapdata` is set",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"data": schema.StringAttribute{
+							Description: "Raw mapping data, such as `X509:<I>O=EXAMPLE.COM,CN=Certificate Authority<S>O=EXAMPLE.COM,CN=jdoe`. Conflicts with the other attributes",
+							Optional:    true,
+						},
+						"certificate": schema.StringAttribute{
+							Description: "PEM or base64 encoded certificate, whose issuer and subject are mapped to the user. Conflicts with the other attributes",
+							Optional:    true,
+						},
+						"issuer": schema.StringAttribute{
+							Description: "Issuer of the mapped certificates, in LDAP order. Requires `subject`",
+							Optional:    true,
+						},
+						"subject": schema.StringAttribute{
+							Description: "Subject of the mapped certificates, in LDAP order. Requires `issuer`",
+							Optional:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *User) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config UserModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Random.ValueBool() && !config.UserPassword.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("random"),
+			"Invalid configuration",
+			"“random” conflicts with “userpassword”, a password is either given or generated.",
+		)
+	}
+
+	expirations := []struct {
+		name  string
+		value types.String
+	}{
+		{"krbprincipalexpiration", config.PrincipalExpiry},
+		{"krbpasswordexpiration", config.PasswordExpiry},
+	}
+
+	for _, e := range expirations {
+		if e.value.IsNull() || e.value.IsUnknown() {
+			continue
+		}
+
+		if _, err := time.Parse(time.RFC3339, e.value.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(e.name),
+				"Invalid configuration",
+				fmt.Sprintf("The “%s” timestamp could not be parsed as RFC3339: %s.", e.name, err.Error()),
+			)
+		}
+	}
+
+	if !config.UserAuthType.IsUnknown() && !config.UserAuthType.IsNull() {
+		var authTypes []string
+
+		resp.Diagnostics.Append(config.UserAuthType.ElementsAs(ctx, &authTypes, false)...)
+
+		for _, authType := range authTypes {
+			if !slices.Contains(userAuthTypes, authType) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("ipauserauthtype"),
+					"Invalid configuration",
+					fmt.Sprintf("Unsupported authentication type “%s”, expected any of %s.", authType, strings.Join(userAuthTypes, ", ")),
+				)
+			}
+		}
+	}
+
+	if !config.CertMapData.IsUnknown() && !config.CertMapData.IsNull() {
+		var entries []UserCertMapDataModel
+
+		resp.Diagnostics.Append(config.CertMapData.ElementsAs(ctx, &entries, false)...)
+
+		for _, e := range entries {
+			if msg := validateCertMapData(e); msg != "" {
+				resp.Diagnostics.AddAttributeError(path.Root("ipacertmapdata"), "Invalid configuration", msg)
+			}
+		}
+	}
+
+	if !config.UserCertificate.IsUnknown() && !config.UserCertificate.IsNull() {
+		var certificates []string
+
+		resp.Diagnostics.Append(config.UserCertificate.ElementsAs(ctx, &certificates, false)...)
+
+		for _, c := range certificates {
+			if _, err := utils.ParseCertificate(c); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("usercertificate"),
+					"Invalid configuration",
+					fmt.Sprintf("Invalid certificate: %s.", err.Error()),
+				)
+			}
+		}
+	}
+}
+
+func (r *User) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan, state UserModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.UserAddArgs{
+		Givenname: plan.GivenName.ValueString(),
+		Sn:        plan.Surname.ValueString(),
+	}
+
+	optArgs := &freeipa.UserAddOptionalArgs{
+		UID:           plan.UID.ValueStringPointer(),
+		Cn:            plan.FullName.ValueStringPointer(),
+		Displayname:   plan.DisplayName.ValueStringPointer(),
+		Initials:      plan.Initials.ValueStringPointer(),
+		Gecos:         plan.Gecos.ValueStringPointer(),
+		Homedirectory: plan.HomeDirectory.ValueStringPointer(),
+		Loginshell:    plan.LoginShell.ValueStringPointer(),
+		Title:         plan.Title.ValueStringPointer(),
+		Ou:            plan.OrgUnit.ValueStringPointer(),
+		Street:        plan.Street.ValueStringPointer(),
+		L:             plan.City.ValueStringPointer(),
+		St:            plan.Province.ValueStringPointer(),
+		Postalcode:    plan.PostalCode.ValueStringPointer(),
+		Userpassword:  plan.UserPassword.ValueStringPointer(),
+		Random:        plan.Random.ValueBoolPointer(),
+		Uidnumber:     int64ToIntPointer(plan.UIDNumber),
+		Gidnumber:     int64ToIntPointer(plan.GIDNumber),
+		All:           freeipa.Bool(true),
+
+		Ipatokenradiusconfiglink: plan.RadiusProxy.ValueStringPointer(),
+		Ipatokenradiususername:   plan.RadiusUsername.ValueStringPointer(),
+		Ipaidpconfiglink:         plan.IdpConfigLink.ValueStringPointer(),
+		Ipaidpsub:                plan.IdpSub.ValueStringPointer(),
+
+		Employeenumber:    plan.EmployeeNumber.ValueStringPointer(),
+		Employeetype:      plan.EmployeeType.ValueStringPointer(),
+		Preferredlanguage: plan.PreferredLanguage.ValueStringPointer(),
+
+		Krbprincipalexpiration: stringToTimePointer(plan.PrincipalExpiry),
+		Krbpasswordexpiration:  stringToTimePointer(plan.PasswordExpiry),
+	}
+
+	resp.Diagnostics.Append(listToStringSlicePointer(ctx, plan.Mail, &optArgs.Mail)...)
+	resp.Diagnostics.Append(listToStringSlicePointer(ctx, plan.TelephoneNumber, &optArgs.Telephonenumber)...)
+	resp.Diagnostics.Append(listToStringSlicePointer(ctx, plan.Mobile, &optArgs.Mobile)...)
+	resp.Diagnostics.Append(listToStringSlicePointer(ctx, plan.SSHPublicKeys, &optArgs.Ipasshpubkey)...)
+	resp.Diagnostics.Append(listToStringSlicePointer(ctx, plan.CarLicense, &optArgs.Carlicense)...)
+
+	if !plan.UserAuthType.IsUnknown() {
+		optArgs.Ipauserauthtype = setToStringSlicePointer(ctx, plan.UserAuthType, &resp.Diagnostics)
+	}
+
+	optArgs.Userclass = setToStringSlicePointer(ctx, plan.UserClass, &resp.Diagnostics)
+	optArgs.Krbprincipalname = setToStringSlicePointer(ctx, plan.PrincipalName, &resp.Diagnostics)
+	optArgs.Departmentnumber = setToStringSlicePointer(ctx, plan.DepartmentNumber, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// FreeIPA only reports a duplicated UID number late and cryptically, the
+	// user holding it is looked up first
+	if !plan.UIDNumber.IsNull() && !plan.UIDNumber.IsUnknown() {
+		owner, err := r.uidNumberOwner(ctx, int(plan.UIDNumber.ValueInt64()))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to check UID number", "Reason: "+err.Error())
+
+			return
+		}
+
+		// FreeIPA reports a user with the same login clearly on its own
+		if owner != "" && owner != plan.UID.ValueString() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("uidnumber"),
+				"UID number already in use",
+				fmt.Sprintf("The UID number %d is already used by the user “%s”.", plan.UIDNumber.ValueInt64(), owner),
+			)
+
+			return
+		}
+	}
+
+	var user *freeipa.User
+
+	// A preserved user holds the login, it is restored and updated instead
+	if plan.Undelete.ValueBool() {
+		preserved, err := r.preserved(ctx, plan.UID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read user", "Reason: "+err.Error())
+
+			return
+		}
+
+		if preserved {
+			user, err = r.undelete(ctx, plan.UID.ValueString(), args, optArgs)
+			if err != nil {
+				resp.Diagnostics.AddError("Failed to restore preserved user", "Reason: "+err.Error())
+
+				return
+			}
+		}
+	}
+
+	if user == nil {
+		tflog.Trace(ctx, "Calling UserAdd", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().UserAdd(args, optArgs)
+
+		tflog.Trace(ctx, "Called UserAdd", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to create user", "Reason: "+err.Error())
+
+			return
+		}
+
+		user = &res.Result
+	}
+
+	state = plan
+	state.RandomPassword = types.StringPointerValue(user.Randompassword)
+
+	resp.Diagnostics.Append(r.setComputed(ctx, &state, user)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Accounts are created enabled and only locked afterwards, restored
+	// accounts keep their lock state
+	if !plan.AccountLocked.IsUnknown() && !plan.AccountLocked.Equal(state.AccountLocked) {
+		if err := r.setAccountLocked(ctx, plan.UID.ValueString(), plan.AccountLocked.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Failed to update user lock state", "Reason: "+err.Error())
+
+			// The user exists, keep it in the state so that it is not leaked
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+			return
+		}
+
+		state.AccountLocked = plan.AccountLocked
+	}
+
+	if !plan.Manager.IsNull() {
+		if _, err := r.applyManagers(ctx, plan.UID.ValueString(), plan.Manager, types.SetNull(types.StringType)); err != nil {
+			resp.Diagnostics.AddError("Failed to add user managers", "Reason: "+err.Error())
+
+			// The user exists, keep it in the state so that it is not leaked
+			state.Manager = types.SetNull(types.StringType)
+			state.CertMapData = emptyCertMapData()
+			state.UserCertificate = types.SetNull(types.StringType)
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+			return
+		}
+	}
+
+	if len(plan.CertMapData.Elements()) > 0 {
+		if _, err := r.applyCertMapData(ctx, plan.UID.ValueString(), plan.CertMapData, emptyCertMapData(), false, nil); err != nil {
+			resp.Diagnostics.AddError("Failed to add user certificate mapping data", "Reason: "+err.Error())
+
+			// The user exists, keep it in the state so that it is not leaked
+			state.CertMapData = emptyCertMapData()
+			state.UserCertificate = types.SetNull(types.StringType)
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+			return
+		}
+	}
+
+	if !plan.UserCertificate.IsNull() {
+		if _, err := r.applyCertificates(ctx, plan.UID.ValueString(), plan.UserCertificate, types.SetNull(types.StringType)); err != nil {
+			resp.Diagnostics.AddError("Failed to add user certificates", "Reason: "+err.Error())
+
+			// The user exists, keep it in the state so that it is not leaked
+			state.UserCertificate = types.SetNull(types.StringType)
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *User) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state UserModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.UserShowArgs{}
+
+	optArgs := &freeipa.UserShowOptionalArgs{
+		UID: state.UID.ValueStringPointer(),
+		All: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling UserShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().UserShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called UserShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read user", "Reason: "+err.Error())
+
+		return
+	}
+
+	// A preserved user is no longer an active account, it is created again
+	// or restored when undelete is set
+	if res.Result.Preserved != nil && *res.Result.Preserved {
+		tflog.Debug(ctx, "User is preserved, removing it from the state", map[string]any{
+			"uid": state.UID.ValueString(),
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	var diags diag.Diagnostics
+
+	user := res.Result
+
+	state.GivenName = types.StringPointerValue(user.Givenname)
+	state.Surname = types.StringValue(user.Sn)
+	state.Title = types.StringPointerValue(user.Title)
+	state.EmployeeNumber = types.StringPointerValue(user.Employeenumber)
+	state.EmployeeType = types.StringPointerValue(user.Employeetype)
+	state.PreferredLanguage = types.StringPointerValue(user.Preferredlanguage)
+	state.OrgUnit = types.StringPointerValue(user.Ou)
+	state.Street = types.StringPointerValue(user.Street)
+	state.City = types.StringPointerValue(user.L)
+	state.Province = types.StringPointerValue(user.St)
+	state.PostalCode = types.StringPointerValue(user.Postalcode)
+	state.RadiusProxy = types.StringPointerValue(user.Ipatokenradiusconfiglink)
+	state.RadiusUsername = types.StringPointerValue(user.Ipatokenradiususername)
+	state.IdpConfigLink = types.StringPointerValue(user.Ipaidpconfiglink)
+	state.IdpSub = types.StringPointerValue(user.Ipaidpsub)
+
+	state.TelephoneNumber, diags = stringSliceToList(ctx, state.TelephoneNumber, user.Telephonenumber)
+	resp.Diagnostics.Append(diags...)
+
+	state.Mobile, diags = stringSliceToList(ctx, state.Mobile, user.Mobile)
+	resp.Diagnostics.Append(diags...)
+
+	state.CarLicense, diags = stringSliceToList(ctx, state.CarLicense, user.Carlicense)
+	resp.Diagnostics.Append(diags...)
+
+	state.PrincipalName, diags = principalsToSet(ctx, state.PrincipalName, user.Krbprincipalname)
+	resp.Diagnostics.Append(diags...)
+
+	// FreeIPA sets the password expiration on its own, the expirations are
+	// only read back when they are configured
+	state.PrincipalExpiry = timeToString(state.PrincipalExpiry, user.Krbprincipalexpiration)
+	state.PasswordExpiry = timeToString(state.PasswordExpiry, user.Krbpasswordexpiration)
+
+	state.SSHPublicKeys, diags = sshPubKeysToList(ctx, state.SSHPublicKeys, user.Ipasshpubkey, state.ManageSSHKeys.ValueBool())
+	resp.Diagnostics.Append(diags...)
+
+	state.CertMapData, diags = certMapDataToSet(ctx, state.CertMapData, user.Ipacertmapdata, state.ManageCertMap.ValueBool())
+	resp.Diagnostics.Append(diags...)
+
+	state.UserClass = stringSliceToSet(ctx, user.Userclass, state.UserClass.IsNull(), &resp.Diagnostics)
+	state.DepartmentNumber = stringSliceToSet(ctx, user.Departmentnumber, state.DepartmentNumber.IsNull(), &resp.Diagnostics)
+
+	managers := utils.SplitManagers(user.Manager)
+	state.Manager = stringSliceToSet(ctx, &managers, state.Manager.IsNull(), &resp.Diagnostics)
+
+	state.UserCertificate, diags = certificatesToSet(ctx, state.UserCertificate, user.Usercertificate)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(r.setComputed(ctx, &state, &user)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *User) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan UserModel
+	var hasDiff bool
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.UserModArgs{}
+
+	optArgs := &freeipa.UserModOptionalArgs{
+		UID: state.UID.ValueStringPointer(),
+		All: freeipa.Bool(true),
+	}
+
+	if !plan.UID.Equal(state.UID) {
+		optArgs.Rename = plan.UID.ValueStringPointer()
+		hasDiff = true
+	}
+
+	// Only send the attributes which effectively changed so that attributes
+	// managed outside of Terraform are left untouched.
+	stringChanges := []struct {
+		plan, state types.String
+		arg         **string
+	}{
+		{plan.GivenName, state.GivenName, &optArgs.Givenname},
+		{plan.Surname, state.Surname, &optArgs.Sn},
+		{plan.FullName, state.FullName, &optArgs.Cn},
+		{plan.DisplayName, state.DisplayName, &optArgs.Displayname},
+		{plan.Initials, state.Initials, &optArgs.Initials},
+		{plan.Gecos, state.Gecos, &optArgs.Gecos},
+		{plan.HomeDirectory, state.HomeDirectory, &optArgs.Homedirectory},
+		{plan.LoginShell, state.LoginShell, &optArgs.Loginshell},
+		{plan.Title, state.Title, &optArgs.Title},
+		{plan.EmployeeNumber, state.EmployeeNumber, &optArgs.Employeenumber},
+		{plan.EmployeeType, state.EmployeeType, &optArgs.Employeetype},
+		{plan.PreferredLanguage, state.PreferredLanguage, &optArgs.Preferredlanguage},
+		{plan.OrgUnit, state.OrgUnit, &optArgs.Ou},
+		{plan.Street, state.Street, &optArgs.Street},
+		{plan.City, state.City, &optArgs.L},
+		{plan.Province, state.Province, &optArgs.St},
+		{plan.PostalCode, state.PostalCode, &optArgs.Postalcode},
+		{plan.RadiusProxy, state.RadiusProxy, &optArgs.Ipatokenradiusconfiglink},
+		{plan.RadiusUsername, state.RadiusUsername, &optArgs.Ipatokenradiususername},
+		{plan.IdpConfigLink, state.IdpConfigLink, &optArgs.Ipaidpconfiglink},
+		{plan.IdpSub, state.IdpSub, &optArgs.Ipaidpsub},
+	}
+
+	for _, c := range stringChanges {
+		if !c.plan.Equal(c.state) && !c.plan.IsUnknown() {
+			*c.arg = freeipa.String(c.plan.ValueString())
+			hasDiff = true
+		}
+	}
+
+	// Do not reset the password unless a change is requested
+	if !plan.UserPassword.Equal(state.UserPassword) && !plan.UserPassword.IsNull() {
+		optArgs.Userpassword = plan.UserPassword.ValueStringPointer()
+		hasDiff = true
+	}
+
+	// A new password is only generated when random is turned on
+	if plan.Random.ValueBool() && !state.Random.ValueBool() {
+		optArgs.Random = freeipa.Bool(true)
+		hasDiff = true
+	}
+
+	expirationChanges := []struct {
+		plan, state types.String
+		arg         **time.Time
+	}{
+		{plan.PrincipalExpiry, state.PrincipalExpiry, &optArgs.Krbprincipalexpiration},
+		{plan.PasswordExpiry, state.PasswordExpiry, &optArgs.Krbpasswordexpiration},
+	}
+
+	for _, c := range expirationChanges {
+		if !c.plan.Equal(c.state) && !c.plan.IsNull() && !c.plan.IsUnknown() {
+			*c.arg = stringToTimePointer(c.plan)
+			hasDiff = true
+		}
+	}
+
+	if !plan.UIDNumber.Equal(state.UIDNumber) && !plan.UIDNumber.IsUnknown() {
+		optArgs.Uidnumber = int64ToIntPointer(plan.UIDNumber)
+		hasDiff = true
+	}
+
+	if !plan.GIDNumber.Equal(state.GIDNumber) && !plan.GIDNumber.IsUnknown() {
+		optArgs.Gidnumber = int64ToIntPointer(plan.GIDNumber)
+		hasDiff = true
+	}
+
+	listChanges := []struct {
+		plan, state types.List
+		arg         **[]string
+	}{
+		{plan.Mail, state.Mail, &optArgs.Mail},
+		{plan.TelephoneNumber, state.TelephoneNumber, &optArgs.Telephonenumber},
+		{plan.Mobile, state.Mobile, &optArgs.Mobile},
+		{plan.CarLicense, state.CarLicense, &optArgs.Carlicense},
+	}
+
+	for _, c := range listChanges {
+		if !c.plan.Equal(c.state) && !c.plan.IsUnknown() {
+			values := []string{}
+
+			resp.Diagnostics.Append(c.plan.ElementsAs(ctx, &values, false)...)
+
+			*c.arg = &values
+			hasDiff = true
+		}
+	}
+
+	if !plan.UserAuthType.IsUnknown() && !plan.UserAuthType.Equal(state.UserAuthType) {
+		optArgs.Ipauserauthtype = setToStringSlicePointer(ctx, plan.UserAuthType, &resp.Diagnostics)
+
+		hasDiff = true
+	}
+
+	setChanges := []struct {
+		plan, state types.Set
+		arg         **[]string
+	}{
+		{plan.UserClass, state.UserClass, &optArgs.Userclass},
+		{plan.PrincipalName, state.PrincipalName, &optArgs.Krbprincipalname},
+		{plan.DepartmentNumber, state.DepartmentNumber, &optArgs.Departmentnumber},
+	}
+
+	for _, c := range setChanges {
+		if !c.plan.Equal(c.state) {
+			*c.arg = setToStringSlicePointer(ctx, c.plan, &resp.Diagnostics)
+			if *c.arg == nil {
+				*c.arg = &[]string{}
+			}
+
+			hasDiff = true
+		}
+	}
+
+	// The keys are replaced as a whole, the current ones are read first to
+	// keep those managed outside of Terraform
+	if !plan.SSHPublicKeys.Equal(state.SSHPublicKeys) || !plan.ManageSSHKeys.Equal(state.ManageSSHKeys) {
+		showArgs := &freeipa.UserShowArgs{}
+
+		// The user is only renamed by the modification below
+		showOptArgs := &freeipa.UserShowOptionalArgs{
+			UID: state.UID.ValueStringPointer(),
+		}
+
+		tflog.Trace(ctx, "Calling UserShow", map[string]any{
+			"args":     showArgs,
+			"opt_args": showOptArgs,
+		})
+
+		res, err := r.provider.Client().UserShow(showArgs, showOptArgs)
+
+		tflog.Trace(ctx, "Called UserShow", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read user", "Reason: "+err.Error())
+
+			return
+		}
+
+		var diags diag.Diagnostics
+
+		optArgs.Ipasshpubkey, diags = sshPubKeysArg(ctx, plan.SSHPublicKeys, state.SSHPublicKeys, plan.ManageSSHKeys.ValueBool(), res.Result.Ipasshpubkey)
+		resp.Diagnostics.Append(diags...)
+
+		hasDiff = hasDiff || optArgs.Ipasshpubkey != nil
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prior := state
+	priorLocked := state.AccountLocked
+	state = plan
+	state.AccountLocked = priorLocked
+	state.RandomPassword = prior.RandomPassword
+
+	if hasDiff {
+		tflog.Trace(ctx, "Calling UserMod", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().UserMod(args, optArgs)
+
+		tflog.Trace(ctx, "Called UserMod", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update user", "Reason: "+err.Error())
+
+			return
+		}
+
+		if optArgs.Random != nil {
+			state.RandomPassword = types.StringPointerValue(res.Result.Randompassword)
+		}
+
+		resp.Diagnostics.Append(r.setComputed(ctx, &state, &res.Result)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The lock state is not part of user-mod, it is toggled on its own
+	if !plan.AccountLocked.IsUnknown() && !plan.AccountLocked.Equal(state.AccountLocked) {
+		if err := r.setAccountLocked(ctx, plan.UID.ValueString(), plan.AccountLocked.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Failed to update user lock state", "Reason: "+err.Error())
+
+			return
+		}
+
+		state.AccountLocked = plan.AccountLocked
+		hasDiff = true
+	}
+
+	// Managers are added and removed with their own commands, leaving the
+	// other attributes of the user untouched
+	if !plan.Manager.Equal(prior.Manager) {
+		changed, err := r.applyManagers(ctx, plan.UID.ValueString(), plan.Manager, prior.Manager)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update user managers", "Reason: "+err.Error())
+
+			return
+		}
+
+		hasDiff = hasDiff || changed
+	}
+
+	// Mapping data is added and removed entry by entry, the current entries
+	// are read first to keep those managed outside of Terraform
+	if !plan.CertMapData.Equal(prior.CertMapData) || !plan.ManageCertMap.Equal(prior.ManageCertMap) {
+		current, err := r.certMapData(ctx, plan.UID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read user", "Reason: "+err.Error())
+
+			return
+		}
+
+		changed, err := r.applyCertMapData(ctx, plan.UID.ValueString(), plan.CertMapData, prior.CertMapData, plan.ManageCertMap.ValueBool(), current)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update user certificate mapping data", "Reason: "+err.Error())
+
+			return
+		}
+
+		hasDiff = hasDiff || changed
+	}
+
+	// Certificates are added and removed one by one so that those added
+	// outside of Terraform are kept
+	if !plan.UserCertificate.Equal(prior.UserCertificate) {
+		changed, err := r.applyCertificates(ctx, plan.UID.ValueString(), plan.UserCertificate, prior.UserCertificate)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update user certificates", "Reason: "+err.Error())
+
+			return
+		}
+
+		hasDiff = hasDiff || changed
+	}
+
+	if !hasDiff {
+		tflog.Debug(ctx, "Updated user has no effective difference", map[string]any{
+			"uid": plan.UID.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *User) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state UserModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.UserDelArgs{}
+
+	optArgs := &freeipa.UserDelOptionalArgs{
+		UID: &[]string{state.UID.ValueString()},
+	}
+
+	if state.Preserve.ValueBool() {
+		optArgs.Preserve = freeipa.Bool(true)
+	}
+
+	tflog.Trace(ctx, "Calling UserDel", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().UserDel(args, optArgs)
+
+	tflog.Trace(ctx, "Called UserDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete user", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *User) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state := UserModel{
+		UID:             types.StringValue(req.ID),
+		Mail:            types.ListNull(types.StringType),
+		TelephoneNumber: types.ListNull(types.StringType),
+		Mobile:          types.ListNull(types.StringType),
+		SSHPublicKeys:   types.ListNull(types.StringType),
+		CertMapData:     emptyCertMapData(),
+		UserAuthType:    types.SetNull(types.StringType),
+		UserClass:       types.SetNull(types.StringType),
+		UserCertificate: types.SetNull(types.StringType),
+		PrincipalName:   types.SetNull(types.StringType),
+		CarLicense:      types.ListNull(types.StringType),
+
+		Manager:          types.SetNull(types.StringType),
+		DepartmentNumber: types.SetNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *User) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                       schema.StringAttribute{},
+					"name":                     schema.StringAttribute{},
+					"first_name":               schema.StringAttribute{},
+					"last_name":                schema.StringAttribute{},
+					"full_name":                schema.StringAttribute{},
+					"display_name":             schema.StringAttribute{},
+					"initials":                 schema.StringAttribute{},
+					"home_directory":           schema.StringAttribute{},
+					"gecos":                    schema.StringAttribute{},
+					"login_shell":              schema.StringAttribute{},
+					"krb_principal_name":       schema.ListAttribute{ElementType: types.StringType},
+					"krb_principal_expiration": schema.StringAttribute{},
+					"krb_password_expiration":  schema.StringAttribute{},
+					"userpassword":             schema.StringAttribute{},
+					"email_address":            schema.ListAttribute{ElementType: types.StringType},
+					"telephone_numbers":        schema.ListAttribute{ElementType: types.StringType},
+					"mobile_numbers":           schema.ListAttribute{ElementType: types.StringType},
+					"random_password":          schema.BoolAttribute{},
+					"uid_number":               schema.Int64Attribute{},
+					"gid_number":               schema.Int64Attribute{},
+					"street_address":           schema.StringAttribute{},
+					"city":                     schema.StringAttribute{},
+					"province":                 schema.StringAttribute{},
+					"postal_code":              schema.StringAttribute{},
+					"organisation_unit":        schema.StringAttribute{},
+					"job_title":                schema.StringAttribute{},
+					"manager":                  schema.StringAttribute{},
+					"employee_number":          schema.StringAttribute{},
+					"employee_type":            schema.StringAttribute{},
+					"preferred_language":       schema.StringAttribute{},
+					"account_disabled":         schema.BoolAttribute{},
+					"ssh_public_key":           schema.ListAttribute{ElementType: types.StringType},
+					"car_license":              schema.ListAttribute{ElementType: types.StringType},
+					"userclass":                schema.ListAttribute{ElementType: types.StringType},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var oldState struct {
+					ID                     types.String `tfsdk:"id"`
+					Name                   types.String `tfsdk:"name"`
+					FirstName              types.String `tfsdk:"first_name"`
+					LastName               types.String `tfsdk:"last_name"`
+					FullName               types.String `tfsdk:"full_name"`
+					DisplayName            types.String `tfsdk:"display_name"`
+					Initials               types.String `tfsdk:"initials"`
+					HomeDirectory          types.String `tfsdk:"home_directory"`
+					Gecos                  types.String `tfsdk:"gecos"`
+					LoginShell             types.String `tfsdk:"login_shell"`
+					KrbPrincipalName       types.List   `tfsdk:"krb_principal_name"`
+					KrbPrincipalExpiration types.String `tfsdk:"krb_principal_expiration"`
+					KrbPasswordExpiration  types.String `tfsdk:"krb_password_expiration"`
+					UserPassword           types.String `tfsdk:"userpassword"`
+					EmailAddress           types.List   `tfsdk:"email_address"`
+					TelephoneNumbers       types.List   `tfsdk:"telephone_numbers"`
+					MobileNumbers          types.List   `tfsdk:"mobile_numbers"`
+					RandomPassword         types.Bool   `tfsdk:"random_password"`
+					UIDNumber              types.Int64  `tfsdk:"uid_number"`
+					GIDNumber              types.Int64  `tfsdk:"gid_number"`
+					StreetAddress          types.String `tfsdk:"street_address"`
+					City                   types.String `tfsdk:"city"`
+					Province               types.String `tfsdk:"province"`
+					PostalCode             types.String `tfsdk:"postal_code"`
+					OrganisationUnit       types.String `tfsdk:"organisation_unit"`
+					JobTitle               types.String `tfsdk:"job_title"`
+					Manager                types.String `tfsdk:"manager"`
+					EmployeeNumber         types.String `tfsdk:"employee_number"`
+					EmployeeType           types.String `tfsdk:"employee_type"`
+					PreferredLanguage      types.String `tfsdk:"preferred_language"`
+					AccountDisabled        types.Bool   `tfsdk:"account_disabled"`
+					SSHPublicKey           types.List   `tfsdk:"ssh_public_key"`
+					CarLicense             types.List   `tfsdk:"car_license"`
+					UserClass              types.List   `tfsdk:"userclass"`
+				}
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &oldState)...)
+
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				var userClass, principals []string
+
+				resp.Diagnostics.Append(oldState.UserClass.ElementsAs(ctx, &userClass, true)...)
+				resp.Diagnostics.Append(oldState.KrbPrincipalName.ElementsAs(ctx, &principals, true)...)
+
+				newState := UserModel{
+					UID:             oldState.Name,
+					GivenName:       oldState.FirstName,
+					Surname:         oldState.LastName,
+					FullName:        oldState.FullName,
+					DisplayName:     oldState.DisplayName,
+					Initials:        oldState.Initials,
+					Gecos:           oldState.Gecos,
+					HomeDirectory:   oldState.HomeDirectory,
+					LoginShell:      oldState.LoginShell,
+					Mail:            oldState.EmailAddress,
+					TelephoneNumber: oldState.TelephoneNumbers,
+					Mobile:          oldState.MobileNumbers,
+					Title:           oldState.JobTitle,
+					OrgUnit:         oldState.OrganisationUnit,
+					Street:          oldState.StreetAddress,
+					City:            oldState.City,
+					Province:        oldState.Province,
+					PostalCode:      oldState.PostalCode,
+					UserPassword:    oldState.UserPassword,
+					PrincipalName:   stringSliceToSet(ctx, &principals, oldState.KrbPrincipalName.IsNull(), &resp.Diagnostics),
+					PrincipalExpiry: oldState.KrbPrincipalExpiration,
+					PasswordExpiry:  oldState.KrbPasswordExpiration,
+					CarLicense:      oldState.CarLicense,
+					UIDNumber:       oldState.UIDNumber,
+					GIDNumber:       oldState.GIDNumber,
+					AccountLocked:   oldState.AccountDisabled,
+					SSHPublicKeys:   oldState.SSHPublicKey,
+					CertMapData:     emptyCertMapData(),
+					UserAuthType:    types.SetNull(types.StringType),
+					UserClass:       stringSliceToSet(ctx, &userClass, oldState.UserClass.IsNull(), &resp.Diagnostics),
+					UserCertificate: types.SetNull(types.StringType),
+
+					Manager:           types.SetNull(types.StringType),
+					EmployeeNumber:    oldState.EmployeeNumber,
+					EmployeeType:      oldState.EmployeeType,
+					DepartmentNumber:  types.SetNull(types.StringType),
+					PreferredLanguage: oldState.PreferredLanguage,
+				}
+
+				// The password was only generated when the flag was set, an
+				// unset flag is left out rather than planned as a change
+				if oldState.RandomPassword.ValueBool() {
+					newState.Random = oldState.RandomPassword
+				}
+
+				if oldState.Manager.ValueString() != "" {
+					newState.Manager = types.SetValueMust(types.StringType, []attr.Value{oldState.Manager})
+				}
+
+				if newState.UID.IsNull() {
+					newState.UID = oldState.ID
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+			},
+		},
+	}
+}
+
+// uidNumberOwner returns the login of the active user holding the UID number
+// uid, or an empty string when it is free.
+func (r *User) uidNumberOwner(ctx context.Context, uid int) (string, error) {
+	args := &freeipa.UserFindArgs{}
+
+	optArgs := &freeipa.UserFindOptionalArgs{
+		Uidnumber: freeipa.Int(uid),
+		NoMembers: freeipa.Bool(true),
+		Sizelimit: freeipa.Int(1),
+	}
+
+	tflog.Trace(ctx, "Calling UserFind", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().UserFind("", args, optArgs)
+
+	tflog.Trace(ctx, "Called UserFind", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(res.Result) == 0 {
+		return "", nil
+	}
+
+	return res.Result[0].UID, nil
+}
+
+// setAccountLocked disables or enables the account of the user uid.
+func (r *User) setAccountLocked(ctx context.Context, uid string, locked bool) error {
+	if !locked {
+		args := &freeipa.UserEnableArgs{}
+
+		optArgs := &freeipa.UserEnableOptionalArgs{
+			UID: freeipa.String(uid),
+		}
+
+		tflog.Trace(ctx, "Calling UserEnable", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().UserEnable(args, optArgs)
+
+		tflog.Trace(ctx, "Called UserEnable", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		return err
+	}
+
+	args := &freeipa.UserDisableArgs{}
+
+	optArgs := &freeipa.UserDisableOptionalArgs{
+		UID: freeipa.String(uid),
+	}
+
+	tflog.Trace(ctx, "Calling UserDisable", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().UserDisable(args, optArgs)
+
+	tflog.Trace(ctx, "Called UserDisable", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	return err
+}
+
+// preserved reports whether uid is a preserved user, false when there is no
+// such user.
+func (r *User) preserved(ctx context.Context, uid string) (bool, error) {
+	args := &freeipa.UserShowArgs{}
+
+	optArgs := &freeipa.UserShowOptionalArgs{
+		UID: freeipa.String(uid),
+	}
+
+	tflog.Trace(ctx, "Calling UserShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().UserShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called UserShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return res.Result.Preserved != nil && *res.Result.Preserved, nil
+}
+
+// undelete restores the preserved user uid and sets the attributes the
+// user would have been created with, the other attributes are kept.
+func (r *User) undelete(ctx context.Context, uid string, addArgs *freeipa.UserAddArgs, addOptArgs *freeipa.UserAddOptionalArgs) (*freeipa.User, error) {
+	undelArgs := &freeipa.UserUndelArgs{}
+
+	undelOptArgs := &freeipa.UserUndelOptionalArgs{
+		UID: freeipa.String(uid),
+	}
+
+	tflog.Trace(ctx, "Calling UserUndel", map[string]any{
+		"args":     undelArgs,
+		"opt_args": undelOptArgs,
+	})
+
+	undelRes, err := r.provider.Client().UserUndel(undelArgs, undelOptArgs)
+
+	tflog.Trace(ctx, "Called UserUndel", map[string]any{
+		"res": undelRes,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	args := &freeipa.UserModArgs{}
+
+	optArgs := &freeipa.UserModOptionalArgs{
+		UID:             freeipa.String(uid),
+		Givenname:       freeipa.String(addArgs.Givenname),
+		Sn:              freeipa.String(addArgs.Sn),
+		Cn:              addOptArgs.Cn,
+		Displayname:     addOptArgs.Displayname,
+		Initials:        addOptArgs.Initials,
+		Gecos:           addOptArgs.Gecos,
+		Homedirectory:   addOptArgs.Homedirectory,
+		Loginshell:      addOptArgs.Loginshell,
+		Title:           addOptArgs.Title,
+		Ou:              addOptArgs.Ou,
+		Street:          addOptArgs.Street,
+		L:               addOptArgs.L,
+		St:              addOptArgs.St,
+		Postalcode:      addOptArgs.Postalcode,
+		Userpassword:    addOptArgs.Userpassword,
+		Random:          addOptArgs.Random,
+		Uidnumber:       addOptArgs.Uidnumber,
+		Gidnumber:       addOptArgs.Gidnumber,
+		Mail:            addOptArgs.Mail,
+		Telephonenumber: addOptArgs.Telephonenumber,
+		Mobile:          addOptArgs.Mobile,
+		Ipasshpubkey:    addOptArgs.Ipasshpubkey,
+		Ipauserauthtype: addOptArgs.Ipauserauthtype,
+		Userclass:       addOptArgs.Userclass,
+		Carlicense:      addOptArgs.Carlicense,
+
+		Krbprincipalname:       addOptArgs.Krbprincipalname,
+		Krbprincipalexpiration: addOptArgs.Krbprincipalexpiration,
+		Krbpasswordexpiration:  addOptArgs.Krbpasswordexpiration,
+
+		Ipatokenradiusconfiglink: addOptArgs.Ipatokenradiusconfiglink,
+		Ipatokenradiususername:   addOptArgs.Ipatokenradiususername,
+		Ipaidpconfiglink:         addOptArgs.Ipaidpconfiglink,
+		Ipaidpsub:                addOptArgs.Ipaidpsub,
+
+		Employeenumber:    addOptArgs.Employeenumber,
+		Employeetype:      addOptArgs.Employeetype,
+		Departmentnumber:  addOptArgs.Departmentnumber,
+		Preferredlanguage: addOptArgs.Preferredlanguage,
+	}
+
+	tflog.Trace(ctx, "Calling UserMod", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	modRes, err := r.provider.Client().UserMod(args, optArgs)
+
+	tflog.Trace(ctx, "Called UserMod", map[string]any{
+		"res": modRes,
+		"err": err,
+	})
+
+	// The restored user may already match the configuration
+	var freeipaErr *freeipa.Error
+
+	if err != nil && (!errors.As(err, &freeipaErr) || freeipaErr.Code != utils.EmptyModlistCode) {
+		return nil, err
+	}
+
+	showArgs := &freeipa.UserShowArgs{}
+
+	showOptArgs := &freeipa.UserShowOptionalArgs{
+		UID: freeipa.String(uid),
+		All: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling UserShow", map[string]any{
+		"args":     showArgs,
+		"opt_args": showOptArgs,
+	})
+
+	showRes, err := r.provider.Client().UserShow(showArgs, showOptArgs)
+
+	tflog.Trace(ctx, "Called UserShow", map[string]any{
+		"res": showRes,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The generated password is only returned by the modification
+	if modRes != nil {
+		showRes.Result.Randompassword = modRes.Result.Randompassword
+	}
+
+	return &showRes.Result, nil
+}
+
+// certMapData returns the certificate mapping data of the user uid.
+func (r *User) certMapData(ctx context.Context, uid string) (*[]string, error) {
+	args := &freeipa.UserShowArgs{}
+
+	optArgs := &freeipa.UserShowOptionalArgs{
+		UID: freeipa.String(uid),
+		All: freeipa.Bool(true),
+	}
+
+	tflog.Trace(ctx, "Calling UserShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().UserShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called UserShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Result.Ipacertmapdata, nil
+}
+
+// applyCertMapData adds the entries of plan missing from the current mapping
+// data of the user uid and removes those which are no longer planned. Unless
+// manage is set, the current entries which are neither in plan nor in state
+// are kept. It reports whether the mapping data changed.
+func (r *User) applyCertMapData(ctx context.Context, uid string, plan, state types.Set, manage bool, current *[]string) (bool, error) {
+	var planned, removed []UserCertMapDataModel
+
+	if !plan.IsNull() {
+		if diags := plan.ElementsAs(ctx, &planned, false); diags.HasError() {
+			return false, fmt.Errorf("invalid planned mapping data")
+		}
+	}
+
+	if !state.IsNull() {
+		if diags := state.ElementsAs(ctx, &removed, false); diags.HasError() {
+			return false, fmt.Errorf("invalid prior mapping data")
+		}
+	}
+
+	values := []string{}
+	if current != nil {
+		values = *current
+	}
+
+	changed := false
+
+	for _, v := range values {
+		if certMapDataIndex(planned, v) >= 0 || (!manage && certMapDataIndex(removed, v) < 0) {
+			continue
+		}
+
+		if err := r.removeCertMapData(ctx, uid, v); err != nil {
+			return changed, err
+		}
+
+		changed = true
+	}
+
+	for _, e := range planned {
+		if slices.ContainsFunc(values, func(v string) bool { return sameCertMapData(e, v) }) {
+			continue
+		}
+
+		if err := r.addCertMapData(ctx, uid, e); err != nil {
+			return changed, err
+		}
+
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// addCertMapData adds a mapping entry to the user uid, letting FreeIPA derive
+// the mapping data of certificates and issuer and subject pairs.
+func (r *User) addCertMapData(ctx context.Context, uid string, e UserCertMapDataModel) error {
+	// go-freeipa sends the mapping data in place of the user login
+	args := []interface{}{uid}
+	options := map[string]interface{}{}
+
+	switch {
+	case !e.Data.IsNull():
+		args = append(args, []string{e.Data.ValueString()})
+	case !e.Certificate.IsNull():
+		der, err := utils.ParseCertificate(e.Certificate.ValueString())
+		if err != nil {
+			return err
+		}
+
+		options["certificate"] = []string{base64.StdEncoding.EncodeToString(der)}
+	default:
+		options["issuer"] = e.Issuer.ValueString()
+		options["subject"] = e.Subject.ValueString()
+	}
+
+	tflog.Trace(ctx, "Calling user_add_certmapdata", map[string]any{
+		"args":    args,
+		"options": options,
+	})
+
+	err := r.provider.RPC().Call(ctx, "user_add_certmapdata", args, options, nil)
+
+	tflog.Trace(ctx, "Called user_add_certmapdata", map[string]any{
+		"err": err,
+	})
+
+	// The user already holds the entry
+	var freeipaErr *freeipa.Error
+	if errors.As(err, &freeipaErr) && freeipaErr.Code == utils.EmptyModlistCode {
+		return nil
+	}
+
+	return err
+}
+
+// removeCertMapData removes the mapping data value from the user uid.
+func (r *User) removeCertMapData(ctx context.Context, uid, value string) error {
+	args := []interface{}{uid, []string{value}}
+
+	tflog.Trace(ctx, "Calling user_remove_certmapdata", map[string]any{
+		"args": args,
+	})
+
+	err := r.provider.RPC().Call(ctx, "user_remove_certmapdata", args, nil, nil)
+
+	tflog.Trace(ctx, "Called user_remove_certmapdata", map[string]any{
+		"err": err,
+	})
+
+	var freeipaErr *freeipa.Error
+	if errors.As(err, &freeipaErr) && freeipaErr.Code == utils.AttrValueNotFoundCode {
+		return nil
+	}
+
+	return err
+}
+
+// applyCertificates adds the certificates of plan missing from state to the
+// user uid and removes those of state missing from plan, and reports whether
+// applyManagers adds the managers of plan which are missing from state and
+// removes those which are no longer in plan. Managers which do not exist are
+// reported by FreeIPA and fail the operation.
+func (r *User) applyManagers(ctx context.Context, uid string, plan, state types.Set) (bool, error) {
+	var diags diag.Diagnostics
+
+	desired := setToStringSlicePointer(ctx, plan, &diags)
+	actual := setToStringSlicePointer(ctx, state, &diags)
+
+	if diags.HasError() {
+		return false, fmt.Errorf("reading managers: %v", diags)
+	}
+
+	if desired == nil {
+		desired = &[]string{}
+	}
+	if actual == nil {
+		actual = &[]string{}
+	}
+
+	added, removed := utils.SetDiff(*actual, *desired)
+
+	if len(removed) > 0 {
+		args := &freeipa.UserRemoveManagerArgs{}
+
+		optArgs := &freeipa.UserRemoveManagerOptionalArgs{
+			UID:  freeipa.String(uid),
+			User: &removed,
+		}
+
+		tflog.Trace(ctx, "Calling UserRemoveManager", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().UserRemoveManager(args, optArgs)
+
+		tflog.Trace(ctx, "Called UserRemoveManager", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			return false, err
+		}
+
+		// Managers removed out-of-band are reported as failures, ignore them
+		if err := utils.FailedOperationsError(res.Failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+			return false, err
+		}
+	}
+
+	if len(added) > 0 {
+		args := &freeipa.UserAddManagerArgs{}
+
+		optArgs := &freeipa.UserAddManagerOptionalArgs{
+			UID:  freeipa.String(uid),
+			User: &added,
+		}
+
+		tflog.Trace(ctx, "Calling UserAddManager", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().UserAddManager(args, optArgs)
+
+		tflog.Trace(ctx, "Called UserAddManager", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil {
+			return false, err
+		}
+
+		// Managers which do not exist are reported as failures too, only
+		// those already managing the user are ignored
+		if err := utils.FailedOperationsError(res.Failed, freeipa.FailedReasonAlreadyAMember); err != nil {
+			return false, err
+		}
+	}
+
+	return len(added) > 0 || len(removed) > 0, nil
+}
+
+// the user changed. Certificates are compared by their DER encoding.
+func (r *User) applyCertificates(ctx context.Context, uid string, plan, state types.Set) (bool, error) {
+	added, removed, err := certificateChanges(ctx, plan, state)
+	if err != nil {
+		return false, err
+	}
+
+	var freeipaErr *freeipa.Error
+
+	if len(removed) > 0 {
+		args := &freeipa.UserRemoveCertArgs{
+			Usercertificate: removed,
+		}
+
+		optArgs := &freeipa.UserRemoveCertOptionalArgs{
+			UID: freeipa.String(uid),
+		}
+
+		tflog.Trace(ctx, "Calling UserRemoveCert", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().UserRemoveCert(args, optArgs)
+
+		tflog.Trace(ctx, "Called UserRemoveCert", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		// Certificates already removed outside of Terraform are gone anyway
+		if err != nil && (!errors.As(err, &freeipaErr) || freeipaErr.Code != utils.AttrValueNotFoundCode) {
+			return false, err
+		}
+	}
+
+	if len(added) > 0 {
+		args := &freeipa.UserAddCertArgs{
+			Usercertificate: added,
+		}
+
+		optArgs := &freeipa.UserAddCertOptionalArgs{
+			UID: freeipa.String(uid),
+		}
+
+		tflog.Trace(ctx, "Calling UserAddCert", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().UserAddCert(args, optArgs)
+
+		tflog.Trace(ctx, "Called UserAddCert", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		// The user may already hold the certificates
+		if err != nil && (!errors.As(err, &freeipaErr) || freeipaErr.Code != utils.EmptyModlistCode) {
+			return false, err
+		}
+	}
+
+	return len(added) > 0 || len(removed) > 0, nil
+}
+
+func NewUser(p *provider.Provider) resource.Resource {
+	r := &User{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+	var _ resource.ResourceWithUpgradeState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewUser)
+}
+
+// setComputed copies the attributes FreeIPA may assign or derive on its own
+// into state, so that values managed outside of Terraform never show a diff.
+func (r *User) setComputed(ctx context.Context, state *UserModel, user *freeipa.User) (diags diag.Diagnostics) {
+	var d diag.Diagnostics
+
+	state.FullName = types.StringPointerValue(user.Cn)
+	state.DisplayName = types.StringPointerValue(user.Displayname)
+	state.Initials = types.StringPointerValue(user.Initials)
+	state.Gecos = types.StringPointerValue(user.Gecos)
+	state.HomeDirectory = types.StringPointerValue(user.Homedirectory)
+	state.LoginShell = types.StringPointerValue(user.Loginshell)
+	state.UIDNumber = intToInt64Value(user.Uidnumber)
+	state.GIDNumber = intToInt64Value(user.Gidnumber)
+	state.AccountLocked = types.BoolValue(user.Nsaccountlock != nil && *user.Nsaccountlock)
+	state.UserAuthType = stringSliceToSet(ctx, user.Ipauserauthtype, false, &diags)
+
+	if user.Mail != nil {
+		state.Mail, d = types.ListValueFrom(ctx, types.StringType, *user.Mail)
+		diags.Append(d...)
+	} else {
+		state.Mail = types.ListValueMust(types.StringType, []attr.Value{})
+	}
+
+	return
+}
+
+func int64ToIntPointer(v types.Int64) *int {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+
+	i := int(v.ValueInt64())
+
+	return &i
+}
+
+func stringToStringPointer(v types.String) *string {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+
+	return v.ValueStringPointer()
+}
+
+// stringToTimePointer parses an RFC3339 timestamp, the configuration being
+// validated beforehand.
+func stringToTimePointer(v types.String) *time.Time {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v.ValueString())
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
+
+// timeToString formats a timestamp returned by FreeIPA, keeping the current
+// representation when it denotes the same instant and the attribute null when
+// it is not configured.
+func timeToString(current types.String, value *time.Time) types.String {
+	if current.IsNull() {
+		return current
+	}
+
+	if value == nil {
+		return types.StringNull()
+	}
+
+	if t := stringToTimePointer(current); t != nil && t.Equal(*value) {
+		return current
+	}
+
+	return types.StringValue(value.UTC().Format(time.RFC3339))
+}
+
+func intToInt64Value(v *int) types.Int64 {
+	if v == nil {
+		return types.Int64Null()
+	}
+
+	return types.Int64Value(int64(*v))
+}
+
+func listToStringSlicePointer(ctx context.Context, list types.List, target **[]string) (diags diag.Diagnostics) {
+	if list.IsNull() || list.IsUnknown() {
+		return
+	}
+
+	values := []string{}
+
+	diags.Append(list.ElementsAs(ctx, &values, false)...)
+
+	*target = &values
+
+	return
+}
+
+// stringSliceToList converts values returned by FreeIPA to a list, keeping the
+// attribute null when it is unset on both sides.
+func stringSliceToList(ctx context.Context, current types.List, values *[]string) (types.List, diag.Diagnostics) {
+	if values == nil || len(*values) == 0 {
+		if current.IsNull() {
+			return current, nil
+		}
+
+		return types.ListValueMust(types.StringType, []attr.Value{}), nil
+	}
+
+	return types.ListValueFrom(ctx, types.StringType, *values)
+}
+
+// principalsToSet returns the principals of current which are still present
+// on the server, a principal without realm matching the one FreeIPA completed.
+// The canonical principal is always present on the server and only kept when
+// configured.
+func principalsToSet(ctx context.Context, current types.Set, principals *[]string) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if current.IsNull() || current.IsUnknown() {
+		return current, nil
+	}
+
+	configured := []string{}
+
+	diags.Append(current.ElementsAs(ctx, &configured, false)...)
+
+	values := []string{}
+
+	for _, c := range configured {
+		if principals == nil {
+			break
+		}
+
+		for _, p := range *principals {
+			if p == c || (!strings.Contains(c, "@") && strings.HasPrefix(p, c+"@")) {
+				values = append(values, c)
+
+				break
+			}
+		}
+	}
+
+	set, d := types.SetValueFrom(ctx, types.StringType, values)
+	diags.Append(d...)
+
+	return set, diags
+}
+
+// sshPubKeysToList returns the keys of current which are still present on the
+// server, keeping their configured representation. The server keys missing
+// from current are only included when manage is set, as they are otherwise
+// managed outside of Terraform.
+func sshPubKeysToList(ctx context.Context, current types.List, keys *[]string, manage bool) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	configured := []string{}
+
+	if !current.IsNull() && !current.IsUnknown() {
+		diags.Append(current.ElementsAs(ctx, &configured, false)...)
+	}
+
+	server := map[string]bool{}
+
+	if keys != nil {
+		for _, k := range *keys {
+			server[utils.NormalizeSSHPublicKey(k)] = true
+		}
+	}
+
+	values := []string{}
+	seen := map[string]bool{}
+
+	for _, k := range configured {
+		if n := utils.NormalizeSSHPublicKey(k); server[n] {
+			values = append(values, k)
+			seen[n] = true
+		}
+	}
+
+	if manage && keys != nil {
+		for _, k := range *keys {
+			if n := utils.NormalizeSSHPublicKey(k); !seen[n] {
+				values = append(values, k)
+				seen[n] = true
+			}
+		}
+	}
+
+	if len(values) == 0 && current.IsNull() {
+		return types.ListNull(types.StringType), diags
+	}
+
+	list, d := types.ListValueFrom(ctx, types.StringType, values)
+	diags.Append(d...)
+
+	return list, diags
+}
+
+// sshPubKeysArg returns the keys replacing keys on the server so that it holds
+// the keys of plan, or nil when it already does. Unless manage is set, the
+// server keys which are neither in plan nor in state are kept.
+func sshPubKeysArg(ctx context.Context, plan, state types.List, manage bool, keys *[]string) (*[]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	planned, removed := []string{}, []string{}
+
+	if !plan.IsNull() {
+		diags.Append(plan.ElementsAs(ctx, &planned, false)...)
+	}
+
+	if !state.IsNull() {
+		diags.Append(state.ElementsAs(ctx, &removed, false)...)
+	}
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	wanted := map[string]bool{}
+	for _, k := range planned {
+		wanted[utils.NormalizeSSHPublicKey(k)] = true
+	}
+
+	dropped := map[string]bool{}
+	for _, k := range removed {
+		dropped[utils.NormalizeSSHPublicKey(k)] = true
+	}
+
+	values := []string{}
+	present := map[string]bool{}
+	changed := false
+
+	if keys != nil {
+		for _, k := range *keys {
+			n := utils.NormalizeSSHPublicKey(k)
+
+			if wanted[n] || (!manage && !dropped[n]) {
+				values = append(values, k)
+				present[n] = true
+			} else {
+				changed = true
+			}
+		}
+	}
+
+	for _, k := range planned {
+		if n := utils.NormalizeSSHPublicKey(k); !present[n] {
+			values = append(values, k)
+			present[n] = true
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil, diags
+	}
+
+	return &values, diags
+}
+
+// validateCertMapData returns why a mapping entry is invalid, or an empty
+// string when it is valid.
+func validateCertMapData(e UserCertMapDataModel) string {
+	forms := 0
+
+	for _, set := range []bool{!e.Data.IsNull(), !e.Certificate.IsNull(), !e.Issuer.IsNull() || !e.Subject.IsNull()} {
+		if set {
+			forms++
+		}
+	}
+
+	switch {
+	case forms != 1:
+		return "Each certificate mapping entry sets exactly one of “data”, “certificate” or “issuer” and “subject”."
+	case e.Issuer.IsNull() != e.Subject.IsNull():
+		return "Certificate mapping entries set both “issuer” and “subject”."
+	case !e.Certificate.IsNull() && !e.Certificate.IsUnknown():
+		if _, err := utils.CertMapDataFromCertificate(e.Certificate.ValueString()); err != nil {
+			return fmt.Sprintf("Invalid certificate: %s.", err)
+		}
+	}
+
+	return ""
+}
+
+// certMapDataValue returns the mapping data FreeIPA stores for the entry.
+func certMapDataValue(e UserCertMapDataModel) (string, error) {
+	switch {
+	case !e.Data.IsNull():
+		return e.Data.ValueString(), nil
+	case !e.Certificate.IsNull():
+		return utils.CertMapDataFromCertificate(e.Certificate.ValueString())
+	default:
+		return utils.CertMapData(e.Issuer.ValueString(), e.Subject.ValueString()), nil
+	}
+}
+
+func sameCertMapData(e UserCertMapDataModel, value string) bool {
+	data, err := certMapDataValue(e)
+
+	return err == nil && utils.SameCertMapData(data, value)
+}
+
+// certMapDataIndex returns the index of the entry designating value, or -1.
+func certMapDataIndex(entries []UserCertMapDataModel, value string) int {
+	return slices.IndexFunc(entries, func(e UserCertMapDataModel) bool { return sameCertMapData(e, value) })
+}
+
+// certMapDataToSet returns the entries of current which are still present on
+// the server, keeping their configured representation. The server entries
+// missing from current are only included, as raw data, when manage is set.
+func certMapDataToSet(ctx context.Context, current types.Set, values *[]string, manage bool) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	configured := []UserCertMapDataModel{}
+
+	if !current.IsNull() && !current.IsUnknown() {
+		diags.Append(current.ElementsAs(ctx, &configured, false)...)
+	}
+
+	server := []string{}
+	if values != nil {
+		server = *values
+	}
+
+	entries := []UserCertMapDataModel{}
+
+	for _, e := range configured {
+		if slices.ContainsFunc(server, func(v string) bool { return sameCertMapData(e, v) }) {
+			entries = append(entries, e)
+		}
+	}
+
+	if manage {
+		for _, v := range server {
+			if certMapDataIndex(entries, v) < 0 {
+				entries = append(entries, UserCertMapDataModel{
+					Data:        types.StringValue(v),
+					Certificate: types.StringNull(),
+					Issuer:      types.StringNull(),
+					Subject:     types.StringNull(),
+				})
+			}
+		}
+	}
+
+	set, d := types.SetValueFrom(ctx, userCertMapDataType, entries)
+	diags.Append(d...)
+
+	return set, diags
+}
+
+// certificateValues maps the base64 encoded DER of the certificates of set to
+// their configured representation.
+func certificateValues(ctx context.Context, set types.Set) (map[string]string, error) {
+	values := map[string]string{}
+
+	if set.IsNull() || set.IsUnknown() {
+		return values, nil
+	}
+
+	var certificates []string
+
+	if diags := set.ElementsAs(ctx, &certificates, false); diags.HasError() {
+		return nil, fmt.Errorf("invalid certificates")
+	}
+
+	for _, c := range certificates {
+		der, err := utils.ParseCertificate(c)
+		if err != nil {
+			return nil, err
+		}
+
+		values[base64.StdEncoding.EncodeToString(der)] = c
+	}
+
+	return values, nil
+}
+
+// certificateChanges returns the base64 encoded DER of the certificates of
+// plan missing from state and of those of state missing from plan.
+func certificateChanges(ctx context.Context, plan, state types.Set) ([]interface{}, []interface{}, error) {
+	planned, err := certificateValues(ctx, plan)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prior, err := certificateValues(ctx, state)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var added, removed []interface{}
+
+	for k := range planned {
+		if _, ok := prior[k]; !ok {
+			added = append(added, k)
+		}
+	}
+
+	for k := range prior {
+		if _, ok := planned[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+
+	return added, removed, nil
+}
+
+// certificatesToSet returns the certificates of current which are still held
+// by the user or the service, keeping their configured representation. The certificates
+// missing from current are managed outside of Terraform and left out.
+func certificatesToSet(ctx context.Context, current types.Set, certificates *[]interface{}) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if current.IsNull() || current.IsUnknown() {
+		return current, diags
+	}
+
+	configured, err := certificateValues(ctx, current)
+	if err != nil {
+		diags.AddError("Invalid user certificate", "Reason: "+err.Error())
+
+		return current, diags
+	}
+
+	values := []string{}
+
+	if certificates != nil {
+		for _, v := range *certificates {
+			der, err := utils.CertificateDER(v)
+			if err != nil {
+				continue
+			}
+
+			if c, ok := configured[base64.StdEncoding.EncodeToString(der)]; ok {
+				values = append(values, c)
+			}
+		}
+	}
+
+	return types.SetValueFrom(ctx, types.StringType, values)
+}
@@ -0,0 +1,449 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testUserSchema returns the current schema of freeipa_user.
+func testUserSchema(t *testing.T) schema.Schema {
+	var resp resource.SchemaResponse
+
+	(&User{}).Schema(context.Background(), resource.SchemaRequest{}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", resp.Diagnostics)
+	}
+
+	return resp.Schema
+}
+
+// testState returns a state of s holding values, the other attributes being
+// null.
+func testState(t *testing.T, s schema.Schema, values map[string]attr.Value) tfsdk.State {
+	ctx := context.Background()
+
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+
+	for name, v := range values {
+		if diags := state.SetAttribute(ctx, path.Root(name), v); diags.HasError() {
+			t.Fatalf("setting %s: %v", name, diags)
+		}
+	}
+
+	return state
+}
+
+func testCertMapData(data, certificate, issuer, subject types.String) attr.Value {
+	return types.ObjectValueMust(userCertMapDataType.AttrTypes, map[string]attr.Value{
+		"data":        data,
+		"certificate": certificate,
+		"issuer":      issuer,
+		"subject":     subject,
+	})
+}
+
+func TestUserUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+
+	upgrader := (&User{}).UpgradeState(ctx)[0]
+	current := testUserSchema(t)
+
+	cases := map[string]struct {
+		prior map[string]attr.Value
+		check func(t *testing.T, state UserModel)
+	}{
+		"full": {
+			prior: map[string]attr.Value{
+				"id":                       types.StringValue("jdoe"),
+				"name":                     types.StringValue("jdoe"),
+				"first_name":               types.StringValue("John"),
+				"last_name":                types.StringValue("Doe"),
+				"email_address":            types.ListValueMust(types.StringType, []attr.Value{types.StringValue("jdoe@example.test")}),
+				"login_shell":              types.StringValue("/bin/bash"),
+				"uid_number":               types.Int64Value(10001),
+				"account_disabled":         types.BoolValue(true),
+				"manager":                  types.StringValue("boss"),
+				"employee_number":          types.StringValue("000001"),
+				"employee_type":            types.StringValue("Developer"),
+				"preferred_language":       types.StringValue("en"),
+				"userclass":                types.ListValueMust(types.StringType, []attr.Value{types.StringValue("user-account")}),
+				"krb_principal_name":       types.ListValueMust(types.StringType, []attr.Value{types.StringValue("jdoe"), types.StringValue("john.doe")}),
+				"krb_principal_expiration": types.StringValue("2049-12-31T23:59:59Z"),
+				"krb_password_expiration":  types.StringValue("2039-06-30T12:00:00+02:00"),
+				"random_password":          types.BoolValue(true),
+				"car_license":              types.ListValueMust(types.StringType, []attr.Value{types.StringValue("AB-123-CD")}),
+			},
+			check: func(t *testing.T, state UserModel) {
+				want := map[string]attr.Value{
+					"uid":                    types.StringValue("jdoe"),
+					"givenname":              types.StringValue("John"),
+					"sn":                     types.StringValue("Doe"),
+					"mail":                   types.ListValueMust(types.StringType, []attr.Value{types.StringValue("jdoe@example.test")}),
+					"loginshell":             types.StringValue("/bin/bash"),
+					"uidnumber":              types.Int64Value(10001),
+					"nsaccountlock":          types.BoolValue(true),
+					"manager":                types.SetValueMust(types.StringType, []attr.Value{types.StringValue("boss")}),
+					"employeenumber":         types.StringValue("000001"),
+					"employeetype":           types.StringValue("Developer"),
+					"preferredlanguage":      types.StringValue("en"),
+					"userclass":              types.SetValueMust(types.StringType, []attr.Value{types.StringValue("user-account")}),
+					"ipacertmapdata":         emptyCertMapData(),
+					"krbprincipalname":       types.SetValueMust(types.StringType, []attr.Value{types.StringValue("jdoe"), types.StringValue("john.doe")}),
+					"krbprincipalexpiration": types.StringValue("2049-12-31T23:59:59Z"),
+					"krbpasswordexpiration":  types.StringValue("2039-06-30T12:00:00+02:00"),
+					"random":                 types.BoolValue(true),
+					"carlicense":             types.ListValueMust(types.StringType, []attr.Value{types.StringValue("AB-123-CD")}),
+				}
+
+				got := map[string]attr.Value{
+					"uid":                    state.UID,
+					"givenname":              state.GivenName,
+					"sn":                     state.Surname,
+					"mail":                   state.Mail,
+					"loginshell":             state.LoginShell,
+					"uidnumber":              state.UIDNumber,
+					"nsaccountlock":          state.AccountLocked,
+					"manager":                state.Manager,
+					"employeenumber":         state.EmployeeNumber,
+					"employeetype":           state.EmployeeType,
+					"preferredlanguage":      state.PreferredLanguage,
+					"userclass":              state.UserClass,
+					"ipacertmapdata":         state.CertMapData,
+					"krbprincipalname":       state.PrincipalName,
+					"krbprincipalexpiration": state.PrincipalExpiry,
+					"krbpasswordexpiration":  state.PasswordExpiry,
+					"random":                 state.Random,
+					"carlicense":             state.CarLicense,
+				}
+
+				for name, v := range want {
+					if !got[name].Equal(v) {
+						t.Errorf("%s: got %s, want %s", name, got[name], v)
+					}
+				}
+			},
+		},
+		"minimal": {
+			// States whose name was not recorded use the ID for the login
+			prior: map[string]attr.Value{
+				"id":              types.StringValue("jdoe"),
+				"first_name":      types.StringValue("John"),
+				"last_name":       types.StringValue("Doe"),
+				"manager":         types.StringValue(""),
+				"random_password": types.BoolValue(false),
+			},
+			check: func(t *testing.T, state UserModel) {
+				if state.UID.ValueString() != "jdoe" {
+					t.Errorf("uid: got %s, want %q", state.UID, "jdoe")
+				}
+
+				if !state.Manager.IsNull() || !state.UserClass.IsNull() || !state.DepartmentNumber.IsNull() {
+					t.Errorf("got manager %s, userclass %s and departmentnumber %s, want them null", state.Manager, state.UserClass, state.DepartmentNumber)
+				}
+
+				if !state.Random.IsNull() || !state.PrincipalName.IsNull() || !state.CarLicense.IsNull() {
+					t.Errorf("got random %s, krbprincipalname %s and carlicense %s, want them null", state.Random, state.PrincipalName, state.CarLicense)
+				}
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			prior := testState(t, *upgrader.PriorSchema, c.prior)
+
+			req := resource.UpgradeStateRequest{State: &prior}
+			resp := resource.UpgradeStateResponse{State: testState(t, current, nil)}
+
+			upgrader.StateUpgrader(ctx, req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var state UserModel
+			if diags := resp.State.Get(ctx, &state); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			c.check(t, state)
+		})
+	}
+}
+
+func TestUserImportState(t *testing.T) {
+	ctx := context.Background()
+
+	resp := resource.ImportStateResponse{State: testState(t, testUserSchema(t), nil)}
+
+	(&User{}).ImportState(ctx, resource.ImportStateRequest{ID: "jdoe"}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var state UserModel
+	if diags := resp.State.Get(ctx, &state); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if state.UID.ValueString() != "jdoe" {
+		t.Errorf("uid: got %s, want %q", state.UID, "jdoe")
+	}
+
+	// The block is empty rather than null, as read from a configuration
+	if !state.CertMapData.Equal(emptyCertMapData()) {
+		t.Errorf("ipacertmapdata: got %s, want an empty set", state.CertMapData)
+	}
+
+	// Sets are only read back once configured
+	if !state.Manager.IsNull() || !state.DepartmentNumber.IsNull() || !state.UserClass.IsNull() {
+		t.Errorf("got manager %s, departmentnumber %s and userclass %s, want them null", state.Manager, state.DepartmentNumber, state.UserClass)
+	}
+}
+
+func TestUserValidateConfigCertMapData(t *testing.T) {
+	ctx := context.Background()
+	s := testUserSchema(t)
+
+	null := types.StringNull()
+	issuer := types.StringValue("CN=Certificate Authority,O=EXAMPLE.TEST")
+	subject := types.StringValue("CN=jdoe,O=EXAMPLE.TEST")
+	data := types.StringValue("X509:<I>O=EXAMPLE.TEST,CN=Certificate Authority<S>O=EXAMPLE.TEST,CN=jdoe")
+
+	cases := map[string]struct {
+		entry   attr.Value
+		invalid bool
+	}{
+		"data":                {testCertMapData(data, null, null, null), false},
+		"issuer and subject":  {testCertMapData(null, null, issuer, subject), false},
+		"issuer only":         {testCertMapData(null, null, issuer, null), true},
+		"data and issuer":     {testCertMapData(data, null, issuer, subject), true},
+		"nothing":             {testCertMapData(null, null, null, null), true},
+		"invalid certificate": {testCertMapData(null, types.StringValue("not a certificate"), null, null), true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			state := testState(t, s, map[string]attr.Value{
+				"uid":            types.StringValue("jdoe"),
+				"givenname":      types.StringValue("John"),
+				"sn":             types.StringValue("Doe"),
+				"ipacertmapdata": types.SetValueMust(userCertMapDataType, []attr.Value{c.entry}),
+			})
+
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: s, Raw: state.Raw}}
+			resp := resource.ValidateConfigResponse{}
+
+			(&User{}).ValidateConfig(ctx, req, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != c.invalid {
+				t.Errorf("got errors %v, want errors: %t", resp.Diagnostics, c.invalid)
+			}
+		})
+	}
+}
+
+func TestCertMapDataToSet(t *testing.T) {
+	ctx := context.Background()
+
+	null := types.StringNull()
+	issuer := types.StringValue("CN=Certificate Authority,O=EXAMPLE.TEST")
+	subject := types.StringValue("CN=jdoe,O=EXAMPLE.TEST")
+
+	configured := types.SetValueMust(userCertMapDataType, []attr.Value{
+		testCertMapData(null, null, issuer, subject),
+		testCertMapData(types.StringValue("X509:<I>O=EXAMPLE.TEST,CN=Removed<S>O=EXAMPLE.TEST,CN=jdoe"), null, null, null),
+	})
+
+	// FreeIPA stores the issuer and subject in reverse order, without spaces
+	server := []string{
+		"X509:<I>O=EXAMPLE.TEST,CN=Certificate Authority<S>O=EXAMPLE.TEST,CN=jdoe",
+		"X509:<I>O=EXAMPLE.TEST,CN=Outside<S>O=EXAMPLE.TEST,CN=jdoe",
+	}
+
+	cases := map[string]struct {
+		current types.Set
+		values  *[]string
+		manage  bool
+		want    int
+	}{
+		"configured entries still set":    {configured, &server, false, 1},
+		"outside entries managed":         {configured, &server, true, 2},
+		"no entries":                      {emptyCertMapData(), nil, false, 0},
+		"null state from older versions":  {types.SetNull(userCertMapDataType), &server, false, 0},
+		"outside entries of a null state": {types.SetNull(userCertMapDataType), &server, true, 2},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := certMapDataToSet(ctx, c.current, c.values, c.manage)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			// Blocks are never null
+			if got.IsNull() || len(got.Elements()) != c.want {
+				t.Errorf("got %s, want %d entries", got, c.want)
+			}
+		})
+	}
+}
+
+func TestUserDelete(t *testing.T) {
+	deleted := func() (int, string) {
+		return http.StatusOK, `{"result":{"result":{"failed":[]},"value":["jdoe"],"summary":"Deleted user \"jdoe\""},"error":null}`
+	}
+
+	cases := map[string]struct {
+		response func() (int, string)
+		deleted  bool
+	}{
+		"deleted":      {deleted, true},
+		"not found":    {func() (int, string) { return freeipaError(4001, "NotFound") }, true},
+		"server error": {func() (int, string) { return http.StatusInternalServerError, "Internal Server Error" }, false},
+		"other error":  {func() (int, string) { return freeipaError(2100, "ACIError") }, false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			server, fake, schemas := testProviderServer(t, map[string]func() (int, string){"user_del": c.response}, nil)
+
+			state, diags := testDestroy(t, server, schemas, "freeipa_user", map[string]tftypes.Value{
+				"uid":       tftypes.NewValue(tftypes.String, "jdoe"),
+				"givenname": tftypes.NewValue(tftypes.String, "John"),
+				"sn":        tftypes.NewValue(tftypes.String, "Doe"),
+			})
+
+			if !fake.called("user_del") {
+				t.Fatal("user_del was not called")
+			}
+
+			if c.deleted && (len(diags) != 0 || !state.IsNull()) {
+				t.Errorf("got diagnostics %v and state %s, want the user removed from the state", diags, state)
+			}
+
+			// A failed deletion keeps the user in the state
+			if !c.deleted && (!hasError(diags, "Failed to delete user") || state.IsNull()) {
+				t.Errorf("got diagnostics %v and state %s, want an error and the user kept", diags, state)
+			}
+		})
+	}
+}
+
+func TestUserValidateConfigExpirations(t *testing.T) {
+	ctx := context.Background()
+	s := testUserSchema(t)
+
+	cases := map[string]struct {
+		values  map[string]attr.Value
+		invalid bool
+	}{
+		"rfc3339":           {map[string]attr.Value{"krbprincipalexpiration": types.StringValue("2049-12-31T23:59:59Z")}, false},
+		"with offset":       {map[string]attr.Value{"krbpasswordexpiration": types.StringValue("2049-12-31T23:59:59+02:00")}, false},
+		"date only":         {map[string]attr.Value{"krbprincipalexpiration": types.StringValue("2049-12-31")}, true},
+		"generalized time":  {map[string]attr.Value{"krbpasswordexpiration": types.StringValue("20491231235959Z")}, true},
+		"random":            {map[string]attr.Value{"random": types.BoolValue(true)}, false},
+		"random and passwd": {map[string]attr.Value{"random": types.BoolValue(true), "userpassword": types.StringValue("secret")}, true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			values := map[string]attr.Value{
+				"uid":       types.StringValue("jdoe"),
+				"givenname": types.StringValue("John"),
+				"sn":        types.StringValue("Doe"),
+			}
+
+			for k, v := range c.values {
+				values[k] = v
+			}
+
+			state := testState(t, s, values)
+
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: s, Raw: state.Raw}}
+			resp := resource.ValidateConfigResponse{}
+
+			(&User{}).ValidateConfig(ctx, req, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != c.invalid {
+				t.Errorf("got errors %v, want errors: %t", resp.Diagnostics, c.invalid)
+			}
+		})
+	}
+}
+
+func TestTimeToString(t *testing.T) {
+	value := time.Date(2049, 12, 31, 21, 59, 59, 0, time.UTC)
+
+	cases := map[string]struct {
+		current types.String
+		value   *time.Time
+		want    types.String
+	}{
+		"same instant":   {types.StringValue("2049-12-31T23:59:59+02:00"), &value, types.StringValue("2049-12-31T23:59:59+02:00")},
+		"changed":        {types.StringValue("2039-12-31T23:59:59Z"), &value, types.StringValue("2049-12-31T21:59:59Z")},
+		"removed":        {types.StringValue("2049-12-31T21:59:59Z"), nil, types.StringNull()},
+		"not configured": {types.StringNull(), &value, types.StringNull()},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := timeToString(c.current, c.value); !got.Equal(c.want) {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestPrincipalsToSet(t *testing.T) {
+	ctx := context.Background()
+
+	configured := types.SetValueMust(types.StringType, []attr.Value{
+		types.StringValue("john.doe"),
+		types.StringValue("jdoe@EXAMPLE.TEST"),
+		types.StringValue("removed"),
+	})
+
+	// FreeIPA completes the realm and always lists the canonical principal
+	server := []string{"jdoe@EXAMPLE.TEST", "john.doe@EXAMPLE.TEST", "outside@EXAMPLE.TEST"}
+
+	cases := map[string]struct {
+		current types.Set
+		values  *[]string
+		want    []string
+	}{
+		"configured principals still set": {configured, &server, []string{"john.doe", "jdoe@EXAMPLE.TEST"}},
+		"no principals":                   {configured, nil, []string{}},
+		"not configured":                  {types.SetNull(types.StringType), &server, nil},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := principalsToSet(ctx, c.current, c.values)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			want := types.SetNull(types.StringType)
+			if c.want != nil {
+				want = stringSliceToSet(ctx, &c.want, false, &diags)
+			}
+
+			if !got.Equal(want) {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,503 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// vaultAccessKinds are the members and the owners of a vault. Members can
+// archive and retrieve the data of the vault, owners can also manage it.
+var vaultAccessKinds = []string{"member", "owner"}
+
+// vaultAccessTypes are the types of the vault members and owners, in the
+// order of the import ID.
+var vaultAccessTypes = []string{"user", "group", "service"}
+
+type VaultAccess struct {
+	provider *provider.Provider
+	kind     string
+}
+
+type VaultAccessModel struct {
+	Vault            types.String `tfsdk:"vault_cn"`
+	Username         types.String `tfsdk:"username"`
+	Service          types.String `tfsdk:"service"`
+	Shared           types.Bool   `tfsdk:"shared"`
+	User             types.String `tfsdk:"user"`
+	Group            types.String `tfsdk:"group"`
+	ServicePrincipal types.String `tfsdk:"service_principal"`
+}
+
+// vault returns the name and scope of the vault.
+func (m VaultAccessModel) vault() VaultModel {
+	return VaultModel{
+		Name:     m.Vault,
+		Username: m.Username,
+		Service:  m.Service,
+		Shared:   m.Shared,
+	}
+}
+
+// member returns the type and the name of the user, group or service granted
+// access to the vault.
+func (m VaultAccessModel) member() (string, string) {
+	for i, v := range []types.String{m.User, m.Group, m.ServicePrincipal} {
+		if !v.IsNull() && !v.IsUnknown() {
+			return vaultAccessTypes[i], v.ValueString()
+		}
+	}
+
+	return "", ""
+}
+
+func (r *VaultAccess) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vault_" + r.kind
+}
+
+func (r *VaultAccess) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	description := "Adds a user, group or service member to a FreeIPA vault, letting it archive and retrieve the vault data."
+	if r.kind == "owner" {
+		description = "Adds a user, group or service owner to a FreeIPA vault, letting it manage the vault, its members and owners, and its data."
+	}
+
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: description,
+		Attributes: map[string]schema.Attribute{
+			"vault_cn": schema.StringAttribute{
+				Description: "Vault name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "User owning the vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service": schema.StringAttribute{
+				Description: "Service principal owning the vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"shared": schema.BoolAttribute{
+				Description: "Whether the vault is a shared vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"user": schema.StringAttribute{
+				Description: "User " + r.kind + " of the vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"group": schema.StringAttribute{
+				Description: "Group " + r.kind + " of the vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service_principal": schema.StringAttribute{
+				Description: "Service principal " + r.kind + " of the vault, e.g. `HTTP/web.example.com`",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *VaultAccess) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config VaultAccessModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scopes := 0
+
+	for _, set := range []bool{!config.Username.IsNull(), !config.Service.IsNull(), config.Shared.ValueBool()} {
+		if set {
+			scopes++
+		}
+	}
+
+	if scopes > 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("username"),
+			"Conflicting vault scopes",
+			`Only one of “username”, “service” and “shared” can be set.`,
+		)
+	}
+
+	members := 0
+
+	for _, v := range []types.String{config.User, config.Group, config.ServicePrincipal} {
+		if !v.IsNull() {
+			members++
+		}
+	}
+
+	if members != 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user"),
+			"Invalid configuration",
+			`Exactly one of “user”, “group” and “service_principal” must be set.`,
+		)
+	}
+}
+
+func (r *VaultAccess) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan VaultAccessModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	memberType, member := plan.member()
+
+	failed, err := r.add(ctx, plan.vault(), memberType, member)
+
+	// Members which already have access are reported as failures, treat them
+	// as success to keep the creation idempotent.
+	if err == nil {
+		err = utils.FailedOperationsError(failed, freeipa.FailedReasonAlreadyAMember)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add vault "+r.kind, "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *VaultAccess) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state VaultAccessModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// go-freeipa cannot decode the salt and public key of symmetric and
+	// asymmetric vaults
+	args := []interface{}{state.Vault.ValueString()}
+	options := map[string]interface{}{}
+
+	username, service, shared := vaultScope(state.vault())
+	if username != nil {
+		options["username"] = *username
+	}
+	if service != nil {
+		options["service"] = *service
+	}
+	if shared != nil {
+		options["shared"] = true
+	}
+
+	tflog.Trace(ctx, "Calling vault_show", map[string]any{
+		"args":    args,
+		"options": options,
+	})
+
+	var res struct {
+		Result map[string]interface{} `json:"result"`
+	}
+
+	err := r.provider.RPC().Call(ctx, "vault_show", args, options, &res)
+
+	tflog.Trace(ctx, "Called vault_show", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read vault", "Reason: "+err.Error())
+
+		return
+	}
+
+	memberType, member := state.member()
+
+	if !vaultAccessContains(rpcStringValues(res.Result[r.kind+"_"+memberType]), memberType, member) {
+		tflog.Debug(ctx, "Vault "+r.kind+" was removed from vault", map[string]any{
+			"vault_cn": state.Vault.ValueString(),
+			memberType: member,
+		})
+
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *VaultAccess) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All attributes require replacement, there is nothing to update
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *VaultAccess) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state VaultAccessModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	memberType, member := state.member()
+
+	failed, err := r.remove(ctx, state.vault(), memberType, member)
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to remove vault "+r.kind, "Reason: "+err.Error())
+		}
+
+		return
+	}
+
+	// Members removed out-of-band are reported as failures, ignore them
+	if err := utils.FailedOperationsError(failed, utils.FailedReasonNotAMember, freeipa.FailedReasonNoSuchEntry); err != nil {
+		resp.Diagnostics.AddError("Failed to remove vault "+r.kind, "Reason: "+err.Error())
+	}
+}
+
+func (r *VaultAccess) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state, ok := parseVaultAccessID(req.ID)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<vault>/<user|group|service>/<%s>”, where “<vault>” is the import ID of the vault, e.g. “shared/ci-secrets/service/HTTP/web.example.com”, got %q.", r.kind, req.ID),
+		)
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *VaultAccess) add(ctx context.Context, vault VaultModel, memberType, member string) (freeipa.FailedOperations, error) {
+	members := &[]string{member}
+
+	username, service, shared := vaultScope(vault)
+
+	tflog.Trace(ctx, "Adding vault "+r.kind, map[string]any{
+		"vault_cn": vault.Name.ValueString(),
+		memberType: member,
+	})
+
+	client := r.provider.Client()
+
+	if r.kind == "owner" {
+		optArgs := &freeipa.VaultAddOwnerOptionalArgs{
+			Username:  username,
+			Service:   service,
+			Shared:    shared,
+			NoMembers: freeipa.Bool(true),
+		}
+		optArgs.User, optArgs.Group, optArgs.Services = vaultAccessMembers(memberType, members)
+
+		res, err := client.VaultAddOwner(&freeipa.VaultAddOwnerArgs{Cn: vault.Name.ValueString()}, optArgs)
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	}
+
+	optArgs := &freeipa.VaultAddMemberOptionalArgs{
+		Username:  username,
+		Service:   service,
+		Shared:    shared,
+		NoMembers: freeipa.Bool(true),
+	}
+	optArgs.User, optArgs.Group, optArgs.Services = vaultAccessMembers(memberType, members)
+
+	res, err := client.VaultAddMember(&freeipa.VaultAddMemberArgs{Cn: vault.Name.ValueString()}, optArgs)
+	if err != nil {
+		return nil, err
+	}
+	return res.Failed, nil
+}
+
+func (r *VaultAccess) remove(ctx context.Context, vault VaultModel, memberType, member string) (freeipa.FailedOperations, error) {
+	members := &[]string{member}
+
+	username, service, shared := vaultScope(vault)
+
+	tflog.Trace(ctx, "Removing vault "+r.kind, map[string]any{
+		"vault_cn": vault.Name.ValueString(),
+		memberType: member,
+	})
+
+	client := r.provider.Client()
+
+	if r.kind == "owner" {
+		optArgs := &freeipa.VaultRemoveOwnerOptionalArgs{
+			Username:  username,
+			Service:   service,
+			Shared:    shared,
+			NoMembers: freeipa.Bool(true),
+		}
+		optArgs.User, optArgs.Group, optArgs.Services = vaultAccessMembers(memberType, members)
+
+		res, err := client.VaultRemoveOwner(&freeipa.VaultRemoveOwnerArgs{Cn: vault.Name.ValueString()}, optArgs)
+		if err != nil {
+			return nil, err
+		}
+		return res.Failed, nil
+	}
+
+	optArgs := &freeipa.VaultRemoveMemberOptionalArgs{
+		Username:  username,
+		Service:   service,
+		Shared:    shared,
+		NoMembers: freeipa.Bool(true),
+	}
+	optArgs.User, optArgs.Group, optArgs.Services = vaultAccessMembers(memberType, members)
+
+	res, err := client.VaultRemoveMember(&freeipa.VaultRemoveMemberArgs{Cn: vault.Name.ValueString()}, optArgs)
+	if err != nil {
+		return nil, err
+	}
+	return res.Failed, nil
+}
+
+// vaultAccessMembers returns the user, group and services arguments of the
+// vault member and owner commands for a single member.
+func vaultAccessMembers(memberType string, members *[]string) (users, groups, services *[]string) {
+	switch memberType {
+	case "user":
+		users = members
+	case "group":
+		groups = members
+	default:
+		services = members
+	}
+
+	return
+}
+
+// vaultAccessContains reports whether the members of a vault read from
+// FreeIPA include member. Service principals are stored with their realm,
+// which may be omitted from the configuration.
+func vaultAccessContains(members []string, memberType, member string) bool {
+	for _, m := range members {
+		if m == member {
+			return true
+		}
+
+		if memberType == "service" && !strings.Contains(member, "@") {
+			if principal, _, _ := strings.Cut(m, "@"); strings.EqualFold(principal, member) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseVaultAccessID parses the `<vault>/<user|group|service>/<member>`
+// import ID of a vault member or owner, where `<vault>` is the import ID of
+// the vault. Both the vault ID and service principals contain slashes, the
+// first separator leaving a valid vault ID and member is used.
+func parseVaultAccessID(id string) (VaultAccessModel, bool) {
+	for i := 0; i < len(id); i++ {
+		if id[i] != '/' {
+			continue
+		}
+
+		memberType, member, ok := strings.Cut(id[i+1:], "/")
+		if !ok || member == "" {
+			continue
+		}
+
+		vault, ok := parseVaultID(id[:i])
+		if !ok {
+			continue
+		}
+
+		m := VaultAccessModel{
+			Vault:            vault.Name,
+			Username:         vault.Username,
+			Service:          vault.Service,
+			Shared:           vault.Shared,
+			User:             types.StringNull(),
+			Group:            types.StringNull(),
+			ServicePrincipal: types.StringNull(),
+		}
+
+		switch {
+		case memberType == "user" && !strings.Contains(member, "/"):
+			m.User = types.StringValue(member)
+		case memberType == "group" && !strings.Contains(member, "/"):
+			m.Group = types.StringValue(member)
+		case memberType == "service":
+			m.ServicePrincipal = types.StringValue(member)
+		default:
+			continue
+		}
+
+		return m, true
+	}
+
+	return VaultAccessModel{}, false
+}
+
+func newVaultAccess(kind string) func(*provider.Provider) resource.Resource {
+	return func(p *provider.Provider) resource.Resource {
+		r := &VaultAccess{
+			provider: p,
+			kind:     kind,
+		}
+
+		var _ resource.Resource = r
+		var _ resource.ResourceWithValidateConfig = r
+		var _ resource.ResourceWithImportState = r
+
+		return r
+	}
+}
+
+func init() {
+	for _, kind := range vaultAccessKinds {
+		resources = append(resources, newVaultAccess(kind))
+	}
+}
@@ -0,0 +1,553 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type VaultData struct {
+	provider *provider.Provider
+}
+
+type VaultDataModel struct {
+	Vault      types.String `tfsdk:"vault_cn"`
+	Username   types.String `tfsdk:"username"`
+	Service    types.String `tfsdk:"service"`
+	Shared     types.Bool   `tfsdk:"shared"`
+	Data       types.String `tfsdk:"data"`
+	Password   types.String `tfsdk:"password"`
+	DataSHA256 types.String `tfsdk:"data_sha256"`
+}
+
+// vault returns the name and scope of the vault holding the data.
+func (m VaultDataModel) vault() VaultModel {
+	return VaultModel{
+		Name:     m.Vault,
+		Username: m.Username,
+		Service:  m.Service,
+		Shared:   m.Shared,
+	}
+}
+
+func (r *VaultData) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vault_data"
+}
+
+func (r *VaultData) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Archives secret data in a FreeIPA vault.",
+		Attributes: map[string]schema.Attribute{
+			"vault_cn": schema.StringAttribute{
+				Description: "Name of the vault the data is archived in",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "User owning the vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service": schema.StringAttribute{
+				Description: "Service principal owning the vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"shared": schema.BoolAttribute{
+				Description: "Whether the vault is a shared vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"data": schema.StringAttribute{
+				Description: "Secret data archived in the vault",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"password": schema.StringAttribute{
+				Description: "Password of a symmetric vault, the data is encrypted with it before being archived",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"data_sha256": schema.StringAttribute{
+				Description: "SHA-256 hash of the archived data, used to detect changes made outside of Terraform",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *VaultData) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config VaultDataModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scopes := 0
+
+	for _, set := range []bool{!config.Username.IsNull(), !config.Service.IsNull(), config.Shared.ValueBool()} {
+		if set {
+			scopes++
+		}
+	}
+
+	if scopes > 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("username"),
+			"Conflicting vault scopes",
+			`Only one of “username”, “service” and “shared” can be set.`,
+		)
+	}
+}
+
+func (r *VaultData) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan VaultDataModel
+
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Data.IsUnknown() {
+		plan.DataSHA256 = types.StringUnknown()
+	} else {
+		plan.DataSHA256 = types.StringValue(vaultDataSHA256([]byte(plan.Data.ValueString())))
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+func (r *VaultData) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan VaultDataModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.archive(ctx, plan, []byte(plan.Data.ValueString())); err != nil {
+		resp.Diagnostics.AddError("Failed to archive vault data", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *VaultData) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state VaultDataModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vaultType, salt, _, err := r.vaultInfo(ctx, state)
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read vault", "Reason: "+err.Error())
+
+		return
+	}
+
+	// Data of asymmetric vaults can only be decrypted with the private key,
+	// which the provider never has, and symmetric ones need the password,
+	// which is unknown after an import.
+	if vaultType == "asymmetric" || (vaultType == "symmetric" && state.Password.IsNull()) {
+		tflog.Debug(ctx, "Skipping drift detection of vault data", map[string]any{
+			"vault_cn": state.Vault.ValueString(),
+		})
+
+		return
+	}
+
+	data, found, err := r.retrieve(ctx, state)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to retrieve vault data", "Reason: "+err.Error())
+
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if vaultType == "symmetric" {
+		data, err = utils.VaultSymmetricDecrypt(state.Password.ValueString(), salt, data)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to decrypt vault data", "Reason: "+err.Error())
+
+			return
+		}
+	}
+
+	// Only the hash of the archived data is refreshed, the data itself is
+	// never read back into the state.
+	state.DataSHA256 = types.StringValue(vaultDataSHA256(data))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *VaultData) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan VaultDataModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.DataSHA256.Equal(state.DataSHA256) || !plan.Password.Equal(state.Password) {
+		if err := r.archive(ctx, plan, []byte(plan.Data.ValueString())); err != nil {
+			resp.Diagnostics.AddError("Failed to archive vault data", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated vault data has no effective difference", map[string]any{
+			"vault_cn": plan.Vault.ValueString(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *VaultData) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state VaultDataModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Vaults always hold data, it is replaced with empty data
+	if err := r.archive(ctx, state, []byte{}); err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete vault data", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *VaultData) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	vault, ok := parseVaultID(req.ID)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<name>”, “user/<username>/<name>”, “service/<principal>/<name>” or “shared/<name>”, got %q.", req.ID),
+		)
+
+		return
+	}
+
+	state := VaultDataModel{
+		Vault:      vault.Name,
+		Username:   vault.Username,
+		Service:    vault.Service,
+		Shared:     vault.Shared,
+		Data:       types.StringNull(),
+		Password:   types.StringNull(),
+		DataSHA256: types.StringNull(),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// vaultInfo returns the type of the vault along with its salt or public key.
+// go-freeipa fails to decode both binary values, they are extracted from the
+// decode error instead.
+func (r *VaultData) vaultInfo(ctx context.Context, m VaultDataModel) (vaultType string, salt, publicKey []byte, err error) {
+	args := &freeipa.VaultShowArgs{
+		Cn: m.Vault.ValueString(),
+	}
+
+	optArgs := &freeipa.VaultShowOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+	optArgs.Username, optArgs.Service, optArgs.Shared = vaultScope(m.vault())
+
+	tflog.Trace(ctx, "Calling VaultShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().VaultShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called VaultShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if salt, ok := utils.DecodeErrorBase64(err, "Ipavaultsalt"); ok {
+			return "symmetric", salt, nil, nil
+		}
+
+		if publicKey, ok := utils.DecodeErrorBase64(err, "Ipavaultpublickey"); ok {
+			return "asymmetric", nil, publicKey, nil
+		}
+
+		return "", nil, nil, err
+	}
+
+	if res.Result.Ipavaulttype != nil {
+		vaultType = *res.Result.Ipavaulttype
+	}
+
+	return vaultType, nil, nil, nil
+}
+
+// transportCert returns the certificate of the KRA, which protects the session
+// keys of archive and retrieve operations.
+func (r *VaultData) transportCert(ctx context.Context) (*x509.Certificate, error) {
+	args := &freeipa.VaultconfigShowArgs{}
+
+	tflog.Trace(ctx, "Calling VaultconfigShow", map[string]any{
+		"args":     args,
+		"opt_args": nil,
+	})
+
+	res, err := r.provider.Client().VaultconfigShow(args, nil)
+
+	tflog.Trace(ctx, "Called VaultconfigShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	var der []byte
+
+	if err != nil {
+		var ok bool
+
+		// The certificate is binary, go-freeipa fails to decode it
+		if der, ok = utils.DecodeErrorBase64(err, "TransportCert"); !ok {
+			return nil, err
+		}
+	} else if der, err = base64.StdEncoding.DecodeString(res.Result.TransportCert); err != nil {
+		return nil, fmt.Errorf("decoding KRA transport certificate: %w", err)
+	}
+
+	return x509.ParseCertificate(der)
+}
+
+// archive encrypts the data as the ipa client does for the type of the vault,
+// and archives it.
+func (r *VaultData) archive(ctx context.Context, m VaultDataModel, data []byte) error {
+	vaultType, salt, publicKey, err := r.vaultInfo(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	switch vaultType {
+	case "symmetric":
+		if m.Password.IsNull() {
+			return fmt.Errorf("vault %q is a symmetric vault, a password is required", m.Vault.ValueString())
+		}
+
+		data, err = utils.VaultSymmetricEncrypt(m.Password.ValueString(), salt, data)
+	case "asymmetric":
+		data, err = utils.VaultAsymmetricEncrypt(publicKey, data)
+	}
+
+	if err != nil {
+		return fmt.Errorf("encrypting vault data: %w", err)
+	}
+
+	cert, err := r.transportCert(ctx)
+	if err != nil {
+		return fmt.Errorf("reading KRA transport certificate: %w", err)
+	}
+
+	key, wrappedKey, err := utils.NewVaultSessionKey(cert)
+	if err != nil {
+		return err
+	}
+
+	nonce, wrappedData, err := utils.WrapVaultData(key, data)
+	if err != nil {
+		return err
+	}
+
+	args := &freeipa.VaultArchiveInternalArgs{
+		Cn:         m.Vault.ValueString(),
+		SessionKey: base64.StdEncoding.EncodeToString(wrappedKey),
+		VaultData:  base64.StdEncoding.EncodeToString(wrappedData),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+	}
+
+	optArgs := &freeipa.VaultArchiveInternalOptionalArgs{
+		WrappingAlgo: freeipa.String(utils.VaultWrappingAlgo),
+	}
+	optArgs.Username, optArgs.Service, optArgs.Shared = vaultScope(m.vault())
+
+	// The arguments hold the data, only the vault is logged
+	tflog.Trace(ctx, "Calling VaultArchiveInternal", map[string]any{
+		"cn": args.Cn,
+	})
+
+	res, err := r.provider.Client().VaultArchiveInternal(args, optArgs)
+
+	tflog.Trace(ctx, "Called VaultArchiveInternal", map[string]any{
+		"err": err,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	tflog.Debug(ctx, "Archived vault data", map[string]any{
+		"summary": res.Summary,
+	})
+
+	return nil
+}
+
+// retrieve returns the archived data of the vault, still encrypted with the
+// vault password for symmetric vaults. It reports whether data was found.
+func (r *VaultData) retrieve(ctx context.Context, m VaultDataModel) ([]byte, bool, error) {
+	cert, err := r.transportCert(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("reading KRA transport certificate: %w", err)
+	}
+
+	key, wrappedKey, err := utils.NewVaultSessionKey(cert)
+	if err != nil {
+		return nil, false, err
+	}
+
+	args := &freeipa.VaultRetrieveInternalArgs{
+		Cn:         m.Vault.ValueString(),
+		SessionKey: base64.StdEncoding.EncodeToString(wrappedKey),
+	}
+
+	optArgs := &freeipa.VaultRetrieveInternalOptionalArgs{
+		WrappingAlgo: freeipa.String(utils.VaultWrappingAlgo),
+	}
+	optArgs.Username, optArgs.Service, optArgs.Shared = vaultScope(m.vault())
+
+	tflog.Trace(ctx, "Calling VaultRetrieveInternal", map[string]any{
+		"cn": args.Cn,
+	})
+
+	res, err := r.provider.Client().VaultRetrieveInternal(args, optArgs)
+
+	tflog.Trace(ctx, "Called VaultRetrieveInternal", map[string]any{
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		// Vaults without archived data are reported as not found
+		if errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	wrappedData, err := vaultResultBytes(res.Result, "vault_data")
+	if err != nil {
+		return nil, false, err
+	}
+
+	nonce, err := vaultResultBytes(res.Result, "nonce")
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := utils.UnwrapVaultData(key, nonce, wrappedData)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+// vaultResultBytes extracts a `{"__base64__": "..."}` value from the untyped
+// result of VaultRetrieveInternal.
+func vaultResultBytes(result interface{}, key string) ([]byte, error) {
+	values, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected vault retrieve result %T", result)
+	}
+
+	value, ok := values[key].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("missing %s in vault retrieve result", key)
+	}
+
+	encoded, ok := value["__base64__"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s value in vault retrieve result", key)
+	}
+
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
+func vaultDataSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func NewVaultData(p *provider.Provider) resource.Resource {
+	r := &VaultData{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithModifyPlan = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewVaultData)
+}
@@ -0,0 +1,476 @@
+package resources
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/provider"
+	"github.com/camptocamp/terraform-provider-freeipa/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type Vault struct {
+	provider *provider.Provider
+}
+
+type VaultModel struct {
+	Name        types.String `tfsdk:"cn"`
+	Description types.String `tfsdk:"description"`
+	Type        types.String `tfsdk:"ipavaulttype"`
+	Username    types.String `tfsdk:"username"`
+	Service     types.String `tfsdk:"service"`
+	Shared      types.Bool   `tfsdk:"shared"`
+	Password    types.String `tfsdk:"password"`
+	PublicKey   types.String `tfsdk:"ipavaultpublickey"`
+}
+
+func (r *Vault) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vault"
+}
+
+func (r *Vault) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     0,
+		Description: "Manages a FreeIPA vault.",
+		Attributes: map[string]schema.Attribute{
+			"cn": schema.StringAttribute{
+				Description: "Vault name",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Vault description",
+				Optional:    true,
+			},
+			"ipavaulttype": schema.StringAttribute{
+				Description: "Vault type (`standard`, `symmetric` or `asymmetric`). Defaults to `standard`",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "User owning the vault. Defaults to the user the provider is connected as when neither service nor shared is set",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service": schema.StringAttribute{
+				Description: "Service principal owning the vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"shared": schema.BoolAttribute{
+				Description: "Whether the vault is a shared vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				Description: "Password of a symmetric vault. It is never sent to FreeIPA, the data is encrypted with it by the client",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"ipavaultpublickey": schema.StringAttribute{
+				Description: "PEM encoded public key of an asymmetric vault",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *Vault) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config VaultModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scopes := 0
+
+	for _, set := range []bool{!config.Username.IsNull(), !config.Service.IsNull(), config.Shared.ValueBool()} {
+		if set {
+			scopes++
+		}
+	}
+
+	if scopes > 1 {
+		resp.Diagnostics.AddError(
+			"Invalid configuration",
+			`Only one of “username”, “service” and “shared” can be set.`,
+		)
+	}
+
+	if config.Type.IsUnknown() || config.Password.IsUnknown() || config.PublicKey.IsUnknown() {
+		return
+	}
+
+	vaultType := config.Type.ValueString()
+	if config.Type.IsNull() {
+		vaultType = "standard"
+	}
+
+	switch vaultType {
+	case "standard":
+	case "symmetric":
+		if config.Password.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("password"),
+				"Missing password",
+				`“password” is required for symmetric vaults.`,
+			)
+		}
+	case "asymmetric":
+		if config.PublicKey.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ipavaultpublickey"),
+				"Missing public key",
+				`“ipavaultpublickey” is required for asymmetric vaults.`,
+			)
+		}
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipavaulttype"),
+			"Invalid configuration",
+			`The vault type must be “standard”, “symmetric” or “asymmetric”.`,
+		)
+	}
+
+	if vaultType != "symmetric" && !config.Password.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password"),
+			"Invalid configuration",
+			`“password” only applies to symmetric vaults.`,
+		)
+	}
+
+	if vaultType != "asymmetric" && !config.PublicKey.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipavaultpublickey"),
+			"Invalid configuration",
+			`“ipavaultpublickey” only applies to asymmetric vaults.`,
+		)
+	}
+}
+
+func (r *Vault) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan VaultModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Type.IsUnknown() {
+		plan.Type = types.StringValue("standard")
+	}
+
+	args := &freeipa.VaultAddInternalArgs{
+		Cn: plan.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.VaultAddInternalOptionalArgs{
+		Description:  plan.Description.ValueStringPointer(),
+		Ipavaulttype: plan.Type.ValueStringPointer(),
+	}
+	optArgs.Username, optArgs.Service, optArgs.Shared = vaultScope(plan)
+
+	// Binary attributes are sent base64 encoded. The salt is used by clients
+	// to derive the encryption key of symmetric vaults from their password.
+	switch plan.Type.ValueString() {
+	case "symmetric":
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			resp.Diagnostics.AddError("Failed to generate vault salt", "Reason: "+err.Error())
+
+			return
+		}
+
+		optArgs.Ipavaultsalt = freeipa.String(base64.StdEncoding.EncodeToString(salt))
+	case "asymmetric":
+		optArgs.Ipavaultpublickey = freeipa.String(base64.StdEncoding.EncodeToString([]byte(plan.PublicKey.ValueString())))
+	}
+
+	tflog.Trace(ctx, "Calling VaultAddInternal", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().VaultAddInternal(args, optArgs)
+
+	tflog.Trace(ctx, "Called VaultAddInternal", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	// The vault is created even when go-freeipa fails to decode its salt or
+	// public key from the response.
+	if err != nil && !utils.IsVaultBytesDecodeError(err) {
+		resp.Diagnostics.AddError("Failed to create vault", "Reason: "+err.Error())
+
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Vault) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state VaultModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.VaultShowArgs{
+		Cn: state.Name.ValueString(),
+	}
+
+	optArgs := &freeipa.VaultShowOptionalArgs{
+		NoMembers: freeipa.Bool(true),
+	}
+	optArgs.Username, optArgs.Service, optArgs.Shared = vaultScope(state)
+
+	tflog.Trace(ctx, "Calling VaultShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().VaultShow(args, optArgs)
+
+	tflog.Trace(ctx, "Called VaultShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		if utils.RemoveResourceIfNotFound(ctx, err, &resp.State) {
+			return
+		}
+
+		// go-freeipa cannot decode the binary attributes of symmetric and
+		// asymmetric vaults, the vault exists so the known state is kept.
+		if utils.IsVaultBytesDecodeError(err) {
+			tflog.Warn(ctx, "Ignoring go-freeipa decode error on VaultShow", map[string]any{
+				"err": err,
+			})
+
+			// Only symmetric vaults have a salt and asymmetric ones a public
+			// key, which tells the type of imported vaults.
+			if state.Type.IsNull() {
+				if strings.Contains(err.Error(), "Ipavaultsalt") {
+					state.Type = types.StringValue("symmetric")
+				} else {
+					state.Type = types.StringValue("asymmetric")
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			}
+
+			return
+		}
+
+		resp.Diagnostics.AddError("Failed to read vault", "Reason: "+err.Error())
+
+		return
+	}
+
+	state.Description = types.StringPointerValue(res.Result.Description)
+	state.Type = types.StringPointerValue(res.Result.Ipavaulttype)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *Vault) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state, plan VaultModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Description.Equal(state.Description) {
+		args := &freeipa.VaultModInternalArgs{
+			Cn: plan.Name.ValueString(),
+		}
+
+		optArgs := &freeipa.VaultModInternalOptionalArgs{
+			Description: freeipa.String(plan.Description.ValueString()),
+		}
+		optArgs.Username, optArgs.Service, optArgs.Shared = vaultScope(plan)
+
+		tflog.Trace(ctx, "Calling VaultModInternal", map[string]any{
+			"args":     args,
+			"opt_args": optArgs,
+		})
+
+		res, err := r.provider.Client().VaultModInternal(args, optArgs)
+
+		tflog.Trace(ctx, "Called VaultModInternal", map[string]any{
+			"res": res,
+			"err": err,
+		})
+
+		if err != nil && !utils.IsVaultBytesDecodeError(err) {
+			resp.Diagnostics.AddError("Failed to update vault", "Reason: "+err.Error())
+
+			return
+		}
+	} else {
+		tflog.Debug(ctx, "Updated vault has no effective difference", map[string]any{
+			"cn": plan.Name.ValueString(),
+		})
+	}
+
+	if plan.Type.IsUnknown() {
+		plan.Type = state.Type
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *Vault) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state VaultModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	args := &freeipa.VaultDelArgs{
+		Cn: []string{state.Name.ValueString()},
+	}
+
+	optArgs := &freeipa.VaultDelOptionalArgs{}
+	optArgs.Username, optArgs.Service, optArgs.Shared = vaultScope(state)
+
+	tflog.Trace(ctx, "Calling VaultDel", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err := r.provider.Client().VaultDel(args, optArgs)
+
+	tflog.Trace(ctx, "Called VaultDel", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if err != nil {
+		var freeipaErr *freeipa.Error
+
+		if !errors.As(err, &freeipaErr) || freeipaErr.Code != freeipa.NotFoundCode {
+			resp.Diagnostics.AddError("Failed to delete vault", "Reason: "+err.Error())
+
+			return
+		}
+	}
+}
+
+func (r *Vault) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	state, ok := parseVaultID(req.ID)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an import ID of the form “<name>”, “user/<username>/<name>”, “service/<principal>/<name>” or “shared/<name>”, got %q.", req.ID),
+		)
+
+		return
+	}
+
+	state.Type = types.StringNull()
+	state.Password = types.StringNull()
+	state.PublicKey = types.StringNull()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// parseVaultID parses the `<name>`, `user/<username>/<name>`,
+// `service/<principal>/<name>` or `shared/<name>` import ID of a vault into its
+// name and scope.
+func parseVaultID(id string) (VaultModel, bool) {
+	m := VaultModel{
+		Username: types.StringNull(),
+		Service:  types.StringNull(),
+		Shared:   types.BoolNull(),
+	}
+
+	scope, rest, _ := strings.Cut(id, "/")
+
+	// Service principals contain slashes, the vault name is the last part
+	i := strings.LastIndex(rest, "/")
+
+	switch {
+	case scope == "shared" && rest != "" && !strings.Contains(rest, "/"):
+		m.Name = types.StringValue(rest)
+		m.Shared = types.BoolValue(true)
+	case scope == "user" && i > 0 && i < len(rest)-1 && !strings.Contains(rest[:i], "/"):
+		m.Name = types.StringValue(rest[i+1:])
+		m.Username = types.StringValue(rest[:i])
+	case scope == "service" && i > 0 && i < len(rest)-1:
+		m.Name = types.StringValue(rest[i+1:])
+		m.Service = types.StringValue(rest[:i])
+	case id != "" && !strings.Contains(id, "/"):
+		m.Name = types.StringValue(id)
+	default:
+		return m, false
+	}
+
+	return m, true
+}
+
+// vaultScope returns the arguments selecting the user, service or shared vault
+// container of the vault.
+func vaultScope(m VaultModel) (username, service *string, shared *bool) {
+	if m.Shared.ValueBool() {
+		shared = freeipa.Bool(true)
+	}
+
+	return stringToStringPointer(m.Username), stringToStringPointer(m.Service), shared
+}
+
+func NewVault(p *provider.Provider) resource.Resource {
+	r := &Vault{
+		provider: p,
+	}
+
+	var _ resource.Resource = r
+	var _ resource.ResourceWithValidateConfig = r
+	var _ resource.ResourceWithImportState = r
+
+	return r
+}
+
+func init() {
+	resources = append(resources, NewVault)
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// CertificateDER decodes a certificate as returned by FreeIPA, either as a
+// base64 string or as a binary value, possibly wrapped in a single element
+// list.
+func CertificateDER(v interface{}) ([]byte, error) {
+	raw := v
+
+	if l, ok := raw.([]interface{}); ok && len(l) == 1 {
+		raw = l[0]
+	}
+
+	if b, ok := raw.(map[string]interface{}); ok {
+		raw = b["__base64__"]
+	}
+
+	s, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected certificate value %v", v)
+	}
+
+	return base64.StdEncoding.DecodeString(s)
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// certMapDataPrefix starts the mapping data FreeIPA derives from a
+// certificate issuer and subject.
+const certMapDataPrefix = "X509:<I>"
+
+// dnAttributeTypes are the short names FreeIPA uses for the attribute types
+// of certificate distinguished names.
+var dnAttributeTypes = map[string]string{
+	"2.5.4.3":                    "CN",
+	"2.5.4.5":                    "serialNumber",
+	"2.5.4.6":                    "C",
+	"2.5.4.7":                    "L",
+	"2.5.4.8":                    "ST",
+	"2.5.4.9":                    "STREET",
+	"2.5.4.10":                   "O",
+	"2.5.4.11":                   "OU",
+	"0.9.2342.19200300.100.1.1":  "UID",
+	"0.9.2342.19200300.100.1.25": "DC",
+	"1.2.840.113549.1.9.1":       "E",
+}
+
+// dnAttributeAliases are the alternative names of the attribute types, as
+// found in distinguished names written by hand.
+var dnAttributeAliases = map[string]string{
+	"EMAILADDRESS": "E",
+	"EMAIL":        "E",
+	"USERID":       "UID",
+}
+
+// CertMapData returns the mapping data FreeIPA stores for the certificates
+// issued by issuer to subject, both given in LDAP order.
+func CertMapData(issuer, subject string) string {
+	return certMapDataPrefix + reverseDN(issuer) + "<S>" + reverseDN(subject)
+}
+
+// CertMapDataFromCertificate returns the mapping data FreeIPA stores for a
+// PEM or base64 encoded certificate.
+func CertMapDataFromCertificate(certificate string) (string, error) {
+	der, err := ParseCertificate(certificate)
+	if err != nil {
+		return "", err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return "", err
+	}
+
+	return certMapDataPrefix + x500Name(cert.Issuer.ToRDNSequence()) + "<S>" + x500Name(cert.Subject.ToRDNSequence()), nil
+}
+
+// ParseCertificate returns the DER encoding of a certificate given either in
+// PEM or as base64 encoded DER.
+func ParseCertificate(certificate string) ([]byte, error) {
+	if block, _ := pem.Decode([]byte(certificate)); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block %q, expected CERTIFICATE", block.Type)
+		}
+
+		return block.Bytes, nil
+	}
+
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(certificate), ""))
+	if err != nil {
+		return nil, fmt.Errorf("certificate is neither PEM nor base64 encoded")
+	}
+
+	return der, nil
+}
+
+// SameCertMapData reports whether both mapping data designate the same
+// certificates, ignoring the case and the spacing of their distinguished
+// names.
+func SameCertMapData(a, b string) bool {
+	return normalizeCertMapData(a) == normalizeCertMapData(b)
+}
+
+func normalizeCertMapData(data string) string {
+	data = strings.TrimSpace(data)
+
+	rest, ok := strings.CutPrefix(data, certMapDataPrefix)
+	if !ok {
+		return data
+	}
+
+	issuer, subject, ok := strings.Cut(rest, "<S>")
+	if !ok {
+		return data
+	}
+
+	return certMapDataPrefix + normalizeDN(issuer) + "<S>" + normalizeDN(subject)
+}
+
+func normalizeDN(dn string) string {
+	rdns := splitDN(dn, ',')
+
+	for i, rdn := range rdns {
+		attrType, value, _ := strings.Cut(rdn, "=")
+
+		attrType = strings.ToUpper(strings.TrimSpace(attrType))
+		if alias, ok := dnAttributeAliases[attrType]; ok {
+			attrType = alias
+		}
+
+		rdns[i] = attrType + "=" + strings.ToLower(strings.TrimSpace(value))
+	}
+
+	return strings.Join(rdns, ",")
+}
+
+// reverseDN returns dn with its RDNs in the reverse order.
+func reverseDN(dn string) string {
+	rdns := splitDN(dn, ',')
+
+	for i, j := 0, len(rdns)-1; i < j; i, j = i+1, j-1 {
+		rdns[i], rdns[j] = rdns[j], rdns[i]
+	}
+
+	return strings.Join(rdns, ",")
+}
+
+// splitDN splits dn on the unescaped occurrences of sep, trimming the
+// surrounding spaces.
+func splitDN(dn string, sep byte) []string {
+	var parts []string
+
+	start := 0
+	for i := 0; i < len(dn); i++ {
+		switch dn[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, strings.TrimSpace(dn[start:i]))
+			start = i + 1
+		}
+	}
+
+	if s := strings.TrimSpace(dn[start:]); s != "" || len(parts) > 0 {
+		parts = append(parts, s)
+	}
+
+	return parts
+}
+
+// x500Name formats name the way FreeIPA writes certificate names in mapping
+// data, starting with the least specific RDN.
+func x500Name(name pkix.RDNSequence) string {
+	rdns := make([]string, 0, len(name))
+
+	for _, rdn := range name {
+		atvs := make([]string, 0, len(rdn))
+
+		for _, atv := range rdn {
+			attrType, ok := dnAttributeTypes[atv.Type.String()]
+			if !ok {
+				attrType = atv.Type.String()
+			}
+
+			atvs = append(atvs, attrType+"="+escapeDNValue(fmt.Sprint(atv.Value)))
+		}
+
+		rdns = append(rdns, strings.Join(atvs, "+"))
+	}
+
+	return strings.Join(rdns, ",")
+}
+
+// escapeDNValue escapes the characters of an attribute value which are
+// special in distinguished names, as described in RFC 4514.
+func escapeDNValue(value string) string {
+	var b strings.Builder
+
+	for i, c := range value {
+		switch {
+		case strings.ContainsRune(",+\"\\<>;=", c),
+			(c == ' ' || c == '#') && i == 0,
+			c == ' ' && i == len(value)-1:
+			b.WriteByte('\\')
+		}
+
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCertMapData(t *testing.T) {
+	got := CertMapData("CN=Certificate Authority,O=EXAMPLE.COM", "CN=jdoe, OU=People\\, Staff,O=EXAMPLE.COM")
+	want := "X509:<I>O=EXAMPLE.COM,CN=Certificate Authority<S>O=EXAMPLE.COM,OU=People\\, Staff,CN=jdoe"
+
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCertMapDataFromCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			Organization: []string{"EXAMPLE.COM"},
+			CommonName:   "jdoe",
+		},
+		NotBefore: time.Now(),
+		NotAfter:  time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := CertMapDataFromCertificate(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "X509:<I>O=EXAMPLE.COM,CN=jdoe<S>O=EXAMPLE.COM,CN=jdoe"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := CertMapDataFromCertificate("not a certificate"); err == nil {
+		t.Error("expected an invalid certificate to fail")
+	}
+}
+
+func TestSameCertMapData(t *testing.T) {
+	cases := map[string]struct {
+		a, b string
+		same bool
+	}{
+		"case and spacing": {
+			a:    "X509:<I>O=EXAMPLE.COM,CN=Certificate Authority<S>O=EXAMPLE.COM,CN=jdoe",
+			b:    "X509:<I>o=example.com, cn=certificate authority<S>O=EXAMPLE.COM,CN=JDOE",
+			same: true,
+		},
+		"attribute alias": {
+			a:    "X509:<I>O=EXAMPLE.COM<S>emailAddress=jdoe@example.com",
+			b:    "X509:<I>O=EXAMPLE.COM<S>E=jdoe@example.com",
+			same: true,
+		},
+		"other subject": {
+			a:    "X509:<I>O=EXAMPLE.COM<S>O=EXAMPLE.COM,CN=jdoe",
+			b:    "X509:<I>O=EXAMPLE.COM<S>O=EXAMPLE.COM,CN=jroe",
+			same: false,
+		},
+		"other format": {
+			a:    "X509:<SR>01",
+			b:    "X509:<SR>01",
+			same: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if same := SameCertMapData(c.a, c.b); same != c.same {
+				t.Errorf("got %v, want %v", same, c.same)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type concurrencyTransport struct {
+	base  http.RoundTripper
+	slots chan struct{}
+}
+
+// NewConcurrencyTransport wraps base so that at most max requests are in
+// flight at once, further requests wait for a slot or for their context to be
+// done. A request holds its slot until its response body is closed. Each
+// transport has its own slots, the clients sharing a limit share the
+// transport. A max of zero or less does not limit the requests.
+func NewConcurrencyTransport(base http.RoundTripper, max int) http.RoundTripper {
+	if max <= 0 {
+		return base
+	}
+
+	return &concurrencyTransport{base: base, slots: make(chan struct{}, max)}
+}
+
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil || res.Body == nil {
+		<-t.slots
+
+		return res, err
+	}
+
+	res.Body = &slotBody{ReadCloser: res.Body, release: func() { <-t.slots }}
+
+	// go-freeipa does not close the response of its Kerberos login, login
+	// responses are read at once to release their slot
+	if strings.Contains(req.URL.Path, "/session/login_") {
+		data, err := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	return res, nil
+}
+
+// slotBody releases the slot of its request once closed, the response being
+// still read from the server until then.
+type slotBody struct {
+	io.ReadCloser
+
+	once    sync.Once
+	release func()
+}
+
+func (b *slotBody) Close() error {
+	err := b.ReadCloser.Close()
+
+	b.once.Do(b.release)
+
+	return err
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowTransport answers every request after a delay, recording the highest
+// number of requests in flight.
+type slowTransport struct {
+	inFlight, max atomic.Int32
+}
+
+func (t *slowTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := t.inFlight.Add(1)
+	defer t.inFlight.Add(-1)
+
+	for {
+		m := t.max.Load()
+		if n <= m || t.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestConcurrencyTransport(t *testing.T) {
+	base := &slowTransport{}
+	tspt := NewConcurrencyTransport(base, 3)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			req, _ := http.NewRequest(http.MethodPost, "https://ipa.example.test/ipa/session/json", nil)
+
+			res, err := tspt.RoundTrip(req)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			res.Body.Close()
+		}()
+	}
+
+	wg.Wait()
+
+	if max := base.max.Load(); max > 3 {
+		t.Errorf("got %d requests in flight, want at most 3", max)
+	}
+}
+
+func TestConcurrencyTransportContextDone(t *testing.T) {
+	tspt := NewConcurrencyTransport(&slowTransport{}, 1).(*concurrencyTransport)
+
+	// Take the only slot
+	tspt.slots <- struct{}{}
+	defer func() { <-tspt.slots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://ipa.example.test/ipa/session/json", nil)
+	if _, err := tspt.RoundTrip(req); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+// testRoundTrip sends a request through tspt, giving up once timeout elapsed
+// waiting for a slot.
+func testRoundTrip(t *testing.T, tspt http.RoundTripper, path string, timeout time.Duration) (*http.Response, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://ipa.example.test"+path, nil)
+
+	return tspt.RoundTrip(req)
+}
+
+func TestConcurrencyTransportBodyClosed(t *testing.T) {
+	tspt := NewConcurrencyTransport(&slowTransport{}, 1)
+
+	res, err := testRoundTrip(t, tspt, "/ipa/session/json", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The response is still being read, its slot is held
+	if _, err := testRoundTrip(t, tspt, "/ipa/session/json", 50*time.Millisecond); err != context.DeadlineExceeded {
+		t.Fatalf("got %v before the body was closed, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Closing the body twice releases the slot once
+	res.Body.Close()
+	res.Body.Close()
+
+	next, err := testRoundTrip(t, tspt, "/ipa/session/json", time.Second)
+	if err != nil {
+		t.Fatalf("got %v after the body was closed, want the request sent", err)
+	}
+
+	if _, err := testRoundTrip(t, tspt, "/ipa/session/json", 50*time.Millisecond); err != context.DeadlineExceeded {
+		t.Errorf("got %v while the slot is held again, want %v", err, context.DeadlineExceeded)
+	}
+
+	next.Body.Close()
+}
+
+func TestConcurrencyTransportLogin(t *testing.T) {
+	tspt := NewConcurrencyTransport(&slowTransport{}, 1)
+
+	// go-freeipa does not close the response of its Kerberos login
+	if _, err := testRoundTrip(t, tspt, "/ipa/session/login_kerberos", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := testRoundTrip(t, tspt, "/ipa/session/json", time.Second); err != nil {
+		t.Errorf("got %v after a login, want the request sent", err)
+	}
+}
+
+func TestConcurrencyTransportIndependent(t *testing.T) {
+	first := NewConcurrencyTransport(&slowTransport{}, 1)
+	second := NewConcurrencyTransport(&slowTransport{}, 1)
+
+	res, err := testRoundTrip(t, first, "/ipa/session/json", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	// Transports with the same limit do not share their slots
+	other, err := testRoundTrip(t, second, "/ipa/session/json", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("got %v, want the request of the other transport sent", err)
+	}
+
+	other.Body.Close()
+}
+
+func TestConcurrencyTransportUnlimited(t *testing.T) {
+	base := &slowTransport{}
+
+	if tspt := NewConcurrencyTransport(base, 0); tspt != http.RoundTripper(base) {
+		t.Errorf("got %T, want the base transport", tspt)
+	}
+}
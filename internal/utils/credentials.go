@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadSecretFile returns the content of the file at path without the
+// surrounding whitespace, such as the trailing newline most secret files end
+// with.
+func ReadSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ReadKeytabFile returns the keytab held by the file at path, either as is or
+// base64 encoded.
+func ReadKeytabFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keytabs start with their format version, 0x0501 or 0x0502, which is not
+	// valid base64 text
+	if len(data) >= 2 && data[0] == 0x05 && (data[1] == 0x01 || data[1] == 0x02) {
+		return data, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(data)), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%s holds neither a keytab nor a base64 encoded one: %w", path, err)
+	}
+
+	return decoded, nil
+}
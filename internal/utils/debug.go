@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// redacted replaces the sensitive values in the logged requests.
+const redacted = "(redacted)"
+
+// sensitiveParams are the options whose values are never logged.
+var sensitiveParams = map[string]bool{
+	"data":            true,
+	"ipatokenotpkey":  true,
+	"ipavaultsalt":    true,
+	"krbprincipalkey": true,
+	"nonce":           true,
+	"private_key":     true,
+	"session_key":     true,
+	"vault_data":      true,
+}
+
+// sensitiveParamParts are the parts of option names whose values are never
+// logged, e.g. `userpassword` or `ipaidpclientsecret`.
+var sensitiveParamParts = []string{"password", "passphrase", "secret", "keytab"}
+
+// sensitiveArgs are the index of the first positional argument which is
+// never logged, by JSON-RPC method.
+var sensitiveArgs = map[string]int{
+	"passwd": 1,
+}
+
+// DebugLoggingEnabled reports whether the FreeIPA calls are logged, either
+// because debug is set or because Terraform logs at the TRACE level.
+func DebugLoggingEnabled(debug bool) bool {
+	if debug {
+		return true
+	}
+
+	level := os.Getenv("TF_LOG_PROVIDER")
+	if level == "" {
+		level = os.Getenv("TF_LOG")
+	}
+
+	return strings.EqualFold(level, "TRACE") || strings.EqualFold(level, "JSON")
+}
+
+type debugTransport struct {
+	base http.RoundTripper
+	ctx  context.Context
+	log  func(ctx context.Context, msg string, additionalFields ...map[string]interface{})
+}
+
+// NewDebugTransport wraps base to log the JSON-RPC method, the redacted
+// parameters and the outcome of every call through tflog, at the TRACE level
+// or at the DEBUG level when debug is set. Requests built without a context,
+// as go-freeipa does, are logged with ctx. Login requests are only logged
+// with their path and status, their credentials are never read.
+func NewDebugTransport(ctx context.Context, base http.RoundTripper, debug bool) http.RoundTripper {
+	t := &debugTransport{base: base, ctx: ctx, log: tflog.Trace}
+	if debug {
+		t.log = tflog.Debug
+	}
+
+	return t
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if ctx == context.Background() {
+		ctx = t.ctx
+	}
+
+	fields := map[string]interface{}{
+		"path": req.URL.Path,
+	}
+
+	rpc := strings.HasSuffix(req.URL.Path, "/session/json")
+	if rpc {
+		method, params := rpcRequest(req)
+
+		fields["method"] = method
+		fields["params"] = params
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	fields["duration"] = time.Since(start).String()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		t.log(ctx, "FreeIPA request failed", fields)
+
+		return resp, err
+	}
+
+	fields["http_status"] = resp.StatusCode
+
+	if rpc {
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+
+		if readErr != nil {
+			fields["error"] = readErr.Error()
+		}
+
+		var body struct {
+			Error *struct {
+				Code    int    `json:"code"`
+				Name    string `json:"name"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+
+		if json.Unmarshal(data, &body) == nil && body.Error != nil {
+			fields["error_code"] = body.Error.Code
+			fields["error_name"] = body.Error.Name
+			fields["error_message"] = body.Error.Message
+		}
+	}
+
+	t.log(ctx, "FreeIPA request", fields)
+
+	return resp, nil
+}
+
+// rpcRequest returns the method of the JSON-RPC request and its redacted
+// parameters.
+func rpcRequest(req *http.Request) (string, interface{}) {
+	if req.GetBody == nil {
+		return "", nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", nil
+	}
+	defer body.Close()
+
+	var rpc struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(body).Decode(&rpc); err != nil {
+		return "", nil
+	}
+
+	var args []interface{}
+	var options map[string]interface{}
+
+	if len(rpc.Params) > 0 {
+		_ = json.Unmarshal(rpc.Params[0], &args)
+	}
+
+	if len(rpc.Params) > 1 {
+		_ = json.Unmarshal(rpc.Params[1], &options)
+	}
+
+	return rpc.Method, RedactParams(rpc.Method, args, options)
+}
+
+// RedactParams returns the parameters of a JSON-RPC call with the values of
+// its sensitive arguments and options replaced.
+func RedactParams(method string, args []interface{}, options map[string]interface{}) []interface{} {
+	redactedArgs := make([]interface{}, len(args))
+
+	for i, arg := range args {
+		if first, ok := sensitiveArgs[method]; ok && i >= first {
+			redactedArgs[i] = redacted
+		} else {
+			redactedArgs[i] = redactValue(arg)
+		}
+	}
+
+	return []interface{}{redactedArgs, redactValue(options)}
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+
+		for k, value := range t {
+			if isSensitiveParam(k) {
+				m[k] = redacted
+			} else {
+				m[k] = redactValue(value)
+			}
+		}
+
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+
+		for i, value := range t {
+			l[i] = redactValue(value)
+		}
+
+		return l
+	}
+
+	return v
+}
+
+func isSensitiveParam(name string) bool {
+	name = strings.ToLower(name)
+
+	if sensitiveParams[name] {
+		return true
+	}
+
+	for _, part := range sensitiveParamParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactParams(t *testing.T) {
+	cases := map[string]struct {
+		method  string
+		args    []interface{}
+		options map[string]interface{}
+		want    []interface{}
+	}{
+		"password option": {
+			method:  "user_add",
+			args:    []interface{}{"jdoe"},
+			options: map[string]interface{}{"givenname": "John", "userpassword": "s3cret"},
+			want:    []interface{}{[]interface{}{"jdoe"}, map[string]interface{}{"givenname": "John", "userpassword": redacted}},
+		},
+		"nested secret": {
+			method:  "idp_add",
+			args:    []interface{}{"keycloak"},
+			options: map[string]interface{}{"all": true, "nested": map[string]interface{}{"ipaidpclientsecret": []interface{}{"s3cret"}}},
+			want:    []interface{}{[]interface{}{"keycloak"}, map[string]interface{}{"all": true, "nested": map[string]interface{}{"ipaidpclientsecret": redacted}}},
+		},
+		"vault data": {
+			method:  "vault_archive_internal",
+			args:    []interface{}{"vault"},
+			options: map[string]interface{}{"vault_data": "abc", "session_key": "def", "nonce": "ghi"},
+			want:    []interface{}{[]interface{}{"vault"}, map[string]interface{}{"vault_data": redacted, "session_key": redacted, "nonce": redacted}},
+		},
+		"positional password": {
+			method: "passwd",
+			args:   []interface{}{"jdoe", "s3cret", "123456"},
+			want:   []interface{}{[]interface{}{"jdoe", redacted, redacted}, map[string]interface{}{}},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := RedactParams(c.method, c.args, c.options)
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %#v, want %#v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDebugTransport(t *testing.T) {
+	var output bytes.Buffer
+
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	tspt := NewDebugTransport(ctx, fakeTransport{
+		body: `{"result": null, "error": {"code": 4001, "name": "NotFound", "message": "jdoe: user not found"}, "id": 0}`,
+	}, false)
+
+	req, err := http.NewRequest(http.MethodPost, "https://ipa.example.test/ipa/session/json", strings.NewReader(`{"method": "user_mod", "params": [["jdoe"], {"userpassword": "s3cret"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := tspt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The response is still readable by the client
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(body), "NotFound") {
+		t.Errorf("response body %q was not kept", body)
+	}
+
+	if strings.Contains(output.String(), "s3cret") {
+		t.Errorf("password logged: %s", output.String())
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("got %d log entries, want 1", len(entries))
+	}
+
+	entry := entries[0]
+
+	if entry["method"] != "user_mod" || entry["error_name"] != "NotFound" || entry["http_status"] != float64(200) {
+		t.Errorf("unexpected log entry %v", entry)
+	}
+}
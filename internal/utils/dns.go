@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+)
+
+// DnsRecordTypes are the DNS record types supported by the provider.
+var DnsRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "PTR", "SRV", "TXT", "SSHFP"}
+
+// DnsRecordValues returns the values of the records of the given type held by
+// record, nil for unsupported types.
+func DnsRecordValues(record *freeipa.Dnsrecord, recordType string) *[]string {
+	switch recordType {
+	case "A":
+		return record.Arecord
+	case "AAAA":
+		return record.Aaaarecord
+	case "CNAME":
+		return record.Cnamerecord
+	case "MX":
+		return record.Mxrecord
+	case "NS":
+		return record.Nsrecord
+	case "PTR":
+		return record.Ptrrecord
+	case "SRV":
+		return record.Srvrecord
+	case "TXT":
+		return record.Txtrecord
+	case "SSHFP":
+		return record.Sshfprecord
+	}
+
+	return nil
+}
+
+// RelativeDnsName returns name relative to zone when it is given as an
+// absolute name (with a trailing dot) within the zone, e.g. the reverse name
+// of an address in a reverse zone, `@` for the zone apex. Other names are
+// returned unchanged.
+func RelativeDnsName(name, zone string) string {
+	if !strings.HasSuffix(name, ".") {
+		return name
+	}
+
+	zone = strings.ToLower(strings.TrimSuffix(zone, ".") + ".")
+
+	if strings.EqualFold(name, zone) {
+		return "@"
+	}
+
+	if strings.HasSuffix(strings.ToLower(name), "."+zone) {
+		return name[:len(name)-len(zone)-1]
+	}
+
+	return name
+}
+
+// DnsNameValue extracts the name from the DNSName objects
+// ([{"__dns_name__": "..."}]) returned by FreeIPA.
+func DnsNameValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case map[string]interface{}:
+		if n, ok := t["__dns_name__"].(string); ok {
+			return n
+		}
+	case []interface{}:
+		if len(t) > 0 {
+			return DnsNameValue(t[0])
+		}
+	}
+	return ""
+}
+
+// AbsoluteDnsName returns name with a trailing dot, the form FreeIPA stores
+// zone names with.
+func AbsoluteDnsName(name string) string {
+	return strings.TrimSuffix(name, ".") + "."
+}
+
+// ReverseDnsName returns the absolute name of the PTR record of the IPv4 or
+// IPv6 address addr, false when addr is not an address.
+func ReverseDnsName(addr string) (string, bool) {
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return "", false
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa.", ip4[3], ip4[2], ip4[1], ip4[0]), true
+	}
+
+	const hexDigits = "0123456789abcdef"
+
+	var b strings.Builder
+
+	for i := len(ip) - 1; i >= 0; i-- {
+		b.WriteByte(hexDigits[ip[i]&0xf])
+		b.WriteByte('.')
+		b.WriteByte(hexDigits[ip[i]>>4])
+		b.WriteByte('.')
+	}
+
+	b.WriteString("ip6.arpa.")
+
+	return b.String(), true
+}
@@ -0,0 +1,35 @@
+package utils
+
+import "testing"
+
+func TestReverseDnsName(t *testing.T) {
+	cases := map[string]struct {
+		addr string
+		want string
+		ok   bool
+	}{
+		"ipv4": {
+			addr: "192.168.1.10",
+			want: "10.1.168.192.in-addr.arpa.",
+			ok:   true,
+		},
+		"ipv6": {
+			addr: "2001:db8::10",
+			want: "0.1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.",
+			ok:   true,
+		},
+		"not an address": {
+			addr: "www.example.com.",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := ReverseDnsName(c.addr)
+
+			if got != c.want || ok != c.ok {
+				t.Errorf("got %q, %v, want %q, %v", got, ok, c.want, c.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	dnskeyType = dnsmessage.Type(48)
+
+	dnskeyFlagZone = 0x0100
+	dnskeyFlagSEP  = 0x0001
+
+	dsDigestSHA1   = 1
+	dsDigestSHA256 = 2
+)
+
+// ZoneDSRecords returns the DS records, with SHA-256 digests, of the key
+// signing keys which the DNS server publishes for the zone. FreeIPA does not
+// expose the DNSSEC keys through its API, the DNSKEY records are queried from
+// the server over TCP instead. The records are in presentation format, e.g.
+// `60485 8 2 A1B2...`, and are empty until the keys of a newly signed zone
+// are generated.
+func ZoneDSRecords(ctx context.Context, server, zone string) ([]string, error) {
+	name, err := dnsmessage.NewName(AbsoluteDnsName(zone))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone name %q: %w", zone, err)
+	}
+
+	query := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: uint16(time.Now().UnixNano()), RecursionDesired: true},
+		Questions: []dnsmessage.Question{
+			{Name: name, Type: dnskeyType, Class: dnsmessage.ClassINET},
+		},
+	}
+
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// Messages sent over TCP are prefixed with their length
+	if _, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(packed))), packed...)); err != nil {
+		return nil, err
+	}
+
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+
+	answer := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, answer); err != nil {
+		return nil, err
+	}
+
+	var parser dnsmessage.Parser
+
+	header, err := parser.Start(answer)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.RCode != dnsmessage.RCodeSuccess {
+		return nil, fmt.Errorf("DNSKEY query for %s failed: %s", zone, header.RCode)
+	}
+
+	if err := parser.SkipAllQuestions(); err != nil {
+		return nil, err
+	}
+
+	records := []string{}
+
+	for {
+		h, err := parser.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if h.Type != dnskeyType || !strings.EqualFold(h.Name.String(), name.String()) {
+			if err := parser.SkipAnswer(); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		rr, err := parser.UnknownResource()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rr.Data) < 4 {
+			return nil, fmt.Errorf("invalid DNSKEY record for %s", zone)
+		}
+
+		// Only the key signing keys are referenced from the parent zone
+		if flags := binary.BigEndian.Uint16(rr.Data); flags&dnskeyFlagZone == 0 || flags&dnskeyFlagSEP == 0 {
+			continue
+		}
+
+		records = append(records, dsRecord(name.String(), rr.Data, dsDigestSHA256))
+	}
+
+	return records, nil
+}
+
+// dsRecord returns the DS record of the DNSKEY record data of the owner.
+func dsRecord(owner string, dnskey []byte, digestType uint8) string {
+	var wire []byte
+
+	for _, label := range strings.Split(strings.TrimSuffix(strings.ToLower(owner), "."), ".") {
+		wire = append(wire, byte(len(label)))
+		wire = append(wire, label...)
+	}
+	wire = append(wire, 0)
+
+	var digest []byte
+
+	switch digestType {
+	case dsDigestSHA1:
+		sum := sha1.Sum(append(wire, dnskey...))
+		digest = sum[:]
+	default:
+		sum := sha256.Sum256(append(wire, dnskey...))
+		digest = sum[:]
+	}
+
+	return fmt.Sprintf("%d %d %d %s", dnskeyTag(dnskey), dnskey[3], digestType, strings.ToUpper(hex.EncodeToString(digest)))
+}
+
+// dnskeyTag computes the key tag of the DNSKEY record data as described in
+// RFC 4034 appendix B.
+func dnskeyTag(dnskey []byte) uint16 {
+	var ac uint32
+
+	for i, b := range dnskey {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+
+	ac += ac >> 16 & 0xffff
+
+	return uint16(ac & 0xffff)
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// serveDNSKEY answers a single DNSKEY query over TCP with the given keys.
+func serveDNSKEY(t *testing.T, keys ...[]byte) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return
+		}
+
+		packed := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, packed); err != nil {
+			return
+		}
+
+		var query dnsmessage.Message
+		if err := query.Unpack(packed); err != nil {
+			return
+		}
+
+		reply := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: true},
+			Questions: query.Questions,
+		}
+
+		for _, key := range keys {
+			reply.Answers = append(reply.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: query.Questions[0].Name, Type: dnskeyType, Class: dnsmessage.ClassINET, TTL: 3600},
+				Body:   &dnsmessage.UnknownResource{Type: dnskeyType, Data: key},
+			})
+		}
+
+		answer, err := reply.Pack()
+		if err != nil {
+			return
+		}
+
+		conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(answer))), answer...))
+	}()
+
+	return l.Addr().String()
+}
+
+func TestZoneDSRecords(t *testing.T) {
+	ksk := []byte{0x01, 0x01, 3, 8, 0xde, 0xad, 0xbe, 0xef}
+	zsk := []byte{0x01, 0x00, 3, 8, 0xca, 0xfe, 0xba, 0xbe}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	records, err := ZoneDSRecords(ctx, serveDNSKEY(t, zsk, ksk), "example.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the key signing key has a DS record
+	if len(records) != 1 {
+		t.Fatalf("got %q, want a single record", records)
+	}
+
+	if !regexp.MustCompile(`^\d+ 8 2 [0-9A-F]{64}$`).MatchString(records[0]) {
+		t.Errorf("got %q, want a SHA-256 DS record of an algorithm 8 key", records[0])
+	}
+
+	if want := dsRecord("example.test.", ksk, dsDigestSHA256); records[0] != want {
+		t.Errorf("got %q, want %q", records[0], want)
+	}
+}
+
+func TestZoneDSRecordsUnsigned(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	records, err := ZoneDSRecords(ctx, serveDNSKEY(t), "example.test.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if records == nil || len(records) != 0 {
+		t.Errorf("got %#v, want no records", records)
+	}
+}
+
+func TestDnskeyTag(t *testing.T) {
+	// 0x0101 + 0x0308 + 0xffff = 0x10408, the carry is added back
+	if got, want := dnskeyTag([]byte{0x01, 0x01, 3, 8, 0xff, 0xff}), uint16(0x0409); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
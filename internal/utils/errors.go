@@ -1,6 +1,59 @@
 package utils
 
-import "strings"
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DependentEntryCode is the FreeIPA error code returned when an entry cannot be
+// deleted because another entry still requires it.
+const DependentEntryCode = 4307
+
+// AlreadyInactiveCode is the FreeIPA error code returned when disabling an
+// entry which is already disabled.
+const AlreadyInactiveCode = 4010
+
+// EmptyModlistCode is the FreeIPA error code returned when a modification
+// leaves an entry unchanged, e.g. when adding a value it already holds.
+const EmptyModlistCode = 4202
+
+// AttrValueNotFoundCode is the FreeIPA error code returned when removing a
+// value an entry does not hold.
+const AttrValueNotFoundCode = 4026
+
+// IsNotFoundError reports whether the given error is FreeIPA reporting the
+// requested entry does not exist.
+func IsNotFoundError(err error) bool {
+	var freeipaErr *freeipa.Error
+
+	return errors.As(err, &freeipaErr) && freeipaErr.Code == freeipa.NotFoundCode
+}
+
+// RemoveResourceIfNotFound removes the resource from state when the error
+// returned while reading it reports its entry was deleted out-of-band, so that
+// Terraform plans to create it again instead of failing. It returns whether
+// the resource was removed.
+func RemoveResourceIfNotFound(ctx context.Context, err error, state *tfsdk.State) bool {
+	if !IsNotFoundError(err) {
+		return false
+	}
+
+	tflog.Debug(ctx, "Entry no longer exists, removing it from state", map[string]any{
+		"err": err.Error(),
+	})
+
+	state.RemoveResource(ctx)
+
+	return true
+}
 
 // IsMembermanagerGroupDecodeError reports whether the given error originates from
 // go-freeipa failing to decode the MembermanagerGroup field returned by IPA.
@@ -11,3 +64,131 @@ func IsMembermanagerGroupDecodeError(err error) bool {
 
 	return strings.Contains(err.Error(), "MembermanagerGroup")
 }
+
+// IsManagedbyHostDecodeError reports whether the given error originates from
+// go-freeipa failing to decode the ManagedbyHost field of a service, which it
+// expects to hold a single host.
+func IsManagedbyHostDecodeError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return strings.Contains(err.Error(), "ManagedbyHost")
+}
+
+// IsDependentEntryError reports whether the given error is FreeIPA refusing to
+// delete an entry still referenced by another one.
+func IsDependentEntryError(err error) bool {
+	var freeipaErr *freeipa.Error
+
+	return errors.As(err, &freeipaErr) && freeipaErr.Code == DependentEntryCode
+}
+
+// IsVaultBytesDecodeError reports whether the given error originates from
+// go-freeipa failing to decode the binary salt or public key of a symmetric or
+// asymmetric vault, which FreeIPA returns base64 encoded.
+func IsVaultBytesDecodeError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return strings.Contains(err.Error(), "Ipavaultsalt") || strings.Contains(err.Error(), "Ipavaultpublickey")
+}
+
+// IsIdviewDecodeError reports whether the given error originates from
+// go-freeipa failing to decode the overrides or hosts of an ID view, which it
+// expects to hold exactly one entry each.
+func IsIdviewDecodeError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, field := range []string{"Useroverrides", "Groupoverrides", "Appliedtohosts"} {
+		if strings.Contains(err.Error(), "field "+field+":") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsFailedOperationsDecodeError reports whether the given error originates from
+// go-freeipa failing to decode a flat list of failures, such as the one of
+// idview_apply, as nested FailedOperations.
+func IsFailedOperationsDecodeError(err error) bool {
+	var typeErr *json.UnmarshalTypeError
+
+	return errors.As(err, &typeErr) && strings.Contains(typeErr.Field, "failed")
+}
+
+// IsCaaclMemberDecodeError reports whether the given error originates from
+// go-freeipa failing to decode the CAs, profiles or services of a CA ACL, which
+// it expects to hold a single member each.
+func IsCaaclMemberDecodeError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, field := range []string{"IpamembercaCa", "IpamembercertprofileCertprofile", "MemberserviceService"} {
+		if strings.Contains(err.Error(), "field "+field+":") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsCertDatetimeDecodeError reports whether the given error originates from
+// go-freeipa failing to decode the validity dates of a certificate, which
+// FreeIPA returns as a single `{"__datetime__": "..."}` object.
+func IsCertDatetimeDecodeError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return strings.Contains(err.Error(), "field ValidNotBefore:") || strings.Contains(err.Error(), "field ValidNotAfter:")
+}
+
+var decodeErrorBase64Pattern = regexp.MustCompile(`__base64__:([A-Za-z0-9+/=]+)`)
+
+// DecodeErrorBase64 extracts the value of a binary field from the error
+// go-freeipa returns when it fails to decode it. FreeIPA sends binary values as
+// `{"__base64__": "..."}` objects, which go-freeipa only reports in the error.
+func DecodeErrorBase64(err error, field string) ([]byte, bool) {
+	if err == nil || !strings.Contains(err.Error(), "field "+field+":") {
+		return nil, false
+	}
+
+	m := decodeErrorBase64Pattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return nil, false
+	}
+
+	v, decodeErr := base64.StdEncoding.DecodeString(m[1])
+	if decodeErr != nil {
+		return nil, false
+	}
+
+	return v, true
+}
+
+// DecodeErrorBase64List is like DecodeErrorBase64 for multivalued binary
+// fields, returning every value in the order FreeIPA sent them.
+func DecodeErrorBase64List(err error, field string) ([][]byte, bool) {
+	if err == nil || !strings.Contains(err.Error(), "field "+field+":") {
+		return nil, false
+	}
+
+	var values [][]byte
+
+	for _, m := range decodeErrorBase64Pattern.FindAllStringSubmatch(err.Error(), -1) {
+		v, decodeErr := base64.StdEncoding.DecodeString(m[1])
+		if decodeErr != nil {
+			return nil, false
+		}
+
+		values = append(values, v)
+	}
+
+	return values, len(values) > 0
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeTransport accepts every login and answers the JSON-RPC calls with body.
+type fakeTransport struct {
+	body string
+}
+
+func (t fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := ""
+	if strings.HasSuffix(req.URL.Path, "/session/json") {
+		body = t.body
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestRemoveResourceIfNotFound(t *testing.T) {
+	cases := map[string]struct {
+		body    string
+		removed bool
+	}{
+		"not found": {
+			body:    `{"result": null, "error": {"code": 4001, "name": "NotFound", "message": "missing: group not found"}, "id": 0}`,
+			removed: true,
+		},
+		"other error": {
+			body:    `{"result": null, "error": {"code": 2100, "name": "ACIError", "message": "Insufficient access"}, "id": 0}`,
+			removed: false,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			client, err := freeipa.Connect("ipa.example.test", fakeTransport{body: c.body}, "admin", "secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = client.GroupShow(&freeipa.GroupShowArgs{Cn: "missing"}, &freeipa.GroupShowOptionalArgs{})
+			if err == nil {
+				t.Fatal("expected the fake client to fail")
+			}
+
+			state := tfsdk.State{
+				Schema: schema.Schema{
+					Attributes: map[string]schema.Attribute{
+						"cn": schema.StringAttribute{Required: true},
+					},
+				},
+				Raw: tftypes.NewValue(
+					tftypes.Object{AttributeTypes: map[string]tftypes.Type{"cn": tftypes.String}},
+					map[string]tftypes.Value{"cn": tftypes.NewValue(tftypes.String, "missing")},
+				),
+			}
+
+			if removed := RemoveResourceIfNotFound(ctx, err, &state); removed != c.removed {
+				t.Errorf("got removed %v, want %v", removed, c.removed)
+			}
+
+			if state.Raw.IsNull() != c.removed {
+				t.Errorf("got null state %v, want %v", state.Raw.IsNull(), c.removed)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"golang.org/x/exp/slices"
+)
+
+// FailedReasonNotAMember is reported by FreeIPA when removing a member which
+// does not belong to the target entry.
+const FailedReasonNotAMember = "This entry is not a member"
+
+// FailedOperationsError turns the failures reported by a FreeIPA add/remove
+// member call into an error, ignoring the given reasons.
+func FailedOperationsError(failed freeipa.FailedOperations, ignoredReasons ...string) error {
+	var msgs []string
+	for kind, ops := range failed.GetFailures() {
+		for _, op := range ops {
+			if slices.Contains(ignoredReasons, op.Reason) {
+				continue
+			}
+			msgs = append(msgs, fmt.Sprintf("%s %s: %s", kind, op.Name, op.Reason))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	slices.Sort(msgs)
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// getKeytabCommand retrieves keytabs with the LDAP extended operation of
+// FreeIPA, which is not available through the JSON-RPC API.
+const getKeytabCommand = "ipa-getkeytab"
+
+// GetKeytabOptions are the server and the Kerberos credentials ipa-getkeytab
+// authenticates with. Exactly one of CCache, ClientKeytab and PKINIT is set.
+type GetKeytabOptions struct {
+	Server        string
+	Krb5ConfPath  string
+	CCache        string
+	ClientKeytab  []byte
+	PKINIT        *PKINITOptions
+	CACertificate []byte
+}
+
+// GetKeytab generates new keys for principal and returns them as a keytab,
+// invalidating the keytabs retrieved before. It runs ipa-getkeytab, which must
+// be installed, with the credentials of options.
+func GetKeytab(ctx context.Context, options GetKeytabOptions, principal string) ([]byte, error) {
+	command, err := exec.LookPath(getKeytabCommand)
+	if err != nil {
+		return nil, fmt.Errorf("%s is required to retrieve keytabs, install the FreeIPA client tools: %w", getKeytabCommand, err)
+	}
+
+	dir, err := os.MkdirTemp("", "terraform-provider-freeipa-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	// The server name is checked against the certificate, the port is the
+	// one of the API
+	server := options.Server
+	if h, _, err := net.SplitHostPort(server); err == nil {
+		server = h
+	}
+
+	keytab := filepath.Join(dir, "service.keytab")
+	args := []string{"-s", server, "-p", principal, "-k", keytab, "-q"}
+
+	if len(options.CACertificate) > 0 {
+		caCert := filepath.Join(dir, "ca.crt")
+		if err := os.WriteFile(caCert, options.CACertificate, 0o600); err != nil {
+			return nil, err
+		}
+
+		args = append(args, "--cacert", caCert)
+	}
+
+	env := append(os.Environ(), "KRB5_CONFIG="+options.Krb5ConfPath)
+
+	switch {
+	case options.CCache != "":
+		env = append(env, "KRB5CCNAME="+options.CCache)
+	case len(options.ClientKeytab) > 0:
+		// GSSAPI obtains the initial tickets from the client keytab, in a
+		// private credential cache
+		clientKeytab := filepath.Join(dir, "client.keytab")
+		if err := os.WriteFile(clientKeytab, options.ClientKeytab, 0o600); err != nil {
+			return nil, err
+		}
+
+		env = append(env, "KRB5_CLIENT_KTNAME=FILE:"+clientKeytab, "KRB5CCNAME=FILE:"+filepath.Join(dir, "ccache"))
+	case options.PKINIT != nil:
+		ccache := filepath.Join(dir, "ccache")
+		if err := KinitPKINIT(ctx, options.Krb5ConfPath, *options.PKINIT, ccache); err != nil {
+			return nil, err
+		}
+
+		env = append(env, "KRB5CCNAME=FILE:"+ccache)
+	default:
+		return nil, errors.New("retrieving keytabs requires Kerberos credentials")
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Env = env
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s failed: %w: %s", getKeytabCommand, err, msg)
+		}
+
+		return nil, fmt.Errorf("%s failed: %w", getKeytabCommand, err)
+	}
+
+	return os.ReadFile(keytab)
+}
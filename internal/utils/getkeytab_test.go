@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeGetKeytab installs an ipa-getkeytab script which writes its arguments
+// and Kerberos environment to the keytab file.
+func fakeGetKeytab(t *testing.T, script string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, getKeytabCommand), []byte("#!/bin/sh\n"+script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", dir)
+}
+
+func TestGetKeytab(t *testing.T) {
+	fakeGetKeytab(t, `
+all="$*"
+while [ $# -gt 0 ]; do
+	[ "$1" = -k ] && keytab="$2"
+	shift
+done
+printf '%s\n%s\n%s\n' "$all" "$KRB5_CONFIG" "$KRB5CCNAME" > "$keytab"
+`)
+
+	keytab, err := GetKeytab(context.Background(), GetKeytabOptions{
+		Server:       "ipa.example.test:8443",
+		Krb5ConfPath: "/etc/krb5.conf",
+		CCache:       "FILE:/tmp/krb5cc",
+	}, "HTTP/web.example.test@EXAMPLE.TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(keytab)
+
+	for _, want := range []string{"-s ipa.example.test ", "-p HTTP/web.example.test@EXAMPLE.TEST ", "\n/etc/krb5.conf\n", "\nFILE:/tmp/krb5cc\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestGetKeytabFailure(t *testing.T) {
+	fakeGetKeytab(t, `echo "Failed to parse result: Insufficient access rights" >&2; exit 9`)
+
+	_, err := GetKeytab(context.Background(), GetKeytabOptions{
+		Server:       "ipa.example.test",
+		ClientKeytab: []byte{0x05, 0x02},
+	}, "HTTP/web.example.test")
+
+	if err == nil || !strings.Contains(err.Error(), "Insufficient access rights") {
+		t.Errorf("got %v, want the error of ipa-getkeytab", err)
+	}
+}
+
+func TestGetKeytabNotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	if _, err := GetKeytab(context.Background(), GetKeytabOptions{CCache: "FILE:/tmp/krb5cc"}, "HTTP/web.example.test"); err == nil {
+		t.Error("got no error without ipa-getkeytab")
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// GroupShow calls GroupShow and, when go-freeipa fails to decode the
+// MembermanagerGroup field, fetches the group again without its member
+// attributes. withoutMembers reports whether the fallback was used, in which
+// case the member attributes of the result are empty.
+func GroupShow(ctx context.Context, client *freeipa.Client, args *freeipa.GroupShowArgs, optArgs *freeipa.GroupShowOptionalArgs) (res *freeipa.GroupShowResult, withoutMembers bool, err error) {
+	tflog.Trace(ctx, "Calling GroupShow", map[string]any{
+		"args":     args,
+		"opt_args": optArgs,
+	})
+
+	res, err = client.GroupShow(args, optArgs)
+	tflog.Trace(ctx, "Called GroupShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	if !IsMembermanagerGroupDecodeError(err) {
+		return res, false, err
+	}
+
+	tflog.Warn(ctx, "go-freeipa failed to decode MembermanagerGroup, reading group without members", map[string]any{
+		"err": err.Error(),
+		"cn":  args.Cn,
+	})
+
+	fallbackOptArgs := freeipa.GroupShowOptionalArgs{}
+	if optArgs != nil {
+		fallbackOptArgs = *optArgs
+	}
+	fallbackOptArgs.NoMembers = freeipa.Bool(true)
+
+	tflog.Trace(ctx, "Calling GroupShow", map[string]any{
+		"args":     args,
+		"opt_args": fallbackOptArgs,
+	})
+
+	res, err = client.GroupShow(args, &fallbackOptArgs)
+	tflog.Trace(ctx, "Called GroupShow", map[string]any{
+		"res": res,
+		"err": err,
+	})
+
+	return res, true, err
+}
+
+// SplitMembermanagers returns the member managers of a group or host group
+// joined by the result fixup transport.
+func SplitMembermanagers(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?)*\.?$`)
+
+// NormalizeHost returns the host, and optional port, of the FreeIPA server
+// given as a bare host name or as an HTTPS URL, e.g. `ipa.example.com` for
+// `https://ipa.example.com/ipa/ui/`. go-freeipa builds the URLs itself from the
+// host name.
+func NormalizeHost(value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	raw := value
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%q is neither a host name nor a URL: %w", value, err)
+	}
+
+	if u.Scheme != "https" {
+		return "", fmt.Errorf("%q uses the %s scheme, FreeIPA is only reached over https", value, u.Scheme)
+	}
+
+	if u.User != nil {
+		return "", fmt.Errorf("%q holds credentials, set username and password instead", value)
+	}
+
+	hostname := u.Hostname()
+	if hostname == "" {
+		return "", fmt.Errorf("%q has no host name", value)
+	}
+
+	if net.ParseIP(hostname) == nil && !hostnamePattern.MatchString(hostname) {
+		return "", fmt.Errorf("%q is not a valid host name", hostname)
+	}
+
+	return u.Host, nil
+}
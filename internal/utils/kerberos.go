@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+	k5client "github.com/jcmturner/gokrb5/v8/client"
+	k5config "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	passwordLoginPath = "/ipa/session/login_password"
+	kerberosLoginPath = "/ipa/session/login_kerberos"
+)
+
+// ConnectWithKerberosCCache connects to FreeIPA with the tickets of an
+// existing Kerberos credential cache, as obtained with `kinit`.
+//
+// go-freeipa only builds Kerberos clients from a keytab, so the client is
+// created with a password login which the returned transport replaces with a
+// SPNEGO login. Renewed logins performed by go-freeipa go through the same
+// path.
+func ConnectWithKerberosCCache(host string, tspt http.RoundTripper, krb5ConfigReader io.Reader, ccacheName string) (*freeipa.Client, error) {
+	krb5Config, err := k5config.NewFromReader(krb5ConfigReader)
+	if err != nil {
+		return nil, fmt.Errorf("reading kerberos configuration: %w", err)
+	}
+
+	ccachePath, err := CCachePath(ccacheName)
+	if err != nil {
+		return nil, err
+	}
+
+	ccache, err := credentials.LoadCCache(ccachePath)
+	if err != nil {
+		return nil, fmt.Errorf("loading kerberos credential cache %s: %w", ccachePath, err)
+	}
+
+	krbClient, err := k5client.NewFromCCache(ccache, krb5Config)
+	if err != nil {
+		return nil, fmt.Errorf("creating kerberos client from credential cache: %w", err)
+	}
+
+	return freeipa.Connect(host, &ccacheLoginTransport{base: tspt, krbClient: krbClient}, "", "")
+}
+
+// CCachePath returns the file of a credential cache name as found in
+// KRB5CCNAME. Only file based caches can be read.
+func CCachePath(name string) (string, error) {
+	if path, ok := strings.CutPrefix(name, "FILE:"); ok {
+		return path, nil
+	}
+
+	for _, prefix := range []string{"DIR:", "KEYRING:", "KCM:", "MEMORY:", "API:", "MSLSA:"} {
+		if strings.HasPrefix(name, prefix) {
+			return "", fmt.Errorf("unsupported kerberos credential cache %q, only FILE caches are supported", name)
+		}
+	}
+
+	return name, nil
+}
+
+type ccacheLoginTransport struct {
+	base      http.RoundTripper
+	krbClient *k5client.Client
+}
+
+func (t *ccacheLoginTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path != passwordLoginPath {
+		return t.base.RoundTrip(req)
+	}
+
+	loginURL := *req.URL
+	loginURL.Path = kerberosLoginPath
+
+	loginReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, loginURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	loginReq.Header.Set("Referer", req.Header.Get("Referer"))
+
+	if err := spnego.SetSPNEGOHeader(t.krbClient, loginReq, ""); err != nil {
+		return nil, fmt.Errorf("building kerberos login request: %w", err)
+	}
+
+	res, err := t.base.RoundTrip(loginReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// The session cookie must be stored for the request go-freeipa sent
+	res.Request = req
+
+	return res, nil
+}
+
+// KerberosIdentity returns the principal and the realm used to log in to
+// host with the keytab data. The principal defaults to the only one of the
+// keytab, the realm to the one of the keytab principal. When the keytab holds
+// the keys of principals of several realms, the realm defaults to the
+// uppercased domain of host.
+func KerberosIdentity(keytab []byte, principal, realm, host string) (string, string, error) {
+	if principal != "" && realm != "" {
+		return principal, realm, nil
+	}
+
+	p, r, err := KeytabPrincipal(keytab, principal, realm)
+	if err != nil && realm == "" {
+		if realm = HostRealm(host); realm != "" {
+			p, r, err = KeytabPrincipal(keytab, principal, realm)
+		}
+	}
+
+	return p, r, err
+}
+
+// HostRealm returns the uppercased domain of host, the usual realm of a
+// FreeIPA server. It is empty for IP addresses and single label names.
+func HostRealm(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	host = strings.TrimSuffix(host, ".")
+
+	if net.ParseIP(strings.Trim(host, "[]")) != nil {
+		return ""
+	}
+
+	_, domain, ok := strings.Cut(host, ".")
+	if !ok || domain == "" {
+		return ""
+	}
+
+	return strings.ToUpper(domain)
+}
+
+// KeytabPrincipal returns the principal and the realm of the keys held by the
+// keytab data, for the principal and the realm which are not given. It fails
+// unless the keys matching the given ones belong to a single principal.
+func KeytabPrincipal(data []byte, principal, realm string) (string, string, error) {
+	kt := keytab.New()
+	if err := kt.Unmarshal(data); err != nil {
+		return "", "", fmt.Errorf("reading keytab: %w", err)
+	}
+
+	// A principal may be given with its realm, e.g. `terraform@EXAMPLE.COM`
+	name, nameRealm, _ := strings.Cut(principal, "@")
+
+	var found []string
+
+	for _, e := range kt.Entries {
+		n := strings.Join(e.Principal.Components, "/")
+
+		if (name != "" && n != name) || (realm != "" && e.Principal.Realm != realm) || (nameRealm != "" && e.Principal.Realm != nameRealm) {
+			continue
+		}
+
+		if p := n + "@" + e.Principal.Realm; !slices.Contains(found, p) {
+			found = append(found, p)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", "", fmt.Errorf("the keytab holds no key for the configured principal and realm")
+	case 1:
+		n, r, _ := strings.Cut(found[0], "@")
+
+		if principal == "" {
+			principal = n
+		}
+		if realm == "" {
+			realm = r
+		}
+
+		return principal, realm, nil
+	}
+
+	return "", "", fmt.Errorf("the keytab holds the keys of several principals (%s), set kerberos_principal", strings.Join(found, ", "))
+}
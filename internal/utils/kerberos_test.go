@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+)
+
+func testKeytab(t *testing.T, principals ...[2]string) []byte {
+	t.Helper()
+
+	kt := keytab.New()
+
+	for _, p := range principals {
+		// Several keys of the same principal count as one principal
+		for _, encType := range []int32{etypeID.AES256_CTS_HMAC_SHA1_96, etypeID.AES128_CTS_HMAC_SHA1_96} {
+			if err := kt.AddEntry(p[0], p[1], "s3cret", time.Now(), 1, encType); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	data, err := kt.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestKeytabPrincipal(t *testing.T) {
+	single := testKeytab(t, [2]string{"terraform/ipa.example.test", "EXAMPLE.TEST"})
+	several := testKeytab(t, [2]string{"terraform", "EXAMPLE.TEST"}, [2]string{"admin", "EXAMPLE.TEST"}, [2]string{"terraform", "OTHER.TEST"})
+
+	cases := map[string]struct {
+		keytab                   []byte
+		principal, realm         string
+		wantPrincipal, wantRealm string
+		wantErr                  bool
+	}{
+		"single principal": {
+			keytab:        single,
+			wantPrincipal: "terraform/ipa.example.test",
+			wantRealm:     "EXAMPLE.TEST",
+		},
+		"single principal, realm given": {
+			keytab:        single,
+			realm:         "EXAMPLE.TEST",
+			wantPrincipal: "terraform/ipa.example.test",
+			wantRealm:     "EXAMPLE.TEST",
+		},
+		"principal given with its realm": {
+			keytab:        single,
+			principal:     "terraform/ipa.example.test@EXAMPLE.TEST",
+			wantPrincipal: "terraform/ipa.example.test@EXAMPLE.TEST",
+			wantRealm:     "EXAMPLE.TEST",
+		},
+		"several principals": {
+			keytab:  several,
+			wantErr: true,
+		},
+		"several principals, principal given": {
+			keytab:        several,
+			principal:     "admin",
+			wantPrincipal: "admin",
+			wantRealm:     "EXAMPLE.TEST",
+		},
+		"several principals, ambiguous principal": {
+			keytab:    several,
+			principal: "terraform",
+			wantErr:   true,
+		},
+		"several principals, realm given": {
+			keytab:        several,
+			realm:         "OTHER.TEST",
+			wantPrincipal: "terraform",
+			wantRealm:     "OTHER.TEST",
+		},
+		"unknown principal": {
+			keytab:    single,
+			principal: "admin",
+			wantErr:   true,
+		},
+		"invalid keytab": {
+			keytab:  []byte("not a keytab"),
+			wantErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			principal, realm, err := KeytabPrincipal(c.keytab, c.principal, c.realm)
+
+			if c.wantErr {
+				if err == nil {
+					t.Errorf("got %q and %q, want an error", principal, realm)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if principal != c.wantPrincipal || realm != c.wantRealm {
+				t.Errorf("got %q and %q, want %q and %q", principal, realm, c.wantPrincipal, c.wantRealm)
+			}
+		})
+	}
+}
+
+func TestKerberosIdentity(t *testing.T) {
+	realms := testKeytab(t, [2]string{"terraform", "EXAMPLE.TEST"}, [2]string{"terraform", "OTHER.TEST"})
+
+	principal, realm, err := KerberosIdentity(realms, "", "", "ipa.example.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The realm of the host picks among the realms of the keytab
+	if principal != "terraform" || realm != "EXAMPLE.TEST" {
+		t.Errorf("got %q and %q, want %q and %q", principal, realm, "terraform", "EXAMPLE.TEST")
+	}
+
+	if _, _, err := KerberosIdentity(realms, "", "", "ipa.corp.test"); err == nil {
+		t.Error("got no error for a host outside the realms of the keytab")
+	}
+
+	// Both providers guess the realm from the host given as a URL once
+	// normalized
+	host, err := NormalizeHost("https://ipa.example.test/ipa/ui/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, realm, err = KerberosIdentity(realms, "", "", host)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if principal != "terraform" || realm != "EXAMPLE.TEST" {
+		t.Errorf("got %q and %q for host %q, want %q and %q", principal, realm, host, "terraform", "EXAMPLE.TEST")
+	}
+}
+
+func TestHostRealm(t *testing.T) {
+	cases := map[string]string{
+		"ipa.example.test":      "EXAMPLE.TEST",
+		"ipa.example.test:8443": "EXAMPLE.TEST",
+		"ipa.example.test.":     "EXAMPLE.TEST",
+		"ipa":                   "",
+		"192.0.2.1":             "",
+		"[2001:db8::1]:443":     "",
+	}
+
+	for host, want := range cases {
+		if got := HostRealm(host); got != want {
+			t.Errorf("HostRealm(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+)
+
+// kinitCommand obtains the initial tickets with PKINIT, which gokrb5 does not
+// implement.
+const kinitCommand = "kinit"
+
+// PKINITOptions are the principal and the certificate and private key files
+// it authenticates with.
+type PKINITOptions struct {
+	Principal       string
+	Realm           string
+	CertificatePath string
+	KeyPath         string
+}
+
+// principal returns the principal qualified with the realm, the realm of the
+// principal taking precedence. kinit defaults to the default_realm of
+// krb5.conf when neither is given.
+func (o PKINITOptions) principal() string {
+	if o.Realm == "" || strings.Contains(o.Principal, "@") {
+		return o.Principal
+	}
+
+	return o.Principal + "@" + o.Realm
+}
+
+// KinitPKINIT obtains the initial tickets of the principal of options with
+// PKINIT and stores them in the credential cache file ccache. It runs the
+// kinit command of MIT Kerberos, which must be installed with its PKINIT
+// plugin, with the krb5.conf at krb5ConfPath. The certificate of the KDC is
+// verified against the pkinit_anchors of krb5.conf, which ipa-client-install
+// sets on enrolled hosts.
+func KinitPKINIT(ctx context.Context, krb5ConfPath string, options PKINITOptions, ccache string) error {
+	command, err := exec.LookPath(kinitCommand)
+	if err != nil {
+		return fmt.Errorf("%s is required to authenticate with PKINIT, install the MIT Kerberos client tools: %w", kinitCommand, err)
+	}
+
+	// The identity lists the certificate and the key separated by a comma
+	if strings.Contains(options.CertificatePath, ",") || strings.Contains(options.KeyPath, ",") {
+		return fmt.Errorf("the PKINIT certificate and key paths must not contain commas")
+	}
+
+	args := []string{
+		"-X", fmt.Sprintf("X509_user_identity=FILE:%s,%s", options.CertificatePath, options.KeyPath),
+		"-c", "FILE:" + ccache,
+		options.principal(),
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Env = append(os.Environ(), "KRB5_CONFIG="+krb5ConfPath)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s failed: %w: %s", kinitCommand, err, msg)
+		}
+
+		return fmt.Errorf("%s failed: %w", kinitCommand, err)
+	}
+
+	return nil
+}
+
+// ConnectWithPKINIT connects to FreeIPA with the tickets KinitPKINIT obtains
+// in a private credential cache, removed once loaded. Logging in again
+// obtains new tickets.
+func ConnectWithPKINIT(host string, tspt http.RoundTripper, krb5ConfPath string, options PKINITOptions) (*freeipa.Client, error) {
+	krb5ConfFile, err := os.Open(krb5ConfPath)
+	if err != nil {
+		return nil, err
+	}
+	defer krb5ConfFile.Close()
+
+	dir, err := os.MkdirTemp("", "terraform-provider-freeipa-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	ccache := filepath.Join(dir, "ccache")
+
+	if err := KinitPKINIT(context.Background(), krb5ConfPath, options, ccache); err != nil {
+		return nil, err
+	}
+
+	return ConnectWithKerberosCCache(host, tspt, krb5ConfFile, "FILE:"+ccache)
+}
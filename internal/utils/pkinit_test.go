@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeKinit installs a kinit script which writes its arguments and Kerberos
+// environment to the credential cache given with -c.
+func fakeKinit(t *testing.T, script string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, kinitCommand), []byte("#!/bin/sh\n"+script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", dir)
+}
+
+func TestKinitPKINIT(t *testing.T) {
+	fakeKinit(t, `
+all="$*"
+while [ $# -gt 0 ]; do
+	[ "$1" = -c ] && ccache="${2#FILE:}"
+	shift
+done
+printf '%s\n%s\n' "$all" "$KRB5_CONFIG" > "$ccache"
+`)
+
+	ccache := filepath.Join(t.TempDir(), "ccache")
+
+	err := KinitPKINIT(context.Background(), "/etc/krb5.conf", PKINITOptions{
+		Principal:       "host/bastion.example.test",
+		Realm:           "EXAMPLE.TEST",
+		CertificatePath: "/var/lib/ipa-client/host.crt",
+		KeyPath:         "/var/lib/ipa-client/host.key",
+	}, ccache)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(ccache)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(data)
+
+	for _, want := range []string{"-X X509_user_identity=FILE:/var/lib/ipa-client/host.crt,/var/lib/ipa-client/host.key ", " host/bastion.example.test@EXAMPLE.TEST\n", "\n/etc/krb5.conf\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestKinitPKINITFailure(t *testing.T) {
+	fakeKinit(t, `echo "kinit: Preauthentication failed while getting initial credentials" >&2; exit 1`)
+
+	err := KinitPKINIT(context.Background(), "/etc/krb5.conf", PKINITOptions{
+		Principal:       "host/bastion.example.test@EXAMPLE.TEST",
+		CertificatePath: "/tmp/host.crt",
+		KeyPath:         "/tmp/host.key",
+	}, filepath.Join(t.TempDir(), "ccache"))
+
+	if err == nil || !strings.Contains(err.Error(), "Preauthentication failed") {
+		t.Errorf("got %v, want the error of kinit", err)
+	}
+}
+
+func TestPKINITPrincipal(t *testing.T) {
+	cases := map[PKINITOptions]string{
+		{Principal: "host/bastion.example.test"}:                                   "host/bastion.example.test",
+		{Principal: "host/bastion.example.test", Realm: "EXAMPLE.TEST"}:            "host/bastion.example.test@EXAMPLE.TEST",
+		{Principal: "host/bastion.example.test@OTHER.TEST", Realm: "EXAMPLE.TEST"}: "host/bastion.example.test@OTHER.TEST",
+	}
+
+	for options, want := range cases {
+		if got := options.principal(); got != want {
+			t.Errorf("principal of %+v = %q, want %q", options, got, want)
+		}
+	}
+}
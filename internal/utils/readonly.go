@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// readOnlyMethods are the JSON-RPC methods which do not modify anything on
+// the server, besides the `*_show` and `*_find` ones.
+var readOnlyMethods = []string{"ping", "env", "hbactest", "subid_match", "vault_retrieve_internal"}
+
+// ErrReadOnly is returned for the calls refused because read_only is set, so
+// that they are told apart from the failures of FreeIPA.
+var ErrReadOnly = errors.New("the provider is read-only (read_only is set)")
+
+type readOnlyTransport struct {
+	base http.RoundTripper
+}
+
+// NewReadOnlyTransport wraps base to refuse the JSON-RPC calls which may
+// modify the server, failing them without sending them. Logins go through.
+func NewReadOnlyTransport(base http.RoundTripper) http.RoundTripper {
+	return &readOnlyTransport{base: base}
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/session/json") {
+		return t.base.RoundTrip(req)
+	}
+
+	method := rpcMethod(req)
+
+	if method != "" && (slices.Contains(readOnlyMethods, method) ||
+		strings.HasSuffix(method, "_show") ||
+		strings.HasSuffix(method, "_find")) {
+		return t.base.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	if method == "" {
+		return nil, fmt.Errorf("%w, refusing to send a FreeIPA call which cannot be inspected", ErrReadOnly)
+	}
+
+	return nil, fmt.Errorf("%w, refusing to call %s", ErrReadOnly, method)
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// countingTransport answers every request, counting the requests sent.
+type countingTransport struct {
+	sent int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.sent++
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestReadOnlyTransport(t *testing.T) {
+	cases := []struct {
+		name    string
+		path    string
+		body    string
+		allowed bool
+	}{
+		{"login", "/ipa/session/login_password", "user=admin&password=secret", true},
+		{"show", "/ipa/session/json", `{"method":"user_show","params":[["jdoe"],{}]}`, true},
+		{"find", "/ipa/session/json", `{"method":"group_find","params":[[],{}]}`, true},
+		{"ping", "/ipa/session/json", `{"method":"ping","params":[[],{}]}`, true},
+		{"hbactest", "/ipa/session/json", `{"method":"hbactest","params":[[],{"user":"jdoe","targethost":"web.example.test","service":"sshd"}]}`, true},
+		{"add", "/ipa/session/json", `{"method":"user_add","params":[["jdoe"],{}]}`, false},
+		{"mod", "/ipa/session/json", `{"method":"hbacrule_mod","params":[["allow_all"],{}]}`, false},
+		{"not inspectable", "/ipa/session/json", "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			base := &countingTransport{}
+			tspt := NewReadOnlyTransport(base)
+
+			var body io.Reader
+			if c.body != "" {
+				body = strings.NewReader(c.body)
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, "https://ipa.example.test"+c.path, body)
+
+			_, err := tspt.RoundTrip(req)
+
+			if c.allowed && (err != nil || base.sent != 1) {
+				t.Errorf("got error %v and %d requests sent, want the request sent", err, base.sent)
+			}
+
+			if !c.allowed && (!errors.Is(err, ErrReadOnly) || base.sent != 0) {
+				t.Errorf("got error %v and %d requests sent, want the request refused with ErrReadOnly", err, base.sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/camptocamp/go-freeipa/freeipa"
+)
+
+// RPCClient sends the JSON-RPC calls go-freeipa does not implement, such as
+// the ones of newer FreeIPA versions. The calls go through the transport the
+// go-freeipa client is connected with, which holds the session.
+type RPCClient struct {
+	host      string
+	transport http.RoundTripper
+}
+
+// NewRPCClient returns a client sending its calls to host through transport,
+// typically the SessionTransport of the go-freeipa client.
+func NewRPCClient(host string, transport http.RoundTripper) *RPCClient {
+	return &RPCClient{host: host, transport: transport}
+}
+
+// Call sends method with the positional args and the options, and decodes the
+// result of the response into result. Errors reported by FreeIPA are returned
+// as *freeipa.Error, like the ones of go-freeipa.
+func (c *RPCClient) Call(ctx context.Context, method string, args []interface{}, options map[string]interface{}, result interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	if options == nil {
+		options = map[string]interface{}{}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"method": method,
+		"params": []interface{}{args, options},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("https://%v/ipa/session/json", c.host), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Referer", fmt.Sprintf("https://%v/ipa", c.host))
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected http status code: %v", resp.StatusCode)
+	}
+
+	var res struct {
+		Error  *freeipa.Error  `json:"error"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return err
+	}
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if len(res.Result) == 0 {
+		return fmt.Errorf("missing result in response")
+	}
+
+	return json.Unmarshal(res.Result, result)
+}
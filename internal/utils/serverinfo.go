@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ServerInfo describes the FreeIPA server a client is connected to.
+type ServerInfo struct {
+	// Version is the version of the FreeIPA server, e.g. `4.11.1`.
+	Version string
+	// APIVersion is the JSON-RPC API version of the server, e.g. `2.254`.
+	APIVersion string
+	// Domain is the primary DNS domain of the FreeIPA deployment.
+	Domain string
+	// Realm is the Kerberos realm of the FreeIPA deployment.
+	Realm string
+}
+
+// ServerInfoRecorder holds the server information recorded by the transport
+// returned by NewServerInfoTransport.
+type ServerInfoRecorder struct {
+	mu   sync.Mutex
+	info ServerInfo
+}
+
+// Info returns the recorded server information, whose fields are empty until
+// a `ping` call went through the transport.
+func (r *ServerInfoRecorder) Info() ServerInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.info
+}
+
+var pingAPIVersionPattern = regexp.MustCompile(`API version ([0-9.]+)`)
+
+type serverInfoTransport struct {
+	base     http.RoundTripper
+	recorder *ServerInfoRecorder
+}
+
+// NewServerInfoTransport wraps base to record the server information of the
+// responses to `ping` calls in recorder. go-freeipa neither decodes the server
+// version sent along with every response nor implements the `env` command, so
+// the domain and realm are queried with an additional `env` call sent with the
+// session of the `ping` call.
+func NewServerInfoTransport(base http.RoundTripper, recorder *ServerInfoRecorder) http.RoundTripper {
+	return &serverInfoTransport{base: base, recorder: recorder}
+}
+
+func (t *serverInfoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/session/json") || rpcMethod(req) != "ping" {
+		return t.base.RoundTrip(req)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	var ping struct {
+		Version   string `json:"version"`
+		Principal string `json:"principal"`
+		Result    struct {
+			Summary string `json:"summary"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &ping); err != nil {
+		return resp, nil
+	}
+
+	info := ServerInfo{
+		Version: ping.Version,
+	}
+
+	if m := pingAPIVersionPattern.FindStringSubmatch(ping.Result.Summary); m != nil {
+		info.APIVersion = m[1]
+	}
+
+	// The realm of the principal is the one of the deployment, unless logged
+	// in as a trusted user
+	if _, realm, ok := strings.Cut(ping.Principal, "@"); ok {
+		info.Realm = realm
+	}
+
+	if domain, realm, err := t.env(req, info.APIVersion); err != nil {
+		log.Printf("[WARN] Failed to read the FreeIPA domain and realm: %v", err)
+	} else {
+		info.Domain = domain
+		if realm != "" {
+			info.Realm = realm
+		}
+	}
+
+	t.recorder.mu.Lock()
+	t.recorder.info = info
+	t.recorder.mu.Unlock()
+
+	return resp, nil
+}
+
+// env returns the domain and realm of the server, sending an `env` call with
+// the headers, and therefore the session cookie, of req.
+func (t *serverInfoTransport) env(req *http.Request, apiVersion string) (string, string, error) {
+	kwargs := map[string]interface{}{}
+	if apiVersion != "" {
+		kwargs["version"] = apiVersion
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"method": "env",
+		"params": []interface{}{[]string{"domain", "realm"}, kwargs},
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	envReq := req.Clone(req.Context())
+	envReq.Body = io.NopCloser(bytes.NewReader(body))
+	envReq.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	envReq.ContentLength = int64(len(body))
+
+	resp, err := t.base.RoundTrip(envReq)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("unexpected http status code: %v", resp.StatusCode)
+	}
+
+	var env struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Result struct {
+			Result struct {
+				Domain string `json:"domain"`
+				Realm  string `json:"realm"`
+			} `json:"result"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return "", "", err
+	}
+
+	if env.Error != nil {
+		return "", "", fmt.Errorf("env: %s", env.Error.Message)
+	}
+
+	return env.Result.Result.Domain, env.Result.Result.Realm, nil
+}
@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// sessionErrorCodes are the FreeIPA error codes reported when the session or
+// the Kerberos ticket of a request is no longer valid (TicketExpired and
+// SessionError).
+var sessionErrorCodes = []int{1104, 1200}
+
+// SessionTransport keeps the FreeIPA session cookie and logs in again when a
+// JSON-RPC call fails because the session expired, sending the call once more
+// with the new session. The clients connected through it share its session,
+// the cookies they hold themselves are ignored.
+type SessionTransport struct {
+	// Reconnect logs in again, typically by connecting a new client through
+	// the transport. The login responses set the session of the transport.
+	Reconnect func() error
+
+	base http.RoundTripper
+	jar  *cookiejar.Jar
+
+	mu         sync.Mutex
+	generation uint64
+}
+
+// NewSessionTransport wraps base to handle expired sessions, see
+// SessionTransport. Logins are only renewed once Reconnect is set.
+func NewSessionTransport(base http.RoundTripper) *SessionTransport {
+	// The jar only fails to be created with invalid options
+	jar, _ := cookiejar.New(nil)
+
+	return &SessionTransport{base: base, jar: jar}
+}
+
+func (t *SessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/session/json") {
+		return t.send(req)
+	}
+
+	t.mu.Lock()
+	generation := t.generation
+	t.mu.Unlock()
+
+	resp, err := t.send(req)
+	if err != nil || t.Reconnect == nil || req.GetBody == nil {
+		return resp, err
+	}
+
+	expired, resp, err := isSessionExpired(resp)
+	if err != nil || !expired {
+		return resp, err
+	}
+
+	if err := t.renew(generation); err != nil {
+		log.Printf("[WARN] Failed to log in to FreeIPA again after the session expired: %v", err)
+
+		return resp, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return resp, nil
+	}
+
+	resp.Body.Close()
+
+	retryReq := req.Clone(req.Context())
+	retryReq.Body = body
+
+	return t.send(retryReq)
+}
+
+// renew logs in again unless another request already did since generation.
+func (t *SessionTransport) renew(generation uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.generation != generation {
+		return nil
+	}
+
+	log.Printf("[INFO] FreeIPA session expired, logging in again")
+
+	if err := t.Reconnect(); err != nil {
+		return err
+	}
+
+	t.generation++
+
+	return nil
+}
+
+// send sends req with the session cookies of the transport and records the
+// ones set by the response.
+func (t *SessionTransport) send(req *http.Request) (*http.Response, error) {
+	if cookies := t.jar.Cookies(req.URL); len(cookies) > 0 {
+		req = req.Clone(req.Context())
+		req.Header.Del("Cookie")
+
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cookies := resp.Cookies(); len(cookies) > 0 {
+		t.jar.SetCookies(req.URL, cookies)
+	}
+
+	return resp, nil
+}
+
+// isSessionExpired reports whether resp is the response of FreeIPA to a call
+// made with an expired session. The body of the returned response can be read
+// again.
+func isSessionExpired(resp *http.Response) (bool, *http.Response, error) {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return true, resp, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, resp, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return false, nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	var body struct {
+		Error *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil || body.Error == nil {
+		return false, resp, nil
+	}
+
+	return slices.Contains(sessionErrorCodes, body.Error.Code), resp, nil
+}
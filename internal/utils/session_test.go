@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// sessionServer is a fake FreeIPA server whose session can be expired, the
+// JSON-RPC calls made without the current session failing with status and
+// body.
+type sessionServer struct {
+	status int
+	body   string
+
+	mu      sync.Mutex
+	session string
+	logins  int
+	calls   []string
+}
+
+func (s *sessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.HasSuffix(r.URL.Path, "/session/login_password") {
+		s.logins++
+		s.session = fmt.Sprintf("session-%d", s.logins)
+		http.SetCookie(w, &http.Cookie{Name: "ipa_session", Value: s.session, Path: "/ipa"})
+
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	s.calls = append(s.calls, string(body))
+
+	if cookie, err := r.Cookie("ipa_session"); err != nil || s.session == "" || cookie.Value != s.session {
+		w.WriteHeader(s.status)
+		w.Write([]byte(s.body))
+
+		return
+	}
+
+	w.Write([]byte(`{"result":{"result":true},"error":null}`))
+}
+
+// expire invalidates the current session until the next login.
+func (s *sessionServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.session = ""
+}
+
+// connect returns a session transport logged in to server.
+func (s *sessionServer) connect(t *testing.T, url string) (*SessionTransport, *atomic.Int32) {
+	tspt := NewSessionTransport(http.DefaultTransport)
+
+	var reconnects atomic.Int32
+
+	login := func() error {
+		req, _ := http.NewRequest(http.MethodPost, url+"/ipa/session/login_password", strings.NewReader("user=admin&password=secret"))
+
+		resp, err := tspt.RoundTrip(req)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	if err := login(); err != nil {
+		t.Fatal(err)
+	}
+
+	tspt.Reconnect = func() error {
+		reconnects.Add(1)
+
+		return login()
+	}
+
+	return tspt, &reconnects
+}
+
+func sessionCall(tspt http.RoundTripper, url, body string) (int, string, error) {
+	req, _ := http.NewRequest(http.MethodPost, url+"/ipa/session/json", strings.NewReader(body))
+
+	resp, err := tspt.RoundTrip(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+
+	return resp.StatusCode, string(data), err
+}
+
+func TestSessionTransport(t *testing.T) {
+	cases := map[string]struct {
+		status  int
+		body    string
+		renewed bool
+	}{
+		"unauthorized":   {http.StatusUnauthorized, "", true},
+		"ticket expired": {http.StatusOK, `{"result":null,"error":{"code":1104,"name":"TicketExpired","message":"Ticket expired"}}`, true},
+		"session error":  {http.StatusOK, `{"result":null,"error":{"code":1200,"name":"SessionError","message":"Session error"}}`, true},
+		"other error":    {http.StatusOK, `{"result":null,"error":{"code":4001,"name":"NotFound","message":"jdoe: user not found"}}`, false},
+		"server error":   {http.StatusInternalServerError, "Internal Server Error", false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := &sessionServer{status: c.status, body: c.body}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			tspt, reconnects := server.connect(t, ts.URL)
+
+			server.expire()
+
+			body := `{"method":"user_show","params":[["jdoe"],{"all":true}]}`
+
+			status, got, err := sessionCall(tspt, ts.URL, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !c.renewed {
+				// The response is passed on untouched, its body readable
+				if reconnects.Load() != 0 || status != c.status || got != c.body {
+					t.Errorf("got %d reconnections and HTTP %d %q, want none and HTTP %d %q", reconnects.Load(), status, got, c.status, c.body)
+				}
+
+				return
+			}
+
+			if reconnects.Load() != 1 {
+				t.Errorf("got %d reconnections, want 1", reconnects.Load())
+			}
+
+			if status != http.StatusOK || !strings.Contains(got, `"error":null`) {
+				t.Errorf("got HTTP %d %q, want the call to succeed", status, got)
+			}
+
+			// The call is sent again with its whole body
+			if len(server.calls) != 2 || server.calls[0] != body || server.calls[1] != body {
+				t.Errorf("got calls %q, want %q twice", server.calls, body)
+			}
+		})
+	}
+}
+
+func TestSessionTransportConcurrentReconnect(t *testing.T) {
+	server := &sessionServer{status: http.StatusUnauthorized}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tspt, reconnects := server.connect(t, ts.URL)
+
+	server.expire()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			status, _, err := sessionCall(tspt, ts.URL, fmt.Sprintf(`{"method":"user_show","params":[["user%d"],{}]}`, i))
+			if err != nil {
+				t.Error(err)
+			} else if status != http.StatusOK {
+				t.Errorf("got HTTP %d, want %d", status, http.StatusOK)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if reconnects.Load() != 1 {
+		t.Errorf("got %d reconnections, want 1", reconnects.Load())
+	}
+}
+
+func TestSessionTransportWithoutReconnect(t *testing.T) {
+	server := &sessionServer{status: http.StatusUnauthorized}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tspt := NewSessionTransport(http.DefaultTransport)
+
+	status, _, err := sessionCall(tspt, ts.URL, `{"method":"ping","params":[[],{}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status != http.StatusUnauthorized || len(server.calls) != 1 {
+		t.Errorf("got HTTP %d after %d calls, want HTTP %d after 1 call", status, len(server.calls), http.StatusUnauthorized)
+	}
+}
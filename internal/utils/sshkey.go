@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"strings"
+)
+
+// sshKeyTypePrefixes are the prefixes of the OpenSSH public key algorithms.
+var sshKeyTypePrefixes = []string{"ssh-", "ecdsa-", "sk-"}
+
+// NormalizeSSHPublicKey returns the algorithm and the base64 blob of an
+// OpenSSH public key, dropping its options and comment, so that functionally
+// identical keys compare equal. Keys which cannot be parsed are returned with
+// their whitespace collapsed.
+func NormalizeSSHPublicKey(key string) string {
+	fields := strings.Fields(key)
+
+	for i := 0; i+1 < len(fields); i++ {
+		for _, prefix := range sshKeyTypePrefixes {
+			if strings.HasPrefix(fields[i], prefix) {
+				return fields[i] + " " + fields[i+1]
+			}
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,366 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryBackoff = time.Second
+
+	maxRetryBackoff = 30 * time.Second
+)
+
+// RetryOptions configures the transport returned by NewRetryTransport.
+type RetryOptions struct {
+	// RequestTimeout bounds every single attempt, zero disables the timeout.
+	RequestTimeout time.Duration
+	// MaxRetries is the number of additional attempts made after a failure.
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled on every attempt.
+	Backoff time.Duration
+}
+
+type retryTransport struct {
+	base http.RoundTripper
+	opts RetryOptions
+}
+
+// NewRetryTransport wraps base with a per attempt timeout and retries with
+// exponential backoff. Read-only JSON-RPC calls (`*_show`, `*_find`, `ping`)
+// and logins are retried on connection errors and 5xx responses, other calls
+// are only retried when the connection to the server could not be opened.
+func NewRetryTransport(base http.RoundTripper, opts RetryOptions) http.RoundTripper {
+	return &retryTransport{base: base, opts: opts}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := isIdempotentRequest(req)
+	rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		var cancel context.CancelFunc
+		if t.opts.RequestTimeout > 0 {
+			var ctx context.Context
+			ctx, cancel = context.WithTimeout(req.Context(), t.opts.RequestTimeout)
+			attemptReq = attemptReq.WithContext(ctx)
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+
+		retry := rewindable &&
+			attempt < t.opts.MaxRetries &&
+			req.Context().Err() == nil &&
+			shouldRetry(idempotent, resp, err)
+
+		if !retry {
+			if cancel != nil {
+				if err != nil {
+					cancel()
+				} else {
+					resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+				}
+			}
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if cancel != nil {
+			cancel()
+		}
+
+		delay := retryDelay(t.opts.Backoff, attempt)
+		if err != nil {
+			log.Printf("[WARN] FreeIPA request to %s failed (%v), retrying in %s", req.URL.Path, err, delay)
+		} else {
+			log.Printf("[WARN] FreeIPA request to %s returned HTTP %d, retrying in %s", req.URL.Path, resp.StatusCode, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func shouldRetry(idempotent bool, resp *http.Response, err error) bool {
+	if err != nil {
+		if idempotent {
+			return true
+		}
+		return isConnectError(err)
+	}
+
+	return idempotent && resp.StatusCode >= http.StatusInternalServerError
+}
+
+// isConnectError reports whether err happened before the request could reach
+// the server, which makes it safe to retry non idempotent calls.
+func isConnectError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func isIdempotentRequest(req *http.Request) bool {
+	if !strings.HasSuffix(req.URL.Path, "/session/json") {
+		// Login requests do not modify anything on the server.
+		return true
+	}
+
+	method := rpcMethod(req)
+
+	return method == "ping" ||
+		strings.HasSuffix(method, "_show") ||
+		strings.HasSuffix(method, "_find")
+}
+
+// rpcMethod returns the JSON-RPC method called by req, or an empty string when
+// its body cannot be read again.
+func rpcMethod(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	var rpc struct {
+		Method string `json:"method"`
+	}
+	if err := json.NewDecoder(body).Decode(&rpc); err != nil {
+		return ""
+	}
+
+	return rpc.Method
+}
+
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	delay := backoff
+	for i := 0; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+type contextTransport struct {
+	base http.RoundTripper
+	ctx  context.Context
+}
+
+// NewContextTransport wraps base to send every request with ctx, go-freeipa
+// building its requests without one. Wrapping the retry transport bounds the
+// retries and their backoff by the deadline of ctx as well.
+func NewContextTransport(ctx context.Context, base http.RoundTripper) http.RoundTripper {
+	return &contextTransport{base: base, ctx: ctx}
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// certDatetimeFormat is the format of the certificate validity dates returned
+// by FreeIPA, see ipalib.x509.format_datetime.
+const certDatetimeFormat = "Mon Jan 02 15:04:05 2006 MST"
+
+// resultFixups rewrite the entries returned by JSON-RPC methods whose results
+// go-freeipa cannot decode as FreeIPA sends them.
+var resultFixups = map[string]func(entry map[string]interface{}){
+	"cert_show":           fixCertResult,
+	"cert_find":           fixCertResult,
+	"dnsforwardzone_add":  fixDnsforwardzoneResult,
+	"dnsforwardzone_mod":  fixDnsforwardzoneResult,
+	"dnsforwardzone_show": fixDnsforwardzoneResult,
+	"dnsforwardzone_find": fixDnsforwardzoneResult,
+	"group_add":           fixMembermanagerResult,
+	"group_mod":           fixMembermanagerResult,
+	"group_show":          fixMembermanagerResult,
+	"group_find":          fixMembermanagerResult,
+	"group_add_member":    fixMembermanagerResult,
+	"group_remove_member": fixMembermanagerResult,
+	"hostgroup_add":       fixMembermanagerResult,
+	"hostgroup_mod":       fixMembermanagerResult,
+	"hostgroup_show":      fixMembermanagerResult,
+	"hostgroup_find":      fixMembermanagerResult,
+	"user_add":            fixManagerResult,
+	"user_mod":            fixManagerResult,
+	"user_show":           fixManagerResult,
+	"user_find":           fixManagerResult,
+}
+
+type resultFixupTransport struct {
+	base http.RoundTripper
+}
+
+// NewResultFixupTransport wraps base to rewrite the results of the JSON-RPC
+// methods listed in resultFixups before go-freeipa decodes them.
+func NewResultFixupTransport(base http.RoundTripper) http.RoundTripper {
+	return &resultFixupTransport{base: base}
+}
+
+func (t *resultFixupTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !strings.HasSuffix(req.URL.Path, "/session/json") {
+		return resp, err
+	}
+
+	fixup, ok := resultFixups[rpcMethod(req)]
+	if !ok {
+		return resp, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err == nil {
+		if outer, ok := body["result"].(map[string]interface{}); ok {
+			switch result := outer["result"].(type) {
+			case map[string]interface{}:
+				fixup(result)
+			case []interface{}:
+				for _, entry := range result {
+					if m, ok := entry.(map[string]interface{}); ok {
+						fixup(m)
+					}
+				}
+			}
+
+			if fixed, err := json.Marshal(body); err == nil {
+				data = fixed
+			}
+		}
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	resp.Header.Del("Content-Length")
+
+	return resp, nil
+}
+
+// fixCertResult rewrites the validity dates of a certificate, which FreeIPA
+// formats as plain strings, and fills the status and revocation reason it
+// omits for valid certificates.
+func fixCertResult(cert map[string]interface{}) {
+	for _, key := range []string{"valid_not_before", "valid_not_after"} {
+		switch v := cert[key].(type) {
+		case string:
+			if tm, err := time.Parse(certDatetimeFormat, v); err == nil {
+				cert[key] = []interface{}{map[string]interface{}{"__datetime__": tm.UTC().Format("20060102150405Z")}}
+			}
+		case map[string]interface{}:
+			cert[key] = []interface{}{v}
+		}
+	}
+
+	_, revoked := cert["revocation_reason"]
+
+	if _, ok := cert["revoked"]; !ok {
+		cert["revoked"] = revoked
+	}
+
+	if _, ok := cert["status"]; !ok {
+		if revoked {
+			cert["status"] = "REVOKED"
+		} else {
+			cert["status"] = "VALID"
+		}
+	}
+
+	// Only meaningful when revoked is true
+	if !revoked {
+		cert["revocation_reason"] = 0
+	}
+}
+
+// fixDnsforwardzoneResult fills the managedby attribute of a forward zone,
+// which FreeIPA only sets once a permission is added to the zone.
+func fixDnsforwardzoneResult(zone map[string]interface{}) {
+	if _, ok := zone["managedby"]; !ok {
+		zone["managedby"] = ""
+	}
+}
+
+// fixMembermanagerResult joins the member managers of a group or host group,
+// which go-freeipa expects to hold exactly one value, into a comma separated
+// list. User and group names cannot contain commas, see SplitMembermanagers.
+func fixMembermanagerResult(entry map[string]interface{}) {
+	for _, key := range []string{"membermanager_user", "membermanager_group"} {
+		switch v := entry[key].(type) {
+		case string:
+		case []interface{}:
+			entry[key] = joinNames(v)
+		default:
+			entry[key] = ""
+		}
+	}
+}
+
+// fixManagerResult joins the managers of a user, which go-freeipa expects to
+// hold at most one value, into a comma separated list, see SplitManagers.
+func fixManagerResult(user map[string]interface{}) {
+	if v, ok := user["manager"].([]interface{}); ok {
+		user["manager"] = joinNames(v)
+	}
+}
+
+func joinNames(values []interface{}) string {
+	names := make([]string, 0, len(values))
+	for _, item := range values {
+		if name, ok := item.(string); ok {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer answers the first failures requests with status and the next
+// ones with 200, recording the bodies it receives.
+type flakyServer struct {
+	status   int
+	failures int
+
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.bodies = append(s.bodies, string(body))
+	n := len(s.bodies)
+	s.mu.Unlock()
+
+	if n <= s.failures {
+		w.WriteHeader(s.status)
+		return
+	}
+
+	w.Write([]byte(`{"result":{},"error":null}`))
+}
+
+func TestRetryTransport(t *testing.T) {
+	cases := map[string]struct {
+		path     string
+		method   string
+		status   int
+		requests int
+		want     int
+	}{
+		"show retried on 5xx":      {"/ipa/session/json", "user_show", http.StatusServiceUnavailable, 3, http.StatusOK},
+		"find retried on 5xx":      {"/ipa/session/json", "group_find", http.StatusBadGateway, 3, http.StatusOK},
+		"ping retried on 5xx":      {"/ipa/session/json", "ping", http.StatusInternalServerError, 3, http.StatusOK},
+		"login retried on 5xx":     {"/ipa/session/login_password", "", http.StatusServiceUnavailable, 3, http.StatusOK},
+		"add not retried on 5xx":   {"/ipa/session/json", "user_add", http.StatusServiceUnavailable, 1, http.StatusServiceUnavailable},
+		"mod not retried on 5xx":   {"/ipa/session/json", "user_mod", http.StatusInternalServerError, 1, http.StatusInternalServerError},
+		"show not retried on 4xx":  {"/ipa/session/json", "user_show", http.StatusNotFound, 1, http.StatusNotFound},
+		"login not retried on 4xx": {"/ipa/session/login_password", "", http.StatusUnauthorized, 1, http.StatusUnauthorized},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := &flakyServer{status: c.status, failures: 2}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			tspt := NewRetryTransport(http.DefaultTransport, RetryOptions{MaxRetries: 3, Backoff: time.Millisecond})
+
+			body := `{"method":"` + c.method + `","params":[["jdoe"],{}]}`
+			if c.method == "" {
+				body = "user=admin&password=secret"
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, ts.URL+c.path, strings.NewReader(body))
+
+			resp, err := tspt.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != c.want {
+				t.Errorf("got HTTP %d, want %d", resp.StatusCode, c.want)
+			}
+
+			if len(server.bodies) != c.requests {
+				t.Errorf("got %d requests, want %d", len(server.bodies), c.requests)
+			}
+
+			// Every attempt sends the whole body again
+			for i, b := range server.bodies {
+				if b != body {
+					t.Errorf("attempt %d sent %q, want %q", i, b, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportMaxRetries(t *testing.T) {
+	server := &flakyServer{status: http.StatusServiceUnavailable, failures: 10}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tspt := NewRetryTransport(http.DefaultTransport, RetryOptions{MaxRetries: 2, Backoff: 10 * time.Millisecond})
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/ipa/session/json", strings.NewReader(`{"method":"user_show","params":[["jdoe"],{}]}`))
+
+	start := time.Now()
+
+	resp, err := tspt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// The backoff doubles between the two retries
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("got the retries in %s, want at least %s", elapsed, 30*time.Millisecond)
+	}
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got HTTP %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	if len(server.bodies) != 3 {
+		t.Errorf("got %d requests, want 3", len(server.bodies))
+	}
+}
+
+func TestRetryTransportUnrewindableBody(t *testing.T) {
+	server := &flakyServer{status: http.StatusServiceUnavailable, failures: 2}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tspt := NewRetryTransport(http.DefaultTransport, RetryOptions{MaxRetries: 3, Backoff: time.Millisecond})
+
+	// Without GetBody the body cannot be sent again
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/ipa/session/login_password", io.NopCloser(strings.NewReader("user=admin&password=secret")))
+
+	resp, err := tspt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(server.bodies) != 1 {
+		t.Errorf("got %d requests, want 1", len(server.bodies))
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{4, 16 * time.Second},
+		{5, maxRetryBackoff},
+		{20, maxRetryBackoff},
+	}
+
+	for _, c := range cases {
+		if got := retryDelay(time.Second, c.attempt); got != c.want {
+			t.Errorf("attempt %d: got %s, want %s", c.attempt, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package utils
+
+// SplitManagers returns the managers of a user joined by the result fixup
+// transport.
+func SplitManagers(value *string) []string {
+	if value == nil {
+		return nil
+	}
+
+	return SplitMembermanagers(*value)
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFixManagerResult(t *testing.T) {
+	user := map[string]interface{}{"manager": []interface{}{"alice", "bob"}}
+
+	fixManagerResult(user)
+
+	if got := user["manager"]; got != "alice,bob" {
+		t.Errorf("got %v, want %q", got, "alice,bob")
+	}
+
+	// Users without managers have no manager attribute
+	user = map[string]interface{}{}
+
+	fixManagerResult(user)
+
+	if _, ok := user["manager"]; ok {
+		t.Errorf("got manager %v, want none", user["manager"])
+	}
+}
+
+func TestSplitManagers(t *testing.T) {
+	value := "alice,bob"
+	empty := ""
+
+	cases := map[string]struct {
+		value *string
+		want  []string
+	}{
+		"managers": {&value, []string{"alice", "bob"}},
+		"empty":    {&empty, nil},
+		"unset":    {nil, nil},
+	}
+
+	for name, c := range cases {
+		if got := SplitManagers(c.value); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %q, want %q", name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// VaultWrappingAlgo is the algorithm of the session keys protecting vault data
+// between the client and the KRA.
+const VaultWrappingAlgo = "aes-128-cbc"
+
+// The vault encryption schemes below match the ones of the ipa client, so
+// that data archived by the provider can be retrieved with `ipa
+// vault-retrieve` and the other way round.
+
+// NewVaultSessionKey generates a session key and wraps it with the KRA
+// transport certificate.
+func NewVaultSessionKey(transportCert *x509.Certificate) (key, wrappedKey []byte, err error) {
+	publicKey, ok := transportCert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported KRA transport certificate key type %T", transportCert.PublicKey)
+	}
+
+	key = make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, err
+	}
+
+	wrappedKey, err = rsa.EncryptPKCS1v15(rand.Reader, publicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("wrapping session key: %w", err)
+	}
+
+	return key, wrappedKey, nil
+}
+
+type vaultData struct {
+	Data string `json:"data"`
+}
+
+// WrapVaultData encrypts data with the session key, returning the nonce and
+// the wrapped data to archive.
+func WrapVaultData(key, data []byte) (nonce, wrapped []byte, err error) {
+	payload, err := json.Marshal(vaultData{Data: base64.StdEncoding.EncodeToString(data)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce = make([]byte, aes.BlockSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	wrapped, err = encryptCBC(key, nonce, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nonce, wrapped, nil
+}
+
+// UnwrapVaultData decrypts data retrieved from the KRA with the session key.
+func UnwrapVaultData(key, nonce, wrapped []byte) ([]byte, error) {
+	payload, err := decryptCBC(key, nonce, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	var v vaultData
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return nil, fmt.Errorf("decoding vault data: %w", err)
+	}
+
+	return base64.StdEncoding.DecodeString(v.Data)
+}
+
+// VaultSymmetricEncrypt encrypts the data of a symmetric vault with a Fernet
+// key derived from the vault password and salt.
+func VaultSymmetricEncrypt(password string, salt, data []byte) ([]byte, error) {
+	signingKey, encryptionKey := vaultSymmetricKeys(password, salt)
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	return fernetEncrypt(signingKey, encryptionKey, iv, time.Now(), data)
+}
+
+// VaultSymmetricDecrypt decrypts the data of a symmetric vault.
+func VaultSymmetricDecrypt(password string, salt, data []byte) ([]byte, error) {
+	signingKey, encryptionKey := vaultSymmetricKeys(password, salt)
+
+	return fernetDecrypt(signingKey, encryptionKey, data)
+}
+
+// VaultAsymmetricEncrypt encrypts the data of an asymmetric vault with its
+// PEM encoded public key.
+func VaultAsymmetricEncrypt(publicKeyPEM, data []byte) ([]byte, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing vault public key: %w", err)
+	}
+
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported vault public key type %T", key)
+	}
+
+	return rsa.EncryptOAEP(sha1.New(), rand.Reader, publicKey, data, nil)
+}
+
+func vaultSymmetricKeys(password string, salt []byte) (signingKey, encryptionKey []byte) {
+	key := pbkdf2.Key([]byte(password), salt, 100000, 32, sha256.New)
+
+	return key[:16], key[16:]
+}
+
+// fernetEncrypt returns the Fernet token of data, as generated by the
+// cryptography Python package.
+func fernetEncrypt(signingKey, encryptionKey, iv []byte, now time.Time, data []byte) ([]byte, error) {
+	ciphertext, err := encryptCBC(encryptionKey, iv, data)
+	if err != nil {
+		return nil, err
+	}
+
+	token := make([]byte, 9, 9+len(iv)+len(ciphertext)+sha256.Size)
+	token[0] = 0x80
+	binary.BigEndian.PutUint64(token[1:9], uint64(now.Unix()))
+	token = append(token, iv...)
+	token = append(token, ciphertext...)
+
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write(token)
+	token = mac.Sum(token)
+
+	return []byte(base64.URLEncoding.EncodeToString(token)), nil
+}
+
+// fernetDecrypt verifies and decrypts a Fernet token, regardless of its age.
+func fernetDecrypt(signingKey, encryptionKey, data []byte) ([]byte, error) {
+	token, err := base64.URLEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding symmetric vault data: %w", err)
+	}
+
+	if len(token) < 9+aes.BlockSize+sha256.Size || token[0] != 0x80 {
+		return nil, errors.New("invalid symmetric vault data")
+	}
+
+	payload, sum := token[:len(token)-sha256.Size], token[len(token)-sha256.Size:]
+
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write(payload)
+	if !hmac.Equal(mac.Sum(nil), sum) {
+		return nil, errors.New("invalid vault password")
+	}
+
+	return decryptCBC(encryptionKey, payload[9:9+aes.BlockSize], payload[9+aes.BlockSize:])
+}
+
+func encryptCBC(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	padded := append(bytes.Clone(data), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(padded, padded)
+
+	return padded, nil
+}
+
+func decryptCBC(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != aes.BlockSize || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid encrypted vault data")
+	}
+
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(plain[len(plain)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("invalid vault data padding")
+	}
+
+	return plain[:len(plain)-padding], nil
+}
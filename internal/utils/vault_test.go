@@ -0,0 +1,262 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+// fernetSpecVector is the token generation vector of the Fernet
+// specification, which the cryptography Python package is tested against.
+var fernetSpecVector = struct {
+	secret string
+	now    time.Time
+	iv     []byte
+	src    string
+	token  string
+}{
+	secret: "cw_0x689RpI-jtRR7oE8h_eQsKImvJapLeSbXpwF4e4=",
+	now:    time.Date(1985, time.October, 26, 1, 20, 0, 0, time.FixedZone("", -7*60*60)),
+	iv:     []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	src:    "hello",
+	token:  "gAAAAAAdwJ6wAAECAwQFBgcICQoLDA0ODy021cpGVWKZ_eEwCGM4BLLF_5CV9dOPmrhuVUPgJobwOz7JcbmrR64jVmpU4IwqDA==",
+}
+
+func TestFernetSpecVector(t *testing.T) {
+	v := fernetSpecVector
+
+	secret, err := base64.URLEncoding.DecodeString(v.secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := fernetEncrypt(secret[:16], secret[16:], v.iv, v.now, []byte(v.src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(token) != v.token {
+		t.Errorf("got %q, want %q", token, v.token)
+	}
+
+	got, err := fernetDecrypt(secret[:16], secret[16:], []byte(v.token))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != v.src {
+		t.Errorf("got %q, want %q", got, v.src)
+	}
+}
+
+func TestVaultSymmetric(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+
+	for _, data := range [][]byte{{}, []byte("secret"), bytes.Repeat([]byte("a"), aes.BlockSize)} {
+		encrypted, err := VaultSymmetricEncrypt("password", salt, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := VaultSymmetricDecrypt("password", salt, encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got, data) {
+			t.Errorf("got %q, want %q", got, data)
+		}
+
+		if _, err := VaultSymmetricDecrypt("wrong password", salt, encrypted); err == nil {
+			t.Error("expected a wrong password to fail")
+		}
+
+		if _, err := VaultSymmetricDecrypt("password", []byte("another salt"), encrypted); err == nil {
+			t.Error("expected another salt to fail")
+		}
+	}
+}
+
+func TestVaultSymmetricDecryptTampered(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+
+	encrypted, err := VaultSymmetricEncrypt("password", salt, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := base64.URLEncoding.DecodeString(string(encrypted))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Flip a bit of the timestamp, the IV, the ciphertext and the HMAC in turn
+	for _, i := range []int{5, 9 + 3, 9 + aes.BlockSize + 1, len(token) - 1} {
+		tampered := bytes.Clone(token)
+		tampered[i] ^= 0x01
+
+		if _, err := VaultSymmetricDecrypt("password", salt, []byte(base64.URLEncoding.EncodeToString(tampered))); err == nil {
+			t.Errorf("expected a token tampered at byte %d to fail", i)
+		}
+	}
+
+	for name, data := range map[string]string{
+		"not base64": "not base64!",
+		"truncated":  base64.URLEncoding.EncodeToString(token[:9+aes.BlockSize]),
+		"version":    base64.URLEncoding.EncodeToString(append([]byte{0x81}, token[1:]...)),
+	} {
+		if _, err := VaultSymmetricDecrypt("password", salt, []byte(data)); err == nil {
+			t.Errorf("expected %s data to fail", name)
+		}
+	}
+}
+
+func TestWrapVaultData(t *testing.T) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range [][]byte{{}, []byte("secret"), {0x00, 0xff, 0x80}} {
+		nonce, wrapped, err := WrapVaultData(key, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(nonce) != aes.BlockSize {
+			t.Errorf("got a nonce of %d bytes, want %d", len(nonce), aes.BlockSize)
+		}
+
+		got, err := UnwrapVaultData(key, nonce, wrapped)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got, data) {
+			t.Errorf("got %q, want %q", got, data)
+		}
+	}
+
+	nonce, wrapped, err := WrapVaultData(key, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := UnwrapVaultData(make([]byte, 16), nonce, wrapped); err == nil {
+		t.Error("expected another session key to fail")
+	}
+}
+
+func TestCBCPadding(t *testing.T) {
+	key := make([]byte, 16)
+	iv := make([]byte, aes.BlockSize)
+
+	cases := map[string]struct {
+		data []byte
+		size int
+	}{
+		"empty":           {[]byte{}, aes.BlockSize},
+		"short":           {[]byte("secret"), aes.BlockSize},
+		"one block":       {bytes.Repeat([]byte("a"), aes.BlockSize), 2 * aes.BlockSize},
+		"one block and 1": {bytes.Repeat([]byte("a"), aes.BlockSize+1), 2 * aes.BlockSize},
+	}
+
+	for name, c := range cases {
+		encrypted, err := encryptCBC(key, iv, c.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(encrypted) != c.size {
+			t.Errorf("%s: got %d encrypted bytes, want %d", name, len(encrypted), c.size)
+		}
+
+		got, err := decryptCBC(key, iv, encrypted)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		if !bytes.Equal(got, c.data) {
+			t.Errorf("%s: got %q, want %q", name, got, c.data)
+		}
+	}
+}
+
+func TestDecryptCBCInvalid(t *testing.T) {
+	key := make([]byte, 16)
+	iv := make([]byte, aes.BlockSize)
+
+	encrypted, err := encryptCBC(key, iv, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Blocks encrypted without padding, whose last bytes are not valid PKCS#7
+	// padding: a zero byte, a length over the block size and inconsistent bytes
+	raw := func(last ...byte) []byte {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data := append(bytes.Repeat([]byte("a"), aes.BlockSize-len(last)), last...)
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+
+		return data
+	}
+
+	cases := map[string]struct {
+		iv, data []byte
+	}{
+		"empty":        {iv, []byte{}},
+		"partial":      {iv, encrypted[:aes.BlockSize-1]},
+		"short iv":     {iv[:8], encrypted},
+		"zero padding": {iv, raw(0x00)},
+		"long padding": {iv, raw(0x11)},
+		"bad padding":  {iv, raw(0x01, 0x02)},
+	}
+
+	for name, c := range cases {
+		if _, err := decryptCBC(key, c.iv, c.data); err == nil {
+			t.Errorf("expected %s data to fail", name)
+		}
+	}
+}
+
+func TestVaultAsymmetricEncrypt(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := VaultAsymmetricEncrypt(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := rsa.DecryptOAEP(sha1.New(), nil, key, encrypted, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "secret" {
+		t.Errorf("got %q, want %q", got, "secret")
+	}
+
+	if _, err := VaultAsymmetricEncrypt([]byte("not a public key"), []byte("secret")); err == nil {
+		t.Error("expected an invalid public key to fail")
+	}
+}
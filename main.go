@@ -25,6 +25,14 @@ import (
 // can be customized.
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs generate -provider-name freeipa
 
+// providers returns the servers of the providers muxed into the one served.
+func providers() []func() tfprotov5.ProviderServer {
+	return []func() tfprotov5.ProviderServer{
+		freeipa.Provider().GRPCProvider, // legacy provider using terraform-sdk-v2
+		providerserver.NewProtocol5(provider.NewFactory(datasources.DataSources(), resources.Resources())()), // new provider built using terraform-plugin-framework
+	}
+}
+
 func main() {
 	var debug bool
 
@@ -33,12 +41,7 @@ func main() {
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like Delve")
 	flag.Parse()
 
-	providers := []func() tfprotov5.ProviderServer{
-		freeipa.Provider().GRPCProvider, // legacy provider using terraform-sdk-v2
-		providerserver.NewProtocol5(provider.NewFactory(datasources.DataSources(), resources.Resources())()), // new provider built using terraform-plugin-framework
-	}
-
-	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers()...)
 	if err != nil {
 		log.Fatal(err)
 	}